@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// 可选检查依赖的 API
+var (
+	capNodes       = client.Capability{Version: "v1", Resource: "nodes", Verb: "get", ClusterWide: true}
+	capNodeList    = client.Capability{Version: "v1", Resource: "nodes", Verb: "list", ClusterWide: true}
+	capEndpoints   = client.Capability{Version: "v1", Resource: "endpoints", Verb: "get"}
+	capConfigMaps  = client.Capability{Version: "v1", Resource: "configmaps", Verb: "get"}
+	capSecrets     = client.Capability{Version: "v1", Resource: "secrets", Verb: "get"}
+	capPVCs        = client.Capability{Version: "v1", Resource: "persistentvolumeclaims", Verb: "get"}
+	capPVs         = client.Capability{Version: "v1", Resource: "persistentvolumes", Verb: "get", ClusterWide: true}
+	capEvents      = client.Capability{Version: "v1", Resource: "events", Verb: "list"}
+	capNodePods    = client.Capability{Version: "v1", Resource: "pods", Verb: "list", ClusterWide: true}
+	capDeployments = client.Capability{Group: "apps", Version: "v1", Resource: "deployments", Verb: "get"}
+	capJobs        = client.Capability{Group: "batch", Version: "v1", Resource: "jobs", Verb: "get"}
+	capPDBs        = client.Capability{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets", Verb: "list"}
+	capQuotas      = client.Capability{Version: "v1", Resource: "resourcequotas", Verb: "list"}
+	capServices    = client.Capability{Version: "v1", Resource: "services", Verb: "list"}
+	capPodMetrics  = client.Capability{Group: "metrics.k8s.io", Version: "v1beta1", Resource: "pods", Verb: "list"}
+	capPSPs        = client.Capability{Group: "policy", Version: "v1beta1", Resource: "podsecuritypolicies", Verb: "list", ClusterWide: true}
+	capVulnReports = client.Capability{Group: "aquasecurity.github.io", Version: "v1alpha1", Resource: "vulnerabilityreports", Verb: "list"}
+	capNetPols     = client.Capability{Group: "networking.k8s.io", Version: "v1", Resource: "networkpolicies", Verb: "list"}
+	capWebhooks    = client.Capability{Group: "admissionregistration.k8s.io", Version: "v1", Resource: "validatingwebhookconfigurations", Verb: "list", ClusterWide: true}
+)
+
+// optionalCheck 是依赖可选 API 或权限的检查
+type optionalCheck struct {
+	name     string
+	enabled  func(opts *analyzer.Options) *bool
+	requires []client.Capability
+}
+
+// optionalChecks 列出需要探测能力的检查，API 不存在或没有权限时整体跳过
+var optionalChecks = []optionalCheck{
+	{"topology check", func(o *analyzer.Options) *bool { return &o.CheckTopology }, []client.Capability{capNodes}},
+	{"node unschedulable check", func(o *analyzer.Options) *bool { return &o.CheckNodeUnschedulable }, []client.Capability{capNodes}},
+	{"node pressure check", func(o *analyzer.Options) *bool { return &o.CheckNodePressure }, []client.Capability{capNodes}},
+	{"PDB check", func(o *analyzer.Options) *bool { return &o.CheckPDB }, []client.Capability{capPDBs}},
+	{"config drift check", func(o *analyzer.Options) *bool { return &o.CheckDrift }, []client.Capability{capDeployments}},
+	{"ConfigMap check", func(o *analyzer.Options) *bool { return &o.CheckConfigMaps }, []client.Capability{capConfigMaps}},
+	{"config hash check", func(o *analyzer.Options) *bool { return &o.CheckConfigHash }, []client.Capability{capConfigMaps}},
+	{"Secret check", func(o *analyzer.Options) *bool { return &o.CheckSecrets }, []client.Capability{capSecrets}},
+	{"pull secret check", func(o *analyzer.Options) *bool { return &o.CheckRefs }, []client.Capability{capSecrets}},
+	{"pull secret type check", func(o *analyzer.Options) *bool { return &o.CheckPullSecretType }, []client.Capability{capSecrets}},
+	{"startup order check", func(o *analyzer.Options) *bool { return &o.CheckStartupOrder }, []client.Capability{capEndpoints}},
+	{"storage check", func(o *analyzer.Options) *bool { return &o.CheckStorage }, []client.Capability{capPVCs, capPVs}},
+	{"preemption check", func(o *analyzer.Options) *bool { return &o.CheckPreemption }, []client.Capability{capEvents, capNodePods}},
+	{"Job TTL lookup", func(o *analyzer.Options) *bool { return &o.CheckJobTTL }, []client.Capability{capJobs}},
+	{"vulnerability check", func(o *analyzer.Options) *bool { return &o.CheckVulnerabilities }, []client.Capability{capVulnReports}},
+	{"webhook check", func(o *analyzer.Options) *bool { return &o.CheckWebhooks }, []client.Capability{capWebhooks}},
+	{"NetworkPolicy check", func(o *analyzer.Options) *bool { return &o.CheckNetworkPolicy }, []client.Capability{capNetPols}},
+	{"Job check", func(o *analyzer.Options) *bool { return &o.CheckJob }, []client.Capability{capJobs}},
+	{"liveness cascade check", func(o *analyzer.Options) *bool { return &o.CheckLivenessCascade }, []client.Capability{capEvents}},
+	{"Service port check", func(o *analyzer.Options) *bool { return &o.CheckServicePorts }, []client.Capability{capServices}},
+	{"pod metrics", func(o *analyzer.Options) *bool { return &o.Metrics }, []client.Capability{capPodMetrics}},
+	{"resource efficiency check", func(o *analyzer.Options) *bool { return &o.CheckResourceEfficiency }, []client.Capability{capPodMetrics}},
+	{"PodSecurityPolicy check", func(o *analyzer.Options) *bool { return &o.CheckPSP }, []client.Capability{capPSPs}},
+	{"rollout quota check", func(o *analyzer.Options) *bool { return &o.CheckQuota }, []client.Capability{capDeployments, capQuotas}},
+}
+
+// versionedCheck 是依赖较新 Kubernetes 版本才有的字段、或已被移除的 API 的检查
+type versionedCheck struct {
+	name         string
+	enabled      func(opts *analyzer.Options) *bool
+	minMinor     int // 需要的最低次版本号，如 26 表示 1.26
+	removedMinor int // 依赖的 API 被移除的次版本号，如 25 表示 1.25 起不可用，0 表示未移除
+}
+
+// versionedChecks 列出需要检查服务器版本的检查，版本不在支持范围内时整体跳过
+var versionedChecks = []versionedCheck{
+	{"Job check", func(o *analyzer.Options) *bool { return &o.CheckJob }, analyzer.PodFailurePolicyMinMinorVersion, 0},
+	{"PodSecurityPolicy check", func(o *analyzer.Options) *bool { return &o.CheckPSP }, 0, analyzer.PSPRemovedMinorVersion},
+}
+
+// disableUnavailableChecks 先检查服务器版本，再探测已启用的可选检查所依赖的能力，关闭不可用的检查并返回跳过的列表
+// 命名空间级别的能力在每个查询的命名空间（见 queryNamespaces）中分别探测，每个能力和命名空间在本次运行中只探测一次
+func disableUnavailableChecks(ctx context.Context, k8sClient *client.Client, namespaces []string, opts *analyzer.Options) []analyzer.SkippedCheck {
+	skipped := disableUnsupportedChecks(k8sClient, opts)
+	for _, check := range optionalChecks {
+		enabled := check.enabled(opts)
+		if !*enabled {
+			continue
+		}
+		for _, capability := range check.requires {
+			if reason := probeNamespaces(ctx, k8sClient, namespaces, capability); reason != "" {
+				*enabled = false
+				skipped = append(skipped, analyzer.SkippedCheck{Check: check.name, Reason: reason})
+				break
+			}
+		}
+	}
+	return skipped
+}
+
+// probeNamespaces 在每个命名空间中探测能力，返回第一个不可用的原因，都可用时返回空字符串
+func probeNamespaces(ctx context.Context, k8sClient *client.Client, namespaces []string, capability client.Capability) string {
+	for _, ns := range namespaces {
+		if reason := k8sClient.ProbeCapability(ctx, ns, capability); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+// disableUnsupportedChecks 关闭服务器版本过低或过高的检查，服务器版本只查询一次
+// 查询失败或版本无法解析时视为支持，由具体检查在数据缺失时跳过
+func disableUnsupportedChecks(k8sClient *client.Client, opts *analyzer.Options) []analyzer.SkippedCheck {
+	var (
+		skipped []analyzer.SkippedCheck
+		minor   int
+		err     error
+		queried bool
+	)
+	for _, check := range versionedChecks {
+		enabled := check.enabled(opts)
+		if !*enabled {
+			continue
+		}
+		if !queried {
+			minor, err = k8sClient.ServerMinorVersion()
+			queried = true
+		}
+		switch {
+		case err != nil:
+		case minor < check.minMinor:
+			*enabled = false
+			skipped = append(skipped, analyzer.SkippedCheck{Check: check.name, Reason: fmt.Sprintf("requires Kubernetes 1.%d+", check.minMinor)})
+		case check.removedMinor > 0 && minor >= check.removedMinor:
+			*enabled = false
+			skipped = append(skipped, analyzer.SkippedCheck{Check: check.name, Reason: fmt.Sprintf("removed in Kubernetes 1.%d", check.removedMinor)})
+		}
+	}
+	return skipped
+}
+
+// printSkippedChecks 在标准错误输出中用一行汇总跳过的检查
+func printSkippedChecks(skipped []analyzer.SkippedCheck) {
+	if len(skipped) == 0 {
+		return
+	}
+	parts := make([]string, 0, len(skipped))
+	for _, s := range skipped {
+		parts = append(parts, fmt.Sprintf("%s (%s)", s.Check, s.Reason))
+	}
+	fmt.Fprintf(os.Stderr, "⚠️  skipped: %s\n", strings.Join(parts, ", "))
+}
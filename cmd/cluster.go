@@ -0,0 +1,706 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// collectClusterData 根据启用的检查获取额外的集群对象，按命名空间列出的对象在每个查询的命名空间中分别获取
+// 获取失败时只打印警告，不中断整体分析
+func collectClusterData(ctx context.Context, k8sClient *client.Client, namespaces []string, pods *corev1.PodList, opts analyzer.Options) *analyzer.ClusterData {
+	data := &analyzer.ClusterData{
+		Nodes:       make(map[string]*corev1.Node),
+		ReplicaSets: make(map[string]*appsv1.ReplicaSet),
+		Deployments: make(map[string]*appsv1.Deployment),
+		ConfigMaps:  make(map[string]*corev1.ConfigMap),
+		Secrets:     make(map[string]*corev1.Secret),
+		Endpoints:   make(map[string]*corev1.Endpoints),
+		PVCs:        make(map[string]*corev1.PersistentVolumeClaim),
+		PVs:         make(map[string]*corev1.PersistentVolume),
+
+		DetailsOmitted: make(map[string]bool),
+	}
+
+	// 按 Pod 补充数据的请求会占用配额，先处理最需要排查的 Pod
+	prioritized := prioritizedPods(pods)
+
+	// -A 模式下命名空间已在列出 Pod 时缓存，不会产生额外请求
+	data.Namespaces = collectNamespaces(ctx, k8sClient, pods)
+
+	if opts.CheckTopology || opts.CheckNodeUnschedulable || opts.CheckNodePressure || analyzer.ProviderRulesNeedNodes(opts.Providers) {
+		data.Nodes = collectNodes(ctx, k8sClient, pods)
+	}
+
+	collectClusterNodes(ctx, k8sClient, pods, data)
+
+	// --check-config 检查 selector，--check-config-drift 比较 Pod 与 ReplicaSet 模板
+	if opts.CheckConfig || opts.CheckDrift {
+		for _, pod := range pods.Items {
+			rsName := analyzer.ControllerReplicaSet(&pod)
+			if rsName == "" {
+				continue
+			}
+			key := pod.Namespace + "/" + rsName
+			if _, ok := data.ReplicaSets[key]; ok {
+				continue
+			}
+			rs, err := k8sClient.GetReplicaSet(ctx, pod.Namespace, rsName)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to get replicaset '%s': %v\n", key, err)
+				}
+				data.ReplicaSets[key] = nil
+				continue
+			}
+			data.ReplicaSets[key] = rs
+		}
+	}
+
+	// --check-quota 需要 Deployment 的新模板和滚动更新进度
+	if opts.CheckDrift || opts.CheckQuota {
+		for _, pod := range pods.Items {
+			kind, name := analyzer.ResolveOwner(&pod)
+			if kind != "Deployment" {
+				continue
+			}
+			key := pod.Namespace + "/" + name
+			if _, ok := data.Deployments[key]; ok {
+				continue
+			}
+			deploy, err := k8sClient.GetDeployment(ctx, pod.Namespace, name)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to get deployment '%s': %v\n", key, err)
+				}
+				data.Deployments[key] = nil
+				continue
+			}
+			data.Deployments[key] = deploy
+		}
+	}
+
+	if opts.CheckConfigMaps || opts.CheckConfigHash {
+		for _, pod := range pods.Items {
+			for _, name := range analyzer.ReferencedConfigMaps(&pod) {
+				key := pod.Namespace + "/" + name
+				if _, ok := data.ConfigMaps[key]; ok {
+					continue
+				}
+				cm, err := k8sClient.GetConfigMap(ctx, pod.Namespace, name)
+				if err != nil {
+					if !apierrors.IsNotFound(err) {
+						fmt.Fprintf(progressOut(), "⚠️  Failed to get configmap '%s': %v\n", key, err)
+					}
+					data.ConfigMaps[key] = nil
+					continue
+				}
+				data.ConfigMaps[key] = cm
+			}
+		}
+	}
+
+	if opts.CheckSecrets || opts.CheckRefs || opts.CheckPullSecretType {
+		for _, pod := range pods.Items {
+			var names []string
+			if opts.CheckSecrets {
+				names = append(names, analyzer.ReferencedSecrets(&pod)...)
+			}
+			if opts.CheckRefs || opts.CheckPullSecretType {
+				names = append(names, analyzer.ImagePullSecrets(&pod)...)
+			}
+			for _, name := range names {
+				key := pod.Namespace + "/" + name
+				if _, ok := data.Secrets[key]; ok {
+					continue
+				}
+				secret, err := k8sClient.GetSecret(ctx, pod.Namespace, name)
+				if err != nil {
+					if !apierrors.IsNotFound(err) {
+						fmt.Fprintf(progressOut(), "⚠️  Failed to get secret '%s': %v\n", key, err)
+					}
+					data.Secrets[key] = nil
+					continue
+				}
+				data.Secrets[key] = secret
+			}
+		}
+	}
+
+	if opts.CheckStartupOrder {
+		for _, pod := range pods.Items {
+			for _, dep := range analyzer.ServiceDependencies(&pod) {
+				key := dep.Namespace + "/" + dep.Name
+				if _, ok := data.Endpoints[key]; ok {
+					continue
+				}
+				endpoints, err := k8sClient.GetEndpoints(ctx, dep.Namespace, dep.Name)
+				if err != nil {
+					if !apierrors.IsNotFound(err) {
+						fmt.Fprintf(progressOut(), "⚠️  Failed to get endpoints '%s': %v\n", key, err)
+					}
+					data.Endpoints[key] = nil
+					continue
+				}
+				data.Endpoints[key] = endpoints
+			}
+		}
+	}
+
+	if opts.CheckStorage {
+		collectStorage(ctx, k8sClient, prioritized, data)
+		collectVolumeEvents(ctx, k8sClient, namespaces, prioritized, data)
+	}
+
+	if opts.CheckPreemption {
+		collectPreemptionData(ctx, k8sClient, prioritized, data)
+	}
+
+	collectSilentFailureEvents(ctx, k8sClient, namespaces, prioritized, data)
+
+	if opts.CheckStaleImages {
+		data.RemoteDigests = collectRemoteDigests(ctx, k8sClient, pods)
+	}
+
+	if opts.CheckJobTTL {
+		data.Jobs = collectJobs(ctx, k8sClient, pods)
+	}
+
+	if opts.CheckJob {
+		collectOwnerJobs(ctx, k8sClient, pods, data)
+	}
+
+	if opts.CheckPDB {
+		pdbs, err := listInNamespaces(namespaces, func(ns string) ([]policyv1.PodDisruptionBudget, error) {
+			list, err := k8sClient.GetPodDisruptionBudgets(ctx, ns)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list poddisruptionbudgets: %v\n", err)
+		} else {
+			data.PDBs = pdbs
+		}
+	}
+
+	if opts.CheckQuota {
+		quotas, err := listInNamespaces(namespaces, func(ns string) ([]corev1.ResourceQuota, error) {
+			list, err := k8sClient.GetResourceQuotas(ctx, ns)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list resourcequotas: %v\n", err)
+		} else {
+			data.ResourceQuotas = quotas
+		}
+	}
+
+	if opts.CheckNetworkPolicy {
+		policies, err := listInNamespaces(namespaces, func(ns string) ([]networkingv1.NetworkPolicy, error) {
+			list, err := k8sClient.GetNetworkPolicies(ctx, ns)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list networkpolicies: %v\n", err)
+		} else {
+			// 空列表表示已获取、没有任何策略，与未获取（nil）区分
+			data.NetworkPolicies = append([]networkingv1.NetworkPolicy{}, policies...)
+		}
+	}
+
+	if opts.CheckServicePorts {
+		services, err := listInNamespaces(namespaces, func(ns string) ([]corev1.Service, error) {
+			list, err := k8sClient.GetServices(ctx, ns)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list services: %v\n", err)
+		} else {
+			data.Services = append([]corev1.Service{}, services...)
+		}
+	}
+
+	if opts.CheckPSP {
+		policies, err := k8sClient.GetPodSecurityPolicies(ctx)
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list podsecuritypolicies: %v\n", err)
+		} else {
+			data.PodSecurityPolicies = make([]analyzer.PodSecurityPolicy, 0, len(policies.Items))
+			for i := range policies.Items {
+				psp, err := analyzer.PodSecurityPolicyFromUnstructured(&policies.Items[i])
+				if err != nil {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to parse podsecuritypolicy %s: %v\n", policies.Items[i].GetName(), err)
+					continue
+				}
+				data.PodSecurityPolicies = append(data.PodSecurityPolicies, psp)
+			}
+		}
+	}
+
+	if opts.Metrics {
+		var usage map[string]client.ContainerUsage
+		var err error
+		for _, ns := range namespaces {
+			var nsUsage map[string]client.ContainerUsage
+			if nsUsage, err = k8sClient.GetPodMetrics(ctx, ns); err != nil {
+				break
+			}
+			if usage == nil {
+				usage = nsUsage
+			} else {
+				maps.Copy(usage, nsUsage)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to get pod metrics: %v\n", err)
+		} else {
+			data.PodUsage = usage
+		}
+	}
+
+	if opts.CheckLivenessCascade {
+		events, err := listInNamespaces(namespaces, func(ns string) ([]corev1.Event, error) {
+			list, err := k8sClient.GetEventsByReason(ctx, ns, analyzer.EventReasonKilling)
+			if err != nil {
+				return nil, err
+			}
+			return list.Items, nil
+		})
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list events: %v\n", err)
+		} else {
+			data.KillingEvents = events
+		}
+	}
+
+	if opts.CheckWebhooks {
+		webhooks, err := k8sClient.GetValidatingWebhooks(ctx)
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list validatingwebhookconfigurations: %v\n", err)
+		} else {
+			data.ValidatingWebhooks = webhooks.Items
+		}
+	}
+
+	if opts.CheckVulnerabilities {
+		data.Vulnerabilities = collectVulnerabilities(ctx, k8sClient, prioritized, data.DetailsOmitted)
+	}
+
+	return data
+}
+
+// listInNamespaces 在每个查询的命名空间中调用 list 并合并结果，任一命名空间失败时返回错误
+// --namespace-file 模式下按文件中的命名空间分别请求，权限只限于这些命名空间的用户也能获取
+func listInNamespaces[T any](namespaces []string, list func(namespace string) ([]T, error)) ([]T, error) {
+	var items []T
+	for _, ns := range namespaces {
+		got, err := list(ns)
+		if err != nil {
+			if ns != "" {
+				err = fmt.Errorf("namespace %s: %w", ns, err)
+			}
+			return nil, err
+		}
+		items = append(items, got...)
+	}
+	return items, nil
+}
+
+// prioritizedPods 返回按补充数据优先级排序的 Pod：Error 在前，其次是 Warning，其余保持原有顺序
+func prioritizedPods(pods *corev1.PodList) *corev1.PodList {
+	rank := func(pod *corev1.Pod) int {
+		switch analyzer.QuickStatus(pod) {
+		case analyzer.StatusError:
+			return 0
+		case analyzer.StatusWarning:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	ordered := &corev1.PodList{Items: make([]corev1.Pod, len(pods.Items))}
+	copy(ordered.Items, pods.Items)
+	sort.SliceStable(ordered.Items, func(i, j int) bool {
+		return rank(&ordered.Items[i]) < rank(&ordered.Items[j])
+	})
+	return ordered
+}
+
+// budgetExhausted 判断请求是否因配额用完被拒绝，是则记录该 Pod 缺少部分数据
+func budgetExhausted(omitted map[string]bool, pod *corev1.Pod, err error) bool {
+	if !errors.Is(err, client.ErrBudgetExhausted) {
+		return false
+	}
+	omitted[pod.Namespace+"/"+pod.Name] = true
+	return true
+}
+
+// collectPreemptionData 获取有提名节点的 Pending Pod 的事件，以及提名节点上的所有 Pod
+func collectPreemptionData(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, data *analyzer.ClusterData) {
+	if data.PodEvents == nil {
+		data.PodEvents = make(map[string][]corev1.Event)
+	}
+	data.NodePods = make(map[string][]corev1.Pod)
+
+	for _, pod := range pods.Items {
+		node := analyzer.NominatedNode(&pod)
+		if node == "" {
+			continue
+		}
+
+		key := pod.Namespace + "/" + pod.Name
+		events, err := k8sClient.GetEvents(ctx, pod.Namespace, pod.Name)
+		if budgetExhausted(data.DetailsOmitted, &pod, err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to get events for pod '%s': %v\n", key, err)
+		} else {
+			data.PodEvents[key] = events.Items
+		}
+
+		if _, ok := data.NodePods[node]; ok {
+			continue
+		}
+		nodePods, err := k8sClient.GetPodsOnNode(ctx, node)
+		if budgetExhausted(data.DetailsOmitted, &pod, err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to list pods on node '%s': %v\n", node, err)
+			data.NodePods[node] = nil
+			continue
+		}
+		data.NodePods[node] = nodePods.Items
+	}
+}
+
+// collectSilentFailureEvents 获取没有原因的 Error/Unknown Pod 的事件，用于显示最近一次活动
+// 事件请求占用额外请求配额；没有列出事件的权限时直接跳过
+func collectSilentFailureEvents(ctx context.Context, k8sClient *client.Client, namespaces []string, pods *corev1.PodList, data *analyzer.ClusterData) {
+	var silent []*corev1.Pod
+	for i := range pods.Items {
+		if analyzer.SilentFailure(&pods.Items[i]) {
+			silent = append(silent, &pods.Items[i])
+		}
+	}
+	if len(silent) == 0 || probeNamespaces(ctx, k8sClient, namespaces, capEvents) != "" {
+		return
+	}
+
+	if data.PodEvents == nil {
+		data.PodEvents = make(map[string][]corev1.Event)
+	}
+	for _, pod := range silent {
+		key := pod.Namespace + "/" + pod.Name
+		if _, ok := data.PodEvents[key]; ok {
+			continue
+		}
+		events, err := k8sClient.GetEvents(ctx, pod.Namespace, pod.Name)
+		if budgetExhausted(data.DetailsOmitted, pod, err) {
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(progressOut(), "⚠️  Failed to get events for pod '%s': %v\n", key, err)
+			continue
+		}
+		data.PodEvents[key] = events.Items
+	}
+}
+
+// collectVolumeEvents 获取卡在卷操作的 Pod（见 StuckOnVolumes）的事件和所在节点，用于识别挂接数上限和等待扩容
+// 已获取的事件和节点不会重复请求；没有列出事件的权限时直接跳过
+func collectVolumeEvents(ctx context.Context, k8sClient *client.Client, namespaces []string, pods *corev1.PodList, data *analyzer.ClusterData) {
+	var stuck []*corev1.Pod
+	for i := range pods.Items {
+		if analyzer.StuckOnVolumes(&pods.Items[i]) {
+			stuck = append(stuck, &pods.Items[i])
+		}
+	}
+	if len(stuck) == 0 || probeNamespaces(ctx, k8sClient, namespaces, capEvents) != "" {
+		return
+	}
+
+	if data.PodEvents == nil {
+		data.PodEvents = make(map[string][]corev1.Event)
+	}
+	for _, pod := range stuck {
+		key := pod.Namespace + "/" + pod.Name
+		if _, ok := data.PodEvents[key]; !ok {
+			events, err := k8sClient.GetEvents(ctx, pod.Namespace, pod.Name)
+			if budgetExhausted(data.DetailsOmitted, pod, err) {
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get events for pod '%s': %v\n", key, err)
+				continue
+			}
+			data.PodEvents[key] = events.Items
+		}
+
+		nodeName := pod.Spec.NodeName
+		if _, ok := data.Nodes[nodeName]; ok {
+			continue
+		}
+		node, err := k8sClient.GetNode(ctx, nodeName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get node '%s': %v\n", nodeName, err)
+			}
+			data.Nodes[nodeName] = nil
+			continue
+		}
+		data.Nodes[nodeName] = node
+	}
+}
+
+// collectStorage 获取 Pod 引用的 PVC 及其绑定的 PV
+func collectStorage(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, data *analyzer.ClusterData) {
+	for _, pod := range pods.Items {
+		for _, name := range analyzer.PersistentVolumeClaims(&pod) {
+			key := pod.Namespace + "/" + name
+			if _, ok := data.PVCs[key]; ok {
+				continue
+			}
+			pvc, err := k8sClient.GetPersistentVolumeClaim(ctx, pod.Namespace, name)
+			if budgetExhausted(data.DetailsOmitted, &pod, err) {
+				continue
+			}
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to get persistentvolumeclaim '%s': %v\n", key, err)
+				}
+				data.PVCs[key] = nil
+				continue
+			}
+			data.PVCs[key] = pvc
+
+			pvName := pvc.Spec.VolumeName
+			if pvName == "" {
+				continue
+			}
+			if _, ok := data.PVs[pvName]; ok {
+				continue
+			}
+			pv, err := k8sClient.GetPersistentVolume(ctx, pvName)
+			if budgetExhausted(data.DetailsOmitted, &pod, err) {
+				continue
+			}
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to get persistentvolume '%s': %v\n", pvName, err)
+				}
+				data.PVs[pvName] = nil
+				continue
+			}
+			data.PVs[pvName] = pv
+		}
+	}
+}
+
+// collectRemoteDigests 从镜像仓库查询 Pod 使用的镜像 tag 当前的 digest，每个镜像只查询一次
+// 查询失败（如私有镜像需要认证）时打印警告，该镜像不参与检查
+func collectRemoteDigests(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList) map[string]string {
+	digests := make(map[string]string)
+	for _, pod := range pods.Items {
+		for _, container := range pod.Spec.Containers {
+			if _, ok := digests[container.Image]; ok {
+				continue
+			}
+			digest, err := k8sClient.GetRemoteImageDigest(ctx, container.Image)
+			if err != nil {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get registry digest for image '%s': %v\n", container.Image, err)
+			}
+			digests[container.Image] = digest
+		}
+	}
+	return digests
+}
+
+// collectJobs 获取保留了大量已结束 Pod 的 Job，找不到的 Job 记录为 nil
+func collectJobs(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList) map[string]*batchv1.Job {
+	jobs := make(map[string]*batchv1.Job)
+	for _, retaining := range analyzer.RetainingJobs(pods) {
+		key := retaining.Namespace + "/" + retaining.Name
+		job, err := k8sClient.GetJob(ctx, retaining.Namespace, retaining.Name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get job '%s': %v\n", key, err)
+			}
+			jobs[key] = nil
+			continue
+		}
+		jobs[key] = job
+	}
+	return jobs
+}
+
+// collectOwnerJobs 获取 Pod 所属的 Job 并合并到 data.Jobs，已获取的 Job 不再重复请求
+func collectOwnerJobs(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, data *analyzer.ClusterData) {
+	if data.Jobs == nil {
+		data.Jobs = make(map[string]*batchv1.Job)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, name := analyzer.ResolveOwner(pod)
+		if kind != "Job" {
+			continue
+		}
+		key := pod.Namespace + "/" + name
+		if _, ok := data.Jobs[key]; ok {
+			continue
+		}
+		job, err := k8sClient.GetJob(ctx, pod.Namespace, name)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get job '%s': %v\n", key, err)
+			}
+			data.Jobs[key] = nil
+			continue
+		}
+		data.Jobs[key] = job
+	}
+}
+
+// collectNamespaces 获取 Pod 所在的命名空间，用于发现 Terminating 的命名空间
+// 没有命名空间读取权限很常见，此时不打印警告；遇到第一个 Forbidden 就停止，返回 nil 表示没有命名空间数据
+func collectNamespaces(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList) map[string]*corev1.Namespace {
+	namespaces := make(map[string]*corev1.Namespace)
+	for _, pod := range pods.Items {
+		if _, ok := namespaces[pod.Namespace]; ok {
+			continue
+		}
+		ns, err := k8sClient.GetNamespace(ctx, pod.Namespace)
+		if apierrors.IsForbidden(err) {
+			return nil
+		}
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get namespace '%s': %v\n", pod.Namespace, err)
+			}
+			namespaces[pod.Namespace] = nil
+			continue
+		}
+		namespaces[pod.Namespace] = ns
+	}
+	return namespaces
+}
+
+// collectNodes 获取 Pod 所在的节点，找不到的节点记录为 nil
+func collectNodes(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList) map[string]*corev1.Node {
+	nodes := make(map[string]*corev1.Node)
+	for _, pod := range pods.Items {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		if _, ok := nodes[nodeName]; ok {
+			continue
+		}
+		node, err := k8sClient.GetNode(ctx, nodeName)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				fmt.Fprintf(progressOut(), "⚠️  Failed to get node '%s': %v\n", nodeName, err)
+			}
+			// 记录为 nil，避免重复请求
+			nodes[nodeName] = nil
+			continue
+		}
+		nodes[nodeName] = node
+	}
+	return nodes
+}
+
+// collectClusterNodes 在有 Pending Pod 按标签选择节点时获取全部节点，用于判断所选标签是否还存在
+// 没有列出节点的权限时静默跳过，这些 Pod 保留调度器给出的原因
+func collectClusterNodes(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, data *analyzer.ClusterData) {
+	needed := false
+	for i := range pods.Items {
+		if analyzer.SelectsNodeLabels(&pods.Items[i]) {
+			needed = true
+			break
+		}
+	}
+	if !needed || k8sClient.ProbeCapability(ctx, "", capNodeList) != "" {
+		return
+	}
+
+	nodes, err := k8sClient.ListNodes(ctx)
+	if err != nil {
+		fmt.Fprintf(progressOut(), "⚠️  Failed to list nodes: %v\n", err)
+		return
+	}
+	data.ClusterNodes = nodes.Items
+}
+
+// collectVulnerabilities 从 Trivy operator 的 VulnerabilityReport 获取每个容器的高危漏洞数量
+// 集群未安装 Trivy operator 时打印提示并跳过
+func collectVulnerabilities(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, omitted map[string]bool) map[string]int64 {
+	available, err := k8sClient.VulnerabilityReportsAvailable()
+	if err != nil {
+		fmt.Fprintf(progressOut(), "⚠️  Failed to discover vulnerability reports: %v\n", err)
+		return nil
+	}
+	if !available {
+		fmt.Fprintf(progressOut(), "⚠️  Trivy operator CRDs not found, skipping vulnerability check\n")
+		return nil
+	}
+
+	counts := make(map[string]int64)
+	// 同一控制者的副本共用一份报告，按 namespace/kind/name/container 缓存，避免每个副本都请求一次
+	// 获取失败的也缓存为 nil，每个工作负载只警告一次
+	reports := make(map[string]*client.VulnerabilitySummary)
+	for _, pod := range pods.Items {
+		// Trivy 扫描的是 Pod 的直接控制者，裸 Pod 则直接扫描 Pod
+		kind, name := "Pod", pod.Name
+		if ref := metav1.GetControllerOf(&pod); ref != nil {
+			kind, name = ref.Kind, ref.Name
+		}
+
+		for _, container := range pod.Spec.Containers {
+			key := pod.Namespace + "/" + kind + "/" + name + "/" + container.Name
+			summary, ok := reports[key]
+			if !ok {
+				var err error
+				summary, err = k8sClient.GetVulnerabilityReport(ctx, pod.Namespace, kind, name, container.Name)
+				if budgetExhausted(omitted, &pod, err) {
+					break
+				}
+				reports[key] = summary
+				if err != nil {
+					fmt.Fprintf(progressOut(), "⚠️  Failed to get vulnerability report for '%s/%s': %v\n", pod.Namespace, pod.Name, err)
+					continue
+				}
+			}
+			if summary != nil {
+				counts[pod.Namespace+"/"+pod.Name+"/"+container.Name] = summary.Critical + summary.High
+			}
+		}
+	}
+	return counts
+}
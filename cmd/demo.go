@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// demoFixture 是内置的演示集群数据，覆盖健康、CrashLoopBackOff、无法调度、OOMKilled、ECI 和配置问题等场景
+//
+//go:embed demo/cluster.yaml
+var demoFixture []byte
+
+// demoNow 是演示数据对应的固定时钟，保证每次输出的 AGE 等列相同
+var demoNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// demoCmd 使用内置数据运行完整的分析和输出流程，不需要连接集群
+// 根命令的参数（由 init 共享）同样适用，如 kubectl podview demo -A --check-config
+var demoCmd = &cobra.Command{
+	Use:   "demo",
+	Short: "Run podview against built-in sample data, no cluster needed",
+	Example: `  # See what podview output looks like
+  kubectl podview demo
+
+  # Try the configuration checks on the sample data
+  kubectl podview demo -A --check-config`,
+	Args: cobra.NoArgs,
+	RunE: runDemo,
+}
+
+func init() {
+	rootCmd.AddCommand(demoCmd)
+}
+
+// runDemo 将客户端和时钟替换为演示数据后执行与根命令相同的流程
+func runDemo(cmd *cobra.Command, args []string) error {
+	objects, err := loadDemoObjects()
+	if err != nil {
+		return fmt.Errorf("failed to load demo data: %w", err)
+	}
+
+	newClient = func(string) (*client.Client, error) {
+		return newFakeClient(objects...), nil
+	}
+	analyzer.Now = func() time.Time { return demoNow }
+
+	fmt.Fprintf(progressOut(), "🎬 Using built-in demo data (clock fixed at %s)\n", demoNow.Format(time.RFC3339))
+	return runPodView(cmd, args)
+}
+
+// newFakeClient 创建基于 fake clientset 的客户端
+// fake clientset 默认没有 discovery 信息，SelfSubjectAccessReview 也总是拒绝，
+// 这里声明内置的可选 API 并允许所有操作，使能力探测与有完整权限的集群一致
+func newFakeClient(objects ...runtime.Object) *client.Client {
+	clientset := fake.NewClientset(objects...)
+
+	resources := make(map[string]*metav1.APIResourceList)
+	for _, check := range optionalChecks {
+		for _, capability := range check.requires {
+			// 带域名的 API 组来自 CRD（如 Trivy operator），演示集群中没有；*.k8s.io 是内置的 API 组，
+			// 但 metrics.k8s.io 由 metrics-server 提供，演示集群中也没有
+			if strings.Contains(capability.Group, ".") && !strings.HasSuffix(capability.Group, ".k8s.io") || capability.Group == capPodMetrics.Group {
+				continue
+			}
+			gv := capability.GroupVersion()
+			if resources[gv] == nil {
+				resources[gv] = &metav1.APIResourceList{GroupVersion: gv}
+				clientset.Resources = append(clientset.Resources, resources[gv])
+			}
+			resources[gv].APIResources = append(resources[gv].APIResources, metav1.APIResource{Name: capability.Resource})
+		}
+	}
+
+	// 默认的 fake 版本无法解析，演示集群按较新的版本处理，使依赖版本的检查可以运行
+	clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{Major: "1", Minor: "34", GitVersion: "v1.34.3"}
+
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		return true, review, nil
+	})
+
+	c := client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	// fake clientset 不经过 HTTP，在最前面的 reactor 中计数，使 --stats 在演示模式下也有数据
+	clientset.PrependReactor("*", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		c.Stats().Record(fakeRequestKind(action))
+		return false, nil, nil
+	})
+	return c
+}
+
+// fakeRequestKind 返回 fake clientset 动作对应的请求类型，与真实请求的统计方式一致
+func fakeRequestKind(action k8stesting.Action) string {
+	gvr := action.GetResource()
+	if gvr.Version == "" {
+		// fake discovery 的动作没有 API 版本
+		return "discovery"
+	}
+	resource := gvr.Resource
+	if sub := action.GetSubresource(); sub != "" {
+		resource += "/" + sub
+	}
+	return action.GetVerb() + " " + resource
+}
+
+// loadDemoObjects 解析内置的 List，返回其中的 Namespace、Node 和 Pod
+func loadDemoObjects() ([]runtime.Object, error) {
+	var list struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := yaml.Unmarshal(demoFixture, &list); err != nil {
+		return nil, err
+	}
+
+	objects := make([]runtime.Object, 0, len(list.Items))
+	for i, raw := range list.Items {
+		var meta metav1.TypeMeta
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("items[%d]: %w", i, err)
+		}
+
+		var obj runtime.Object
+		switch meta.Kind {
+		case "Namespace":
+			obj = &corev1.Namespace{}
+		case "Node":
+			obj = &corev1.Node{}
+		case "Pod":
+			obj = &corev1.Pod{}
+		default:
+			return nil, fmt.Errorf("items[%d]: unsupported kind %q", i, meta.Kind)
+		}
+		if err := json.Unmarshal(raw, obj); err != nil {
+			return nil, fmt.Errorf("items[%d]: %w", i, err)
+		}
+		objects = append(objects, obj)
+	}
+	return objects, nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// TestDemo 通过 demo 子命令走一遍完整的获取、分析和输出流程
+func TestDemo(t *testing.T) {
+	out, err := executeRoot(t, "demo", "-A", "--check-config", "--verbose-issues", "--find-duplicates")
+	if err != nil {
+		t.Fatalf("demo failed: %v\noutput:\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		"Analyzing 9 pods",
+		"CoreDNS 1/1",
+		"api-5d8f6b7c9-r4t2n",
+		"CrashLoopBackOff",
+		"panic: dial tcp 10.96.14.2:5432",
+		"Unschedulable: 0/3 nodes are available",
+		"worker-6b9c8d7f5-m3n7p",
+		"eci*",
+		"2h55m", // AGE 由固定时钟计算
+		string(analyzer.IssueMissingLimits),
+		"Total Pods:     9",
+		"Running on ECI: 1",
+		"Recommendations",
+		"same name: web",
+		"Deployment shop-old/web  1 replica",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("demo output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+}
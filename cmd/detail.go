@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/printer"
+)
+
+// detailOutput 是 detail 子命令的输出格式：text、json 或 yaml
+var detailOutput string
+
+// detailCmd 显示单个 Pod 的详细信息和生命周期时间线
+var detailCmd = &cobra.Command{
+	Use:   "detail POD",
+	Short: "Show a single pod in detail, including its lifecycle timeline",
+	Example: `  # Show where a pod spent its startup time
+  kubectl podview detail my-app-7c79c4bf97-abc12 -n test-gatekeeper
+
+  # Export the pod and its timeline for scripting
+  kubectl podview detail my-app-7c79c4bf97-abc12 -n test-gatekeeper -o json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDetail,
+}
+
+func init() {
+	detailCmd.Flags().StringVarP(&detailOutput, "output", "o", "text", "Output format: text, json or yaml")
+	rootCmd.AddCommand(detailCmd)
+}
+
+// runDetail 获取并打印单个 Pod 的详细信息
+func runDetail(cmd *cobra.Command, args []string) error {
+	if detailOutput != "text" && detailOutput != "json" && detailOutput != "yaml" {
+		return fmt.Errorf("--output must be text, json or yaml, got %q", detailOutput)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	k8sClient, err := newClient(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	pod, err := k8sClient.GetPod(ctx, namespace, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	// 详情视图列出全部配置问题，因此总是运行 --check-config 的检查
+	result := analyzer.AnalyzePods(&corev1.PodList{Items: []corev1.Pod{*pod}}, analyzer.Options{CheckConfig: true})
+	timeline := analyzer.PodTimeline(pod)
+	switch detailOutput {
+	case "json":
+		return printer.NewJSONPrinter(os.Stdout).PrintPodDetail(result.Pods[0], timeline)
+	case "yaml":
+		return printer.NewYAMLPrinter(os.Stdout).PrintPodDetail(result.Pods[0], timeline)
+	}
+	printer.NewPrinter(os.Stdout).PrintPodDetail(result.Pods[0], timeline)
+	return nil
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/config"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/printer"
+)
+
+// eci 子命令的参数
+var (
+	eciOutput        string
+	eciAllNamespaces bool
+)
+
+// eciCmd 导出 ECI Pod 及其实例 ID、可用区和规格，用于与云控制台对账
+var eciCmd = &cobra.Command{
+	Use:   "eci",
+	Short: "Export ECI pods with their instance IDs, zones and specs as CSV or JSON",
+	Example: `  # Export ECI instances across the cluster for billing reconciliation
+  kubectl podview eci -A -o csv > eci-instances.csv
+
+  # Same data as JSON
+  kubectl podview eci -n batch -o json`,
+	Args: cobra.NoArgs,
+	RunE: runECI,
+}
+
+func init() {
+	eciCmd.Flags().StringVarP(&eciOutput, "output", "o", "csv", "Output format: csv or json")
+	eciCmd.Flags().BoolVarP(&eciAllNamespaces, "all-namespaces", "A", false, "Query all namespaces")
+	rootCmd.AddCommand(eciCmd)
+}
+
+// runECI 获取 Pod，识别 ECI Pod 并按指定格式输出，标准输出只包含导出的数据
+func runECI(cmd *cobra.Command, args []string) (err error) {
+	if eciOutput != "csv" && eciOutput != "json" {
+		return fmt.Errorf("--output must be csv or json, got %q", eciOutput)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	providers := cfg.ProviderRules()
+
+	timeout := 30 * time.Second
+	if eciAllNamespaces {
+		timeout = 60 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	k8sClient, err := newClient(kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	var pods *corev1.PodList
+	if eciAllNamespaces {
+		var failed map[string]error
+		pods, failed, err = fetchPodsPerNamespace(ctx, k8sClient)
+		if err != nil {
+			return err
+		}
+		if len(failed) > 0 {
+			// 导出已获取的部分，失败的命名空间写到标准错误，并以专用退出码结束
+			defer func() {
+				if err == nil {
+					printFailedNamespaces(os.Stderr, failed, true)
+					cmd.SilenceUsage = true
+					cmd.SilenceErrors = true
+					err = &PartialResultError{Failed: failed}
+				}
+			}()
+		}
+	} else {
+		pods, err = k8sClient.GetPods(ctx, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get pods: %w", err)
+		}
+	}
+
+	// 按节点标签或 taint 识别的虚拟节点需要 Pod 所在的节点
+	opts := analyzer.Options{Providers: providers}
+	if analyzer.ProviderRulesNeedNodes(providers) {
+		opts.Cluster = &analyzer.ClusterData{Nodes: eciNodes(ctx, k8sClient, pods)}
+	}
+	instances := analyzer.ECIInstances(analyzer.AnalyzePods(pods, opts))
+
+	p := printer.NewPrinter(os.Stdout)
+	if eciOutput == "json" {
+		return p.PrintECIInstancesJSON(instances)
+	}
+	return p.PrintECIInstancesCSV(instances)
+}
+
+// eciNodes 获取 Pod 所在的节点，与 collectNodes 相同，但警告写到标准错误，不混入导出的数据
+// 获取失败的节点记录为 nil，这些 Pod 只按自身的注解和节点名称识别
+func eciNodes(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList) map[string]*corev1.Node {
+	nodes := make(map[string]*corev1.Node)
+	for _, pod := range pods.Items {
+		nodeName := pod.Spec.NodeName
+		if nodeName == "" {
+			continue
+		}
+		if _, ok := nodes[nodeName]; ok {
+			continue
+		}
+		node, err := k8sClient.GetNode(ctx, nodeName)
+		if err != nil && !apierrors.IsNotFound(err) {
+			fmt.Fprintf(os.Stderr, "⚠️  Failed to get node '%s': %v\n", nodeName, err)
+		}
+		nodes[nodeName] = node
+	}
+	return nodes
+}
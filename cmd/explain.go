@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/printer"
+)
+
+// explainOutput 是 explain 子命令的输出格式
+var explainOutput string
+
+// explainCmd 说明一个配置问题或 Pod 状态原因：是什么、为什么重要、如何检测和如何修复
+// 说明与建议列表来自同一张表，不带参数时列出所有可用的 ID
+var explainCmd = &cobra.Command{
+	Use:   "explain [FINDING]",
+	Short: "Explain a finding: what it means, why it matters, how it is detected and how to fix it",
+	Example: `  # Why does podview flag pods without limits?
+  kubectl podview explain missing-limits
+
+  # Pod status reasons use their Kubernetes name
+  kubectl podview explain CrashLoopBackOff
+
+  # List all finding IDs
+  kubectl podview explain
+
+  # All explanations as JSON, for docs or other tools
+  kubectl podview explain -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplain,
+}
+
+func init() {
+	explainCmd.Flags().StringVarP(&explainOutput, "output", "o", "text", "Output format: text or json")
+	rootCmd.AddCommand(explainCmd)
+}
+
+// runExplain 打印一个发现的说明，没有参数时列出所有 ID（-o json 时输出全部说明）
+func runExplain(cmd *cobra.Command, args []string) error {
+	if explainOutput != "text" && explainOutput != "json" {
+		return fmt.Errorf("--output must be text or json, got %q", explainOutput)
+	}
+	var id string
+	if len(args) > 0 {
+		id = args[0]
+	}
+
+	p := printer.NewPrinter(os.Stdout)
+	if explainOutput == "json" {
+		if err := p.PrintExplainJSON(id); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%w, run kubectl podview explain to list finding IDs", err)
+		}
+		return nil
+	}
+	if id == "" {
+		p.PrintFindingIDs()
+		return nil
+	}
+	if err := p.PrintExplain(id); err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%w, see the list above", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	out, err := executeRoot(t, "explain", "missing-limits")
+	if err != nil {
+		t.Fatalf("explain failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"missing-limits: Missing resource limits", "Why it matters:", "Set resource limits"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+
+	out, err = executeRoot(t, "explain", "missing-limitz")
+	if err == nil || !strings.Contains(err.Error(), `unknown finding "missing-limitz"`) {
+		t.Errorf("error = %v, want unknown finding", err)
+	}
+	for _, want := range []string{"missing-limits", "CrashLoopBackOff"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("unknown ID output does not list %q\noutput:\n%s", want, out)
+		}
+	}
+}
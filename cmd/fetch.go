@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// ExitPartialResults 是只获取到部分命名空间数据时的退出码
+const ExitPartialResults = 3
+
+// namespaceFetchWorkers 是 -A 模式下并发获取 Pod 的命名空间数量
+const namespaceFetchWorkers = 8
+
+// PartialResultError 表示部分命名空间的 Pod 获取失败，输出只包含成功的部分
+type PartialResultError struct {
+	Failed map[string]error // 命名空间 -> 错误
+}
+
+func (e *PartialResultError) Error() string {
+	return fmt.Sprintf("results are partial: failed to get pods in %d namespaces", len(e.Failed))
+}
+
+// fetchPodsPerNamespace 按命名空间并发获取 Pod
+// 单个命名空间失败（如 API 优先级与公平性返回 429）不影响其他命名空间，失败的命名空间单独返回
+// 没有列出命名空间的权限时退回到一次跨命名空间的请求，能列出所有 Pod 的身份不需要命名空间权限
+func fetchPodsPerNamespace(ctx context.Context, k8sClient *client.Client) (*corev1.PodList, map[string]error, error) {
+	namespaces, err := k8sClient.GetNamespaces(ctx)
+	if apierrors.IsForbidden(err) {
+		pods, err := k8sClient.GetPods(ctx, "")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get pods: %w", err)
+		}
+		return pods, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+	names := make([]string, 0, len(namespaces.Items))
+	for _, ns := range namespaces.Items {
+		names = append(names, ns.Name)
+	}
+	pods, failed := fetchPodsInNamespaces(ctx, k8sClient, names)
+	return pods, failed, nil
+}
+
+// fetchPodsInNamespaces 并发获取指定命名空间中的 Pod，返回按命名空间和名称排序的 Pod 及失败的命名空间
+func fetchPodsInNamespaces(ctx context.Context, k8sClient *client.Client, namespaces []string) (*corev1.PodList, map[string]error) {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		pods   = &corev1.PodList{}
+		failed = make(map[string]error)
+		sem    = make(chan struct{}, namespaceFetchWorkers)
+	)
+	for _, ns := range namespaces {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			list, err := k8sClient.GetPods(ctx, name)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[name] = err
+				return
+			}
+			pods.Items = append(pods.Items, list.Items...)
+		}(ns)
+	}
+	wg.Wait()
+
+	// 并发返回的顺序不确定，按命名空间和名称排序保证输出稳定
+	sort.Slice(pods.Items, func(i, j int) bool {
+		if pods.Items[i].Namespace != pods.Items[j].Namespace {
+			return pods.Items[i].Namespace < pods.Items[j].Namespace
+		}
+		return pods.Items[i].Name < pods.Items[j].Name
+	})
+	return pods, failed
+}
+
+// readNamespaceFile 读取 --namespace-file 指定的命名空间列表
+// 每行一个命名空间，# 之后为注释，空行忽略，重复的命名空间只保留第一次出现
+func readNamespaceFile(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("--namespace-file: %w", err)
+	}
+
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		name := strings.TrimSpace(line)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		namespaces = append(namespaces, name)
+	}
+	if len(namespaces) == 0 {
+		return nil, fmt.Errorf("--namespace-file %s lists no namespaces", path)
+	}
+	return namespaces, nil
+}
+
+// printFailedNamespaces 向 w 列出获取失败的命名空间及原因，partial 表示已经输出了其余命名空间的结果
+func printFailedNamespaces(w io.Writer, failed map[string]error, partial bool) {
+	names := make([]string, 0, len(failed))
+	for name := range failed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if partial {
+		fmt.Fprintf(w, "⚠️  Results are partial: failed to get pods in %d namespaces\n", len(failed))
+	} else {
+		fmt.Fprintf(w, "⚠️  Failed to get pods in %d namespaces\n", len(failed))
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 40))
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s: %v\n", name, failed[name])
+	}
+	fmt.Fprintln(w)
+}
@@ -3,13 +3,19 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
 	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/config"
 	"github.com/FishPie-HQ/kubectl-podview/pkg/printer"
 )
 
@@ -19,8 +25,79 @@ var (
 	kubeconfig    string
 	showAll       bool
 	checkConfig   bool
+	checkTopology bool
+	cronJobs      bool
+	templates     bool
+	checkPDB      bool
+	topProblems   int
+	failedHusks   bool
+	checkVulns    bool
+	checkDNS      bool
+	checkDrift    bool
+	showAddons    bool
+	checkCMs      bool
+	checkSecrets  bool
+	checkCfgHash  bool
+	suggestECI    bool
+	checkCordon   bool
+	requireFull   bool
+	checkStartup  bool
+	checkSvcPorts bool
+	checkSecurity bool
+	naturalAge    bool
+	checkRefs     bool
+	pullSecretAnn string
+	checkPullType bool
+	checkPressure bool
+	configPath    string
+	explainDetect string
+	checkStorage  bool
+	checkScaler   bool
+	checkPreempt  bool
+	findDupes     bool
+	requestBudget int
+	checkBurst    bool
+	cpuBurstRatio float64
+	cleanupPlan   bool
+	checkStale    bool
+	checkAnnPol   bool
+	costAnn       string
+	verbosity     int
+	enableChecks  []string
+	disableChecks []string
+	rawSections   []string
+	rawAnnPrefix  []string
+	rawAnnMax     int
+	showStats     bool
+	checkWebhook  bool
+	maxAnnSize    int
+	namespaceFile string
+	checkJob      bool
+	checkNetPol   bool
+	verboseIssues bool
+	maxIssueLines int
+	checkLiveness bool
+	deployTimes   bool
+	checkGrace    bool
+	checkProbes   bool
+	ownerContact  bool
+	staleReady    time.Duration
+	checkQuota    bool
+	checkInit     bool
+	initTimeout   time.Duration
+	checkSched    bool
+	schedTimeout  time.Duration
+	showMetrics   bool
+	checkEffic    bool
+	effThreshold  float64
+	output        string
+	groupBy       string
+	checkPSP      bool
 )
 
+// newClient 创建 Kubernetes 客户端，demo 子命令会将其替换为基于内置数据的客户端
+var newClient = client.NewClient
+
 // rootCmd 是根命令
 var rootCmd = &cobra.Command{
 	Use:   "kubectl-podview",
@@ -47,18 +124,119 @@ Examples:
   kubectl podview -n test-gatekeeper --all
 
   # Check resource configuration issues
-  kubectl podview -n test-gatekeeper --check-config`,
+  kubectl podview -n test-gatekeeper --check-config
+
+  # List every config issue under its pod instead of the compact marker
+  kubectl podview -n test-gatekeeper --check-config --verbose-issues
+
+  # Check whether replicas are spread across availability zones
+  kubectl podview -n test-gatekeeper --check-topology
+
+  # Warn when liveness probes restart several workloads at once
+  kubectl podview -A --check-liveness-cascade
+
+  # Show the 10 worst pods across the cluster
+  kubectl podview -A --top-problems 10
+
+  # Find pods that are good candidates for ECI offload
+  kubectl podview -A --suggest-eci
+
+  # Check CronJob schedule health
+  kubectl podview -n test-gatekeeper --cronjobs
+
+  # Check workload pod templates instead of pods
+  kubectl podview -n test-gatekeeper --templates --check-config
+
+  # Export ECI pods with their instance IDs as CSV
+  kubectl podview eci -A -o csv
+
+  # Show the lifecycle timeline of a single pod
+  kubectl podview detail my-app-7c79c4bf97-abc12 -n test-gatekeeper
+
+  # Try podview on built-in sample data without a cluster
+  kubectl podview demo --check-config`,
 
 	RunE: runPodView,
 }
 
 func init() {
 	// 添加命令行参数
-	rootCmd.Flags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace to inspect")
+	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "default", "Kubernetes namespace to inspect")
 	rootCmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "Query all namespaces")
-	rootCmd.Flags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (default: ~/.kube/config)")
+	rootCmd.Flags().StringVar(&namespaceFile, "namespace-file", "", "Query the namespaces listed in this file (one per line, # comments) instead of -n/-A")
+	rootCmd.PersistentFlags().StringVar(&kubeconfig, "kubeconfig", "", "Path to kubeconfig file (default: ~/.kube/config)")
 	rootCmd.Flags().BoolVarP(&showAll, "all", "a", false, "Show all pods, including healthy ones")
+	rootCmd.Flags().BoolVar(&requireFull, "require-complete", false, "With -A or --namespace-file, fail instead of printing partial results when some namespaces cannot be listed")
+	rootCmd.Flags().IntVar(&requestBudget, "request-budget", client.DefaultRequestBudget, "Max extra API calls per run for per-pod details (events, PVCs, vulnerability reports); 0 means no limit")
+	rootCmd.Flags().IntVarP(&verbosity, "verbose", "v", 0, "Verbosity level; 1 prints the extra API calls used from --request-budget")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", "", "Print the pod table in sections: status (Error, Warning, Pending, then Healthy)")
+	rootCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table, wide to add the SCHED (scheduling latency) and EFF (usage vs requests) columns, or json/yaml for the full analysis result without progress output")
+	rootCmd.Flags().BoolVar(&showStats, "stats", false, "Print run statistics after the output: phase durations, API requests by type, bytes received and pods/second")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "", "Path to config file (default: "+config.DefaultPath()+")")
+	rootCmd.Flags().StringVar(&explainDetect, "explain-detection", "", "Print which virtual-node provider rule matches the given pod, then exit")
+	rootCmd.Flags().BoolVar(&naturalAge, "age-natural", false, "Show AGE/RUNNING in natural language (\"2 minutes\", \"3 hours\")")
 	rootCmd.Flags().BoolVar(&checkConfig, "check-config", false, "Check and highlight resource configuration issues")
+	rootCmd.Flags().BoolVar(&verboseIssues, "verbose-issues", false, "List each config issue on its own line under the pod instead of the compact marker (e.g. ⚙ req,lim,probe)")
+	rootCmd.Flags().IntVar(&maxIssueLines, "max-issue-lines", printer.DefaultMaxIssueLines, "Max config issues listed per pod in the table, the rest are folded into \"+N more\"; 0 means no limit")
+	rootCmd.Flags().DurationVar(&staleReady, "stale-condition-threshold", analyzer.DefaultStaleConditionThreshold, "With --check-config, flag pods whose Ready=True condition has not transitioned for longer than this")
+	rootCmd.Flags().BoolVar(&ownerContact, "owner-contact", false, "Show the owning team (pod label, falling back to the namespace label; \"team\" unless ownerLabel is set in the config file) in an OWNER column")
+	rootCmd.Flags().IntVar(&maxAnnSize, "max-annotation-size", analyzer.DefaultMaxAnnotationSize, "With --check-config, flag pods whose annotation values add up to more than this many bytes")
+	rootCmd.Flags().StringSliceVar(&enableChecks, "enable-check", nil, "Enable checks by name even without their --check-* flag ("+strings.Join(analyzer.CheckNames(), ", ")+")")
+	rootCmd.Flags().StringSliceVar(&disableChecks, "disable-check", nil, "Disable checks by name, including registered custom checks")
+	rootCmd.Flags().StringSliceVar(&rawSections, "include-raw", nil, "Embed raw pod data in JSON/YAML results: "+strings.Join(analyzer.RawSections, ", "))
+	rootCmd.Flags().StringSliceVar(&rawAnnPrefix, "raw-annotation-prefix", nil, "With --include-raw annotations, only include annotation keys with these prefixes (default: all)")
+	rootCmd.Flags().IntVar(&rawAnnMax, "raw-annotation-max-length", analyzer.DefaultRawAnnotationMaxLength, "With --include-raw annotations, truncate longer values")
+	rootCmd.Flags().BoolVar(&checkBurst, "check-cpu-burst", false, "Flag containers whose CPU limit/request ratio exceeds --cpu-burst-ratio")
+	rootCmd.Flags().Float64Var(&cpuBurstRatio, "cpu-burst-ratio", analyzer.DefaultCPUBurstRatio, "With --check-cpu-burst, the CPU limit/request ratio above which a container is flagged")
+	rootCmd.Flags().BoolVar(&checkPDB, "check-pdb", false, "Flag Deployment/StatefulSet pods not covered by any PodDisruptionBudget")
+	rootCmd.Flags().BoolVar(&checkCMs, "check-configmaps", false, "Fetch ConfigMaps referenced by pods for ConfigMap-related checks")
+	rootCmd.Flags().BoolVar(&checkSecrets, "check-secrets", false, "Fetch Secrets referenced by pods for Secret-related checks")
+	rootCmd.Flags().BoolVar(&checkCfgHash, "check-config-hash", false, "Flag pods whose checksum/config annotation no longer matches their ConfigMaps")
+	rootCmd.Flags().BoolVar(&checkDrift, "check-config-drift", false, "Flag containers whose running image or resources differ from the owning Deployment spec, and pods edited away from their ReplicaSet template")
+	rootCmd.Flags().BoolVar(&checkStale, "check-stale-images", false, "Flag containers whose running image digest differs from the tag's current digest in the registry (anonymous registry access only)")
+	rootCmd.Flags().BoolVar(&checkRefs, "check-refs", false, "Flag imagePullSecrets whose registry tokens are expired or expire within an hour")
+	rootCmd.Flags().StringVar(&pullSecretAnn, "pull-secret-expiry-annotation", analyzer.DefaultPullSecretExpiryAnnotation, "Secret annotation holding an RFC3339 expiry time, checked before decoding the token")
+	rootCmd.Flags().BoolVar(&checkPullType, "check-image-pull-secret-validity", false, "Flag imagePullSecrets that are not of type kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg")
+	rootCmd.Flags().BoolVar(&checkStorage, "check-storage", false, "Flag pods pinned to a node by hostPath volumes or local PersistentVolumes, or waiting on unbound PVCs")
+	rootCmd.Flags().BoolVar(&checkAnnPol, "check-annotation-policy", false, "Flag pods missing the cost allocation annotation set by --require-cost-annotation")
+	rootCmd.Flags().StringVar(&costAnn, "require-cost-annotation", analyzer.DefaultCostAnnotation, "Cost allocation annotation key required by --check-annotation-policy")
+	rootCmd.Flags().BoolVar(&checkScaler, "check-autoscaler", false, "Flag pods with emptyDir/hostPath volumes and no safe-to-evict annotation, and Job pods marked safe-to-evict: \"false\"")
+	rootCmd.Flags().BoolVar(&checkSecurity, "check-security", false, "Report security findings such as Secrets consumed via environment variables")
+	rootCmd.Flags().BoolVar(&checkStartup, "check-startup-order", false, "Flag pods whose dependent Services (from *_HOST env vars) have no ready endpoints")
+	rootCmd.Flags().BoolVar(&checkSvcPorts, "check-service-ports", false, "Flag Services whose targetPort matches no containerPort (number or name) of the pods they select")
+	rootCmd.Flags().BoolVar(&checkPressure, "check-node-pressure", false, "Warn about BestEffort/Burstable pods on nodes with Memory/Disk/PID pressure")
+	rootCmd.Flags().BoolVar(&checkPreempt, "check-preemption", false, "List pending pods preempting a nominated node and the lower-priority pods there that may be evicted")
+	rootCmd.Flags().BoolVar(&checkCordon, "check-node-unschedulable", false, "Flag pods running on nodes that have been cordoned, and pods scheduled there after the cordon")
+	rootCmd.Flags().BoolVar(&checkDNS, "check-dns", false, "Check pod DNS policy and ndots settings (heuristic)")
+	rootCmd.Flags().BoolVar(&checkNetPol, "check-network-policy", false, "Flag pods in namespaces without a default-deny NetworkPolicy (empty podSelector, no ingress/egress rules)")
+	rootCmd.Flags().BoolVar(&checkWebhook, "check-webhook", false, "Flag pods whose deletion is intercepted by a ValidatingWebhook that validates DELETE (may block eviction and rollouts)")
+	rootCmd.Flags().BoolVar(&checkGrace, "check-grace", false, "Flag containers started via \"sh -c\"/\"bash -c\" whose shell runs as PID 1 and may not forward SIGTERM")
+	rootCmd.Flags().BoolVar(&checkProbes, "check-probes", false, "Flag multi-container pods where several containers' liveness/readiness probes target the same port")
+	rootCmd.Flags().BoolVar(&checkInit, "check-pod-stuck-in-init", false, "Flag Pending pods whose init containers have not finished within --init-timeout")
+	rootCmd.Flags().DurationVar(&initTimeout, "init-timeout", analyzer.DefaultInitTimeout, "With --check-pod-stuck-in-init, how long a pod may stay in its init containers")
+	rootCmd.Flags().BoolVar(&checkSched, "check-pod-scheduling-timeout", false, "Flag pods that took longer than --scheduling-timeout from creation to being scheduled, or are still waiting that long")
+	rootCmd.Flags().DurationVar(&schedTimeout, "scheduling-timeout", analyzer.DefaultSchedulingTimeout, "With --check-pod-scheduling-timeout, how long a pod may wait in the scheduling queue")
+	rootCmd.Flags().BoolVar(&showMetrics, "metrics", false, "Fetch current pod CPU/memory usage from metrics-server (metrics.k8s.io) and show usage vs requests in the EFF column of -o wide")
+	rootCmd.Flags().BoolVar(&checkEffic, "check-pod-resource-efficiency", false, "Flag running pods whose CPU or memory usage is below --efficiency-threshold of their requests (implies --metrics)")
+	rootCmd.Flags().Float64Var(&effThreshold, "efficiency-threshold", analyzer.DefaultEfficiencyThreshold, "With --check-pod-resource-efficiency, the usage/request ratio below which a pod is over-provisioned")
+	rootCmd.Flags().BoolVar(&checkPSP, "check-psp", false, "Flag pods whose spec no PodSecurityPolicy admits (clusters before Kubernetes 1.25 only)")
+	rootCmd.Flags().BoolVar(&checkQuota, "check-quota", false, "Warn when an in-progress Deployment rollout will run out of namespace ResourceQuota before it finishes")
+	rootCmd.Flags().BoolVar(&checkLiveness, "check-liveness-cascade", false, fmt.Sprintf("Warn when liveness probes restart pods of %d+ workloads within %s (probes that depend on downstream services)", analyzer.LivenessCascadeMinWorkloads, analyzer.LivenessCascadeWindow))
+	rootCmd.Flags().BoolVar(&deployTimes, "deploy-times", false, "Show when each Deployment was last deployed (oldest pod of its newest pod-template-hash) and whether its issues began after that deploy")
+	rootCmd.Flags().BoolVar(&checkJob, "check-job", false, "Flag Job pods whose Job has no podFailurePolicy and retries every failure the same way (Kubernetes 1.26+)")
+	rootCmd.Flags().BoolVar(&checkVulns, "check-vulnerabilities", false, "Flag containers with HIGH/CRITICAL CVEs from Trivy operator VulnerabilityReports")
+	rootCmd.Flags().BoolVar(&cleanupPlan, "cleanup-plan", false, "Print (never execute) commands to delete retained Succeeded/Failed pods and set ttlSecondsAfterFinished on Jobs")
+	rootCmd.Flags().BoolVar(&showAddons, "addons", false, "Show cluster addon health (CoreDNS, kube-proxy, CNI, metrics-server); always on with -A")
+	rootCmd.Flags().IntVar(&topProblems, "top-problems", 0, "Only print the N worst pods ranked by problem score")
+	rootCmd.Flags().BoolVar(&failedHusks, "failed-husks", false, "Only show failed pods rejected at kubelet admission (OutOfpods, UnexpectedAdmissionError, ...)")
+	rootCmd.Flags().BoolVar(&findDupes, "find-duplicates", false, "With -A, list workloads with the same name or images in several namespaces (possible stale copies)")
+	rootCmd.Flags().BoolVar(&suggestECI, "suggest-eci", false, "Rank regular-node pods that are good candidates for ECI offload")
+	rootCmd.Flags().BoolVar(&cronJobs, "cronjobs", false, "Report CronJob schedule health instead of pods")
+	rootCmd.Flags().BoolVar(&templates, "templates", false, "Run the config, security and policy checks against Deployment/StatefulSet/DaemonSet/CronJob pod templates and report once per workload instead of per pod")
+	rootCmd.Flags().BoolVar(&checkTopology, "check-topology", false, "Check whether Deployment replicas are spread across availability zones")
+
+	// demo 子命令走与根命令相同的流程，所有参数都应对演示数据生效
+	demoCmd.Flags().AddFlagSet(rootCmd.Flags())
 }
 
 // Execute 执行根命令
@@ -67,59 +245,497 @@ func Execute() error {
 }
 
 // runPodView 是主要的执行逻辑
-func runPodView(cmd *cobra.Command, args []string) error {
-	// 创建带超时的 context，全命名空间查询需要更长时间
+func runPodView(cmd *cobra.Command, args []string) (err error) {
+	// --namespace-file 与 -n/-A 互斥，文件缺失或为空都是用法错误
+	var fileNamespaces []string
+	if namespaceFile != "" {
+		switch {
+		case allNamespaces:
+			return fmt.Errorf("--namespace-file cannot be combined with -A")
+		case cmd.Flags().Changed("namespace"):
+			return fmt.Errorf("--namespace-file cannot be combined with -n")
+		case cronJobs:
+			return fmt.Errorf("--namespace-file does not support --cronjobs")
+		}
+		if fileNamespaces, err = readNamespaceFile(namespaceFile); err != nil {
+			return err
+		}
+	}
+	multiNamespace := allNamespaces || len(fileNamespaces) > 0
+
+	// 创建带超时的 context，多命名空间查询需要更长时间
 	timeout := 30 * time.Second
-	if allNamespaces {
+	if multiNamespace {
 		timeout = 60 * time.Second
 	}
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
+	if findDupes && !allNamespaces {
+		return fmt.Errorf("--find-duplicates requires -A")
+	}
+	if cpuBurstRatio <= 0 {
+		return fmt.Errorf("--cpu-burst-ratio must be greater than 0, got %v", cpuBurstRatio)
+	}
+	if maxAnnSize <= 0 {
+		return fmt.Errorf("--max-annotation-size must be positive")
+	}
+	if effThreshold <= 0 || effThreshold > 1 {
+		return fmt.Errorf("--efficiency-threshold must be greater than 0 and at most 1, got %v", effThreshold)
+	}
+	if output != "table" && output != "wide" && output != "json" && output != "yaml" {
+		return fmt.Errorf("--output must be table, wide, json or yaml, got %q", output)
+	}
+	if machineOutput() && (cronJobs || templates || explainDetect != "") {
+		return fmt.Errorf("--output %s cannot be combined with --cronjobs, --templates or --explain-detection", output)
+	}
+	if templates && cronJobs {
+		return fmt.Errorf("--templates cannot be combined with --cronjobs")
+	}
+	if groupBy != "" && groupBy != "status" {
+		return fmt.Errorf("--group-by must be status, got %q", groupBy)
+	}
+	if err := analyzer.ValidateRawSections(rawSections); err != nil {
+		return fmt.Errorf("--include-raw: %w", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	providers := cfg.ProviderRules()
+	for _, names := range [][]string{enableChecks, disableChecks} {
+		if err := analyzer.ValidateCheckNames(names); err != nil {
+			return fmt.Errorf("%w (available: %s)", err, strings.Join(analyzer.CheckNames(), ", "))
+		}
+	}
+
 	// 1. 创建 Kubernetes 客户端
-	fmt.Printf("🔗 Connecting to cluster...\n")
-	k8sClient, err := client.NewClient(kubeconfig)
+	timer := newPhaseTimer()
+	fmt.Fprintf(progressOut(), "🔗 Connecting to cluster...\n")
+	k8sClient, err := newClient(kubeconfig)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	k8sClient.SetBudget(client.NewBudget(requestBudget))
+	timer.mark(analyzer.PhaseConnect)
+
+	if templates {
+		return runTemplateView(ctx, k8sClient, fileNamespaces, analysisOptions(cfg, providers))
+	}
 
-	// 2. 确定查询范围
-	queryNamespace := namespace
+	// 2. 确定查询范围：PDB 等按命名空间列出的数据和能力探测在每个查询的命名空间中分别进行
+	queryNamespaces := []string{namespace}
 	if allNamespaces {
-		queryNamespace = "" // 空字符串表示所有命名空间
-		fmt.Printf("📦 Fetching pods across all namespaces...\n")
+		queryNamespaces = []string{""} // 空字符串表示所有命名空间
+		fmt.Fprintf(progressOut(), "📦 Fetching pods across all namespaces...\n")
+	} else if len(fileNamespaces) > 0 {
+		// 只按文件中的命名空间请求，权限只限于这些命名空间的用户也能获取
+		queryNamespaces = fileNamespaces
+		fmt.Fprintf(progressOut(), "📦 Fetching pods in %d namespaces from %s...\n", len(fileNamespaces), namespaceFile)
 	} else {
-		fmt.Printf("📦 Fetching pods in namespace '%s'...\n", namespace)
+		fmt.Fprintf(progressOut(), "📦 Fetching pods in namespace '%s'...\n", namespace)
 	}
 
-	// 3. 获取 Pod 列表
-	pods, err := k8sClient.GetPods(ctx, queryNamespace)
-	if err != nil {
-		return fmt.Errorf("failed to get pods: %w", err)
+	// 3. 获取 Pod 列表，多个命名空间时按命名空间并发获取
+	var (
+		pods   *corev1.PodList
+		failed map[string]error
+	)
+	if multiNamespace {
+		if allNamespaces {
+			pods, failed, err = fetchPodsPerNamespace(ctx, k8sClient)
+			if err != nil {
+				return err
+			}
+		} else {
+			pods, failed = fetchPodsInNamespaces(ctx, k8sClient, fileNamespaces)
+		}
+		if len(failed) > 0 {
+			if requireFull {
+				// 没有输出任何结果，以普通错误退出，不使用表示部分结果的退出码
+				printFailedNamespaces(progressOut(), failed, false)
+				cmd.SilenceUsage = true
+				return fmt.Errorf("--require-complete: failed to get pods in %d namespaces", len(failed))
+			}
+			// 先输出已获取的部分，最后列出失败的命名空间并以专用退出码结束
+			defer func() {
+				if err == nil {
+					printFailedNamespaces(progressOut(), failed, true)
+					cmd.SilenceUsage = true
+					cmd.SilenceErrors = true
+					err = &PartialResultError{Failed: failed}
+				}
+			}()
+		}
+	} else {
+		pods, err = k8sClient.GetPods(ctx, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get pods: %w", err)
+		}
+	}
+
+	timer.mark(analyzer.PhaseList)
+
+	if cronJobs {
+		return runCronJobView(ctx, k8sClient, queryNamespaces, pods)
+	}
+
+	if explainDetect != "" {
+		return explainDetection(ctx, k8sClient, pods, explainDetect, providers)
+	}
+
+	if suggestECI {
+		fmt.Fprintf(progressOut(), "🔍 Looking for ECI candidates among %d pods...\n\n", len(pods.Items))
+		nodes := collectNodes(ctx, k8sClient, pods)
+		candidates := analyzer.SuggestECICandidates(pods, nodes, providers)
+		if machineOutput() {
+			return newMachinePrinter().PrintECICandidates(candidates)
+		}
+		printer.NewPrinter(os.Stdout).PrintECICandidates(candidates)
+		return nil
 	}
 
 	if len(pods.Items) == 0 {
 		if allNamespaces {
-			fmt.Printf("⚠️  No pods found in the cluster\n")
+			fmt.Fprintf(progressOut(), "⚠️  No pods found in the cluster\n")
+		} else if len(fileNamespaces) > 0 {
+			fmt.Fprintf(progressOut(), "⚠️  No pods found in the namespaces listed in %s\n", namespaceFile)
 		} else {
-			fmt.Printf("⚠️  No pods found in namespace '%s'\n", namespace)
+			fmt.Fprintf(progressOut(), "⚠️  No pods found in namespace '%s'\n", namespace)
+		}
+		if machineOutput() {
+			return printAnalysis(&analyzer.AnalysisResult{})
 		}
 		return nil
 	}
 
-	// 4. 分析 Pod 状态
-	fmt.Printf("🔍 Analyzing %d pods...\n\n", len(pods.Items))
-	results := analyzer.AnalyzePods(pods, checkConfig)
+	// 4. 获取分析所需的额外集群数据
+	opts := analysisOptions(cfg, providers)
+	skipped := disableUnavailableChecks(ctx, k8sClient, queryNamespaces, &opts)
+	printSkippedChecks(skipped)
+	opts.Cluster = collectClusterData(ctx, k8sClient, queryNamespaces, pods, opts)
+	timer.mark(analyzer.PhaseEnrich)
+	if verbosity >= 1 {
+		printBudgetUsage(k8sClient.Budget(), len(opts.Cluster.DetailsOmitted))
+	}
+
+	// 5. 分析 Pod 状态
+	fmt.Fprintf(progressOut(), "🔍 Analyzing %d pods...\n\n", len(pods.Items))
+	results := analyzer.AnalyzePods(pods, opts)
+	results.SkippedChecks = skipped
+	timer.mark(analyzer.PhaseAnalyze)
 
-	// 5. 打印结果
+	// 6. 打印结果；JSON/YAML 输出没有打印阶段，统计只包含到分析为止的耗时
+	if machineOutput() {
+		if showStats {
+			results.Stats = runStats(timer, k8sClient.Stats(), len(pods.Items))
+		}
+		return printAnalysis(results)
+	}
 	p := printer.NewPrinter(os.Stdout)
-	p.PrintPodTable(results, showAll, allNamespaces)
+	p.CostAllocationDocs = cfg.CostAllocationDocs
+	p.CleanupPlan = cleanupPlan
+	if showStats {
+		// 统计放在所有输出之后，打印阶段结束时才能得到完整的耗时
+		defer func() {
+			if err != nil {
+				return
+			}
+			timer.mark(analyzer.PhasePrint)
+			results.Stats = runStats(timer, k8sClient.Stats(), len(pods.Items))
+			p.PrintRunStats(results.Stats)
+		}()
+	}
+	if topProblems > 0 {
+		p.PrintTopProblems(analyzer.TopProblems(results, topProblems))
+		return nil
+	}
+
+	// 核心组件健康状况放在最前面，排查应用问题前先确认集群本身正常
+	if allNamespaces || showAddons {
+		addonRules := cfg.AddonRules()
+		addonPods := pods
+		if !allNamespaces {
+			addonPods, err = fetchAddonPods(ctx, k8sClient, addonRules, queryNamespaces, pods)
+			if err != nil {
+				return fmt.Errorf("failed to get addon pods: %w", err)
+			}
+		}
+		p.PrintAddons(analyzer.AnalyzeAddons(addonPods, addonRules))
+	}
+
+	if checkPressure {
+		p.PrintNodePressure(results)
+	}
+	if checkPreempt {
+		p.PrintPreemptions(results)
+	}
+	if checkLiveness {
+		p.PrintLivenessCascade(results)
+	}
+	if checkQuota {
+		p.PrintQuotaRollouts(results)
+	}
+	if deployTimes {
+		p.PrintDeploys(results)
+	}
+	if findDupes {
+		p.PrintDuplicates(results)
+	}
+	if cleanupPlan {
+		p.PrintCleanupPlan(results)
+	}
+
+	p.PrintTerminatingNamespaces(results)
+	p.PrintPodTable(results, printer.TableOptions{
+		ShowAll:       showAll,
+		ShowNamespace: multiNamespace,
+		ShowPDB:       checkPDB,
+		ShowOwner:     ownerContact,
+		Wide:          output == "wide",
+		GroupByStatus: groupBy == "status",
+
+		FailedHusksOnly: failedHusks,
+		VerboseIssues:   verboseIssues,
+		MaxIssueLines:   maxIssueLines,
+	})
 	p.PrintSummary(results)
+	if len(fileNamespaces) > 0 {
+		p.PrintNamespaceSummary(analyzer.SummarizeNamespaces(results, fileNamespaces), failed)
+	}
 
-	// 6. 如果有问题，打印建议
-	if results.HasIssues() {
+	// 7. 如果有问题，打印建议；选择了不存在的节点标签的 Pending Pod、--cleanup-plan 下或大量保留的已结束 Pod 也有明确的建议
+	retained := len(results.RetainedPods) > 0 && cleanupPlan || results.HasExcessRetainedPods()
+	if results.HasIssues() || retained || results.HasMissingNodeLabels() {
 		p.PrintRecommendations(results)
 	}
 
 	return nil
 }
+
+// analysisOptions 根据命令行参数和配置文件组装分析选项
+func analysisOptions(cfg *config.Config, providers []analyzer.ProviderRule) analyzer.Options {
+	opts := analyzer.Options{
+		CheckConfig:   checkConfig,
+		CheckTopology: checkTopology,
+		CheckPDB:      checkPDB,
+		CheckDNS:      checkDNS,
+		CheckDrift:    checkDrift,
+
+		CheckNetworkPolicy: checkNetPol,
+
+		CheckConfigMaps: checkCMs,
+		CheckSecrets:    checkSecrets,
+		CheckConfigHash: checkCfgHash,
+
+		CheckCPUBurst:     checkBurst,
+		MaxCPUBurstRatio:  cpuBurstRatio,
+		MaxAnnotationSize: maxAnnSize,
+
+		StaleConditionThreshold: staleReady,
+
+		CheckRefs:              checkRefs,
+		CheckStaleImages:       checkStale,
+		CheckStorage:           checkStorage,
+		CheckAutoscaler:        checkScaler,
+		CheckAnnotationPolicy:  checkAnnPol,
+		CostAnnotation:         costAnn,
+		CheckSecurity:          checkSecurity,
+		CheckStartupOrder:      checkStartup,
+		CheckServicePorts:      checkSvcPorts,
+		CheckNodeUnschedulable: checkCordon,
+		CheckNodePressure:      checkPressure,
+		CheckPreemption:        checkPreempt,
+		CheckJobTTL:            cleanupPlan,
+		CheckJob:               checkJob,
+
+		CheckVulnerabilities: checkVulns,
+		CheckWebhooks:        checkWebhook,
+		CheckLivenessCascade: checkLiveness,
+		DeployTimes:          deployTimes,
+		CheckQuota:           checkQuota,
+		CheckPSP:             checkPSP,
+		CheckInitTimeout:     checkInit,
+		InitTimeout:          initTimeout,
+		CheckGrace:           checkGrace,
+		CheckProbes:          checkProbes,
+
+		CheckSchedulingTimeout: checkSched,
+		SchedulingTimeout:      schedTimeout,
+
+		Metrics:                 showMetrics || checkEffic,
+		CheckResourceEfficiency: checkEffic,
+		EfficiencyThreshold:     effThreshold,
+
+		PullSecretExpiryAnnotation: pullSecretAnn,
+		CheckPullSecretType:        checkPullType,
+
+		Providers:  providers,
+		NaturalAge: naturalAge,
+
+		FindDuplicates:      findDupes,
+		DuplicateExclusions: cfg.DuplicateExclusions,
+
+		Raw: analyzer.RawOptions{
+			Sections:            rawSections,
+			AnnotationPrefixes:  rawAnnPrefix,
+			MaxAnnotationLength: rawAnnMax,
+		},
+
+		EnabledChecks:  append(cfg.EnabledChecks, enableChecks...),
+		DisabledChecks: append(cfg.DisabledChecks, disableChecks...),
+
+		RequiredLabels: cfg.RequiredLabels,
+	}
+	if ownerContact {
+		opts.OwnerLabel = cfg.OwnerContactLabel()
+	}
+	// 按名称启用的检查打开对应的开关，之后才能按开关获取集群数据和探测 API
+	opts.ApplyCheckNames()
+	opts.Metrics = opts.Metrics || opts.CheckResourceEfficiency
+	return opts
+}
+
+// machineOutput 判断是否输出机器可读的格式（-o json 或 -o yaml）
+func machineOutput() bool {
+	return output == "json" || output == "yaml"
+}
+
+// machinePrinter 是 -o json 和 -o yaml 共用的输出接口
+type machinePrinter interface {
+	PrintAnalysis(results *analyzer.AnalysisResult) error
+	PrintTopProblems(pods []analyzer.ScoredPod) error
+	PrintECICandidates(candidates []analyzer.ECICandidate) error
+}
+
+// newMachinePrinter 返回 -o 指定的机器可读格式的 Printer
+func newMachinePrinter() machinePrinter {
+	if output == "yaml" {
+		return printer.NewYAMLPrinter(os.Stdout)
+	}
+	return printer.NewJSONPrinter(os.Stdout)
+}
+
+// printAnalysis 以 -o 指定的机器可读格式输出分析结果，--top-problems 时只输出排名
+func printAnalysis(results *analyzer.AnalysisResult) error {
+	if topProblems > 0 {
+		return newMachinePrinter().PrintTopProblems(analyzer.TopProblems(results, topProblems))
+	}
+	return newMachinePrinter().PrintAnalysis(results)
+}
+
+// progressOut 返回进度和警告信息的输出位置，-o json/yaml 时写到标准错误，标准输出只包含导出的数据
+func progressOut() io.Writer {
+	if machineOutput() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// printBudgetUsage 打印额外请求配额的使用情况
+func printBudgetUsage(budget *client.Budget, omitted int) {
+	if budget.Limit() == 0 {
+		fmt.Fprintf(progressOut(), "📡 Extra API calls: %d (no limit)\n", budget.Used())
+		return
+	}
+	fmt.Fprintf(progressOut(), "📡 Extra API calls: %d/%d used", budget.Used(), budget.Limit())
+	if omitted > 0 {
+		fmt.Fprintf(progressOut(), ", %d denied, details omitted for %d pods", budget.Denied(), omitted)
+	}
+	fmt.Fprintln(progressOut())
+}
+
+// fetchAddonPods 获取核心组件规则涉及的命名空间中的 Pod，queried 中的命名空间的 Pod 已经获取过，直接复用
+// 有规则不限定命名空间时获取所有命名空间的 Pod
+func fetchAddonPods(ctx context.Context, k8sClient *client.Client, rules []analyzer.AddonRule, queried []string, pods *corev1.PodList) (*corev1.PodList, error) {
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, rule := range rules {
+		if rule.Namespace == "" {
+			return k8sClient.GetPods(ctx, "")
+		}
+		if !seen[rule.Namespace] {
+			seen[rule.Namespace] = true
+			namespaces = append(namespaces, rule.Namespace)
+		}
+	}
+
+	addonPods := &corev1.PodList{}
+	for _, ns := range namespaces {
+		if slices.Contains(queried, ns) {
+			for _, pod := range pods.Items {
+				if pod.Namespace == ns {
+					addonPods.Items = append(addonPods.Items, pod)
+				}
+			}
+			continue
+		}
+		list, err := k8sClient.GetPods(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		addonPods.Items = append(addonPods.Items, list.Items...)
+	}
+	return addonPods, nil
+}
+
+// runCronJobView 分析并打印查询的命名空间中 CronJob 的调度健康状况
+func runCronJobView(ctx context.Context, k8sClient *client.Client, namespaces []string, pods *corev1.PodList) error {
+	cronJobItems, err := listInNamespaces(namespaces, func(ns string) ([]batchv1.CronJob, error) {
+		list, err := k8sClient.GetCronJobs(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get cronjobs: %w", err)
+	}
+	cronJobList := &batchv1.CronJobList{Items: cronJobItems}
+
+	jobItems, err := listInNamespaces(namespaces, func(ns string) ([]batchv1.Job, error) {
+		list, err := k8sClient.GetJobs(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+	jobs := &batchv1.JobList{Items: jobItems}
+
+	fmt.Printf("🔍 Analyzing %d cronjobs...\n\n", len(cronJobList.Items))
+	results := analyzer.AnalyzeCronJobs(cronJobList, jobs, pods)
+
+	p := printer.NewPrinter(os.Stdout)
+	p.PrintCronJobTable(results, allNamespaces)
+	return nil
+}
+
+// explainDetection 打印虚拟节点识别规则对指定 Pod 的匹配过程
+func explainDetection(ctx context.Context, k8sClient *client.Client, pods *corev1.PodList, podName string, providers []analyzer.ProviderRule) error {
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Name != podName {
+			continue
+		}
+
+		var node *corev1.Node
+		if pod.Spec.NodeName != "" && analyzer.ProviderRulesNeedNodes(providers) {
+			if n, err := k8sClient.GetNode(ctx, pod.Spec.NodeName); err != nil {
+				fmt.Printf("⚠️  Failed to get node '%s': %v\n", pod.Spec.NodeName, err)
+			} else {
+				node = n
+			}
+		}
+
+		fmt.Printf("🔎 Provider detection for %s/%s (node: %s)\n", pod.Namespace, pod.Name, pod.Spec.NodeName)
+		for _, line := range analyzer.ExplainProviderDetection(pod, node, providers) {
+			fmt.Printf("  %s\n", line)
+		}
+		return nil
+	}
+	return fmt.Errorf("pod %q not found", podName)
+}
@@ -0,0 +1,1410 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// captureStdout 运行 fn 并返回其写到标准输出的内容
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+
+	done := make(chan string)
+	go func() {
+		var buf bytes.Buffer
+		_, _ = io.Copy(&buf, r)
+		done <- buf.String()
+	}()
+
+	runErr := fn()
+	w.Close()
+	os.Stdout = stdout
+	return <-done, runErr
+}
+
+// executeRoot 以给定参数执行根命令并返回输出
+// 参数（包括子命令的参数）都是包级变量，每次执行前恢复默认值，执行后恢复客户端工厂和时钟
+func executeRoot(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+
+	origClient, origNow := newClient, analyzer.Now
+	t.Cleanup(func() {
+		newClient, analyzer.Now = origClient, origNow
+		rootCmd.SetArgs(nil)
+	})
+
+	flagSets := []*pflag.FlagSet{rootCmd.PersistentFlags(), rootCmd.Flags()}
+	for _, sub := range rootCmd.Commands() {
+		flagSets = append(flagSets, sub.Flags())
+	}
+	for _, flags := range flagSets {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if slice, ok := f.Value.(pflag.SliceValue); ok {
+				_ = slice.Replace(nil)
+			} else {
+				_ = f.Value.Set(f.DefValue)
+			}
+			f.Changed = false
+		})
+	}
+
+	rootCmd.SetArgs(append(args, "--config", os.DevNull))
+	return captureStdout(t, rootCmd.Execute)
+}
+
+// useFakeCluster 让 runPodView 使用预置对象的 fake clientset
+func useFakeCluster(t *testing.T, objects ...runtime.Object) {
+	t.Helper()
+
+	origClient := newClient
+	t.Cleanup(func() { newClient = origClient })
+	newClient = func(string) (*client.Client, error) {
+		return newFakeClient(objects...), nil
+	}
+}
+
+func testPod(namespace, name string, ready bool, waitingReason string) *corev1.Pod {
+	status := corev1.ContainerStatus{Name: "app", Ready: ready}
+	if waitingReason != "" {
+		status.RestartCount = 7
+		status.State.Waiting = &corev1.ContainerStateWaiting{Reason: waitingReason}
+	} else {
+		status.State.Running = &corev1.ContainerStateRunning{}
+	}
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         namespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour)),
+		},
+		Spec: corev1.PodSpec{
+			NodeName:   "node-1",
+			Containers: []corev1.Container{{Name: "app", Image: "example.com/app:1.0"}},
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{status},
+		},
+	}
+}
+
+func TestRunPodView(t *testing.T) {
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		testPod("default", "web-healthy", true, ""),
+		testPod("default", "api-crashing", false, "CrashLoopBackOff"),
+		testPod("payments", "ledger-pulling", false, "ImagePullBackOff"),
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "unhealthy pods only by default",
+			want:    []string{"Fetching pods in namespace 'default'", "Analyzing 2 pods", "api-crashing", "⚠ Warning", "CrashLoopBackOff", "Total Pods:     2"},
+			notWant: []string{"web-healthy", "ledger-pulling", "NAMESPACE"},
+		},
+		{
+			name:    "all shows healthy pods",
+			args:    []string{"--all"},
+			want:    []string{"web-healthy", "✓ Healthy", "api-crashing"},
+			notWant: []string{string(analyzer.IssueMissingRequests)},
+		},
+		{
+			name:    "group by status",
+			args:    []string{"--all", "--group-by", "status"},
+			want:    []string{"⚠ Warning (1)", "✓ Healthy (1)"},
+			notWant: []string{"Error (", "Pending ("},
+		},
+		{
+			name: "check-config marks issues inline by default",
+			args: []string{"--all", "--check-config"},
+			want: []string{
+				"⚙ req,lim,probe,termpolicy",
+				"Config Issues:  9",
+				"Set resource requests to enable proper scheduling",
+			},
+			notWant: []string{"└─ " + string(analyzer.IssueMissingRequests)},
+		},
+		{
+			name: "check-config flags missing resources and probes",
+			args: []string{"--all", "--check-config", "--verbose-issues"},
+			want: []string{
+				string(analyzer.IssueMissingRequests),
+				string(analyzer.IssueMissingLimits),
+				string(analyzer.IssueNoProbe),
+				string(analyzer.IssueNoTerminationMessagePolicy),
+				"Config Issues:  9",
+				"Set resource requests to enable proper scheduling",
+			},
+		},
+		{
+			name:    "disable-check drops a config check",
+			args:    []string{"--all", "--check-config", "--verbose-issues", "--disable-check", "probes"},
+			want:    []string{string(analyzer.IssueMissingRequests)},
+			notWant: []string{string(analyzer.IssueNoProbe)},
+		},
+		{
+			name:    "enable-check without check-config",
+			args:    []string{"--all", "--verbose-issues", "--enable-check", "resources"},
+			want:    []string{string(analyzer.IssueMissingRequests)},
+			notWant: []string{string(analyzer.IssueNoProbe)},
+		},
+		{
+			name:    "namespace flag",
+			args:    []string{"-n", "payments"},
+			want:    []string{"ledger-pulling", "ImagePullBackOff"},
+			notWant: []string{"api-crashing"},
+		},
+		{
+			name:    "all namespaces",
+			args:    []string{"-A"},
+			want:    []string{"NAMESPACE", "default", "payments", "api-crashing", "ledger-pulling", "Total Pods:     3"},
+			notWant: []string{"web-healthy"},
+		},
+		{
+			name: "empty namespace",
+			args: []string{"-n", "staging"},
+			want: []string{"No pods found in namespace 'staging'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useFakeCluster(t, objects...)
+			out, err := executeRoot(t, tt.args...)
+			if err != nil {
+				t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+				}
+			}
+			for _, unwanted := range tt.notWant {
+				if strings.Contains(out, unwanted) {
+					t.Errorf("output unexpectedly contains %q\noutput:\n%s", unwanted, out)
+				}
+			}
+		})
+	}
+}
+
+func TestTerminatingNamespace(t *testing.T) {
+	deleted := metav1.NewTime(time.Now().Add(-2 * time.Hour))
+	objects := []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", DeletionTimestamp: &deleted},
+			Spec:       corev1.NamespaceSpec{Finalizers: []corev1.FinalizerName{corev1.FinalizerKubernetes}},
+			Status: corev1.NamespaceStatus{
+				Phase: corev1.NamespaceTerminating,
+				Conditions: []corev1.NamespaceCondition{{
+					Type:    corev1.NamespaceContentRemaining,
+					Status:  corev1.ConditionTrue,
+					Message: "Some resources are remaining: widgets.example.com has 1 resource instances",
+				}},
+			},
+		},
+		testPod("default", "web", true, ""),
+		testPod("legacy", "old-api", true, ""),
+	}
+
+	for _, args := range [][]string{{"-n", "legacy"}, {"-A"}} {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			useFakeCluster(t, objects...)
+			out, err := executeRoot(t, args...)
+			if err != nil {
+				t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+			}
+			for _, want := range []string{
+				"Namespace 'legacy' is Terminating for 2h",
+				"old-api",
+				analyzer.NamespaceTerminatingReason,
+				"finalizers: kubernetes; NamespaceContentRemaining: Some resources are remaining: widgets.example.com",
+			} {
+				if !strings.Contains(out, want) {
+					t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+				}
+			}
+			if strings.Contains(out, "Namespace 'default'") {
+				t.Errorf("healthy namespace bannered\noutput:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestCheckCPUBurst(t *testing.T) {
+	pod := testPod("default", "web", true, "")
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+	}
+
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: []string{"--check-cpu-burst"}},
+		{args: []string{"--check-cpu-burst", "--cpu-burst-ratio", "5"}, want: true},
+		{args: []string{"--cpu-burst-ratio", "5"}},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			useFakeCluster(t, pod)
+			out, err := executeRoot(t, append(tt.args, "--all", "--verbose-issues")...)
+			if err != nil {
+				t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+			}
+			if got := strings.Contains(out, "10x: request 100m, limit 1"); got != tt.want {
+				t.Errorf("burst ratio flagged = %v, want %v\noutput:\n%s", got, tt.want, out)
+			}
+		})
+	}
+}
+
+func TestCheckWebhook(t *testing.T) {
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&admissionregistrationv1.ValidatingWebhookConfiguration{
+			ObjectMeta: metav1.ObjectMeta{Name: "guards"},
+			Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+				Name: "deny-delete.example.com",
+				Rules: []admissionregistrationv1.RuleWithOperations{{
+					Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+					Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+				}},
+			}},
+		},
+		testPod("default", "web", true, ""),
+	)
+	out, err := executeRoot(t, "--check-webhook", "--all", "--verbose-issues")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if want := "deny-delete.example.com in guards, failurePolicy Fail"; !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+	}
+}
+
+func TestMissingNodeLabel(t *testing.T) {
+	pending := testPod("default", "web", false, "")
+	pending.Spec.NodeName = ""
+	pending.Spec.NodeSelector = map[string]string{"pool": "old-name"}
+	pending.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type:    corev1.PodScheduled,
+			Status:  corev1.ConditionFalse,
+			Reason:  corev1.PodReasonUnschedulable,
+			Message: "0/2 nodes are available: 2 node(s) didn't match Pod's node affinity/selector.",
+		}},
+	}
+	useFakeCluster(t,
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"pool": "new-name"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2", Labels: map[string]string{"pool": "batch"}}},
+		pending,
+	)
+	out, err := executeRoot(t)
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{
+		"no node has label pool=old-name",
+		"closest existing values for pool: new-name, batch",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckNetworkPolicy(t *testing.T) {
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "sandbox"}},
+		&networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "payments", Name: "default-deny"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			},
+		},
+		testPod("payments", "api", true, ""),
+		testPod("sandbox", "scratch", true, ""),
+	)
+	out, err := executeRoot(t, "--check-network-policy", "-A")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"scratch", "namespace sandbox"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"api ", "namespace payments"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q\noutput:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestCheckServicePorts(t *testing.T) {
+	api := testPod("default", "api", true, "")
+	api.Labels = map[string]string{"app": "api"}
+	api.Spec.Containers[0].Ports = []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{"app": "api"},
+				Ports:    []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt32(8081)}},
+			},
+		},
+		api,
+	)
+	// 按名称启用时同样要获取 Service
+	for _, args := range [][]string{{"--check-service-ports"}, {"--enable-check", "service-ports"}} {
+		out, err := executeRoot(t, args...)
+		if err != nil {
+			t.Fatalf("%v: runPodView failed: %v\noutput:\n%s", args, err, out)
+		}
+		want := "service api port 80 targets 8081; container ports: 8080 (http)"
+		if !strings.Contains(out, want) {
+			t.Errorf("%v: output does not contain %q\noutput:\n%s", args, want, out)
+		}
+	}
+}
+
+func TestOutputJSON(t *testing.T) {
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		testPod("default", "web", true, ""),
+		testPod("default", "worker", false, "CrashLoopBackOff"),
+	)
+	out, err := executeRoot(t, "-o", "json", "--check-config")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, unwanted := range []string{"\x1b[", "Connecting to cluster", "Fetching pods", "Analyzing"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q\noutput:\n%s", unwanted, out)
+		}
+	}
+
+	var result analysisv1.AnalysisResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if result.Summary.TotalPods != 2 || result.Summary.WarningPods != 1 || result.Summary.TotalRestarts != 7 {
+		t.Errorf("summary = %+v, want 2 pods, 1 warning, 7 restarts", result.Summary)
+	}
+	if len(result.Pods) != 2 {
+		t.Fatalf("got %d pods, want 2", len(result.Pods))
+	}
+	for _, pod := range result.Pods {
+		if pod.Name == "worker" && (pod.Restarts != 7 || pod.ReadyContainers != 0 || pod.TotalContainers != 1) {
+			t.Errorf("worker = %+v, want 7 restarts and 0/1 ready", pod)
+		}
+	}
+	for _, field := range []string{`"readyContainers":`, `"totalContainers":`, `"restarts": 7`, `"configIssues":`} {
+		if !strings.Contains(out, field) {
+			t.Errorf("output does not contain %s\noutput:\n%s", field, out)
+		}
+	}
+}
+
+func TestOutputJSONNoPods(t *testing.T) {
+	useFakeCluster(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})
+	out, err := executeRoot(t, "-o", "json")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	var result analysisv1.AnalysisResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if result.Pods == nil || len(result.Pods) != 0 {
+		t.Errorf("pods = %#v, want an empty list", result.Pods)
+	}
+}
+
+func TestCheckVolumeAttachLimit(t *testing.T) {
+	db := testPod("default", "db-0", false, "ContainerCreating")
+	db.Status.Phase = corev1.PodPending
+	db.Status.ContainerStatuses[0].RestartCount = 0
+	db.Spec.Volumes = []corev1.Volume{{
+		Name:         "data",
+		VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}},
+	}}
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+			Status: corev1.NodeStatus{
+				Allocatable:     corev1.ResourceList{"attachable-volumes-aws-ebs": resource.MustParse("1")},
+				VolumesAttached: []corev1.AttachedVolume{{Name: "kubernetes.io/aws-ebs/vol-1"}},
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "data-db-0"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "db-0.attach"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "db-0"},
+			Reason:         "FailedAttachVolume",
+			Message:        `AttachVolume.Attach failed for volume "pvc-1" : node node-1 exceed max volume count`,
+		},
+		db,
+	)
+	out, err := executeRoot(t, "--check-storage")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"node node-1: 1 attached, allocatable attachable-volumes-aws-ebs: 1", "Cordon node-1 and delete the pod"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckProbes(t *testing.T) {
+	pod := testPod("default", "api", true, "")
+	probe := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(8080)}}}
+	pod.Spec.Containers[0].LivenessProbe = probe
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{Name: "envoy", Image: "envoyproxy/envoy:v1.30", LivenessProbe: probe})
+	useFakeCluster(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, pod)
+
+	out, err := executeRoot(t, "--check-probes", "--verbose-issues")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	want := "Multiple containers use same probe port (may test wrong container) (port 8080: app, envoy)"
+	if !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+	}
+}
+
+func TestOutputYAML(t *testing.T) {
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		testPod("payments", "ledger", true, ""),
+		testPod("default", "worker", false, "CrashLoopBackOff"),
+		testPod("default", "web", true, ""),
+	)
+	out, err := executeRoot(t, "-A", "-o", "yaml", "--check-config")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, unwanted := range []string{"\x1b[", "Connecting to cluster", "Summary", "Recommendations", "✗", "⚠"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q\noutput:\n%s", unwanted, out)
+		}
+	}
+
+	var result analysisv1.AnalysisResult
+	if err := yaml.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not valid YAML: %v\noutput:\n%s", err, out)
+	}
+	var got []string
+	for _, pod := range result.Pods {
+		got = append(got, pod.Namespace+"/"+pod.Name)
+	}
+	if want := []string{"default/web", "default/worker", "payments/ledger"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pods = %v, want %v", got, want)
+	}
+	if result.Summary.TotalPods != 3 || result.Summary.TotalRestarts != 7 {
+		t.Errorf("summary = %+v, want 3 pods and 7 restarts", result.Summary)
+	}
+}
+
+func TestCheckLivenessCascade(t *testing.T) {
+	objects := []runtime.Object{&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}}
+	for i, name := range []string{"cart", "checkout", "search"} {
+		objects = append(objects,
+			testPod("default", name, true, ""),
+			&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("%s.kill", name)},
+				InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: name},
+				Reason:         "Killing",
+				Message:        "Container app failed liveness probe, will be restarted",
+				LastTimestamp:  metav1.NewTime(time.Now().Add(time.Duration(i-10) * time.Minute)),
+			})
+	}
+	useFakeCluster(t, objects...)
+
+	out, err := executeRoot(t, "--check-liveness-cascade")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"3 liveness probe restarts across 3 workloads", "default/Pod/checkout", "move dependency checks to readinessProbe"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	controller, replicas := true, int32(4)
+	pod := testPod("shop", "api-7c79c4bf97-x2k4p", true, "")
+	pod.Labels = map[string]string{"pod-template-hash": "7c79c4bf97"}
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7c79c4bf97", Controller: &controller}}
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "api"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status:     appsv1.DeploymentStatus{UpdatedReplicas: 1},
+	}
+	deploy.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:      "app",
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")}},
+	}}
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "shop"}},
+		pod, deploy,
+		&corev1.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "memory"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("16Gi")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsMemory: resource.MustParse("12Gi")},
+			},
+		},
+	)
+
+	out, err := executeRoot(t, "-n", "shop", "--check-quota")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{
+		"shop/api: rollout will exhaust quota after 2 more pods",
+		"3 pods left × requests.memory 2Gi = 6Gi, quota memory has 4Gi left (hard 16Gi, used 12Gi)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+}
+
+func TestECIExport(t *testing.T) {
+	billed := testPod("batch", "etl-1", true, "")
+	billed.Spec.NodeName = "virtual-kubelet-cn-hangzhou-b"
+	billed.Annotations = map[string]string{
+		"k8s.aliyun.com/eci-instance-id":   "eci-bp1abc",
+		"k8s.aliyun.com/eci-zone-id":       "cn-hangzhou-b",
+		"k8s.aliyun.com/eci-vswitch":       "vsw-bp1a,vsw-bp1b",
+		"k8s.aliyun.com/eci-instance-spec": "ecs.c6.large",
+	}
+	// 运行在虚拟节点上但还没有实例 ID 注解，仍然导出，ID 为空
+	noID := testPod("batch", "etl-2", true, "")
+	noID.Spec.NodeName = "virtual-kubelet-cn-hangzhou-b"
+	useFakeCluster(t, billed, noID, testPod("batch", "web", true, ""))
+
+	out, err := executeRoot(t, "eci", "-n", "batch")
+	if err != nil {
+		t.Fatalf("eci failed: %v\noutput:\n%s", err, out)
+	}
+	want := "namespace,pod,instance_id,zone,vswitch,spec,age,status\n" +
+		"batch,etl-1,eci-bp1abc,cn-hangzhou-b,\"vsw-bp1a,vsw-bp1b\",ecs.c6.large,1h0m,Healthy\n" +
+		"batch,etl-2,,,,,1h0m,Healthy\n"
+	if out != want {
+		t.Errorf("csv output = %q, want %q", out, want)
+	}
+
+	out, err = executeRoot(t, "eci", "-n", "batch", "-o", "json")
+	if err != nil {
+		t.Fatalf("eci -o json failed: %v\noutput:\n%s", err, out)
+	}
+	var instances []analysisv1.ECIInstance
+	if err := json.Unmarshal([]byte(out), &instances); err != nil {
+		t.Fatalf("output is not JSON: %v\noutput:\n%s", err, out)
+	}
+	if len(instances) != 2 || instances[0].InstanceID != "eci-bp1abc" || instances[1].InstanceID != "" {
+		t.Errorf("instances = %+v", instances)
+	}
+
+	if _, err := executeRoot(t, "eci", "-o", "yaml"); err == nil {
+		t.Error("eci -o yaml should fail")
+	}
+}
+
+func TestNamespaceFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	tier1 := write("tier1.txt", "# tier-1 namespaces\npayments\n\ncheckout  # storefront\npayments\nledger\n")
+
+	useFakeCluster(t,
+		testPod("payments", "api", true, ""),
+		testPod("checkout", "web", false, "CrashLoopBackOff"),
+		testPod("sandbox", "scratch", false, "CrashLoopBackOff"),
+	)
+	out, err := executeRoot(t, "--namespace-file", tier1, "--all")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"Fetching pods in 3 namespaces", "NAMESPACE", "payments", "checkout", "Namespaces"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "scratch") {
+		t.Errorf("pod outside the listed namespaces shown\noutput:\n%s", out)
+	}
+	summary := out[strings.Index(out, "Namespaces"):]
+	for _, want := range []*regexp.Regexp{
+		regexp.MustCompile(`checkout\s+1\s+0\s+1\s+0\s+0\s+7`),
+		regexp.MustCompile(`ledger\s+0\s+0\s+0\s+0\s+0\s+0`),
+		regexp.MustCompile(`payments\s+1\s+1\s+0\s+0\s+0\s+0`),
+	} {
+		if !want.MatchString(summary) {
+			t.Errorf("namespace summary does not match %s\nsummary:\n%s", want, summary)
+		}
+	}
+
+	empty := write("empty.txt", "# nothing yet\n\n")
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing file", []string{"--namespace-file", filepath.Join(dir, "missing.txt")}, "no such file"},
+		{"empty file", []string{"--namespace-file", empty}, "lists no namespaces"},
+		{"with -A", []string{"--namespace-file", tier1, "-A"}, "cannot be combined with -A"},
+		{"with -n", []string{"--namespace-file", tier1, "-n", "payments"}, "cannot be combined with -n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := executeRoot(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnknownCheck(t *testing.T) {
+	useFakeCluster(t, testPod("default", "web", true, ""))
+	_, err := executeRoot(t, "--disable-check", "no-such-check")
+	if err == nil || !strings.Contains(err.Error(), `unknown check "no-such-check"`) {
+		t.Fatalf("err = %v, want unknown check error", err)
+	}
+}
+
+func TestRequestBudget(t *testing.T) {
+	withClaim := func(pod *corev1.Pod, claim string) *corev1.Pod {
+		pod.Spec.Volumes = []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claim}},
+		}}
+		return pod
+	}
+	failed := withClaim(testPod("default", "b-failed", false, ""), "b-data")
+	failed.Status.Phase = corev1.PodFailed
+	// 带退出码的失败原因，不会额外获取事件
+	failed.Status.ContainerStatuses[0].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}
+
+	// 配额只够一次请求，Error 的 Pod 排在 Warning 之前，尽管它的名称靠后
+	useFakeCluster(t,
+		withClaim(testPod("default", "a-crashing", false, "CrashLoopBackOff"), "a-data"),
+		failed,
+	)
+	out, err := executeRoot(t, "--check-storage", "--request-budget", "1", "--verbose", "1")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+
+	if want := "Extra API calls: 1/1 used, 1 denied, details omitted for 1 pods"; !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+	}
+
+	lines := strings.Split(out, "\n")
+	var notes []string
+	for i, line := range lines {
+		if strings.Contains(line, "details omitted (budget)") {
+			notes = append(notes, lines[i-1])
+		}
+	}
+	if len(notes) != 1 || !strings.Contains(notes[0], "a-crashing") {
+		t.Errorf("expected one budget note under a-crashing, got notes under %q\noutput:\n%s", notes, out)
+	}
+}
+
+func TestStats(t *testing.T) {
+	useFakeCluster(t, testPod("default", "web", true, ""), testPod("default", "api", false, "CrashLoopBackOff"))
+	out, err := executeRoot(t, "--stats")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+
+	i := strings.Index(out, "Run Statistics")
+	if i < strings.Index(out, "Recommendations") {
+		t.Fatalf("stats not printed after the normal output\noutput:\n%s", out)
+	}
+	stats := out[i:]
+	for _, want := range []string{"connect ", "list ", "enrich ", "analyze ", "print ", "list pods 1", "pods/s (2 pods)"} {
+		if !strings.Contains(stats, want) {
+			t.Errorf("stats do not contain %q\noutput:\n%s", want, stats)
+		}
+	}
+
+	out, err = executeRoot(t)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "Run Statistics") {
+		t.Errorf("stats printed without --stats\noutput:\n%s", out)
+	}
+}
+
+func TestSilentFailureLastActivity(t *testing.T) {
+	failedPod := func(name, reason string) *corev1.Pod {
+		pod := testPod("default", name, false, "")
+		pod.Status.Phase = corev1.PodFailed
+		pod.Status.Reason = reason
+		pod.Status.ContainerStatuses = nil
+		return pod
+	}
+	event := func(pod string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: pod + ".1"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Reason:         "NodeNotReady",
+			LastTimestamp:  metav1.NewTime(time.Now().Add(-6 * time.Hour)),
+		}
+	}
+
+	useFakeCluster(t, failedPod("husk", ""), failedPod("evicted", "Evicted"), event("husk"))
+	out, err := executeRoot(t, "--stats")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	// 只为没有原因的 Pod 获取事件
+	for _, want := range []string{"Failed, last activity 6h0m ago: NodeNotReady", "list events 1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+
+	// 事件请求占用额外请求配额，配额用完的 Pod 标记为缺少细节
+	useFakeCluster(t, failedPod("husk-a", ""), failedPod("husk-b", ""), event("husk-a"), event("husk-b"))
+	out, err = executeRoot(t, "--request-budget", "1")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if got := strings.Count(out, "last activity"); got != 1 {
+		t.Errorf("pods with last activity = %d, want 1\noutput:\n%s", got, out)
+	}
+	if !strings.Contains(out, "details omitted (budget)") {
+		t.Errorf("output does not mark the pod over budget\noutput:\n%s", out)
+	}
+}
+
+func TestCleanupPlan(t *testing.T) {
+	controller := true
+	ttl := int32(3600)
+	objects := []runtime.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nightly"}},
+		&batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "hourly"},
+			Spec:       batchv1.JobSpec{TTLSecondsAfterFinished: &ttl},
+		},
+		testPod("default", "web", true, ""),
+	}
+	for _, job := range []string{"nightly", "hourly"} {
+		for i := 0; i < analyzer.RetainedJobPodsThreshold; i++ {
+			pod := testPod("default", fmt.Sprintf("%s-%d", job, i), false, "")
+			pod.Status.Phase = corev1.PodSucceeded
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: job, Controller: &controller}}
+			objects = append(objects, pod)
+		}
+	}
+	evicted := testPod("default", "evicted", false, "")
+	evicted.Status.Phase = corev1.PodFailed
+	evicted.Status.Reason = "Evicted"
+	objects = append(objects, evicted)
+
+	useFakeCluster(t, objects...)
+	out, err := executeRoot(t, "--cleanup-plan")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+
+	for _, want := range []string{
+		"Cleanup Plan (printed, not executed)",
+		"# default: 10 Succeeded, 1 Failed (1 Evicted)",
+		"kubectl delete pods -n default --field-selector=status.phase==Succeeded",
+		"kubectl delete pods -n default --field-selector=status.phase==Failed",
+		`kubectl patch job -n default nightly --type=merge -p '{"spec":{"ttlSecondsAfterFinished":86400}}'`,
+		"Cleanup:        11 finished pods retained",
+		"Set ttlSecondsAfterFinished on Job default/nightly (5 finished pods retained)",
+		"Delete 11 finished pods in default",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "job -n default hourly") {
+		t.Errorf("Job with ttlSecondsAfterFinished should not be reported\noutput:\n%s", out)
+	}
+}
+
+func TestRetainedPodsWithoutCleanupPlan(t *testing.T) {
+	done := testPod("default", "migrate", false, "")
+	done.Status.Phase = corev1.PodSucceeded
+	useFakeCluster(t, testPod("default", "web", true, ""), done)
+
+	out, err := executeRoot(t)
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if !strings.Contains(out, "Cleanup:        1 finished pod retained") {
+		t.Errorf("summary does not count the finished pod\noutput:\n%s", out)
+	}
+	if strings.Contains(out, "finished pods in default") {
+		t.Errorf("a few finished pods should not get a cleanup recommendation without --cleanup-plan\noutput:\n%s", out)
+	}
+}
+
+func TestDisableUnavailableChecks(t *testing.T) {
+	opts := analyzer.Options{CheckConfig: true, CheckPDB: true, CheckVulnerabilities: true}
+	skipped := disableUnavailableChecks(context.Background(), newFakeClient(), []string{"default"}, &opts)
+
+	want := []analyzer.SkippedCheck{{Check: "vulnerability check", Reason: client.ReasonAPINotFound}}
+	if len(skipped) != len(want) || skipped[0] != want[0] {
+		t.Fatalf("skipped = %v, want %v", skipped, want)
+	}
+	if opts.CheckVulnerabilities {
+		t.Error("vulnerability check should be disabled when its API is missing")
+	}
+	if !opts.CheckPDB || !opts.CheckConfig {
+		t.Error("available checks should stay enabled")
+	}
+}
+
+func TestNamespaceFileQueriesEachNamespace(t *testing.T) {
+	pdb := func(namespace string) *policyv1.PodDisruptionBudget {
+		return &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "api"}}
+	}
+	clientset := fake.NewClientset(pdb("payments"), pdb("sandbox"))
+	clientset.Resources = []*metav1.APIResourceList{{GroupVersion: "policy/v1", APIResources: []metav1.APIResource{{Name: "poddisruptionbudgets"}}}}
+	// 权限只限于文件中的命名空间：跨命名空间的请求会被拒绝
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Namespace != ""
+		return true, review, nil
+	})
+	var listed []string
+	clientset.PrependReactor("list", "poddisruptionbudgets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listed = append(listed, action.GetNamespace())
+		return false, nil, nil
+	})
+	k8sClient := client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	namespaces := []string{"payments", "checkout"}
+
+	opts := analyzer.Options{CheckPDB: true}
+	if skipped := disableUnavailableChecks(context.Background(), k8sClient, namespaces, &opts); len(skipped) != 0 || !opts.CheckPDB {
+		t.Fatalf("skipped = %v, want the PDB check to stay enabled", skipped)
+	}
+	data := collectClusterData(context.Background(), k8sClient, namespaces, &corev1.PodList{}, opts)
+	if !reflect.DeepEqual(listed, namespaces) {
+		t.Errorf("listed PDBs in %q, want %q", listed, namespaces)
+	}
+	if len(data.PDBs) != 1 || data.PDBs[0].Namespace != "payments" {
+		t.Errorf("PDBs = %v, want only payments/api", data.PDBs)
+	}
+
+	// 跨命名空间查询时同样的权限不足以运行检查
+	opts = analyzer.Options{CheckPDB: true}
+	if skipped := disableUnavailableChecks(context.Background(), k8sClient, []string{""}, &opts); len(skipped) != 1 || opts.CheckPDB {
+		t.Errorf("skipped = %v, want the PDB check skipped cluster-wide", skipped)
+	}
+}
+
+func TestDisableUnsupportedChecks(t *testing.T) {
+	jobSkipped := analyzer.SkippedCheck{Check: "Job check", Reason: "requires Kubernetes 1.26+"}
+	pspSkipped := analyzer.SkippedCheck{Check: "PodSecurityPolicy check", Reason: "removed in Kubernetes 1.25"}
+	tests := []struct {
+		name        string
+		gitVersion  string
+		wantJob     bool
+		wantPSP     bool
+		wantSkipped []analyzer.SkippedCheck
+	}{
+		{"before PSP removal", "v1.24.17", false, true, []analyzer.SkippedCheck{jobSkipped}},
+		{"too old", "v1.25.16", false, false, []analyzer.SkippedCheck{jobSkipped, pspSkipped}},
+		{"supported", "v1.26.0-gke.1", true, false, []analyzer.SkippedCheck{pspSkipped}},
+		{"unparsable version", "master", true, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientset := fake.NewClientset()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: tt.gitVersion}
+			k8sClient := client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+
+			opts := analyzer.Options{CheckJob: true, CheckPSP: true}
+			skipped := disableUnsupportedChecks(k8sClient, &opts)
+			if opts.CheckJob != tt.wantJob {
+				t.Errorf("CheckJob = %v, want %v", opts.CheckJob, tt.wantJob)
+			}
+			if opts.CheckPSP != tt.wantPSP {
+				t.Errorf("CheckPSP = %v, want %v", opts.CheckPSP, tt.wantPSP)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestCheckImagePullSecretValidity(t *testing.T) {
+	pod := testPod("default", "api", true, "")
+	pod.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: "registry-creds"}}
+	useFakeCluster(t, pod, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "registry-creds"},
+		Type:       corev1.SecretTypeOpaque,
+	})
+
+	out, err := executeRoot(t, "--check-image-pull-secret-validity", "--all", "--verbose-issues")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if want := "ImagePullSecret has incorrect type (registry-creds: Opaque)"; !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+	}
+}
+
+func TestOwnerContact(t *testing.T) {
+	labeled := testPod("payments", "ledger", false, "CrashLoopBackOff")
+	labeled.Labels = map[string]string{"team": "ledger-core"}
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		labeled,
+		testPod("payments", "refunds", false, "CrashLoopBackOff"),
+	)
+
+	out, err := executeRoot(t, "-n", "payments", "--owner-contact")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"OWNER", "ledger-core", "refunds"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+	// refunds 没有 team 标签，回退到命名空间的标签
+	for _, line := range strings.Split(out, "\n") {
+		if strings.Contains(line, "refunds") && !strings.Contains(line, "payments") {
+			t.Errorf("refunds row does not show the namespace team label: %q", line)
+		}
+	}
+}
+
+func TestCheckJob(t *testing.T) {
+	controller := true
+	job := func(name string, restartPolicy corev1.RestartPolicy, policy *batchv1.PodFailurePolicy) *batchv1.Job {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: batchv1.JobSpec{
+				PodFailurePolicy: policy,
+				Template:         corev1.PodTemplateSpec{Spec: corev1.PodSpec{RestartPolicy: restartPolicy}},
+			},
+		}
+	}
+	jobPod := func(jobName string) *corev1.Pod {
+		pod := testPod("default", jobName+"-x7k2p", true, "")
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: jobName, Controller: &controller}}
+		return pod
+	}
+	useFakeCluster(t,
+		job("migrate", corev1.RestartPolicyNever, nil),
+		job("report", corev1.RestartPolicyNever, &batchv1.PodFailurePolicy{
+			Rules: []batchv1.PodFailurePolicyRule{{
+				Action:          batchv1.PodFailurePolicyActionIgnore,
+				OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{{Type: corev1.DisruptionTarget}},
+			}},
+		}),
+		job("retry", corev1.RestartPolicyOnFailure, nil),
+		jobPod("migrate"), jobPod("report"), jobPod("retry"),
+	)
+	out, err := executeRoot(t, "--check-job", "--all", "--verbose-issues")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if want := "job migrate"; !strings.Contains(out, want) {
+		t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+	}
+	for _, unwanted := range []string{"job report", "job retry"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("output contains %q\noutput:\n%s", unwanted, out)
+		}
+	}
+}
+
+func TestTemplates(t *testing.T) {
+	replicas := int32(3)
+	spec := testPod("default", "unused", true, "").Spec
+	spec.Containers[0].Resources = corev1.ResourceRequirements{}
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "api"},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas, Template: corev1.PodTemplateSpec{Spec: spec}},
+		},
+		&batchv1.CronJob{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "nightly"},
+			Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{Spec: spec},
+			}}},
+		},
+		// 模板检查不看 Pod：有问题的运行中 Pod 不出现在输出中
+		testPod("default", "api-7c79c4bf97-abc12", false, "CrashLoopBackOff"),
+	)
+
+	out, err := executeRoot(t, "--templates", "--check-config", "--verbose-issues")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"Analyzing 2 workload templates", "Deployment", "api", "CronJob", "nightly", "Missing resource limits", "2 of 2 workload templates have config issues"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+	if !regexp.MustCompile(`Deployment\s+api\s+3\s`).MatchString(out) {
+		t.Errorf("Deployment row does not show 3 replicas\noutput:\n%s", out)
+	}
+	if !regexp.MustCompile(`CronJob\s+nightly\s+-\s`).MatchString(out) {
+		t.Errorf("CronJob row does not show - for replicas\noutput:\n%s", out)
+	}
+	if strings.Contains(out, "abc12") || strings.Contains(out, "CrashLoopBackOff") {
+		t.Errorf("output lists pods\noutput:\n%s", out)
+	}
+}
+
+func TestDeployTimes(t *testing.T) {
+	pod := testPod("default", "api-7c79c4bf97-abc12", false, "CrashLoopBackOff")
+	pod.Labels = map[string]string{"pod-template-hash": "7c79c4bf97"}
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7c79c4bf97", Controller: &controller}}
+	useFakeCluster(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, pod)
+
+	out, err := executeRoot(t, "--deploy-times")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	for _, want := range []string{"Last Deploys", "default/api", "1h0m ago", "issues began 0s after deploy"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output does not contain %q\noutput:\n%s", want, out)
+		}
+	}
+
+	out, err = executeRoot(t, "--deploy-times", "-o", "json")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	var result analysisv1.AnalysisResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if len(result.Deploys) != 1 || !result.Deploys[0].DeployedAt.Equal(pod.CreationTimestamp.Time) {
+		t.Errorf("deploys = %+v, want api deployed at %v", result.Deploys, pod.CreationTimestamp.Time)
+	}
+}
+
+func TestTopProblemsJSON(t *testing.T) {
+	useFakeCluster(t,
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		testPod("default", "web", true, ""),
+		testPod("default", "worker", false, "CrashLoopBackOff"),
+		testPod("default", "api", false, "ImagePullBackOff"),
+	)
+	out, err := executeRoot(t, "--top-problems", "1", "-o", "json")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+
+	var ranked []analysisv1.ScoredPod
+	if err := json.Unmarshal([]byte(out), &ranked); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if len(ranked) != 1 || ranked[0].Rank != 1 || ranked[0].Score == 0 || ranked[0].Pod.Status == analysisv1.StatusHealthy {
+		t.Errorf("ranked = %+v, want the single worst pod with rank 1", ranked)
+	}
+}
+
+func TestCollectVulnerabilitiesCachesReports(t *testing.T) {
+	reportGVR := schema.GroupVersionResource{Group: "aquasecurity.github.io", Version: "v1alpha1", Resource: "vulnerabilityreports"}
+	clientset := fake.NewClientset()
+	clientset.Resources = []*metav1.APIResourceList{{
+		GroupVersion: reportGVR.GroupVersion().String(),
+		APIResources: []metav1.APIResource{{Name: reportGVR.Resource}},
+	}}
+	report := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "aquasecurity.github.io/v1alpha1",
+		"kind":       "VulnerabilityReport",
+		"metadata": map[string]interface{}{
+			"namespace": "default",
+			"name":      "replicaset-api-7c79c4bf97-app",
+			"labels": map[string]interface{}{
+				"trivy-operator.resource.kind":  "ReplicaSet",
+				"trivy-operator.resource.name":  "api-7c79c4bf97",
+				"trivy-operator.container.name": "app",
+			},
+		},
+		"report": map[string]interface{}{"summary": map[string]interface{}{"criticalCount": int64(1), "highCount": int64(2)}},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{reportGVR: "VulnerabilityReportList"})
+	if _, err := dynamicClient.Resource(reportGVR).Namespace("default").Create(context.Background(), report, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	k8sClient := client.NewClientFromInterfaces(clientset, dynamicClient)
+	k8sClient.SetBudget(client.NewBudget(10))
+
+	controller := true
+	pods := &corev1.PodList{}
+	for _, name := range []string{"api-7c79c4bf97-a", "api-7c79c4bf97-b", "api-7c79c4bf97-c"} {
+		pod := testPod("default", name, true, "")
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7c79c4bf97", Controller: &controller}}
+		pods.Items = append(pods.Items, *pod)
+	}
+
+	counts := collectVulnerabilities(context.Background(), k8sClient, pods, make(map[string]bool))
+	if used := k8sClient.Budget().Used(); used != 1 {
+		t.Errorf("requests = %d, want 1 for three replicas of one ReplicaSet", used)
+	}
+	for _, pod := range pods.Items {
+		if got := counts["default/"+pod.Name+"/app"]; got != 3 {
+			t.Errorf("count for %s = %d, want 3", pod.Name, got)
+		}
+	}
+}
+
+func TestFetchAddonPods(t *testing.T) {
+	k8sClient := newFakeClient(
+		testPod("kube-system", "coredns", true, ""),
+		testPod("ingress-nginx", "controller", true, ""),
+		testPod("payments", "ledger", true, ""),
+	)
+	queried := &corev1.PodList{Items: []corev1.Pod{*testPod("default", "web", true, "")}}
+	rules := []analyzer.AddonRule{
+		{Name: "ingress-nginx", Namespace: "ingress-nginx"},
+		{Name: "CoreDNS", Namespace: "kube-system"},
+		{Name: "web", Namespace: "default"},
+	}
+
+	pods, err := fetchAddonPods(context.Background(), k8sClient, rules, []string{"default"}, queried)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, pod := range pods.Items {
+		names = append(names, pod.Namespace+"/"+pod.Name)
+	}
+	if want := []string{"ingress-nginx/controller", "kube-system/coredns", "default/web"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("pods = %v, want %v", names, want)
+	}
+
+	// 不限定命名空间的规则需要所有命名空间的 Pod
+	pods, err = fetchAddonPods(context.Background(), k8sClient, append(rules, analyzer.AddonRule{Name: "anywhere"}), []string{"default"}, queried)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 3 {
+		t.Errorf("got %d pods, want all 3 cluster pods", len(pods.Items))
+	}
+}
+
+func TestRequireComplete(t *testing.T) {
+	nsFile := filepath.Join(t.TempDir(), "namespaces.txt")
+	if err := os.WriteFile(nsFile, []byte("payments\ncheckout\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	origClient := newClient
+	t.Cleanup(func() { newClient = origClient })
+	newClient = func(string) (*client.Client, error) {
+		clientset := fake.NewClientset(testPod("payments", "api", true, ""), testPod("checkout", "web", true, ""))
+		clientset.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if action.GetNamespace() != "checkout" {
+				return false, nil, nil
+			}
+			return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "", fmt.Errorf("no access"))
+		})
+		return client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())), nil
+	}
+
+	// 部分结果：输出已获取的命名空间，以专用退出码结束
+	out, err := executeRoot(t, "--namespace-file", nsFile, "--all")
+	var partial *PartialResultError
+	if !errors.As(err, &partial) || !strings.Contains(out, "api") || !strings.Contains(out, "Results are partial") {
+		t.Errorf("err = %v, want PartialResultError with the fetched pods\noutput:\n%s", err, out)
+	}
+
+	// --require-complete 不输出结果，以普通错误结束
+	out, err = executeRoot(t, "--namespace-file", nsFile, "--all", "--require-complete")
+	if err == nil || errors.As(err, &partial) {
+		t.Fatalf("err = %v, want a plain error", err)
+	}
+	if strings.Contains(out, "api") || strings.Contains(out, "Results are partial") || !strings.Contains(out, "checkout") {
+		t.Errorf("output should only list the failed namespaces\noutput:\n%s", out)
+	}
+}
+
+func TestFetchPodsPerNamespaceForbidden(t *testing.T) {
+	clientset := fake.NewClientset(
+		testPod("default", "web", true, ""),
+		testPod("payments", "ledger", true, ""),
+	)
+	// 没有列出命名空间的权限时退回到一次跨命名空间的请求
+	clientset.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", fmt.Errorf("no access"))
+	})
+	k8sClient := client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+
+	pods, failed, err := fetchPodsPerNamespace(context.Background(), k8sClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(failed) != 0 {
+		t.Errorf("failed = %v, want none", failed)
+	}
+	if len(pods.Items) != 2 {
+		t.Errorf("got %d pods, want 2", len(pods.Items))
+	}
+}
+
+func TestCollectNamespacesStopsAtForbidden(t *testing.T) {
+	clientset := fake.NewClientset()
+	gets := 0
+	clientset.PrependReactor("get", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		gets++
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "namespaces"}, "", fmt.Errorf("no access"))
+	})
+	k8sClient := client.NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()))
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		*testPod("default", "web", true, ""),
+		*testPod("payments", "ledger", true, ""),
+		*testPod("batch", "report", true, ""),
+	}}
+
+	if got := collectNamespaces(context.Background(), k8sClient, pods); got != nil {
+		t.Errorf("namespaces = %v, want nil when access is forbidden", got)
+	}
+	if gets != 1 {
+		t.Errorf("namespace requests = %d, want 1", gets)
+	}
+}
+
+func TestSuggestECIOutputJSON(t *testing.T) {
+	pod := testPod("default", "nightly-report-x7k2p", true, "")
+	controller := true
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "Job", Name: "nightly-report", Controller: &controller}}
+	pod.Status.QOSClass = corev1.PodQOSBestEffort
+	useFakeCluster(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}, pod)
+
+	out, err := executeRoot(t, "--suggest-eci", "-o", "json")
+	if err != nil {
+		t.Fatalf("runPodView failed: %v\noutput:\n%s", err, out)
+	}
+	if strings.Contains(out, "Looking for ECI candidates") {
+		t.Errorf("progress line written to stdout\noutput:\n%s", out)
+	}
+	var candidates []analysisv1.ECICandidate
+	if err := json.Unmarshal([]byte(out), &candidates); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if len(candidates) != 1 || candidates[0].Rank != 1 || candidates[0].Name != pod.Name || candidates[0].OwnerKind != "Job" || len(candidates[0].Labels) == 0 {
+		t.Errorf("candidates = %+v, want the Job pod ranked first with ECI labels", candidates)
+	}
+}
+
+func TestDetailOutputJSON(t *testing.T) {
+	pod := testPod("default", "web", true, "")
+	created := pod.CreationTimestamp.Time
+	pod.Status.Conditions = []corev1.PodCondition{
+		{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(2 * time.Second))},
+		{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.NewTime(created.Add(42 * time.Second))},
+	}
+	useFakeCluster(t, pod)
+
+	out, err := executeRoot(t, "detail", "web", "-o", "json")
+	if err != nil {
+		t.Fatalf("detail failed: %v\noutput:\n%s", err, out)
+	}
+	var detail analysisv1.PodDetail
+	if err := json.Unmarshal([]byte(out), &detail); err != nil {
+		t.Fatalf("output is not valid JSON: %v\noutput:\n%s", err, out)
+	}
+	if detail.Pod.Name != "web" {
+		t.Errorf("pod = %q, want web", detail.Pod.Name)
+	}
+	var names []string
+	for _, event := range detail.Timeline {
+		names = append(names, event.Name)
+	}
+	if want := []string{"created", "scheduled", "ready"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("timeline = %v, want %v", names, want)
+	}
+	if ready := detail.Timeline[2]; ready.SinceSeconds != 40 || !ready.Slowest {
+		t.Errorf("ready = %+v, want sinceSeconds 40 and slowest", ready)
+	}
+
+	if _, err := executeRoot(t, "detail", "web", "-o", "csv"); err == nil || !strings.Contains(err.Error(), "--output must be text, json or yaml") {
+		t.Errorf("err = %v, want an --output error", err)
+	}
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+)
+
+// phaseTimer 记录一次运行中各阶段的耗时，每个阶段从上一个阶段结束时算起
+type phaseTimer struct {
+	last   time.Time
+	phases []analyzer.PhaseDuration
+}
+
+// newPhaseTimer 创建从当前时刻开始计时的 phaseTimer
+func newPhaseTimer() *phaseTimer {
+	return &phaseTimer{last: time.Now()}
+}
+
+// mark 结束名为 name 的阶段
+func (t *phaseTimer) mark(name string) {
+	now := time.Now()
+	t.phases = append(t.phases, analyzer.PhaseDuration{Name: name, Duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// runStats 汇总阶段耗时和客户端的请求统计
+func runStats(timer *phaseTimer, requests *client.RequestStats, pods int) *analyzer.RunStats {
+	return &analyzer.RunStats{
+		Phases:        timer.phases,
+		Requests:      requests.Requests(),
+		BytesReceived: requests.BytesReceived(),
+		Pods:          pods,
+	}
+}
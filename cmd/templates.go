@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/client"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/printer"
+)
+
+// runTemplateView 检查 Deployment/StatefulSet/DaemonSet/CronJob 的 Pod 模板，每个工作负载报告一次
+func runTemplateView(ctx context.Context, k8sClient *client.Client, fileNamespaces []string, opts analyzer.Options) error {
+	scopes := []string{namespace}
+	switch {
+	case allNamespaces:
+		scopes = []string{""}
+		fmt.Printf("📦 Fetching workloads across all namespaces...\n")
+	case len(fileNamespaces) > 0:
+		scopes = fileNamespaces
+		fmt.Printf("📦 Fetching workloads in %d namespaces from %s...\n", len(fileNamespaces), namespaceFile)
+	default:
+		fmt.Printf("📦 Fetching workloads in namespace '%s'...\n", namespace)
+	}
+
+	var workloads []analyzer.WorkloadTemplate
+	for _, scope := range scopes {
+		listed, err := fetchWorkloadTemplates(ctx, k8sClient, scope)
+		if err != nil {
+			return err
+		}
+		workloads = append(workloads, listed...)
+	}
+
+	// 必需标签可以由命名空间提供，需要获取工作负载所在的命名空间
+	if len(opts.RequiredLabels) > 0 {
+		pods := &corev1.PodList{}
+		for _, w := range workloads {
+			pods.Items = append(pods.Items, corev1.Pod{ObjectMeta: w.Template.ObjectMeta})
+			pods.Items[len(pods.Items)-1].Namespace = w.Namespace
+		}
+		opts.Cluster = &analyzer.ClusterData{Namespaces: collectNamespaces(ctx, k8sClient, pods)}
+	}
+
+	fmt.Printf("🔍 Analyzing %d workload templates...\n\n", len(workloads))
+	results := analyzer.AnalyzeTemplates(workloads, opts)
+
+	p := printer.NewPrinter(os.Stdout)
+	p.PrintWorkloadTemplates(results, printer.TableOptions{
+		ShowAll:       showAll,
+		ShowNamespace: allNamespaces || len(fileNamespaces) > 0,
+		VerboseIssues: verboseIssues,
+		MaxIssueLines: maxIssueLines,
+	})
+	return nil
+}
+
+// fetchWorkloadTemplates 获取一个命名空间（为空时为所有命名空间）中工作负载的 Pod 模板
+// 某种工作负载无法列出（如没有权限）时打印警告并跳过，全部失败时返回错误
+func fetchWorkloadTemplates(ctx context.Context, k8sClient *client.Client, scope string) ([]analyzer.WorkloadTemplate, error) {
+	var (
+		workloads []analyzer.WorkloadTemplate
+		lastErr   error
+		failed    int
+	)
+	warn := func(kind string, err error) {
+		fmt.Printf("⚠️  Failed to list %s: %v\n", kind, err)
+		lastErr = err
+		failed++
+	}
+
+	if list, err := k8sClient.GetDeployments(ctx, scope); err != nil {
+		warn("deployments", err)
+	} else {
+		for _, d := range list.Items {
+			workloads = append(workloads, analyzer.WorkloadTemplate{
+				Kind: "Deployment", Namespace: d.Namespace, Name: d.Name,
+				Replicas: desiredReplicas(d.Spec.Replicas), Template: d.Spec.Template,
+			})
+		}
+	}
+
+	if list, err := k8sClient.GetStatefulSets(ctx, scope); err != nil {
+		warn("statefulsets", err)
+	} else {
+		for _, s := range list.Items {
+			workloads = append(workloads, analyzer.WorkloadTemplate{
+				Kind: "StatefulSet", Namespace: s.Namespace, Name: s.Name,
+				Replicas: desiredReplicas(s.Spec.Replicas), Template: s.Spec.Template,
+			})
+		}
+	}
+
+	if list, err := k8sClient.GetDaemonSets(ctx, scope); err != nil {
+		warn("daemonsets", err)
+	} else {
+		for _, ds := range list.Items {
+			workloads = append(workloads, analyzer.WorkloadTemplate{
+				Kind: "DaemonSet", Namespace: ds.Namespace, Name: ds.Name,
+				Replicas: ds.Status.DesiredNumberScheduled, Template: ds.Spec.Template,
+			})
+		}
+	}
+
+	if list, err := k8sClient.GetCronJobs(ctx, scope); err != nil {
+		warn("cronjobs", err)
+	} else {
+		for _, cj := range list.Items {
+			workloads = append(workloads, analyzer.WorkloadTemplate{
+				Kind: "CronJob", Namespace: cj.Namespace, Name: cj.Name,
+				Replicas: -1, Template: cj.Spec.JobTemplate.Spec.Template,
+			})
+		}
+	}
+
+	if failed == 4 {
+		return nil, fmt.Errorf("failed to list workloads: %w", lastErr)
+	}
+	return workloads, nil
+}
+
+// desiredReplicas 返回期望的副本数，未设置时 Kubernetes 默认为 1
+func desiredReplicas(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
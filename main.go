@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 
 	"github.com/FishPie-HQ/kubectl-podview/cmd"
@@ -8,6 +9,10 @@ import (
 
 func main() {
 	if err := cmd.Execute(); err != nil {
+		var partial *cmd.PartialResultError
+		if errors.As(err, &partial) {
+			os.Exit(cmd.ExitPartialResults)
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,6 @@
+// Package v1 定义 kubectl-podview 分析结果的稳定类型
+//
+// 这些类型面向外部工具使用：只依赖标准库，JSON 字段名固定为 lowerCamelCase。
+// 在 v1 中字段只会新增、不会重命名或删除；不兼容的修改会放到新的 v2 包中。
+// 内部的 pkg/analyzer 可以随意重构，通过 analyzer.AnalysisResult.ToV1 转换为这里的类型。
+package v1
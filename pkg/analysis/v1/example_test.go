@@ -0,0 +1,52 @@
+package v1_test
+
+import (
+	"encoding/json"
+	"fmt"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+)
+
+// 下游工具通常从 `kubectl podview -o json` 的输出中解析结果
+func ExampleAnalysisResult() {
+	data := []byte(`{
+		"pods": [
+			{"name": "api-0", "namespace": "prod", "status": "Warning", "reason": "CrashLoopBackOff",
+			 "readyContainers": 0, "totalContainers": 1, "restarts": 12}
+		],
+		"summary": {"totalPods": 1, "warningPods": 1, "totalRestarts": 12}
+	}`)
+
+	var result analysisv1.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		panic(err)
+	}
+
+	for _, pod := range result.Pods {
+		if pod.Status != analysisv1.StatusHealthy {
+			fmt.Printf("%s/%s %s (%d/%d ready, %d restarts)\n",
+				pod.Namespace, pod.Name, pod.Reason, pod.ReadyContainers, pod.TotalContainers, pod.Restarts)
+		}
+	}
+	// Output:
+	// prod/api-0 CrashLoopBackOff (0/1 ready, 12 restarts)
+}
+
+func ExamplePodAnalysis() {
+	pod := analysisv1.PodAnalysis{
+		Name:            "web-7c79c4bf97-abc12",
+		Namespace:       "default",
+		Status:          analysisv1.StatusHealthy,
+		Phase:           "Running",
+		ReadyContainers: 1,
+		TotalContainers: 1,
+		Age:             "2d5h",
+		RunningTime:     "2d5h",
+		Containers:      []analysisv1.ContainerAnalysis{{Name: "web", Ready: true}},
+	}
+
+	out, _ := json.Marshal(pod)
+	fmt.Println(string(out))
+	// Output:
+	// {"name":"web-7c79c4bf97-abc12","namespace":"default","status":"Healthy","phase":"Running","readyContainers":1,"totalContainers":1,"restarts":0,"age":"2d5h","runningTime":"2d5h","containers":[{"name":"web","ready":true,"restartCount":0}],"runningOnECI":false,"hasECIConfig":false}
+}
@@ -0,0 +1,357 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// PodStatus 表示 Pod 的状态分类
+type PodStatus string
+
+const (
+	StatusHealthy PodStatus = "Healthy"
+	StatusWarning PodStatus = "Warning"
+	StatusError   PodStatus = "Error"
+	StatusPending PodStatus = "Pending"
+	StatusUnknown PodStatus = "Unknown"
+)
+
+// ConfigIssue 是人类可读的配置问题描述
+type ConfigIssue string
+
+// PodAnalysis 是单个 Pod 的分析结果
+type PodAnalysis struct {
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace"`
+	Status    PodStatus `json:"status"`
+	Phase     string    `json:"phase"`
+	Reason    string    `json:"reason,omitempty"`
+
+	ReadyContainers int    `json:"readyContainers"`
+	TotalContainers int    `json:"totalContainers"`
+	Restarts        int32  `json:"restarts"`
+	Age             string `json:"age"`
+	RunningTime     string `json:"runningTime"`
+
+	// SchedulingLatencySeconds 是从创建到调度成功的秒数，还未调度成功时没有该字段
+	SchedulingLatencySeconds *float64 `json:"schedulingLatencySeconds,omitempty"`
+
+	// EfficiencyScore 是 CPU 和内存用量占 requests 比例的平均值，没有用量数据时没有该字段
+	EfficiencyScore *float64 `json:"efficiencyScore,omitempty"`
+
+	ConfigIssues []ConfigIssue       `json:"configIssues,omitempty"`
+	Containers   []ContainerAnalysis `json:"containers"`
+
+	RunningOnECI  bool   `json:"runningOnECI"`
+	HasECIConfig  bool   `json:"hasECIConfig"`
+	ECIInstanceID string `json:"eciInstanceID,omitempty"`
+	ECIZone       string `json:"eciZone,omitempty"`
+	ECIVSwitch    string `json:"eciVSwitch,omitempty"`
+	ECISpec       string `json:"eciSpec,omitempty"`
+	// Provider 是匹配的虚拟节点识别规则名称，如 "eci"
+	Provider string `json:"provider,omitempty"`
+
+	NodeName      string `json:"nodeName,omitempty"`
+	Zone          string `json:"zone,omitempty"`
+	OwnerKind     string `json:"ownerKind,omitempty"`
+	OwnerName     string `json:"ownerName,omitempty"`
+	MainContainer string `json:"mainContainer,omitempty"`
+	// OwnerContact 是负责团队标签的值（Pod 优先，其次是命名空间），仅在 --owner-contact 时有
+	OwnerContact string `json:"ownerContact,omitempty"`
+
+	// ProblemSince 是问题开始的大致时间，健康的 Pod 没有该字段
+	ProblemSince *time.Time `json:"problemSince,omitempty"`
+	// PDBDisruptionsAllowed 是覆盖该 Pod 的 PDB 允许的中断数，没有 PDB 时没有该字段
+	PDBDisruptionsAllowed *int `json:"pdbDisruptionsAllowed,omitempty"`
+	// AdmissionFailure 表示 Pod 被 kubelet 准入拒绝
+	AdmissionFailure bool `json:"admissionFailure,omitempty"`
+	// NodePressure 是所在节点当前生效的压力条件，如 MemoryPressure
+	NodePressure []string `json:"nodePressure,omitempty"`
+	// MissingNodeLabel 是 Pending Pod 选择、但没有任何节点带有的标签
+	MissingNodeLabel *MissingNodeLabel `json:"missingNodeLabel,omitempty"`
+	// NominatedNodeName 是调度器为抢占提名的节点，只有 Pending Pod 才可能有
+	NominatedNodeName string `json:"nominatedNodeName,omitempty"`
+	// DetailsOmitted 表示额外请求的配额已用完，部分检查缺少该 Pod 的数据
+	DetailsOmitted bool `json:"detailsOmitted,omitempty"`
+	// RestartPolicy 是 Pod 生效的重启策略：Always、OnFailure 或 Never
+	RestartPolicy string `json:"restartPolicy,omitempty"`
+	// Raw 是按请求附加的源 Pod 原始数据，没有请求时没有该字段
+	Raw *RawPod `json:"raw,omitempty"`
+}
+
+// ScoredPod 是 --top-problems 排名中的一个 Pod，Rank 从 1 开始，Score 越高问题越严重
+type ScoredPod struct {
+	Rank  int         `json:"rank"`
+	Score int         `json:"score"`
+	Pod   PodAnalysis `json:"pod"`
+}
+
+// PodDetail 是单个 Pod 的详细信息及其生命周期时间线（kubectl podview detail -o json）
+type PodDetail struct {
+	Pod      PodAnalysis     `json:"pod"`
+	Timeline []TimelineEvent `json:"timeline"`
+}
+
+// TimelineEvent 是 Pod 生命周期中的一个阶段，如 "scheduled" 或 "ready"
+type TimelineEvent struct {
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+	// SinceSeconds 是距上一个阶段的秒数，第一个阶段为 0
+	SinceSeconds float64 `json:"sinceSeconds"`
+	// Slowest 表示这是耗时最长的一跳
+	Slowest bool `json:"slowest,omitempty"`
+}
+
+// ECICandidate 是适合迁移到 ECI 的 Pod（--suggest-eci），按 Rank 从 1 开始排序
+type ECICandidate struct {
+	Rank      int    `json:"rank"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	OwnerKind string `json:"ownerKind,omitempty"`
+	OwnerName string `json:"ownerName,omitempty"`
+	Score     int    `json:"score"`
+	// Fit 是 "good" 或 "possible"
+	Fit     string   `json:"fit"`
+	Reasons []string `json:"reasons"`
+	// Labels/Annotations 是调度到 ECI 需要在 Pod 模板上添加的配置
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ECIInstance 是一个 ECI Pod 及其背后的 ECI 实例（kubectl podview eci -o json 的一行）
+type ECIInstance struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// InstanceID 在 Pod 没有实例 ID 注解时为空字符串
+	InstanceID string    `json:"instanceID"`
+	Zone       string    `json:"zone"`
+	VSwitch    string    `json:"vswitch"`
+	Spec       string    `json:"spec"`
+	Age        string    `json:"age"`
+	Status     PodStatus `json:"status"`
+}
+
+// Finding 是一种有稳定 ID 的发现及其说明（kubectl podview explain -o json）
+type Finding struct {
+	// ID 是稳定的标识，如 "missing-limits" 或 "CrashLoopBackOff"
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	// Issue 是对应的配置问题，Tag 是它在表格行尾标记中的简写；Pod 状态原因没有这两个字段
+	Issue       ConfigIssue `json:"issue,omitempty"`
+	Tag         string      `json:"tag,omitempty"`
+	Description string      `json:"description"`
+	Why         string      `json:"why"`
+	Detection   string      `json:"detection"`
+	Remediation string      `json:"remediation"`
+}
+
+// MissingNodeLabel 是没有任何节点带有的节点标签
+type MissingNodeLabel struct {
+	Key string `json:"key"`
+	// Values 是要求的取值，没有时表示没有节点带有该键
+	Values []string `json:"values,omitempty"`
+	// Closest 是最接近的现有取值，没有节点带有该键时是最接近的现有键
+	Closest []string `json:"closest,omitempty"`
+}
+
+// RawPod 是源 Pod 的原始数据，各字段是 Kubernetes API 中对应字段的原样 JSON
+// 注解按前缀白名单过滤，过长的值被截断并以 "...[truncated]" 结尾
+type RawPod struct {
+	Conditions        json.RawMessage   `json:"conditions,omitempty"`        // status.conditions
+	ContainerStatuses json.RawMessage   `json:"containerStatuses,omitempty"` // status.containerStatuses
+	Labels            map[string]string `json:"labels,omitempty"`            // metadata.labels
+	Annotations       map[string]string `json:"annotations,omitempty"`       // metadata.annotations
+}
+
+// ContainerAnalysis 是单个容器的分析结果
+type ContainerAnalysis struct {
+	Name               string `json:"name"`
+	Ready              bool   `json:"ready"`
+	RestartCount       int32  `json:"restartCount"`
+	LastTermination    string `json:"lastTermination,omitempty"`
+	TerminationMessage string `json:"terminationMessage,omitempty"`
+	// State 是容器当前状态：running、waiting 或 terminated
+	State         string `json:"state,omitempty"`
+	WaitingReason string `json:"waitingReason,omitempty"`
+}
+
+// AnalysisResult 是一次分析的完整结果
+type AnalysisResult struct {
+	// Pods 按 namespace/name 排序
+	Pods    []PodAnalysis `json:"pods"`
+	Summary Summary       `json:"summary"`
+
+	// Preemptions 是正在抢占提名节点的 Pending Pod 及可能被驱逐的低优先级 Pod
+	Preemptions []Preemption `json:"preemptions,omitempty"`
+	// Duplicates 是在多个命名空间中名称或镜像相同的工作负载
+	Duplicates []DuplicateWorkload `json:"duplicates,omitempty"`
+	// RetainedPods 是各命名空间保留的已结束 Pod
+	RetainedPods []RetainedPods `json:"retainedPods,omitempty"`
+	// JobsWithoutTTL 是没有设置 ttlSecondsAfterFinished、保留了大量已结束 Pod 的 Job
+	JobsWithoutTTL []RetainingJob `json:"jobsWithoutTTL,omitempty"`
+	// SkippedChecks 是因 API 不存在、没有权限或服务器版本过低而跳过的检查，非空时结果是不完整的
+	SkippedChecks []SkippedCheck `json:"skippedChecks,omitempty"`
+	// TerminatingNamespaces 是处于 Terminating 状态的命名空间，其中的 Pod 原因为 "Namespace terminating"
+	TerminatingNamespaces []TerminatingNamespace `json:"terminatingNamespaces,omitempty"`
+	// LivenessCascade 是多个工作负载在同一时间窗口内被存活探针重启的情况
+	LivenessCascade *LivenessCascade `json:"livenessCascade,omitempty"`
+	// QuotaRollouts 是剩余副本会耗尽命名空间 ResourceQuota 的 Deployment 滚动更新
+	QuotaRollouts []QuotaRollout `json:"quotaRollouts,omitempty"`
+	// Deploys 是每个 Deployment 最近一次发布的大致时间，仅在 --deploy-times 时存在
+	Deploys []WorkloadDeploy `json:"deploys,omitempty"`
+	// Stats 是本次运行的统计信息，仅在 --stats 时存在
+	Stats *RunStats `json:"stats,omitempty"`
+}
+
+// LivenessCascade 是短时间内多个工作负载的容器因存活探针失败而重启的情况
+type LivenessCascade struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Kills int       `json:"kills"`
+	// Workloads 的格式为 "namespace/Kind/name"
+	Workloads []string `json:"workloads"`
+}
+
+// WorkloadDeploy 是 Deployment 最近一次发布的大致时间：当前 pod-template-hash 中最早的 Pod 的创建时间
+type WorkloadDeploy struct {
+	Namespace  string    `json:"namespace"`
+	Name       string    `json:"name"`
+	DeployedAt time.Time `json:"deployedAt"`
+	// ProblemSince 是该工作负载最早的问题开始时间，没有问题时没有该字段
+	ProblemSince *time.Time `json:"problemSince,omitempty"`
+	// IssuesAfterDeploySeconds 是问题在发布之后多少秒开始，问题早于发布或没有问题时没有该字段
+	IssuesAfterDeploySeconds *float64 `json:"issuesAfterDeploySeconds,omitempty"`
+}
+
+// QuotaRollout 是剩余副本会耗尽命名空间 ResourceQuota 的 Deployment 滚动更新
+type QuotaRollout struct {
+	Namespace  string `json:"namespace"`
+	Deployment string `json:"deployment"`
+	// RemainingPods 是还没有更新到新模板的副本数，FitsPods 是剩余配额还能容纳的新 Pod 数
+	RemainingPods int `json:"remainingPods"`
+	FitsPods      int `json:"fitsPods"`
+	// Quota 和 Resource 是限制最紧的 ResourceQuota 及其中的资源，如 "requests.cpu"
+	Quota    string `json:"quota"`
+	Resource string `json:"resource"`
+	// PerPod、Hard、Used 是 Kubernetes 数量格式的字符串，如 "500m"、"8Gi"
+	PerPod string `json:"perPod"`
+	Hard   string `json:"hard"`
+	Used   string `json:"used"`
+}
+
+// RunStats 是一次运行的统计信息
+type RunStats struct {
+	// Phases 是按执行顺序排列的各阶段耗时：connect、list、enrich、analyze、print
+	Phases []Phase `json:"phases"`
+	// DurationSeconds 是所有阶段的总耗时
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Requests 是按类型统计的 API 请求数，键形如 "list pods"、"get nodes"
+	Requests      map[string]int `json:"requests"`
+	TotalRequests int            `json:"totalRequests"`
+	// BytesReceived 是 API 响应体的总字节数
+	BytesReceived int64   `json:"bytesReceived"`
+	Pods          int     `json:"pods"`
+	PodsPerSecond float64 `json:"podsPerSecond"`
+}
+
+// Phase 是一个阶段的耗时
+type Phase struct {
+	Name    string  `json:"name"`
+	Seconds float64 `json:"seconds"`
+}
+
+// TerminatingNamespace 是处于 Terminating 状态的命名空间
+type TerminatingNamespace struct {
+	Name string `json:"name"`
+	// Since 是删除开始的时间
+	Since      *time.Time `json:"since,omitempty"`
+	Age        string     `json:"age,omitempty"`
+	Finalizers []string   `json:"finalizers,omitempty"`
+	// Conditions 是阻塞删除的条件，格式为 "<type>: <message>"
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// SkippedCheck 是一个被跳过的可选检查
+type SkippedCheck struct {
+	Check string `json:"check"`
+	// Reason 是 "API not found"、"forbidden" 或 "requires Kubernetes 1.N+"
+	Reason string `json:"reason"`
+}
+
+// RetainedPods 是一个命名空间中保留的已结束 Pod 数量
+type RetainedPods struct {
+	Namespace string `json:"namespace"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// Evicted 是 Failed 中被驱逐的 Pod 数量
+	Evicted int `json:"evicted,omitempty"`
+}
+
+// RetainingJob 是保留了已结束 Pod 的 Job
+type RetainingJob struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Pods      int    `json:"pods"`
+}
+
+// DuplicateWorkload 是在多个命名空间中出现的同一工作负载
+type DuplicateWorkload struct {
+	// MatchedBy 是 "name" 或 "image"
+	MatchedBy string             `json:"matchedBy"`
+	Key       string             `json:"key"`
+	Instances []WorkloadInstance `json:"instances"`
+}
+
+// WorkloadInstance 是重复工作负载在某个命名空间中的一份
+type WorkloadInstance struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Replicas  int    `json:"replicas"`
+	Age       string `json:"age"`
+}
+
+// Preemption 是一个正在抢占节点的 Pending Pod
+type Preemption struct {
+	Namespace         string             `json:"namespace"`
+	Name              string             `json:"name"`
+	Priority          int32              `json:"priority"`
+	NominatedNodeName string             `json:"nominatedNodeName"`
+	Victims           []PreemptionVictim `json:"victims,omitempty"`
+}
+
+// PreemptionVictim 是提名节点上可能被驱逐的低优先级 Pod
+type PreemptionVictim struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Priority  int32  `json:"priority"`
+}
+
+// Summary 是分析结果的汇总统计
+type Summary struct {
+	TotalPods         int   `json:"totalPods"`
+	HealthyPods       int   `json:"healthyPods"`
+	WarningPods       int   `json:"warningPods"`
+	ErrorPods         int   `json:"errorPods"`
+	PendingPods       int   `json:"pendingPods"`
+	TotalRestarts     int32 `json:"totalRestarts"`
+	ConfigIssueCount  int   `json:"configIssueCount"`
+	RunningOnECICount int   `json:"runningOnECICount"`
+	HasECIConfigCount int   `json:"hasECIConfigCount"`
+
+	// AdmissionFailures 按节点统计被 kubelet 准入拒绝的 Pod：节点 -> 原因 -> 数量
+	AdmissionFailures map[string]map[string]int `json:"admissionFailures,omitempty"`
+	// PressuredNodes 是处于压力状态的节点：节点 -> 压力条件
+	PressuredNodes map[string][]string `json:"pressuredNodes,omitempty"`
+	// Containers 按当前状态汇总所有容器
+	Containers ContainerSummary `json:"containers"`
+}
+
+// ContainerSummary 是容器按当前状态的汇总
+type ContainerSummary struct {
+	Running    int `json:"running"`
+	Waiting    int `json:"waiting"`
+	Terminated int `json:"terminated"`
+	// WaitingReasons 是 waiting 容器的原因分布：原因 -> 数量
+	WaitingReasons map[string]int `json:"waitingReasons,omitempty"`
+}
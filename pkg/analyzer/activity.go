@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SilentFailure 判断 Pod 是否处于 Error/Unknown 且没有可用的原因
+// 例如节点在创建 Pod 的过程中重启，Pod 变为 Failed，却没有 status.reason 和容器终止信息
+func SilentFailure(pod *corev1.Pod) bool {
+	switch pod.Status.Phase {
+	case corev1.PodFailed:
+		return getFailedReason(pod) == "Failed"
+	case corev1.PodUnknown:
+		return pod.Status.Reason == ""
+	}
+	return false
+}
+
+// eventTimestamp 返回事件最后一次发生的时间
+// 旧版 API 填写 lastTimestamp，events.k8s.io 写入的事件只有 eventTime 或 series
+func eventTimestamp(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	case !event.FirstTimestamp.IsZero():
+		return event.FirstTimestamp.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// lastEvent 返回最近发生的事件，没有事件时返回 nil
+func lastEvent(events []corev1.Event) (*corev1.Event, time.Time) {
+	var (
+		last   *corev1.Event
+		lastAt time.Time
+	)
+	for i := range events {
+		if at := eventTimestamp(&events[i]); last == nil || at.After(lastAt) {
+			last, lastAt = &events[i], at
+		}
+	}
+	return last, lastAt
+}
+
+// applyLastActivity 为没有原因的 Error/Unknown Pod 附加最近一次事件的时间和原因，
+// 如 "Failed, last activity 6h ago: NodeNotReady"，用于区分新发生的故障和早已遗留的 Pod
+func applyLastActivity(analysis *PodAnalysis, pod *corev1.Pod, data *ClusterData, format func(time.Time) string) {
+	if !SilentFailure(pod) {
+		return
+	}
+	event, at := lastEvent(data.podEvents(pod.Namespace, pod.Name))
+	if event == nil {
+		return
+	}
+	analysis.Reason += ", last activity " + formatAgo(format, at) + ": " + event.Reason
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// AddonRule 描述如何识别一个集群核心组件
+// Pod 在指定命名空间且匹配任意一个 Selector 即视为属于该组件，Namespace 为空时匹配所有命名空间
+type AddonRule struct {
+	Name      string       `json:"name"`
+	Namespace string       `json:"namespace,omitempty"`
+	Selectors []labels.Set `json:"selectors"`
+}
+
+// DefaultAddons 是内置的集群核心组件识别规则
+var DefaultAddons = []AddonRule{
+	{
+		Name:      "CoreDNS",
+		Namespace: "kube-system",
+		Selectors: []labels.Set{{"k8s-app": "kube-dns"}, {"k8s-app": "coredns"}},
+	},
+	{
+		Name:      "kube-proxy",
+		Namespace: "kube-system",
+		Selectors: []labels.Set{{"k8s-app": "kube-proxy"}},
+	},
+	{
+		Name:      "CNI",
+		Namespace: "kube-system",
+		Selectors: []labels.Set{
+			{"app": "terway-eniip"},
+			{"app": "terway"},
+			{"app": "flannel"},
+			{"k8s-app": "calico-node"},
+			{"k8s-app": "cilium"},
+		},
+	},
+	{
+		Name:      "metrics-server",
+		Namespace: "kube-system",
+		Selectors: []labels.Set{{"k8s-app": "metrics-server"}},
+	},
+}
+
+// MergeAddonRules 合并内置规则和自定义规则，自定义规则排在前面，同名的自定义规则覆盖内置规则
+func MergeAddonRules(builtin, custom []AddonRule) []AddonRule {
+	overridden := make(map[string]bool)
+	for _, rule := range custom {
+		overridden[rule.Name] = true
+	}
+
+	merged := append([]AddonRule(nil), custom...)
+	for _, rule := range builtin {
+		if !overridden[rule.Name] {
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// ValidateAddonRules 校验规则，错误信息中包含出错规则的位置和名称
+func ValidateAddonRules(rules []AddonRule) error {
+	seen := make(map[string]bool)
+	for i, rule := range rules {
+		entry := fmt.Sprintf("addons[%d]", i)
+		if rule.Name == "" {
+			return fmt.Errorf("%s: name is required", entry)
+		}
+		entry = fmt.Sprintf("%s (%q)", entry, rule.Name)
+		if seen[rule.Name] {
+			return fmt.Errorf("%s: duplicate addon name", entry)
+		}
+		seen[rule.Name] = true
+
+		if len(rule.Selectors) == 0 {
+			return fmt.Errorf("%s: at least one selector is required", entry)
+		}
+		for j, selector := range rule.Selectors {
+			// 空的 selector 会匹配命名空间中的所有 Pod
+			if len(selector) == 0 {
+				return fmt.Errorf("%s: selectors[%d] must not be empty", entry, j)
+			}
+			if _, err := labels.ValidatedSelectorFromSet(selector); err != nil {
+				return fmt.Errorf("%s: selectors[%d]: %v", entry, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AddonStatus 是单个核心组件的健康状况
+type AddonStatus struct {
+	Name    string
+	Healthy int
+	Total   int
+}
+
+// OK 判断组件的所有 Pod 是否都健康
+func (s AddonStatus) OK() bool {
+	return s.Total > 0 && s.Healthy == s.Total
+}
+
+// AnalyzeAddons 使用常规的 Pod 分析逻辑评估集群核心组件
+// 集群中找不到 Pod 的组件会被忽略（可能没有安装）
+func AnalyzeAddons(pods *corev1.PodList, rules []AddonRule) []AddonStatus {
+	var statuses []AddonStatus
+	for _, rule := range rules {
+		status := AddonStatus{Name: rule.Name}
+		for i := range pods.Items {
+			pod := &pods.Items[i]
+			if !rule.matches(pod) {
+				continue
+			}
+			status.Total++
+			if analyzeSinglePod(pod, Options{}, nil).Status == StatusHealthy {
+				status.Healthy++
+			}
+		}
+		if status.Total > 0 {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// matches 判断 Pod 是否属于该组件
+func (r AddonRule) matches(pod *corev1.Pod) bool {
+	if r.Namespace != "" && pod.Namespace != r.Namespace {
+		return false
+	}
+	for _, selector := range r.Selectors {
+		if labels.SelectorFromSet(selector).Matches(labels.Set(pod.Labels)) {
+			return true
+		}
+	}
+	return false
+}
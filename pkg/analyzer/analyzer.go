@@ -2,10 +2,20 @@ package analyzer
 
 import (
 	"fmt"
+	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // PodStatus 表示 Pod 的状态分类
@@ -26,18 +36,118 @@ const (
 	IssueMissingRequests ConfigIssue = "Missing resource requests"
 	IssueMissingLimits   ConfigIssue = "Missing resource limits"
 	IssueNoProbe         ConfigIssue = "Missing health probe"
+
+	IssueNoTerminationMessage ConfigIssue = "Restarted container left no termination message (consider terminationMessagePolicy: FallbackToLogsOnError)"
+
+	// IssueNoTerminationMessagePolicy 是不看重启情况的静态检查：默认的 File 策略下，只写 stdout 的程序退出时不会留下终止消息
+	IssueNoTerminationMessagePolicy ConfigIssue = "Container does not configure terminationMessagePolicy: FallbackToLogsOnError"
+
+	IssueAllReplicasSameZone ConfigIssue = "All replicas in same availability zone"
+	IssueSelectorMismatch    ConfigIssue = "Pod labels no longer match owner's selector"
+	IssueNoPDB               ConfigIssue = "No PodDisruptionBudget covers this workload"
+	IssueMissingKataOverhead ConfigIssue = "Kata Containers pod missing overhead annotation"
+	IssueHighSeverityCVE     ConfigIssue = "Container has HIGH/CRITICAL CVEs"
+	IssueImageDrift          ConfigIssue = "Running image differs from Deployment spec"
+	IssuePodFinalizer        ConfigIssue = "Pod has custom finalizer"
+
+	IssueMissingDefaultContainer ConfigIssue = "Pod has multiple containers but no kubectl.kubernetes.io/default-container annotation"
+	IssueNameTooLong             ConfigIssue = "Pod name may exceed DNS limits in some integrations"
+	IssueTemplateHashMismatch    ConfigIssue = "pod-template-hash label mismatch with owning ReplicaSet"
+
+	// stdin/tty 是交互式调试用的设置，通常是 kubectl run -it 试验后遗留在生产 spec 中的
+	IssueStdinEnabled ConfigIssue = "Container has stdin: true (debug flag in production?)"
+	IssueTTYEnabled   ConfigIssue = "Container has tty: true (debug flag in production?)"
+)
+
+// standardPodFinalizers 是 Kubernetes 自身会添加的 finalizer，由内置控制器负责移除
+var standardPodFinalizers = map[string]bool{
+	"kubernetes.io/pvc-protection":     true,
+	"batch.kubernetes.io/job-tracking": true,
+	metav1.FinalizerOrphanDependents:   true,
+	metav1.FinalizerDeleteDependents:   true,
+}
+
+// Is 判断问题是否属于指定的问题类型
+// 部分问题会在类型之后附带细节（如容器名称），因此按前缀匹配
+func (i ConfigIssue) Is(base ConfigIssue) bool {
+	return strings.HasPrefix(string(i), string(base))
+}
+
+// withDetail 为问题附加细节，如 "Missing resource limits (init container: setup)"
+func withDetail(base ConfigIssue, detail string) ConfigIssue {
+	return ConfigIssue(fmt.Sprintf("%s (%s)", base, detail))
+}
+
+// Severity 表示配置问题的严重程度
+type Severity int
+
+const (
+	SeverityLow Severity = iota
+	SeverityMedium
+	SeverityHigh
 )
 
+// Severity 返回问题的严重程度，未单独定级的问题为 SeverityMedium
+func (i ConfigIssue) Severity() Severity {
+	switch {
+	case i.Is(IssueRunsAsRoot):
+		return SeverityHigh
+	case i.Is(IssueReplicasPinnedSameNode):
+		return SeverityHigh
+	case i.Is(IssueRootNotPrevented), i.Is(IssueHostPathReadOnly), i.Is(IssueDaemonSetAfterCordon), i.Is(IssueNoPodFailurePolicy),
+		i.Is(IssueServicePortUnverified), i.Is(IssueOverProvisionedCPU), i.Is(IssueOverProvisionedMemory):
+		return SeverityLow
+	default:
+		return SeverityMedium
+	}
+}
+
+// Now 返回分析时使用的当前时间，demo 模式下替换为固定时钟以得到稳定的输出
+var Now = time.Now
+
 // ECI 相关的标签和注解
 const (
 	// 阿里云 ECI Pod 的标识
-	ECINodeLabelKey    = "type"
-	ECINodeLabelValue  = "virtual-kubelet"
-	ECIPodAnnotation   = "k8s.aliyun.com/eci-instance-id"
-	ECINodeNamePrefix  = "virtual-kubelet"
-	VirtualKubeletType = "virtual-kubelet"
+	ECINodeLabelKey       = "type"
+	ECINodeLabelValue     = "virtual-kubelet"
+	ECIPodAnnotation      = "k8s.aliyun.com/eci-instance-id"
+	ECIZoneAnnotation     = "k8s.aliyun.com/eci-zone-id"
+	ECIVSwitchAnnotation  = "k8s.aliyun.com/eci-vswitch"
+	ECISpecAnnotation     = "k8s.aliyun.com/eci-instance-spec"
+	ECIUseSpecsAnnotation = "k8s.aliyun.com/eci-use-specs"
+	ECINodeNamePrefix     = "virtual-kubelet"
+	VirtualKubeletType    = "virtual-kubelet"
+)
+
+// 名称长度限制：DNS label 最长 63 个字符，Pod 名称预留 10 个字符给服务网格等添加的后缀
+const (
+	maxDNSLabelLen    = 63
+	maxSafePodNameLen = 53
 )
 
+// DefaultContainerAnnotation 标识多容器 Pod 中的主容器
+const DefaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// knownSidecarContainers 是常见的 sidecar 容器名称，选择主容器时会跳过它们
+var knownSidecarContainers = map[string]bool{
+	"istio-proxy":     true,
+	"linkerd-proxy":   true,
+	"envoy":           true,
+	"daprd":           true,
+	"vault-agent":     true,
+	"cloudsql-proxy":  true,
+	"cloud-sql-proxy": true,
+	"kube-rbac-proxy": true,
+	"oauth2-proxy":    true,
+	"config-reloader": true,
+	"jaeger-agent":    true,
+	"fluent-bit":      true,
+	"fluentd":         true,
+	"filebeat":        true,
+	"logtail":         true,
+	"aliyun-logtail":  true,
+}
+
 // PodAnalysis 包含单个 Pod 的分析结果
 type PodAnalysis struct {
 	Name          string
@@ -51,10 +161,52 @@ type PodAnalysis struct {
 	Reason        string        // 如果有问题，说明原因
 	ConfigIssues  []ConfigIssue // 配置问题列表
 	ContainerInfo []ContainerAnalysis
-	RunningOnECI  bool   // 是否实际运行在 ECI 节点上
-	HasECIConfig  bool   // 是否配置了 ECI 相关设置
-	ECIInstanceID string // ECI 实例 ID（如果有）
-	NodeName      string // 节点名称
+	RunningOnECI  bool      // 是否实际运行在 ECI 节点上
+	HasECIConfig  bool      // 是否配置了 ECI 相关设置
+	ECIInstanceID string    // ECI 实例 ID（如果有）
+	ECIZone       string    // ECI 实例所在可用区（eci-zone-id 注解）
+	ECIVSwitch    string    // ECI 实例使用的虚拟交换机（eci-vswitch 注解，可能是逗号分隔的多个）
+	ECISpec       string    // ECI 实例规格（eci-instance-spec 注解，没有时为 eci-use-specs）
+	Provider      string    // 匹配的虚拟节点识别规则名称，如 "eci"
+	NodeName      string    // 节点名称
+	Zone          string    // 节点所在可用区（仅在 --check-topology 时获取）
+	OwnerKind     string    // 所属工作负载类型，如 Deployment、StatefulSet
+	OwnerName     string    // 所属工作负载名称
+	MainContainer string    // 主容器名称（default-container 注解或第一个非 sidecar 容器）
+	ProblemSince  time.Time // 问题开始的大致时间，健康的 Pod 为零值
+	NominatedNode string    // 调度器为抢占提名的节点（仅 Pending Pod）
+
+	// RestartPolicy 是 Pod 生效的重启策略，未设置时为 Always
+	RestartPolicy corev1.RestartPolicy
+
+	// Raw 是按 Options.Raw 从源 Pod 复制的原始数据，未请求时为 nil
+	Raw *RawPod
+
+	// NodePressure 是所在节点当前生效的压力条件（仅在 --check-node-pressure 时获取）
+	NodePressure []string
+
+	// AdmissionFailure 表示 Pod 被 kubelet 准入拒绝（如 OutOfpods），只剩下 Failed 的空壳
+	AdmissionFailure bool
+
+	// MissingNodeLabel 是 Pending Pod 选择、但没有任何节点带有的标签，没有时为 nil
+	MissingNodeLabel *MissingNodeLabel
+
+	// DetailsOmitted 表示额外请求的配额已用完，该 Pod 的部分检查缺少数据
+	DetailsOmitted bool
+
+	// OwnerContact 是 Options.OwnerLabel 标签的值（Pod 优先，其次是命名空间），没有时为空
+	OwnerContact string
+
+	// SchedulingLatency 是从创建到调度成功的耗时，还未调度成功（或缺少时间）时为 -1
+	SchedulingLatency time.Duration
+
+	// EfficiencyScore 是 CPU 和内存用量占 requests 比例的平均值（1 表示用量等于 requests）
+	// 没有获取用量、Pod 没有用量数据或没有设置 requests 时为 -1
+	EfficiencyScore float64
+
+	// PDBDisruptionsAllowed 是覆盖该 Pod 的 PDB 当前允许的中断数
+	// 有多个 PDB 时取最小值，没有 PDB（或未启用 --check-pdb）时为 -1
+	PDBDisruptionsAllowed int
 }
 
 // ContainerAnalysis 包含容器级别的分析
@@ -66,6 +218,31 @@ type ContainerAnalysis struct {
 	HasRequests     bool
 	HasLimits       bool
 	HasProbe        bool
+
+	// TerminationMessage 是最近一次终止消息的第一行，通常就是 panic 信息
+	TerminationMessage string
+
+	// State 是容器当前状态：running、waiting 或 terminated，没有状态上报时为空
+	State string
+	// WaitingReason 是 waiting 状态的原因，如 ImagePullBackOff
+	WaitingReason string
+}
+
+// 容器当前状态
+const (
+	ContainerRunning    = "running"
+	ContainerWaiting    = "waiting"
+	ContainerTerminated = "terminated"
+)
+
+// ContainerSummary 按当前状态汇总所有容器，事故排查时比 Pod 数量更直观
+type ContainerSummary struct {
+	Running    int
+	Waiting    int
+	Terminated int
+
+	// WaitingReasons 是 waiting 容器的原因分布：原因 -> 数量
+	WaitingReasons map[string]int
 }
 
 // AnalysisResult 包含整体分析结果
@@ -80,6 +257,452 @@ type AnalysisResult struct {
 	ConfigIssueCount  int
 	RunningOnECICount int // 实际运行在 ECI 上的 Pod 数量
 	HasECIConfigCount int // 配置了 ECI 的 Pod 数量
+
+	// AdmissionFailures 按节点统计被 kubelet 准入拒绝的 Pod：节点 -> 原因 -> 数量
+	AdmissionFailures map[string]map[string]int
+
+	// Containers 按当前状态汇总所有 Pod 的容器
+	Containers ContainerSummary
+
+	// PressuredNodes 是承载被分析 Pod 且处于压力状态的节点：节点 -> 压力条件
+	PressuredNodes map[string][]string
+
+	// Preemptions 是正在抢占提名节点的 Pending Pod（仅在 --check-preemption 时计算）
+	Preemptions []Preemption
+
+	// Duplicates 是可能被遗忘的跨命名空间重复工作负载（仅在 --find-duplicates 时计算）
+	Duplicates []DuplicateWorkload
+
+	// RetainedPods 是各命名空间保留的已结束 Pod，按命名空间排序
+	RetainedPods []RetainedPods
+	// SkippedChecks 是因 API 不存在、没有权限或服务器版本过低而跳过的检查，说明结果不完整
+	SkippedChecks []SkippedCheck
+
+	// JobsWithoutTTL 是没有设置 ttlSecondsAfterFinished、保留了大量已结束 Pod 的 Job（需要 Job 数据）
+	JobsWithoutTTL []RetainingJob
+
+	// TerminatingNamespaces 是处于 Terminating 状态的命名空间，按名称排序
+	TerminatingNamespaces []TerminatingNamespace
+
+	// LivenessCascade 是多个工作负载在同一时间窗口内被存活探针重启的情况（仅在 --check-liveness-cascade 时计算），没有时为 nil
+	LivenessCascade *LivenessCascade
+
+	// QuotaRollouts 是剩余副本会耗尽命名空间配额的滚动更新（仅在 --check-quota 时计算）
+	QuotaRollouts []QuotaRollout
+
+	// Deploys 是每个 Deployment 最近一次发布的大致时间（仅在 --deploy-times 时计算），按命名空间和名称排序
+	Deploys []WorkloadDeploy
+
+	// Stats 是本次运行的统计信息，仅在 --stats 时由调用方填充
+	Stats *RunStats
+}
+
+// SkippedCheck 是一个被跳过的可选检查及原因，如 "PDB check" / "forbidden"
+type SkippedCheck struct {
+	Check  string
+	Reason string
+}
+
+// Options 控制分析行为
+type Options struct {
+	CheckConfig   bool // 检查资源配置和探针
+	CheckTopology bool // 检查副本的可用区分布
+	CheckPDB      bool // 检查工作负载是否有 PodDisruptionBudget 保护
+	CheckDNS      bool // 检查 DNS 策略和 ndots 配置（启发式）
+	CheckDrift    bool // 检查运行中的 Pod 是否偏离所属 Deployment 的声明
+
+	// CheckNetworkPolicy 检查 Pod 所在命名空间是否有拒绝所有入站/出站流量的 NetworkPolicy
+	CheckNetworkPolicy bool
+
+	// CheckConfigMaps/CheckSecrets 获取 Pod 引用的 ConfigMap/Secret 用于相关检查
+	CheckConfigMaps bool
+	CheckSecrets    bool
+
+	// CheckConfigHash 比较 checksum/config 注解与引用的 ConfigMap 内容
+	CheckConfigHash bool
+
+	// CheckRefs 检查 Pod 引用的 imagePullSecrets 是否已过期或即将过期
+	// PullSecretExpiryAnnotation 是记录凭证过期时间的注解，为空时只解析令牌本身
+	CheckRefs                  bool
+	PullSecretExpiryAnnotation string
+
+	// CheckPullSecretType 检查 Pod 引用的 imagePullSecrets 是否为 docker 凭证类型
+	CheckPullSecretType bool
+
+	// CheckPreemption 列出正在抢占节点的 Pending Pod 及提名节点上可能被驱逐的低优先级 Pod
+	CheckPreemption bool
+
+	// CheckStorage 检查 Pod 是否通过 hostPath 或 local PV 被固定在节点上
+	CheckStorage bool
+
+	// CheckCPUBurst 检查容器的 CPU limit/request 比例是否超过 MaxCPUBurstRatio（0 时使用 DefaultCPUBurstRatio）
+	CheckCPUBurst    bool
+	MaxCPUBurstRatio float64
+
+	// MaxAnnotationSize 是 --check-config 时 Pod 注解值总大小的上限（字节），0 时使用 DefaultMaxAnnotationSize
+	MaxAnnotationSize int
+
+	// StaleConditionThreshold 是 --check-config 时 Ready 条件没有变化的时长上限，0 时使用 DefaultStaleConditionThreshold
+	StaleConditionThreshold time.Duration
+
+	// CheckStaleImages 从镜像仓库查询 tag 的最新 digest，检查容器是否运行着旧镜像
+	CheckStaleImages bool
+
+	// CheckJobTTL 获取保留了大量已结束 Pod 的 Job，检查是否设置了 ttlSecondsAfterFinished
+	CheckJobTTL bool
+
+	// CheckJob 获取 Pod 所属的 Job，检查是否设置了 podFailurePolicy（需要 Kubernetes 1.26+）
+	CheckJob bool
+
+	// CheckAnnotationPolicy 检查 Pod 是否带有 CostAnnotation 指定的成本分摊注解
+	// CostAnnotation 为空时使用 DefaultCostAnnotation
+	CheckAnnotationPolicy bool
+	CostAnnotation        string
+
+	// CheckAutoscaler 检查 Pod 的 safe-to-evict 注解是否会影响 cluster autoscaler 缩容
+	CheckAutoscaler bool
+
+	// CheckSecurity 执行安全相关的检查（如通过环境变量使用 Secret）
+	CheckSecurity bool
+
+	// CheckStartupOrder 检查 Pod 通过环境变量依赖的 Service 是否有就绪的 endpoint
+	CheckStartupOrder bool
+
+	// CheckServicePorts 检查选中 Pod 的 Service 的 targetPort 是否对应 Pod 声明的容器端口
+	CheckServicePorts bool
+
+	// CheckNodeUnschedulable 检查 Pod 所在节点是否已被 cordon
+	CheckNodeUnschedulable bool
+
+	// CheckNodePressure 将节点的内存/磁盘/PID 压力反映到 Pod 上，节点是否被 cordon 由 CheckNodeUnschedulable 检查
+	CheckNodePressure bool
+
+	// CheckVulnerabilities 根据 Trivy operator 的扫描报告检查高危漏洞
+	CheckVulnerabilities bool
+
+	// CheckWebhooks 检查删除 Pod 是否会经过校验 DELETE 的 ValidatingWebhook
+	CheckWebhooks bool
+
+	// CheckGrace 检查容器能否在终止时收到 SIGTERM 并优雅退出（如 shell 作为 PID 1）
+	CheckGrace bool
+
+	// CheckProbes 检查多容器 Pod 中是否有多个容器的探针探测同一个端口
+	CheckProbes bool
+
+	// RequiredLabels 是 Pod 必须带有的组织标签（如 team、oncall），Pod 上没有时读取所在命名空间的标签
+	RequiredLabels []string
+	// OwnerLabel 是记录负责团队的标签，非空时填充 PodAnalysis.OwnerContact
+	OwnerLabel string
+
+	// CheckLivenessCascade 根据 Killing 事件检查多个工作负载是否在同一时间窗口内被存活探针重启
+	CheckLivenessCascade bool
+
+	// DeployTimes 根据 pod-template-hash 估算每个 Deployment 最近一次发布的时间，并与问题开始的时间比较
+	DeployTimes bool
+
+	// CheckInitTimeout 检查 Pending 的 Pod 是否在 init 容器阶段停留超过 InitTimeout（0 时使用 DefaultInitTimeout）
+	CheckInitTimeout bool
+	InitTimeout      time.Duration
+
+	// CheckSchedulingTimeout 检查 Pod 从创建到调度成功是否超过 SchedulingTimeout（0 时使用 DefaultSchedulingTimeout）
+	CheckSchedulingTimeout bool
+	SchedulingTimeout      time.Duration
+
+	// Metrics 从 metrics-server 获取 Pod 的当前用量，用于计算 PodAnalysis.EfficiencyScore
+	Metrics bool
+
+	// CheckResourceEfficiency 检查运行中 Pod 的 CPU/内存用量是否低于 requests 的 EfficiencyThreshold（0 时使用 DefaultEfficiencyThreshold）
+	// 需要 Metrics 获取的用量
+	CheckResourceEfficiency bool
+	EfficiencyThreshold     float64
+
+	// CheckPSP 检查是否有 PodSecurityPolicy 允许 Pod 的 spec（Kubernetes 1.25 起已移除 PodSecurityPolicy）
+	CheckPSP bool
+
+	// CheckQuota 检查进行中的 Deployment 滚动更新是否会在完成前耗尽命名空间的 ResourceQuota
+	CheckQuota bool
+
+	// Providers 是虚拟节点识别规则，为 nil 时使用 DefaultProviderRules
+	Providers []ProviderRule
+
+	// FindDuplicates 找出在多个命名空间中名称或镜像相同的工作负载，用于 -A 模式
+	FindDuplicates bool
+	// DuplicateExclusions 是重复检测的排除规则，为 nil 时使用 DefaultDuplicateExclusions
+	DuplicateExclusions *DuplicateExclusions
+
+	// NaturalAge 使用自然语言格式化 AGE/RUNNING 列，如 "2 minutes"
+	NaturalAge bool
+
+	// EnabledChecks/DisabledChecks 按名称启用或关闭 Check（见 CheckNames），同时出现时关闭优先
+	// 依赖集群数据的内置检查按名称启用时，先调用 ApplyCheckNames 打开对应的开关再获取数据
+	EnabledChecks  []string
+	DisabledChecks []string
+
+	// Raw 选择附加到每个 Pod 结果中的原始数据，供 JSON/YAML 输出使用
+	Raw RawOptions
+
+	// Workers 是并发分析 Pod 的 goroutine 数，0 时使用 GOMAXPROCS，1 时在调用方的 goroutine 中逐个分析
+	// 检查和规则会被并发调用，必须可以安全地并发执行
+	Workers int
+
+	// Cluster 提供分析所需的额外集群对象，未启用相关检查时可为 nil
+	Cluster *ClusterData
+}
+
+// providerRules 返回生效的虚拟节点识别规则
+func (o Options) providerRules() []ProviderRule {
+	if o.Providers == nil {
+		return DefaultProviderRules
+	}
+	return o.Providers
+}
+
+// workers 返回实际使用的并发数
+func (o Options) workers() int {
+	if o.Workers > 0 {
+		return o.Workers
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// duplicateExclusions 返回实际使用的重复检测排除规则
+func (o Options) duplicateExclusions() DuplicateExclusions {
+	if o.DuplicateExclusions == nil {
+		return DefaultDuplicateExclusions
+	}
+	return *o.DuplicateExclusions
+}
+
+// costAnnotation 返回要求的成本分摊注解，未设置时使用默认值
+func (o Options) costAnnotation() string {
+	if o.CostAnnotation == "" {
+		return DefaultCostAnnotation
+	}
+	return o.CostAnnotation
+}
+
+// formatTime 返回 AGE/RUNNING 列使用的时间格式化函数
+func (o Options) formatTime() func(time.Time) string {
+	if o.NaturalAge {
+		return formatAgeNatural
+	}
+	return formatAge
+}
+
+// formatElapsed 返回格式化经过时长的函数，结果接在 "for" 后面，如 "12s"、"3h0m"，--age-natural 时如 "3 hours"
+func (o Options) formatElapsed() func(time.Time) string {
+	return func(t time.Time) string {
+		elapsed := Now().Sub(t)
+		if o.NaturalAge && elapsed >= 5*time.Second {
+			return formatAgeNatural(t)
+		}
+		return formatDuration(elapsed)
+	}
+}
+
+// ClusterData 保存由调用方预先获取的集群对象
+type ClusterData struct {
+	Nodes       map[string]*corev1.Node                  // 按节点名称索引
+	ReplicaSets map[string]*appsv1.ReplicaSet            // 按 namespace/name 索引
+	Deployments map[string]*appsv1.Deployment            // 按 namespace/name 索引
+	ConfigMaps  map[string]*corev1.ConfigMap             // 按 namespace/name 索引
+	Secrets     map[string]*corev1.Secret                // 按 namespace/name 索引
+	Endpoints   map[string]*corev1.Endpoints             // 按 namespace/name 索引
+	PVCs        map[string]*corev1.PersistentVolumeClaim // 按 namespace/name 索引
+	PVs         map[string]*corev1.PersistentVolume      // 按名称索引
+	PDBs        []policyv1.PodDisruptionBudget
+
+	// ResourceQuotas 是被分析命名空间的 ResourceQuota（仅在 --check-quota 时获取）
+	ResourceQuotas []corev1.ResourceQuota
+
+	// NetworkPolicies 是被分析 Pod 所在命名空间的 NetworkPolicy（仅在 --check-network-policy 时获取），为 nil 表示未获取
+	NetworkPolicies []networkingv1.NetworkPolicy
+
+	// Services 是被分析 Pod 所在命名空间的 Service（仅在 --check-service-ports 时获取），为 nil 表示未获取
+	Services []corev1.Service
+
+	// PodSecurityPolicies 是集群中的 PodSecurityPolicy（仅在 --check-psp 且 Kubernetes 1.25 之前获取），为 nil 表示未获取
+	PodSecurityPolicies []PodSecurityPolicy
+
+	// PodUsage 是 metrics-server 报告的各容器当前用量，按 namespace/name 和容器名称索引（仅在 --metrics 时获取），为 nil 表示未获取
+	PodUsage map[string]map[string]corev1.ResourceList
+
+	// ClusterNodes 是集群中的全部节点，仅在有 Pending Pod 按标签选择节点时获取，为 nil 表示未获取
+	ClusterNodes []corev1.Node
+
+	// PodEvents 是 Pod 的事件，按 namespace/name 索引
+	// 在 --check-preemption 时获取，没有原因的 Error/Unknown Pod 也会获取（见 SilentFailure），
+	// --check-storage 时获取卡在卷操作的 Pod 的事件（见 StuckOnVolumes）
+	PodEvents map[string][]corev1.Event
+	// NodePods 是节点上的所有 Pod（跨命名空间），按节点名称索引
+	NodePods map[string][]corev1.Pod
+
+	// Vulnerabilities 是每个容器的 HIGH/CRITICAL 漏洞数量，按 namespace/pod/container 索引
+	Vulnerabilities map[string]int64
+
+	// RemoteDigests 是镜像仓库中 tag 当前的 digest，按 Pod spec 中的镜像索引（仅在 --check-stale-images 时获取）
+	RemoteDigests map[string]string
+
+	// Jobs 按 namespace/name 索引：--cleanup-plan 时获取保留了大量已结束 Pod 的 Job，--check-job 时获取 Pod 所属的 Job
+	Jobs map[string]*batchv1.Job
+
+	// DetailsOmitted 是因额外请求配额用完而缺少部分数据的 Pod，按 namespace/name 索引
+	DetailsOmitted map[string]bool
+
+	// Namespaces 是被分析 Pod 所在的命名空间，按名称索引
+	Namespaces map[string]*corev1.Namespace
+
+	// KillingEvents 是被分析命名空间中原因为 Killing 的事件（仅在 --check-liveness-cascade 时获取）
+	KillingEvents []corev1.Event
+
+	// ValidatingWebhooks 是集群中的 ValidatingWebhookConfiguration（仅在 --check-webhook 时获取）
+	ValidatingWebhooks []admissionregistrationv1.ValidatingWebhookConfiguration
+}
+
+// namespace 返回指定名称的 Namespace，不存在时返回 nil
+func (d *ClusterData) namespace(name string) *corev1.Namespace {
+	if d == nil || d.Namespaces == nil {
+		return nil
+	}
+	return d.Namespaces[name]
+}
+
+// node 返回指定名称的 Node，不存在时返回 nil
+func (d *ClusterData) node(name string) *corev1.Node {
+	if d == nil || d.Nodes == nil {
+		return nil
+	}
+	return d.Nodes[name]
+}
+
+// replicaSet 返回指定的 ReplicaSet，不存在时返回 nil
+func (d *ClusterData) replicaSet(namespace, name string) *appsv1.ReplicaSet {
+	if d == nil || d.ReplicaSets == nil {
+		return nil
+	}
+	return d.ReplicaSets[namespace+"/"+name]
+}
+
+// deployment 返回指定的 Deployment，不存在时返回 nil
+func (d *ClusterData) deployment(namespace, name string) *appsv1.Deployment {
+	if d == nil || d.Deployments == nil {
+		return nil
+	}
+	return d.Deployments[namespace+"/"+name]
+}
+
+// configMap 返回指定的 ConfigMap，不存在时返回 nil
+func (d *ClusterData) configMap(namespace, name string) *corev1.ConfigMap {
+	if d == nil || d.ConfigMaps == nil {
+		return nil
+	}
+	return d.ConfigMaps[namespace+"/"+name]
+}
+
+// secret 返回指定的 Secret，不存在时返回 nil
+func (d *ClusterData) secret(namespace, name string) *corev1.Secret {
+	if d == nil || d.Secrets == nil {
+		return nil
+	}
+	return d.Secrets[namespace+"/"+name]
+}
+
+// endpoints 返回指定 Service 的 Endpoints，不存在时返回 nil
+func (d *ClusterData) endpoints(namespace, name string) *corev1.Endpoints {
+	if d == nil || d.Endpoints == nil {
+		return nil
+	}
+	return d.Endpoints[namespace+"/"+name]
+}
+
+// persistentVolumeClaim 返回指定的 PVC，不存在时返回 nil
+func (d *ClusterData) persistentVolumeClaim(namespace, name string) *corev1.PersistentVolumeClaim {
+	if d == nil || d.PVCs == nil {
+		return nil
+	}
+	return d.PVCs[namespace+"/"+name]
+}
+
+// persistentVolume 返回指定的 PV，不存在时返回 nil
+func (d *ClusterData) persistentVolume(name string) *corev1.PersistentVolume {
+	if d == nil || d.PVs == nil {
+		return nil
+	}
+	return d.PVs[name]
+}
+
+// podEvents 返回指定 Pod 的事件
+func (d *ClusterData) podEvents(namespace, name string) []corev1.Event {
+	if d == nil || d.PodEvents == nil {
+		return nil
+	}
+	return d.PodEvents[namespace+"/"+name]
+}
+
+// nodePods 返回指定节点上的 Pod
+func (d *ClusterData) nodePods(name string) []corev1.Pod {
+	if d == nil || d.NodePods == nil {
+		return nil
+	}
+	return d.NodePods[name]
+}
+
+// remoteDigest 返回镜像在镜像仓库中的最新 digest，未获取时返回空字符串
+func (d *ClusterData) remoteDigest(image string) string {
+	if d == nil || d.RemoteDigests == nil {
+		return ""
+	}
+	return d.RemoteDigests[image]
+}
+
+// job 返回指定的 Job，不存在时返回 nil
+func (d *ClusterData) job(namespace, name string) *batchv1.Job {
+	if d == nil || d.Jobs == nil {
+		return nil
+	}
+	return d.Jobs[namespace+"/"+name]
+}
+
+// podUsage 返回 Pod 中各容器的当前用量，没有获取用量时返回 nil，获取了但没有该 Pod 时返回空表
+func (d *ClusterData) podUsage(pod *corev1.Pod) map[string]corev1.ResourceList {
+	if d == nil || d.PodUsage == nil {
+		return nil
+	}
+	if usage, ok := d.PodUsage[pod.Namespace+"/"+pod.Name]; ok {
+		return usage
+	}
+	return map[string]corev1.ResourceList{}
+}
+
+// detailsOmitted 判断 Pod 是否因请求配额用完而缺少部分数据
+func (d *ClusterData) detailsOmitted(namespace, name string) bool {
+	if d == nil || d.DetailsOmitted == nil {
+		return false
+	}
+	return d.DetailsOmitted[namespace+"/"+name]
+}
+
+// matchingPDBs 返回 selector 匹配该 Pod 的 PodDisruptionBudget
+func (d *ClusterData) matchingPDBs(pod *corev1.Pod) []*policyv1.PodDisruptionBudget {
+	if d == nil {
+		return nil
+	}
+
+	var matched []*policyv1.PodDisruptionBudget
+	for i := range d.PDBs {
+		pdb := &d.PDBs[i]
+		if pdb.Namespace != pod.Namespace || pdb.Spec.Selector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil {
+			continue
+		}
+		if selector.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pdb)
+		}
+	}
+	return matched
 }
 
 // HasIssues 检查是否有任何问题
@@ -87,17 +710,69 @@ func (r *AnalysisResult) HasIssues() bool {
 	return r.ErrorPods > 0 || r.WarningPods > 0 || r.ConfigIssueCount > 0
 }
 
+// add 将一个 Pod 的容器计入汇总
+func (s *ContainerSummary) add(containers []ContainerAnalysis) {
+	for _, c := range containers {
+		switch c.State {
+		case ContainerRunning:
+			s.Running++
+		case ContainerTerminated:
+			s.Terminated++
+		case ContainerWaiting:
+			s.Waiting++
+			reason := c.WaitingReason
+			if reason == "" {
+				reason = "Unknown"
+			}
+			if s.WaitingReasons == nil {
+				s.WaitingReasons = make(map[string]int)
+			}
+			s.WaitingReasons[reason]++
+		}
+	}
+}
+
 // AnalyzePods 分析 Pod 列表
-func AnalyzePods(pods *corev1.PodList, checkConfig bool) *AnalysisResult {
+func AnalyzePods(pods *corev1.PodList, opts Options) *AnalysisResult {
 	result := &AnalysisResult{
 		Pods:      make([]PodAnalysis, 0, len(pods.Items)),
 		TotalPods: len(pods.Items),
 	}
 
-	for _, pod := range pods.Items {
-		analysis := analyzeSinglePod(&pod, checkConfig)
-		result.Pods = append(result.Pods, analysis)
+	result.Pods = analyzePodList(pods.Items, opts, newCheckRunner(opts))
+
+	// 跨 Pod 的检查需要在所有 Pod 分析完成后进行
+	if opts.CheckTopology {
+		checkZoneSpread(result.Pods)
+	}
+	if opts.CheckStorage {
+		checkLocalStoragePinning(result.Pods)
+	}
+
+	if opts.CheckNodePressure {
+		result.PressuredNodes = pressuredNodes(result.Pods)
+	}
+	if opts.CheckLivenessCascade {
+		result.LivenessCascade = findLivenessCascade(pods, opts.Cluster)
+	}
+	if opts.CheckQuota {
+		result.QuotaRollouts = findQuotaRollouts(opts.Cluster)
+	}
+	if opts.CheckPreemption {
+		result.Preemptions = findPreemptions(pods, opts.Cluster)
+		markPreemptionVictims(result.Pods, result.Preemptions)
+	}
+	if opts.FindDuplicates {
+		result.Duplicates = findDuplicateWorkloads(pods, opts.duplicateExclusions(), opts.formatTime())
+	}
+	if opts.DeployTimes {
+		result.Deploys = findDeploys(pods, result.Pods, opts.formatTime())
+	}
+	result.RetainedPods = retainedPods(pods)
+	result.JobsWithoutTTL = jobsWithoutTTL(pods, opts.Cluster)
+	result.TerminatingNamespaces = terminatingNamespaces(opts.Cluster, opts.formatElapsed())
 
+	for _, analysis := range result.Pods {
 		// 更新统计
 		result.TotalRestarts += analysis.Restarts
 		if analysis.RunningOnECI {
@@ -117,26 +792,96 @@ func AnalyzePods(pods *corev1.PodList, checkConfig bool) *AnalysisResult {
 			result.PendingPods++
 		}
 		result.ConfigIssueCount += len(analysis.ConfigIssues)
+		result.Containers.add(analysis.ContainerInfo)
+
+		if analysis.AdmissionFailure {
+			if result.AdmissionFailures == nil {
+				result.AdmissionFailures = make(map[string]map[string]int)
+			}
+			if result.AdmissionFailures[analysis.NodeName] == nil {
+				result.AdmissionFailures[analysis.NodeName] = make(map[string]int)
+			}
+			result.AdmissionFailures[analysis.NodeName][analysis.Reason]++
+		}
 	}
 
 	return result
 }
 
-// analyzeSinglePod 分析单个 Pod
-func analyzeSinglePod(pod *corev1.Pod, checkConfig bool) PodAnalysis {
+// analyzePodList 用 opts.workers() 个 goroutine 分析 Pod，结果与输入顺序一致
+// 每个 goroutine 只写入自己领取的下标，统计在合并后单线程完成，因此不需要加锁
+func analyzePodList(pods []corev1.Pod, opts Options, checks *checkRunner) []PodAnalysis {
+	results := make([]PodAnalysis, len(pods))
+	workers := min(opts.workers(), len(pods))
+	if workers <= 1 {
+		for i := range pods {
+			results[i] = analyzeSinglePod(&pods[i], opts, checks)
+		}
+		return results
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(pods) {
+					return
+				}
+				results[i] = analyzeSinglePod(&pods[i], opts, checks)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// analyzeSinglePod 分析单个 Pod，配置问题由 checks 检测（为 nil 时不检测）
+func analyzeSinglePod(pod *corev1.Pod, opts Options, checks *checkRunner) PodAnalysis {
+	formatTime := opts.formatTime()
 	analysis := PodAnalysis{
 		Name:      pod.Name,
 		Namespace: pod.Namespace,
 		Phase:     pod.Status.Phase,
-		Age:       formatAge(pod.CreationTimestamp.Time),
+		Age:       formatTime(pod.CreationTimestamp.Time),
 		NodeName:  pod.Spec.NodeName,
+
+		NominatedNode: NominatedNode(pod),
+		RestartPolicy: restartPolicy(pod),
+
+		PDBDisruptionsAllowed: -1,
+	}
+
+	analysis.OwnerKind, analysis.OwnerName = ResolveOwner(pod)
+	analysis.MainContainer = mainContainer(pod)
+	analysis.Raw = rawPod(pod, opts.Raw)
+	if opts.OwnerLabel != "" {
+		analysis.OwnerContact = PodLabel(pod, opts.Cluster.namespace(pod.Namespace), opts.OwnerLabel)
 	}
 
 	// 检测 ECI 状态：区分实际运行位置和配置
-	analysis.RunningOnECI, analysis.HasECIConfig, analysis.ECIInstanceID = detectECI(pod)
+	providers := opts.providerRules()
+	node := opts.Cluster.node(pod.Spec.NodeName)
+	analysis.RunningOnECI, analysis.HasECIConfig, analysis.ECIInstanceID = detectECI(pod, node, providers)
+	analysis.Provider, _ = matchProvider(pod, node, providers)
+	if analysis.RunningOnECI || analysis.HasECIConfig {
+		analysis.ECIZone, analysis.ECIVSwitch, analysis.ECISpec = eciPlacement(pod)
+	}
+
+	if opts.CheckTopology {
+		analysis.Zone = podZone(pod, opts.Cluster, providers)
+	}
 
 	// 计算运行时间（从容器实际开始运行算起）
-	analysis.RunningTime = calculateRunningTime(pod)
+	analysis.RunningTime = calculateRunningTime(pod, formatTime)
+	analysis.SchedulingLatency = -1
+	if latency, ok := SchedulingLatency(pod); ok {
+		analysis.SchedulingLatency = latency
+	}
+	analysis.EfficiencyScore = efficiencyScore(pod, opts.Cluster.podUsage(pod))
 
 	// 分析容器状态
 	readyCount := 0
@@ -144,64 +889,187 @@ func analyzeSinglePod(pod *corev1.Pod, checkConfig bool) PodAnalysis {
 	var totalRestarts int32 = 0
 
 	for i, container := range pod.Spec.Containers {
-		containerAnalysis := analyzeContainer(&container, pod, i, checkConfig)
+		containerAnalysis := analyzeContainer(&container, pod, i, opts.CheckConfig)
 		analysis.ContainerInfo = append(analysis.ContainerInfo, containerAnalysis)
 
 		if containerAnalysis.Ready {
 			readyCount++
 		}
 		totalRestarts += containerAnalysis.RestartCount
+	}
 
-		// 收集配置问题
-		if checkConfig {
-			if !containerAnalysis.HasRequests {
-				analysis.ConfigIssues = appendIfNotExists(analysis.ConfigIssues, IssueMissingRequests)
-			}
-			if !containerAnalysis.HasLimits {
-				analysis.ConfigIssues = appendIfNotExists(analysis.ConfigIssues, IssueMissingLimits)
-			}
-			if !containerAnalysis.HasProbe {
-				analysis.ConfigIssues = appendIfNotExists(analysis.ConfigIssues, IssueNoProbe)
+	if opts.CheckPDB {
+		for _, pdb := range opts.Cluster.matchingPDBs(pod) {
+			allowed := int(pdb.Status.DisruptionsAllowed)
+			if analysis.PDBDisruptionsAllowed < 0 || allowed < analysis.PDBDisruptionsAllowed {
+				analysis.PDBDisruptionsAllowed = allowed
 			}
 		}
 	}
 
+	// 收集配置问题
+	analysis.ConfigIssues = checks.Run(pod)
+
 	analysis.Ready = fmt.Sprintf("%d/%d", readyCount, totalCount)
 	analysis.Restarts = totalRestarts
 
 	// 确定整体状态
 	analysis.Status, analysis.Reason = determinePodStatus(pod, readyCount, totalCount, totalRestarts)
+	if analysis.Status != StatusHealthy {
+		analysis.ProblemSince = problemSince(pod)
+	}
+	applyLastActivity(&analysis, pod, opts.Cluster, opts.formatTime())
+	applyMissingNodeLabel(&analysis, pod, opts.Cluster)
+	applyNamespaceTerminating(&analysis, opts.Cluster.namespace(pod.Namespace))
+	analysis.AdmissionFailure = isAdmissionFailure(pod)
+	analysis.DetailsOmitted = opts.Cluster.detailsOmitted(pod.Namespace, pod.Name)
+
+	if opts.CheckNodePressure {
+		applyNodePressure(&analysis, pod, opts.Cluster.node(pod.Spec.NodeName))
+	}
 
 	return analysis
 }
 
+// problemSince 估算 Pod 问题开始的时间
+// 优先使用 Ready condition 变为 False 的时间，否则使用 Pod 创建时间
+func problemSince(pod *corev1.Pod) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status != corev1.ConditionTrue && !cond.LastTransitionTime.IsZero() {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return pod.CreationTimestamp.Time
+}
+
+// ResolveOwner 解析 Pod 所属的工作负载
+// ReplicaSet 创建的 Pod 会根据 pod-template-hash 标签还原出 Deployment 名称
+func ResolveOwner(pod *corev1.Pod) (kind, name string) {
+	for _, ref := range pod.OwnerReferences {
+		if ref.Controller == nil || !*ref.Controller {
+			continue
+		}
+		if ref.Kind == "ReplicaSet" {
+			if hash := pod.Labels["pod-template-hash"]; hash != "" && strings.HasSuffix(ref.Name, "-"+hash) {
+				return "Deployment", strings.TrimSuffix(ref.Name, "-"+hash)
+			}
+		}
+		return ref.Kind, ref.Name
+	}
+	return "", ""
+}
+
+// mainContainer 确定 Pod 的主容器
+// 优先使用 default-container 注解，否则选择第一个非 sidecar 容器
+func mainContainer(pod *corev1.Pod) string {
+	if name := pod.Annotations[DefaultContainerAnnotation]; name != "" {
+		for _, c := range pod.Spec.Containers {
+			if c.Name == name {
+				return name
+			}
+		}
+	}
+
+	for _, c := range pod.Spec.Containers {
+		if !knownSidecarContainers[c.Name] {
+			return c.Name
+		}
+	}
+
+	if len(pod.Spec.Containers) > 0 {
+		return pod.Spec.Containers[0].Name
+	}
+	return ""
+}
+
+// containerStatusesMainFirst 返回主容器排在最前面的容器状态列表
+// 这样在多个容器都有问题时，主容器的状态决定 REASON
+func containerStatusesMainFirst(pod *corev1.Pod) []corev1.ContainerStatus {
+	main := mainContainer(pod)
+	statuses := make([]corev1.ContainerStatus, 0, len(pod.Status.ContainerStatuses))
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == main {
+			statuses = append(statuses, cs)
+		}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != main {
+			statuses = append(statuses, cs)
+		}
+	}
+	return statuses
+}
+
+// isKataRuntime 判断 Pod 是否使用 Kata Containers 运行时
+func isKataRuntime(pod *corev1.Pod) bool {
+	if pod.Spec.RuntimeClassName == nil {
+		return false
+	}
+	name := *pod.Spec.RuntimeClassName
+	return name == "kata" || strings.HasPrefix(name, "kata-")
+}
+
+// ControllerReplicaSet 返回控制该 Pod 的 ReplicaSet 名称，没有时返回空字符串
+func ControllerReplicaSet(pod *corev1.Pod) string {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil || ref.Kind != "ReplicaSet" {
+		return ""
+	}
+	return ref.Name
+}
+
+// selectorMismatch 检查 Pod 的标签是否仍满足所属 ReplicaSet 的 selector
+func selectorMismatch(pod *corev1.Pod, data *ClusterData) bool {
+	rsName := ControllerReplicaSet(pod)
+	if rsName == "" {
+		return false
+	}
+	rs := data.replicaSet(pod.Namespace, rsName)
+	if rs == nil || rs.Spec.Selector == nil {
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(rs.Spec.Selector)
+	if err != nil {
+		return false
+	}
+	return !selector.Matches(labels.Set(pod.Labels))
+}
+
+// checkTemplateHash 检查 pod-template-hash 标签是否与控制该 Pod 的 ReplicaSet 名称后缀一致
+// Deployment 创建的 ReplicaSet 名称为 <deployment>-<hash>，标签被改坏后 Pod 会被 ReplicaSet 孤立；
+// 没有该标签的 Pod（如直接创建的 ReplicaSet）不检查
+func checkTemplateHash(pod *corev1.Pod) []Finding {
+	rsName := ControllerReplicaSet(pod)
+	hash, ok := pod.Labels["pod-template-hash"]
+	if rsName == "" || !ok || strings.HasSuffix(rsName, "-"+hash) {
+		return nil
+	}
+	return []Finding{{Issue: IssueTemplateHashMismatch, Detail: fmt.Sprintf("label %q, ReplicaSet %s", hash, rsName)}}
+}
+
 // detectECI 检测 Pod 的 ECI 状态
+// 是否运行在虚拟节点上由 providers 规则判断，node 为 nil 时只使用 Pod 上的信息
 // 返回: (是否实际运行在ECI节点, 是否有ECI配置, ECI实例ID)
-func detectECI(pod *corev1.Pod) (runningOnECI bool, hasECIConfig bool, eciInstanceID string) {
+func detectECI(pod *corev1.Pod, node *corev1.Node, providers []ProviderRule) (runningOnECI bool, hasECIConfig bool, eciInstanceID string) {
 	// 1. 检查是否有 ECI 实例 ID（表示实际运行在 ECI 上）
 	if id, ok := pod.Annotations[ECIPodAnnotation]; ok && id != "" {
 		eciInstanceID = id
-		runningOnECI = true
 		hasECIConfig = true
-		return
 	}
 
-	// 2. 检查节点名称判断是否实际运行在 ECI 节点
-	// 阿里云 ECI 节点名称通常包含 virtual-kubelet 或 eci
-	nodeName := strings.ToLower(pod.Spec.NodeName)
-	if nodeName != "" {
-		if strings.Contains(nodeName, "virtual-kubelet") ||
-			strings.Contains(nodeName, "-eci-") ||
-			strings.HasPrefix(nodeName, "eci-") ||
-			strings.Contains(nodeName, "virtual-node") {
-			runningOnECI = true
-		}
+	// 2. 根据虚拟节点识别规则判断是否实际运行在虚拟节点上
+	if provider, _ := matchProvider(pod, node, providers); provider != "" {
+		runningOnECI = true
+	}
+	if hasECIConfig {
+		return
 	}
 
 	// 3. 检查是否有 ECI 相关配置（即使没有实际运行在 ECI 上）
 	eciConfigAnnotations := []string{
-		"k8s.aliyun.com/eci-use-specs",
-		"k8s.aliyun.com/eci-instance-spec",
+		ECIUseSpecsAnnotation,
+		ECISpecAnnotation,
 		"k8s.aliyun.com/eci-spot-strategy",
 		"k8s.aliyun.com/eci-spot-price-limit",
 		"k8s.aliyun.com/eci-with-eip",
@@ -243,7 +1111,7 @@ func detectECI(pod *corev1.Pod) (runningOnECI bool, hasECIConfig bool, eciInstan
 }
 
 // calculateRunningTime 计算 Pod 实际运行时间
-func calculateRunningTime(pod *corev1.Pod) string {
+func calculateRunningTime(pod *corev1.Pod, formatTime func(time.Time) string) string {
 	// 如果 Pod 不在 Running 状态，返回 "-"
 	if pod.Status.Phase != corev1.PodRunning {
 		return "-"
@@ -273,10 +1141,10 @@ func calculateRunningTime(pod *corev1.Pod) string {
 
 	// 如果还是没有，返回 Age
 	if earliestStart == nil {
-		return formatAge(pod.CreationTimestamp.Time)
+		return formatTime(pod.CreationTimestamp.Time)
 	}
 
-	return formatAge(*earliestStart)
+	return formatTime(*earliestStart)
 }
 
 // analyzeContainer 分析单个容器
@@ -295,6 +1163,21 @@ func analyzeContainer(container *corev1.Container, pod *corev1.Pod, index int, c
 			if cs.LastTerminationState.Terminated != nil {
 				term := cs.LastTerminationState.Terminated
 				analysis.LastTermination = fmt.Sprintf("%s (exit: %d)", term.Reason, term.ExitCode)
+				analysis.TerminationMessage = firstLine(term.Message)
+			}
+			// 当前处于终止状态时，以当前的终止消息为准
+			if cs.State.Terminated != nil && cs.State.Terminated.Message != "" {
+				analysis.TerminationMessage = firstLine(cs.State.Terminated.Message)
+			}
+
+			switch {
+			case cs.State.Running != nil:
+				analysis.State = ContainerRunning
+			case cs.State.Waiting != nil:
+				analysis.State = ContainerWaiting
+				analysis.WaitingReason = cs.State.Waiting.Reason
+			case cs.State.Terminated != nil:
+				analysis.State = ContainerTerminated
 			}
 			break
 		}
@@ -311,6 +1194,21 @@ func analyzeContainer(container *corev1.Container, pod *corev1.Pod, index int, c
 	return analysis
 }
 
+// QuickStatus 不做完整分析，只根据容器状态计算 Pod 的整体状态
+// 用于在获取额外数据前决定 Pod 的优先级
+func QuickStatus(pod *corev1.Pod) PodStatus {
+	readyCount := 0
+	var restarts int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Ready {
+			readyCount++
+		}
+		restarts += cs.RestartCount
+	}
+	status, _ := determinePodStatus(pod, readyCount, len(pod.Spec.Containers), restarts)
+	return status
+}
+
 // determinePodStatus 根据各种条件确定 Pod 状态
 func determinePodStatus(pod *corev1.Pod, readyCount, totalCount int, restarts int32) (PodStatus, string) {
 	// 检查 Pod Phase
@@ -331,12 +1229,12 @@ func determinePodStatus(pod *corev1.Pod, readyCount, totalCount int, restarts in
 	}
 
 	// 检查重启次数
-	if restarts > 10 {
+	if restarts > HighRestartThreshold {
 		return StatusWarning, fmt.Sprintf("High restart count: %d", restarts)
 	}
 
 	// 检查是否有异常的容器状态
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range containerStatusesMainFirst(pod) {
 		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
 			return StatusWarning, cs.State.Waiting.Reason
 		}
@@ -355,7 +1253,7 @@ func getPendingReason(pod *corev1.Pod) string {
 	}
 
 	// 检查容器状态
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range containerStatusesMainFirst(pod) {
 		if cs.State.Waiting != nil {
 			return cs.State.Waiting.Reason
 		}
@@ -380,7 +1278,7 @@ func getFailedReason(pod *corev1.Pod) string {
 		return pod.Status.Reason
 	}
 
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range containerStatusesMainFirst(pod) {
 		if cs.State.Terminated != nil {
 			return fmt.Sprintf("%s (exit: %d)", cs.State.Terminated.Reason, cs.State.Terminated.ExitCode)
 		}
@@ -389,11 +1287,21 @@ func getFailedReason(pod *corev1.Pod) string {
 	return "Failed"
 }
 
+// isAdmissionFailure 判断 Pod 是否在 kubelet 准入阶段被拒绝
+// 例如设备插件资源消失（UnexpectedAdmissionError）或节点容量不足（OutOfcpu/OutOfmemory/OutOfpods）
+func isAdmissionFailure(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodFailed {
+		return false
+	}
+	reason := pod.Status.Reason
+	return reason == "UnexpectedAdmissionError" || strings.HasPrefix(reason, "OutOf")
+}
+
 // getNotReadyReason 获取容器未就绪的原因
 func getNotReadyReason(pod *corev1.Pod) string {
 	var reasons []string
 
-	for _, cs := range pod.Status.ContainerStatuses {
+	for _, cs := range containerStatusesMainFirst(pod) {
 		if !cs.Ready {
 			if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
 				reasons = append(reasons, cs.State.Waiting.Reason)
@@ -410,9 +1318,29 @@ func getNotReadyReason(pod *corev1.Pod) string {
 }
 
 // formatAge 格式化时间为易读的 age 格式
+// 一分钟以内的时间给出更多细节："just now"（5 秒内）或 "12s ago"
 func formatAge(t time.Time) string {
-	duration := time.Since(t)
+	duration := Now().Sub(t)
+	if duration < 5*time.Second {
+		return "just now"
+	}
+	if duration < time.Minute {
+		return fmt.Sprintf("%ds ago", int(duration.Seconds()))
+	}
+	return formatDuration(duration)
+}
 
+// formatAgo 返回 "3h ago" 这样的相对时间，format 已经给出 "just now" 或 "12s ago" 时不再追加 " ago"
+func formatAgo(format func(time.Time) string, t time.Time) string {
+	s := format(t)
+	if s == "just now" || strings.HasSuffix(s, " ago") {
+		return s
+	}
+	return s + " ago"
+}
+
+// formatDuration 将时长格式化为紧凑形式，如 "2d5h"、"1h30m"、"45s"
+func formatDuration(duration time.Duration) string {
 	days := int(duration.Hours() / 24)
 	hours := int(duration.Hours()) % 24
 	minutes := int(duration.Minutes()) % 60
@@ -429,6 +1357,42 @@ func formatAge(t time.Time) string {
 	return fmt.Sprintf("%ds", int(duration.Seconds()))
 }
 
+// formatAgeNatural 使用自然语言格式化时间，如 "2 minutes"、"3 hours"、"2 days"
+// 适合演示和报告，只保留最大的时间单位
+func formatAgeNatural(t time.Time) string {
+	duration := Now().Sub(t)
+	switch {
+	case duration < 5*time.Second:
+		return "just now"
+	case duration < time.Minute:
+		return pluralize(int(duration.Seconds()), "second")
+	case duration < time.Hour:
+		return pluralize(int(duration.Minutes()), "minute")
+	case duration < 24*time.Hour:
+		return pluralize(int(duration.Hours()), "hour")
+	default:
+		return pluralize(int(duration.Hours()/24), "day")
+	}
+}
+
+// pluralize 返回 "1 day"、"2 days" 这样的数量短语
+func pluralize(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// firstLine 返回字符串中第一个非空行
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
 // appendIfNotExists 如果不存在则追加
 func appendIfNotExists(slice []ConfigIssue, item ConfigIssue) []ConfigIssue {
 	for _, existing := range slice {
@@ -0,0 +1,2827 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+// testNow 是测试使用的固定时钟
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func useTestClock(t testing.TB) {
+	t.Helper()
+	orig := Now
+	Now = func() time.Time { return testNow }
+	t.Cleanup(func() { Now = orig })
+}
+
+func ago(d time.Duration) metav1.Time {
+	return metav1.NewTime(testNow.Add(-d))
+}
+
+// 以下构造函数生成测试用的合成 Pod
+
+func runningPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         "default",
+			CreationTimestamp: ago(3 * time.Hour),
+		},
+		Spec: corev1.PodSpec{
+			NodeName: "node-1",
+			Containers: []corev1.Container{{
+				Name: "app",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+					Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")},
+				},
+				ReadinessProbe:           &corev1.Probe{},
+				TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			}},
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				Ready: true,
+				State: corev1.ContainerState{
+					Running: &corev1.ContainerStateRunning{StartedAt: ago(2 * time.Hour)},
+				},
+			}},
+		},
+	}
+}
+
+func crashingPod(name string) *corev1.Pod {
+	pod := runningPod(name)
+	pod.Status.ContainerStatuses[0] = corev1.ContainerStatus{
+		Name:         "app",
+		RestartCount: 14,
+		State: corev1.ContainerState{
+			Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+		},
+		LastTerminationState: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{
+				Reason:   "Error",
+				ExitCode: 1,
+				Message:  "panic: boom\ngoroutine 1 [running]:",
+			},
+		},
+	}
+	return pod
+}
+
+func oomKilledPod(name string) *corev1.Pod {
+	pod := runningPod(name)
+	pod.Status.ContainerStatuses[0].RestartCount = 3
+	pod.Status.ContainerStatuses[0].LastTerminationState = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+	}
+	return pod
+}
+
+func pendingPod(name string) *corev1.Pod {
+	pod := runningPod(name)
+	pod.Spec.NodeName = ""
+	pod.Status = corev1.PodStatus{
+		Phase: corev1.PodPending,
+		Conditions: []corev1.PodCondition{{
+			Type:    corev1.PodScheduled,
+			Status:  corev1.ConditionFalse,
+			Reason:  corev1.PodReasonUnschedulable,
+			Message: "0/3 nodes are available: 3 Insufficient memory.",
+		}},
+	}
+	return pod
+}
+
+func misconfiguredPod(name string) *corev1.Pod {
+	pod := runningPod(name)
+	pod.Spec.Containers[0].Resources = corev1.ResourceRequirements{}
+	pod.Spec.Containers[0].ReadinessProbe = nil
+	return pod
+}
+
+func TestAnalyzePods(t *testing.T) {
+	useTestClock(t)
+
+	tests := []struct {
+		name       string
+		pods       []*corev1.Pod
+		opts       Options
+		wantTotal  int
+		wantHealth int
+		wantWarn   int
+		wantErr    int
+		wantPend   int
+		wantIssues int
+		wantRestrt int32
+		hasIssues  bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:       "all healthy",
+			pods:       []*corev1.Pod{runningPod("a"), runningPod("b")},
+			opts:       Options{CheckConfig: true},
+			wantTotal:  2,
+			wantHealth: 2,
+		},
+		{
+			name:       "mixed statuses",
+			pods:       []*corev1.Pod{runningPod("a"), crashingPod("b"), pendingPod("c"), oomKilledPod("d")},
+			wantTotal:  4,
+			wantHealth: 2,
+			wantWarn:   1,
+			wantPend:   1,
+			wantRestrt: 17,
+			hasIssues:  true,
+		},
+		{
+			name:       "config issues only counted with check-config",
+			pods:       []*corev1.Pod{misconfiguredPod("a")},
+			wantTotal:  1,
+			wantHealth: 1,
+		},
+		{
+			name:       "config issues",
+			pods:       []*corev1.Pod{misconfiguredPod("a")},
+			opts:       Options{CheckConfig: true},
+			wantTotal:  1,
+			wantHealth: 1,
+			wantIssues: 3,
+			hasIssues:  true,
+		},
+		{
+			name: "failed pod",
+			pods: []*corev1.Pod{{
+				ObjectMeta: metav1.ObjectMeta{Name: "evicted", CreationTimestamp: ago(time.Hour)},
+				Status:     corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+			}},
+			wantTotal: 1,
+			wantErr:   1,
+			hasIssues: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &corev1.PodList{}
+			for _, pod := range tt.pods {
+				list.Items = append(list.Items, *pod)
+			}
+
+			result := AnalyzePods(list, tt.opts)
+
+			if len(result.Pods) != tt.wantTotal || result.TotalPods != tt.wantTotal {
+				t.Errorf("pods = %d, TotalPods = %d, want %d", len(result.Pods), result.TotalPods, tt.wantTotal)
+			}
+			if result.HealthyPods != tt.wantHealth || result.WarningPods != tt.wantWarn ||
+				result.ErrorPods != tt.wantErr || result.PendingPods != tt.wantPend {
+				t.Errorf("healthy/warning/error/pending = %d/%d/%d/%d, want %d/%d/%d/%d",
+					result.HealthyPods, result.WarningPods, result.ErrorPods, result.PendingPods,
+					tt.wantHealth, tt.wantWarn, tt.wantErr, tt.wantPend)
+			}
+			if result.ConfigIssueCount != tt.wantIssues {
+				t.Errorf("ConfigIssueCount = %d, want %d", result.ConfigIssueCount, tt.wantIssues)
+			}
+			if result.TotalRestarts != tt.wantRestrt {
+				t.Errorf("TotalRestarts = %d, want %d", result.TotalRestarts, tt.wantRestrt)
+			}
+			if got := result.HasIssues(); got != tt.hasIssues {
+				t.Errorf("HasIssues() = %v, want %v", got, tt.hasIssues)
+			}
+		})
+	}
+}
+
+func TestContainerSummary(t *testing.T) {
+	useTestClock(t)
+
+	terminated := runningPod("done")
+	terminated.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"},
+	}
+
+	list := &corev1.PodList{}
+	for _, pod := range []*corev1.Pod{runningPod("a"), crashingPod("b"), crashingPod("c"), pendingPod("d"), terminated} {
+		list.Items = append(list.Items, *pod)
+	}
+
+	got := AnalyzePods(list, Options{}).Containers
+	if got.Running != 1 || got.Waiting != 2 || got.Terminated != 1 {
+		t.Errorf("running/waiting/terminated = %d/%d/%d, want 1/2/1", got.Running, got.Waiting, got.Terminated)
+	}
+	if len(got.WaitingReasons) != 1 || got.WaitingReasons["CrashLoopBackOff"] != 2 {
+		t.Errorf("WaitingReasons = %v, want map[CrashLoopBackOff:2]", got.WaitingReasons)
+	}
+}
+
+// syntheticPods 轮流使用上面的构造函数生成 n 个 Pod，用于测试并发分析和基准测试
+func syntheticPods(n int) *corev1.PodList {
+	builders := []func(string) *corev1.Pod{runningPod, crashingPod, oomKilledPod, pendingPod, misconfiguredPod}
+	list := &corev1.PodList{Items: make([]corev1.Pod, 0, n)}
+	for i := range n {
+		pod := builders[i%len(builders)](fmt.Sprintf("pod-%05d", i))
+		pod.Namespace = fmt.Sprintf("team-%02d", i%40)
+		list.Items = append(list.Items, *pod)
+	}
+	return list
+}
+
+func TestAnalyzePodsParallel(t *testing.T) {
+	useTestClock(t)
+
+	pods := syntheticPods(2000)
+	sequential := AnalyzePods(pods, Options{CheckConfig: true, Workers: 1})
+	parallel := AnalyzePods(pods, Options{CheckConfig: true, Workers: 8})
+	if !reflect.DeepEqual(parallel, sequential) {
+		t.Fatal("parallel analysis differs from sequential analysis")
+	}
+	for i, pod := range parallel.Pods {
+		if pod.Name != pods.Items[i].Name {
+			t.Fatalf("Pods[%d] = %s, want %s: input order not preserved", i, pod.Name, pods.Items[i].Name)
+		}
+	}
+}
+
+// BenchmarkAnalyzePods 比较 20k 个 Pod 逐个分析与按 GOMAXPROCS 并发分析的耗时
+func BenchmarkAnalyzePods(b *testing.B) {
+	useTestClock(b)
+
+	pods := syntheticPods(20000)
+	for _, workers := range []int{1, 0} {
+		name := "sequential"
+		if workers == 0 {
+			name = fmt.Sprintf("workers=%d", runtime.GOMAXPROCS(0))
+		}
+		b.Run(name, func(b *testing.B) {
+			opts := Options{CheckConfig: true, Workers: workers}
+			for b.Loop() {
+				AnalyzePods(pods, opts)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSinglePod(t *testing.T) {
+	useTestClock(t)
+
+	tests := []struct {
+		name         string
+		pod          *corev1.Pod
+		opts         Options
+		wantStatus   PodStatus
+		wantReason   string
+		wantReady    string
+		wantRestarts int32
+		wantAge      string
+		wantRunning  string
+		wantIssues   []ConfigIssue
+		wantLastTerm string
+		wantMessage  string
+	}{
+		{
+			name:        "running",
+			pod:         runningPod("web"),
+			opts:        Options{CheckConfig: true},
+			wantStatus:  StatusHealthy,
+			wantReady:   "1/1",
+			wantAge:     "3h0m",
+			wantRunning: "2h0m",
+		},
+		{
+			name:         "crashing",
+			pod:          crashingPod("api"),
+			wantStatus:   StatusWarning,
+			wantReason:   "CrashLoopBackOff",
+			wantReady:    "0/1",
+			wantRestarts: 14,
+			wantAge:      "3h0m",
+			wantRunning:  "3h0m", // 没有运行中的容器时退回到 Age
+			wantLastTerm: "Error (exit: 1)",
+			wantMessage:  "panic: boom",
+		},
+		{
+			name:         "oom killed",
+			pod:          oomKilledPod("worker"),
+			wantStatus:   StatusHealthy,
+			wantReady:    "1/1",
+			wantRestarts: 3,
+			wantAge:      "3h0m",
+			wantRunning:  "2h0m",
+			wantLastTerm: "OOMKilled (exit: 137)",
+		},
+		{
+			name:        "pending",
+			pod:         pendingPod("job"),
+			wantStatus:  StatusPending,
+			wantReason:  "Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+			wantReady:   "0/1",
+			wantAge:     "3h0m",
+			wantRunning: "-",
+		},
+		{
+			name:        "config issues",
+			pod:         misconfiguredPod("bare"),
+			opts:        Options{CheckConfig: true},
+			wantStatus:  StatusHealthy,
+			wantReady:   "1/1",
+			wantAge:     "3h0m",
+			wantRunning: "2h0m",
+			wantIssues:  []ConfigIssue{IssueMissingRequests, IssueMissingLimits, IssueNoProbe},
+		},
+		{
+			name:        "natural age",
+			pod:         runningPod("web"),
+			opts:        Options{NaturalAge: true},
+			wantStatus:  StatusHealthy,
+			wantReady:   "1/1",
+			wantAge:     "3 hours",
+			wantRunning: "2 hours",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := analyzeSinglePod(tt.pod, tt.opts, newCheckRunner(tt.opts))
+
+			if got.Status != tt.wantStatus || got.Reason != tt.wantReason {
+				t.Errorf("status = %q (%q), want %q (%q)", got.Status, got.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if got.Ready != tt.wantReady {
+				t.Errorf("Ready = %q, want %q", got.Ready, tt.wantReady)
+			}
+			if got.Restarts != tt.wantRestarts {
+				t.Errorf("Restarts = %d, want %d", got.Restarts, tt.wantRestarts)
+			}
+			if got.Age != tt.wantAge || got.RunningTime != tt.wantRunning {
+				t.Errorf("Age/RunningTime = %q/%q, want %q/%q", got.Age, got.RunningTime, tt.wantAge, tt.wantRunning)
+			}
+			if len(got.ConfigIssues) != len(tt.wantIssues) {
+				t.Fatalf("ConfigIssues = %v, want %v", got.ConfigIssues, tt.wantIssues)
+			}
+			for i, issue := range tt.wantIssues {
+				if !got.ConfigIssues[i].Is(issue) {
+					t.Errorf("ConfigIssues[%d] = %q, want %q", i, got.ConfigIssues[i], issue)
+				}
+			}
+			if len(got.ContainerInfo) != 1 {
+				t.Fatalf("ContainerInfo has %d entries, want 1", len(got.ContainerInfo))
+			}
+			if c := got.ContainerInfo[0]; c.LastTermination != tt.wantLastTerm || c.TerminationMessage != tt.wantMessage {
+				t.Errorf("LastTermination/TerminationMessage = %q/%q, want %q/%q",
+					c.LastTermination, c.TerminationMessage, tt.wantLastTerm, tt.wantMessage)
+			}
+			if (tt.wantStatus == StatusHealthy) != got.ProblemSince.IsZero() {
+				t.Errorf("ProblemSince = %v for status %q", got.ProblemSince, got.Status)
+			}
+		})
+	}
+}
+
+func TestDetectECI(t *testing.T) {
+	virtualNode := &corev1.Node{ObjectMeta: metav1.ObjectMeta{
+		Name:   "vk-node-1",
+		Labels: map[string]string{ECINodeLabelKey: ECINodeLabelValue},
+	}}
+	labelRule := []ProviderRule{{Name: "eci", NodeLabels: map[string]string{ECINodeLabelKey: ECINodeLabelValue}}}
+
+	tests := []struct {
+		name        string
+		mutate      func(pod *corev1.Pod)
+		node        *corev1.Node
+		providers   []ProviderRule
+		wantRunning bool
+		wantConfig  bool
+		wantID      string
+	}{
+		{
+			name: "regular pod",
+		},
+		{
+			name: "instance id annotation",
+			mutate: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{ECIPodAnnotation: "eci-123"}
+			},
+			wantRunning: true,
+			wantConfig:  true,
+			wantID:      "eci-123",
+		},
+		{
+			name: "virtual node name",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.NodeName = "virtual-kubelet-cn-hangzhou-h"
+			},
+			wantRunning: true,
+		},
+		{
+			name: "virtual node label",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.NodeName = virtualNode.Name
+			},
+			node:        virtualNode,
+			providers:   labelRule,
+			wantRunning: true,
+		},
+		{
+			name: "virtual node label without node data",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.NodeName = virtualNode.Name
+			},
+			providers: labelRule,
+		},
+		{
+			name: "eci annotation on regular node",
+			mutate: func(pod *corev1.Pod) {
+				pod.Annotations = map[string]string{"k8s.aliyun.com/eci-use-specs": "2-4Gi"}
+			},
+			wantConfig: true,
+		},
+		{
+			name: "virtual-kubelet toleration",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.Tolerations = []corev1.Toleration{{Key: "virtual-kubelet.io/provider", Operator: corev1.TolerationOpExists}}
+			},
+			wantConfig: true,
+		},
+		{
+			name: "virtual-kubelet node selector",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"type": "virtual-kubelet"}
+			},
+			wantConfig: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := runningPod("p")
+			if tt.mutate != nil {
+				tt.mutate(pod)
+			}
+			providers := tt.providers
+			if providers == nil {
+				providers = DefaultProviderRules
+			}
+
+			running, config, id := detectECI(pod, tt.node, providers)
+			if running != tt.wantRunning || config != tt.wantConfig || id != tt.wantID {
+				t.Errorf("detectECI() = %v, %v, %q, want %v, %v, %q", running, config, id, tt.wantRunning, tt.wantConfig, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestCheckVirtualNodeZone(t *testing.T) {
+	pod := func(annotations map[string]string) *corev1.Pod {
+		p := runningPod("p")
+		p.Spec.NodeName = "virtual-kubelet-cn-hangzhou-h"
+		p.Annotations = annotations
+		p.Spec.TopologySpreadConstraints = []corev1.TopologySpreadConstraint{{TopologyKey: ZoneLabelKey, MaxSkew: 1}}
+		return p
+	}
+	labeled := &ClusterData{Nodes: map[string]*corev1.Node{"virtual-kubelet-cn-hangzhou-h": {
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{ZoneLabelKey: "cn-hangzhou-h"}},
+	}}}
+
+	if got, want := checkVirtualNodeZone(pod(nil), nil, DefaultProviderRules), []ConfigIssue{IssueVirtualNodeNoZone}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unlabeled node: issues = %v, want %v", got, want)
+	}
+	// eci-zone-id 注解不影响调度器的拓扑域，只放进细节
+	annotated := pod(map[string]string{ECIZoneAnnotation: "cn-hangzhou-b"})
+	if got, want := checkVirtualNodeZone(annotated, nil, DefaultProviderRules), []ConfigIssue{withDetail(IssueVirtualNodeNoZone, "instance zone cn-hangzhou-b")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("zone annotation on unlabeled node: issues = %v, want %v", got, want)
+	}
+	if got := checkVirtualNodeZone(annotated, labeled, DefaultProviderRules); got != nil {
+		t.Errorf("labeled node: issues = %v, want none", got)
+	}
+	noSpread := pod(nil)
+	noSpread.Spec.TopologySpreadConstraints = nil
+	if got := checkVirtualNodeZone(noSpread, nil, DefaultProviderRules); got != nil {
+		t.Errorf("no zone spread: issues = %v, want none", got)
+	}
+}
+
+func TestCalculateRunningTime(t *testing.T) {
+	useTestClock(t)
+
+	tests := []struct {
+		name string
+		pod  func() *corev1.Pod
+		want string
+	}{
+		{
+			name: "not running",
+			pod:  func() *corev1.Pod { return pendingPod("p") },
+			want: "-",
+		},
+		{
+			name: "earliest running container",
+			pod: func() *corev1.Pod {
+				pod := runningPod("p")
+				pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+					Name:  "sidecar",
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: ago(150 * time.Minute)}},
+				})
+				return pod
+			},
+			want: "2h30m",
+		},
+		{
+			name: "ready condition when no container is running",
+			pod: func() *corev1.Pod {
+				pod := crashingPod("p")
+				pod.Status.Conditions = []corev1.PodCondition{{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionTrue,
+					LastTransitionTime: ago(45 * time.Minute),
+				}}
+				return pod
+			},
+			want: "45m",
+		},
+		{
+			name: "falls back to age",
+			pod:  func() *corev1.Pod { return crashingPod("p") },
+			want: "3h0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := calculateRunningTime(tt.pod(), formatAge); got != tt.want {
+				t.Errorf("calculateRunningTime() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeterminePodStatus(t *testing.T) {
+	highRestarts := runningPod("p")
+	highRestarts.Status.ContainerStatuses[0].RestartCount = 11
+
+	waiting := runningPod("p")
+	waiting.Status.ContainerStatuses[0].State = corev1.ContainerState{
+		Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"},
+	}
+
+	notReady := runningPod("p")
+	notReady.Status.ContainerStatuses[0].Ready = false
+
+	unknown := runningPod("p")
+	unknown.Status.Phase = corev1.PodUnknown
+
+	failed := runningPod("p")
+	failed.Status.Phase = corev1.PodFailed
+	failed.Status.Reason = "Evicted"
+
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		ready      int
+		restarts   int32
+		wantStatus PodStatus
+		wantReason string
+	}{
+		{"healthy", runningPod("p"), 1, 0, StatusHealthy, ""},
+		{"pending", pendingPod("p"), 0, 0, StatusPending, "Unschedulable: 0/3 nodes are available: 3 Insufficient memory."},
+		{"failed", failed, 0, 0, StatusError, "Evicted"},
+		{"unknown", unknown, 0, 0, StatusUnknown, "Pod status unknown"},
+		{"crash loop", crashingPod("p"), 0, 14, StatusWarning, "CrashLoopBackOff"},
+		{"running but not ready", notReady, 0, 0, StatusWarning, "NotReady"},
+		{"high restart count", highRestarts, 1, 11, StatusWarning, "High restart count: 11"},
+		{"ready but waiting", waiting, 1, 0, StatusWarning, "ContainerCreating"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason := determinePodStatus(tt.pod, tt.ready, len(tt.pod.Spec.Containers), tt.restarts)
+			if status != tt.wantStatus || reason != tt.wantReason {
+				t.Errorf("determinePodStatus() = %q, %q, want %q, %q", status, reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetPendingReason(t *testing.T) {
+	pullBackOff := pendingPod("p")
+	pullBackOff.Status.Conditions = nil
+	pullBackOff.Status.ContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "app",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+	}}
+
+	initWaiting := pendingPod("p")
+	initWaiting.Status.Conditions = nil
+	initWaiting.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "migrate",
+		State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+	}}
+
+	initRunning := pendingPod("p")
+	initRunning.Status.Conditions = nil
+	initRunning.Status.InitContainerStatuses = []corev1.ContainerStatus{{
+		Name:  "migrate",
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	}}
+
+	bare := pendingPod("p")
+	bare.Status.Conditions = nil
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want string
+	}{
+		{"unschedulable", pendingPod("p"), "Unschedulable: 0/3 nodes are available: 3 Insufficient memory."},
+		{"container waiting", pullBackOff, "ImagePullBackOff"},
+		{"init container waiting", initWaiting, "Init:CrashLoopBackOff"},
+		{"init container running", initRunning, "Init:migrate running"},
+		{"no details", bare, "Pending"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getPendingReason(tt.pod); got != tt.want {
+				t.Errorf("getPendingReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFailedReason(t *testing.T) {
+	tests := []struct {
+		name   string
+		status corev1.PodStatus
+		want   string
+	}{
+		{
+			name:   "pod reason",
+			status: corev1.PodStatus{Reason: "Evicted"},
+			want:   "Evicted",
+		},
+		{
+			name: "terminated container",
+			status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+			}}},
+			want: "OOMKilled (exit: 137)",
+		},
+		{
+			name: "no details",
+			want: "Failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := runningPod("p")
+			pod.Status = tt.status
+			pod.Status.Phase = corev1.PodFailed
+			if got := getFailedReason(pod); got != tt.want {
+				t.Errorf("getFailedReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatAge(t *testing.T) {
+	useTestClock(t)
+
+	tests := []struct {
+		age  time.Duration
+		want string
+	}{
+		{0, "just now"},
+		{4 * time.Second, "just now"},
+		{12 * time.Second, "12s ago"},
+		{59 * time.Second, "59s ago"},
+		{time.Minute, "1m"},
+		{45 * time.Minute, "45m"},
+		{90 * time.Minute, "1h30m"},
+		{23*time.Hour + 59*time.Minute, "23h59m"},
+		{24 * time.Hour, "1d0h"},
+		{53 * time.Hour, "2d5h"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := formatAge(testNow.Add(-tt.age)); got != tt.want {
+				t.Errorf("formatAge(now - %v) = %q, want %q", tt.age, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckScheduledAfterCordon(t *testing.T) {
+	cordonedAt := metav1.NewTime(testNow.Add(-time.Hour))
+	labeledAt := metav1.NewTime(testNow.Add(-30 * time.Minute))
+	// 与 API server 返回的一致：节点控制器添加的污点没有 timeAdded，cordon 时间记录在 kubectl-cordon 的 managedFields 中
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node-1",
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{Manager: "kubectl-cordon", Operation: metav1.ManagedFieldsOperationUpdate, Time: &cordonedAt,
+					FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:unschedulable":{}}}`)}},
+				{Manager: "kube-controller-manager", Operation: metav1.ManagedFieldsOperationUpdate, Time: &labeledAt,
+					FieldsType: "FieldsV1", FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:taints":{}}}`)}},
+			},
+		},
+		Spec: corev1.NodeSpec{
+			Unschedulable: true,
+			Taints:        []corev1.Taint{{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}},
+		},
+	}
+	tolerateAll := []corev1.Toleration{{Operator: corev1.TolerationOpExists}}
+
+	tests := []struct {
+		name   string
+		mutate func(pod *corev1.Pod)
+		want   ConfigIssue
+	}{
+		{
+			name:   "created before cordon",
+			mutate: func(pod *corev1.Pod) { pod.CreationTimestamp = metav1.NewTime(testNow.Add(-2 * time.Hour)) },
+		},
+		{
+			name:   "created after cordon",
+			mutate: func(pod *corev1.Pod) { pod.Spec.Tolerations = tolerateAll },
+			want:   withDetail(IssueScheduledAfterCordon, "node node-1 cordoned at 2025-03-01T11:00:00Z, tolerates * Exists"),
+		},
+		{
+			name: "DaemonSet pod",
+			mutate: func(pod *corev1.Pod) {
+				controller := true
+				pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "agent", Controller: &controller}}
+				pod.Spec.Tolerations = []corev1.Toleration{{
+					Key:      corev1.TaintNodeUnschedulable,
+					Operator: corev1.TolerationOpExists,
+					Effect:   corev1.TaintEffectNoSchedule,
+				}}
+			},
+			want: withDetail(IssueDaemonSetAfterCordon, "node node-1 cordoned at 2025-03-01T11:00:00Z, tolerates node.kubernetes.io/unschedulable:NoSchedule Exists"),
+		},
+		{
+			name: "bound without toleration",
+			want: withDetail(IssueScheduledAfterCordon, "node node-1 cordoned at 2025-03-01T11:00:00Z, no toleration, nodeName set directly"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(testNow)},
+				Spec:       corev1.PodSpec{NodeName: node.Name},
+				Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+			}
+			if tt.mutate != nil {
+				tt.mutate(pod)
+			}
+			issues := checkScheduledAfterCordon(pod, node)
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Errorf("issues = %v, want none", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0] != tt.want {
+				t.Errorf("issues = %v, want [%s]", issues, tt.want)
+			}
+		})
+	}
+
+	// 没有 cordon 时间时无法判断
+	node.ManagedFields = nil
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", CreationTimestamp: metav1.NewTime(testNow)},
+		Spec:       corev1.PodSpec{NodeName: node.Name},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if issues := checkScheduledAfterCordon(pod, node); len(issues) != 0 {
+		t.Errorf("issues without a cordon time = %v, want none", issues)
+	}
+}
+
+func TestNodePressureDoesNotFlagCordon(t *testing.T) {
+	useTestClock(t)
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}, Spec: corev1.NodeSpec{Unschedulable: true}}
+	pod := runningPod("web")
+	pod.Spec.NodeName = node.Name
+	pods := &corev1.PodList{Items: []corev1.Pod{*pod}}
+	cluster := &ClusterData{Nodes: map[string]*corev1.Node{node.Name: node}}
+
+	// cordon 只由 --check-node-unschedulable 检查
+	result := AnalyzePods(pods, Options{CheckNodePressure: true, Cluster: cluster})
+	if issues := result.Pods[0].ConfigIssues; len(issues) != 0 {
+		t.Errorf("--check-node-pressure issues = %v, want none", issues)
+	}
+	result = AnalyzePods(pods, Options{CheckNodeUnschedulable: true, Cluster: cluster})
+	if issues := result.Pods[0].ConfigIssues; len(issues) != 1 || issues[0] != IssueNodeCordoned {
+		t.Errorf("--check-node-unschedulable issues = %v, want [%s]", issues, IssueNodeCordoned)
+	}
+}
+
+func TestCheckSequentialInitContainers(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	tests := []struct {
+		name  string
+		inits []corev1.Container
+		want  ConfigIssue
+	}{
+		{
+			name:  "single wait",
+			inits: []corev1.Container{{Name: "wait-for-db"}, {Name: "migrate"}},
+		},
+		{
+			name:  "two waits",
+			inits: []corev1.Container{{Name: "wait-for-db"}, {Name: "migrate"}, {Name: "check-cache"}},
+			want:  withDetail(IssueSequentialInitContainers, "wait-for-db, check-cache"),
+		},
+		{
+			name:  "sidecar ignored",
+			inits: []corev1.Container{{Name: "wait-for-db"}, {Name: "check-proxy", RestartPolicy: &always}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{InitContainers: tt.inits}}
+			issues := checkSequentialInitContainers(pod)
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Errorf("issues = %v, want none", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0] != tt.want {
+				t.Errorf("issues = %v, want [%s]", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSpecDrift(t *testing.T) {
+	declared := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+	}
+	deploy := &appsv1.Deployment{}
+	deploy.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Resources: declared}}
+
+	tests := []struct {
+		name    string
+		running corev1.ResourceRequirements
+		want    []ConfigIssue
+	}{
+		{
+			name: "same values, different notation",
+			running: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.1")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("256Mi")},
+			},
+		},
+		{
+			name: "webhook raised limit, LimitRange added request",
+			running: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+			want: []ConfigIssue{withDetail(IssueSpecDrift, "app: limits.memory 512Mi, template 256Mi")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{
+				{Name: "app", Resources: tt.running},
+				{Name: "injected-sidecar"},
+			}}}
+			got := checkSpecDrift(pod, deploy)
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issues[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckTemplateDrift(t *testing.T) {
+	template := corev1.Container{
+		Name:      "app",
+		Image:     "nginx:1.24",
+		Env:       []corev1.EnvVar{{Name: "LOG_LEVEL"}, {Name: "PORT"}},
+		Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}},
+	}
+	rs := &appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-7c79c4bf97", ResourceVersion: "42"}}
+	rs.Spec.Template.Spec.Containers = []corev1.Container{template}
+
+	tests := []struct {
+		name string
+		edit func(c *corev1.Container)
+		want []ConfigIssue
+	}{
+		{name: "unchanged", edit: func(c *corev1.Container) {}},
+		{
+			name: "equivalent image reference and env order",
+			edit: func(c *corev1.Container) {
+				c.Image = "docker.io/library/nginx:1.24"
+				c.Env = []corev1.EnvVar{{Name: "PORT"}, {Name: "LOG_LEVEL"}}
+				c.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("0.1")}
+			},
+		},
+		{
+			name: "live-edited image",
+			edit: func(c *corev1.Container) { c.Image = "nginx:1.25" },
+			want: []ConfigIssue{withDetail(IssueTemplateDrift, "app: image nginx:1.25, template nginx:1.24")},
+		},
+		{
+			// 运行中的 Pod 不能修改环境变量和资源，差异来自 LimitRange 或准入 webhook
+			name: "env and resources from admission defaults",
+			edit: func(c *corev1.Container) {
+				c.Env = []corev1.EnvVar{{Name: "DEBUG"}, {Name: "PORT"}}
+				c.Resources.Requests = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("200m")}
+			},
+		},
+	}
+
+	fingerprints := newTemplateFingerprints()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := *template.DeepCopy()
+			tt.edit(&app)
+			// 准入时注入的 sidecar 不在模板中，不参与比较
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: []corev1.Container{app, {Name: "istio-proxy", Image: "istio/proxyv2"}}}}
+			got, edited := checkTemplateDrift(pod, rs, fingerprints)
+			if len(edited) != len(tt.want) {
+				t.Errorf("edited = %v, want %d containers", edited, len(tt.want))
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issues[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+	if len(fingerprints.hashes) != 1 {
+		t.Errorf("template fingerprints computed %d times, want once per ReplicaSet", len(fingerprints.hashes))
+	}
+}
+
+func TestCheckImageDriftSkipsEdited(t *testing.T) {
+	deploy := &appsv1.Deployment{}
+	deploy.Spec.Template.Spec.Containers = []corev1.Container{{Name: "app", Image: "nginx:1.24"}}
+	pod := &corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Image: "nginx:1.25"}}}}
+
+	want := []ConfigIssue{withDetail(IssueImageDrift, "app: running nginx:1.25, spec nginx:1.24")}
+	if got := checkImageDrift(pod, deploy, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	// 已报告为直接修改的容器不重复报告
+	if got := checkImageDrift(pod, deploy, map[string]bool{"app": true}); got != nil {
+		t.Errorf("issues for an edited container = %v, want none", got)
+	}
+}
+
+func TestCheckAnnotationSize(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		limit       int
+		want        []ConfigIssue
+	}{
+		{name: "no annotations"},
+		{name: "at the default limit", annotations: map[string]string{"a": strings.Repeat("x", 6000), "b": strings.Repeat("y", 4240)}},
+		{
+			name: "sum over the default limit",
+			annotations: map[string]string{
+				"kubectl.kubernetes.io/last-applied-configuration": strings.Repeat("x", 8000),
+				"operator.example.com/state":                       strings.Repeat("y", 3000),
+			},
+			want: []ConfigIssue{withDetail(IssueAnnotationTooLarge, "11000 bytes, limit 10240; largest kubectl.kubernetes.io/last-applied-configuration (8000 bytes)")},
+		},
+		{
+			name:        "custom limit",
+			annotations: map[string]string{"b": "1234", "a": "5678"},
+			limit:       5,
+			want:        []ConfigIssue{withDetail(IssueAnnotationTooLarge, "8 bytes, limit 5; largest a (4 bytes)")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}}
+			got := checkAnnotationSize(pod, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issues[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckPodFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		restartPolicy corev1.RestartPolicy
+		policy        *batchv1.PodFailurePolicy
+		want          bool
+	}{
+		{name: "no policy", restartPolicy: corev1.RestartPolicyNever, want: true},
+		{
+			name:          "policy set",
+			restartPolicy: corev1.RestartPolicyNever,
+			policy: &batchv1.PodFailurePolicy{Rules: []batchv1.PodFailurePolicyRule{{
+				Action:          batchv1.PodFailurePolicyActionIgnore,
+				OnPodConditions: []batchv1.PodFailurePolicyOnPodConditionsPattern{{Type: corev1.DisruptionTarget}},
+			}}},
+		},
+		{name: "OnFailure cannot use a policy", restartPolicy: corev1.RestartPolicyOnFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "migrate"},
+				Spec: batchv1.JobSpec{
+					PodFailurePolicy: tt.policy,
+					Template:         corev1.PodTemplateSpec{Spec: corev1.PodSpec{RestartPolicy: tt.restartPolicy}},
+				},
+			}
+			got := checkPodFailurePolicy(job)
+			if !tt.want {
+				if len(got) != 0 {
+					t.Errorf("issues = %v, want none", got)
+				}
+				return
+			}
+			want := withDetail(IssueNoPodFailurePolicy, "job migrate")
+			if len(got) != 1 || got[0] != want {
+				t.Errorf("issues = %v, want [%q]", got, want)
+			}
+			if got[0].Severity() != SeverityLow {
+				t.Errorf("severity = %v, want SeverityLow", got[0].Severity())
+			}
+		})
+	}
+}
+
+func TestCheckBarePodRestarts(t *testing.T) {
+	controller := true
+	tests := []struct {
+		name   string
+		mutate func(pod *corev1.Pod)
+		want   bool
+	}{
+		{name: "bare pod restarting", want: true},
+		{
+			name:   "few restarts",
+			mutate: func(pod *corev1.Pod) { pod.Status.ContainerStatuses[0].RestartCount = 3 },
+		},
+		{
+			name:   "OnFailure policy",
+			mutate: func(pod *corev1.Pod) { pod.Spec.RestartPolicy = corev1.RestartPolicyOnFailure },
+		},
+		{
+			name: "managed by ReplicaSet",
+			mutate: func(pod *corev1.Pod) {
+				pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "web-abc", Controller: &controller}}
+			},
+		},
+		{
+			name:   "static pod",
+			mutate: func(pod *corev1.Pod) { pod.Annotations = map[string]string{corev1.MirrorPodAnnotationKey: "hash"} },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", RestartCount: 42}},
+			}}
+			if tt.mutate != nil {
+				tt.mutate(pod)
+			}
+			findings := checkBarePodRestarts(pod)
+			if got := len(findings) == 1; got != tt.want {
+				t.Errorf("findings = %v, want flagged %v", findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckUnboundPVC(t *testing.T) {
+	fast := "fast-ssd"
+	data := &ClusterData{PVCs: map[string]*corev1.PersistentVolumeClaim{
+		"default/data-pending": {
+			Spec:   corev1.PersistentVolumeClaimSpec{StorageClassName: &fast},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		"default/data-default": {
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		},
+		"default/data-bound": {
+			Spec:   corev1.PersistentVolumeClaimSpec{VolumeName: "pv-1"},
+			Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+		},
+	}}
+
+	tests := []struct {
+		claim string
+		want  []ConfigIssue
+	}{
+		{claim: "data-pending", want: []ConfigIssue{withDetail(IssueUnboundPVC, "data-pending, storage class: fast-ssd")}},
+		{claim: "data-default", want: []ConfigIssue{withDetail(IssueUnboundPVC, "data-default, storage class: <default>")}},
+		{claim: "data-bound"},
+		{claim: "missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.claim, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "db-0"},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+					Name:         "data",
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: tt.claim}},
+				}}},
+			}
+			got := checkLocalStorage(pod, data)
+			if len(got) != len(tt.want) || (len(got) == 1 && got[0] != tt.want[0]) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckVolumeOperations(t *testing.T) {
+	stuckPod := func() *corev1.Pod {
+		pod := runningPod("db-0")
+		pod.Spec.Volumes = []corev1.Volume{{
+			Name:         "data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data-db-0"}},
+		}}
+		pod.Status.Phase = corev1.PodPending
+		pod.Status.ContainerStatuses[0].Ready = false
+		pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ContainerCreating"}}
+		return pod
+	}
+	event := func(reason, message string) corev1.Event {
+		return corev1.Event{Reason: reason, Message: message}
+	}
+	attachLimit := event("FailedAttachVolume", `AttachVolume.Attach failed for volume "pvc-1" : rpc error: code = Internal desc = node node-1 exceed max volume count`)
+	fullNode := &corev1.Node{Status: corev1.NodeStatus{
+		Allocatable:     corev1.ResourceList{"attachable-volumes-aws-ebs": resource.MustParse("2"), corev1.ResourceCPU: resource.MustParse("4")},
+		VolumesAttached: []corev1.AttachedVolume{{Name: "kubernetes.io/aws-ebs/vol-1"}, {Name: "kubernetes.io/aws-ebs/vol-2"}},
+	}}
+	csiNode := &corev1.Node{Status: corev1.NodeStatus{VolumesAttached: []corev1.AttachedVolume{{Name: "kubernetes.io/csi/ebs.csi.aws.com^vol-1"}}}}
+
+	tests := []struct {
+		name   string
+		mutate func(*corev1.Pod)
+		events []corev1.Event
+		node   *corev1.Node
+		want   []ConfigIssue
+	}{
+		{
+			name:   "attach limit with allocatable",
+			events: []corev1.Event{attachLimit},
+			node:   fullNode,
+			want:   []ConfigIssue{withDetail(IssueVolumeAttachLimit, "node node-1: 2 attached, allocatable attachable-volumes-aws-ebs: 2")},
+		},
+		{
+			name:   "attach limit reported by CSINode",
+			events: []corev1.Event{attachLimit},
+			node:   csiNode,
+			want:   []ConfigIssue{withDetail(IssueVolumeAttachLimit, "node node-1: 1 attached, limit in CSINode node-1")},
+		},
+		{
+			name:   "attach limit without node data",
+			events: []corev1.Event{attachLimit, attachLimit},
+			want:   []ConfigIssue{withDetail(IssueVolumeAttachLimit, "node node-1")},
+		},
+		{
+			name:   "expansion pending",
+			events: []corev1.Event{event("FailedMount", `MountVolume.NodeExpandVolume failed for volume "pvc-1" : resize2fs failed`)},
+			want:   []ConfigIssue{withDetail(IssueVolumeExpansionPending, "node node-1")},
+		},
+		{
+			name:   "unrelated mount failure",
+			events: []corev1.Event{event("FailedMount", `MountVolume.SetUp failed for volume "config" : configmap "app" not found`)},
+		},
+		{
+			name:   "signature in another event reason",
+			events: []corev1.Event{event("FailedScheduling", "0/3 nodes are available: 3 node(s) exceed max volume count.")},
+		},
+		{
+			name:   "running pod is not stuck",
+			mutate: func(pod *corev1.Pod) { *pod = *runningPod("db-0") },
+			events: []corev1.Event{attachLimit},
+		},
+		{
+			name:   "pod without attachable volumes",
+			mutate: func(pod *corev1.Pod) { pod.Spec.Volumes = nil },
+			events: []corev1.Event{attachLimit},
+		},
+		{
+			name: "init containers waiting for volumes",
+			mutate: func(pod *corev1.Pod) {
+				initializing := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}
+				pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "migrate", State: initializing}}
+				pod.Status.ContainerStatuses[0].State = initializing
+			},
+			events: []corev1.Event{attachLimit},
+			want:   []ConfigIssue{withDetail(IssueVolumeAttachLimit, "node node-1")},
+		},
+		{
+			name: "init container already running",
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.InitContainers = []corev1.Container{{Name: "migrate"}}
+				pod.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "migrate", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}}
+				pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}
+			},
+			events: []corev1.Event{attachLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := stuckPod()
+			if tt.mutate != nil {
+				tt.mutate(pod)
+			}
+			data := &ClusterData{
+				PodEvents: map[string][]corev1.Event{"default/db-0": tt.events},
+				Nodes:     map[string]*corev1.Node{"node-1": tt.node},
+			}
+			if got := checkVolumeOperations(pod, data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRawPod(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			Labels:    map[string]string{"app": "web"},
+			Annotations: map[string]string{
+				"team.example.com/owner": "payments",
+				"team.example.com/notes": strings.Repeat("x", 20),
+				"other.io/ignored":       "yes",
+			},
+		},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+
+	if raw := rawPod(pod, RawOptions{}); raw != nil {
+		t.Errorf("rawPod without sections = %+v, want nil", raw)
+	}
+
+	raw := rawPod(pod, RawOptions{
+		Sections:            []string{RawConditions, RawAnnotations},
+		AnnotationPrefixes:  []string{"team.example.com/"},
+		MaxAnnotationLength: 10,
+	})
+	if raw.Labels != nil || raw.ContainerStatuses != nil {
+		t.Errorf("unrequested sections included: %+v", raw)
+	}
+	wantAnnotations := map[string]string{
+		"team.example.com/owner": "payments",
+		"team.example.com/notes": strings.Repeat("x", 10) + RawTruncatedMarker,
+	}
+	if len(raw.Annotations) != len(wantAnnotations) {
+		t.Fatalf("Annotations = %v, want %v", raw.Annotations, wantAnnotations)
+	}
+	for k, v := range wantAnnotations {
+		if raw.Annotations[k] != v {
+			t.Errorf("Annotations[%s] = %q, want %q", k, raw.Annotations[k], v)
+		}
+	}
+
+	v1 := raw.toV1()
+	if got := string(v1.Conditions); got != `[{"type":"Ready","status":"True","lastProbeTime":null,"lastTransitionTime":null}]` {
+		t.Errorf("v1 Conditions = %s", got)
+	}
+	if v1.ContainerStatuses != nil {
+		t.Errorf("v1 ContainerStatuses = %s, want omitted", v1.ContainerStatuses)
+	}
+
+	if err := ValidateRawSections([]string{"conditions", "spec"}); err == nil {
+		t.Error("ValidateRawSections(spec) = nil, want error")
+	}
+}
+
+func TestCheckInteractiveContainers(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "setup", Stdin: true}},
+		Containers: []corev1.Container{
+			{Name: "app", Stdin: true, TTY: true},
+			{Name: "sidecar"},
+		},
+		EphemeralContainers: []corev1.EphemeralContainer{{
+			EphemeralContainerCommon: corev1.EphemeralContainerCommon{Name: "debugger", Stdin: true, TTY: true},
+		}},
+	}}
+	want := []Finding{
+		{Issue: IssueStdinEnabled, Detail: "setup, app"},
+		{Issue: IssueTTYEnabled, Detail: "app"},
+	}
+	got := checkInteractiveContainers(pod)
+	if len(got) != len(want) {
+		t.Fatalf("findings = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("findings[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := checkInteractiveContainers(runningPod("web")); got != nil {
+		t.Errorf("findings for a non-interactive pod = %v, want none", got)
+	}
+}
+
+func TestCheckTerminationMessagePolicy(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		InitContainers: []corev1.Container{{Name: "migrate"}},
+		Containers: []corev1.Container{
+			{Name: "app", TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError},
+			{Name: "sidecar", TerminationMessagePolicy: corev1.TerminationMessageReadFile},
+		},
+	}}
+	want := Finding{Issue: IssueNoTerminationMessagePolicy, Detail: "migrate, sidecar"}
+	if got := checkTerminationMessagePolicy(pod); len(got) != 1 || got[0] != want {
+		t.Errorf("findings = %v, want [%v]", got, want)
+	}
+
+	if got := checkTerminationMessagePolicy(runningPod("web")); got != nil {
+		t.Errorf("findings for a pod with FallbackToLogsOnError = %v, want none", got)
+	}
+}
+
+func TestCheckSecurityContextConflict(t *testing.T) {
+	root, user, nonRoot := int64(0), int64(1000), true
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{RunAsUser: &root},
+		InitContainers: []corev1.Container{
+			{Name: "setup", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: &nonRoot}},
+		},
+		Containers: []corev1.Container{
+			{Name: "app", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: &nonRoot}},
+			{Name: "own-user", SecurityContext: &corev1.SecurityContext{RunAsUser: &user, RunAsNonRoot: &nonRoot}},
+			{Name: "sidecar"},
+		},
+	}}
+	want := Finding{Issue: IssueSecurityContextConflict, Detail: "setup, app"}
+	if got := checkSecurityContextConflict(pod); len(got) != 1 || got[0] != want {
+		t.Errorf("findings = %v, want [%v]", got, want)
+	}
+
+	pod.Spec.SecurityContext.RunAsUser = &user
+	if got := checkSecurityContextConflict(pod); got != nil {
+		t.Errorf("findings for a non-root pod = %v, want none", got)
+	}
+}
+
+func TestCheckPullSecretTypes(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: "api"},
+		Spec: corev1.PodSpec{ImagePullSecrets: []corev1.LocalObjectReference{
+			{Name: "registry"}, {Name: "legacy"}, {Name: "opaque"}, {Name: "untyped"}, {Name: "missing"},
+		}},
+	}
+	secret := func(name string, secretType corev1.SecretType) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "prod", Name: name}, Type: secretType}
+	}
+	data := &ClusterData{Secrets: map[string]*corev1.Secret{
+		"prod/registry": secret("registry", corev1.SecretTypeDockerConfigJson),
+		"prod/legacy":   secret("legacy", corev1.SecretTypeDockercfg),
+		"prod/opaque":   secret("opaque", corev1.SecretTypeOpaque),
+		"prod/untyped":  secret("untyped", ""),
+		"prod/missing":  nil,
+	}}
+
+	want := []ConfigIssue{
+		withDetail(IssueInvalidImagePullSecretType, "opaque: Opaque"),
+		withDetail(IssueInvalidImagePullSecretType, "untyped: Opaque"),
+	}
+	if got := checkPullSecretTypes(pod, data); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+}
+
+func TestCheckRequiredLabels(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Namespace: "payments",
+		Name:      "ledger",
+		Labels:    map[string]string{"oncall": "ledger-pager"},
+	}}
+	data := &ClusterData{Namespaces: map[string]*corev1.Namespace{
+		"payments": {ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+	}}
+	required := []string{"team", "oncall", "cost-center"}
+
+	want := []ConfigIssue{withDetail(IssueMissingRequiredLabels, "cost-center")}
+	if got := checkRequiredLabels(pod, data.namespace(pod.Namespace), required); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+
+	// 命名空间未获取到时只读取 Pod 的标签
+	want = []ConfigIssue{withDetail(IssueMissingRequiredLabels, "team, cost-center")}
+	if got := checkRequiredLabels(pod, nil, required); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues without namespace = %v, want %v", got, want)
+	}
+
+	pod.Labels["team"] = "ledger"
+	if got := PodLabel(pod, data.namespace("payments"), "team"); got != "ledger" {
+		t.Errorf("PodLabel = %q, want the pod label to take precedence", got)
+	}
+}
+
+func TestTerminatingNamespacesAge(t *testing.T) {
+	useTestClock(t)
+	namespace := func(deleted time.Duration) *ClusterData {
+		since := ago(deleted)
+		return &ClusterData{Namespaces: map[string]*corev1.Namespace{"legacy": {
+			ObjectMeta: metav1.ObjectMeta{Name: "legacy", DeletionTimestamp: &since},
+		}}}
+	}
+
+	tests := []struct {
+		name    string
+		deleted time.Duration
+		opts    Options
+		want    string
+	}{
+		{name: "seconds", deleted: 12 * time.Second, want: "12s"},
+		{name: "hours", deleted: 2 * time.Hour, want: "2h0m"},
+		{name: "natural", deleted: 2 * time.Hour, opts: Options{NaturalAge: true}, want: "2 hours"},
+		{name: "natural just started", deleted: 2 * time.Second, opts: Options{NaturalAge: true}, want: "2s"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := terminatingNamespaces(namespace(tt.deleted), tt.opts.formatElapsed())
+			if len(got) != 1 || got[0].Age != tt.want {
+				t.Errorf("terminatingNamespaces() = %+v, want age %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckStaleReadyCondition(t *testing.T) {
+	useTestClock(t)
+
+	pod := func(status corev1.ConditionStatus, since time.Duration) *corev1.Pod {
+		return &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+			{Type: corev1.PodReady, Status: status, LastTransitionTime: metav1.NewTime(testNow.Add(-since))},
+		}}}
+	}
+	day := 24 * time.Hour
+
+	want := []ConfigIssue{withDetail(IssueStaleReadyCondition, "unchanged for 120 days, threshold 90 days")}
+	if got := checkStaleReadyCondition(pod(corev1.ConditionTrue, 120*day), 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if got := checkStaleReadyCondition(pod(corev1.ConditionTrue, 30*day), 0); got != nil {
+		t.Errorf("issues for a recent transition = %v, want none", got)
+	}
+	want = []ConfigIssue{withDetail(IssueStaleReadyCondition, "unchanged for 30 days, threshold 7 days")}
+	if got := checkStaleReadyCondition(pod(corev1.ConditionTrue, 30*day), 7*day); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues with a 7d threshold = %v, want %v", got, want)
+	}
+	if got := checkStaleReadyCondition(pod(corev1.ConditionFalse, 120*day), 0); got != nil {
+		t.Errorf("issues for a not-ready pod = %v, want none", got)
+	}
+}
+
+func TestCheckDNSWithHostNetwork(t *testing.T) {
+	tests := []struct {
+		name         string
+		hostNetwork  bool
+		policy       corev1.DNSPolicy
+		want         bool
+		wantOverride bool
+	}{
+		{"host network with ClusterFirst", true, corev1.DNSClusterFirst, true, false},
+		{"host network with unset policy", true, "", true, false},
+		// 按建议设置的 ClusterFirstWithHostNet 不算覆盖
+		{"host network with ClusterFirstWithHostNet", true, corev1.DNSClusterFirstWithHostNet, false, false},
+		{"pod network with ClusterFirst", false, corev1.DNSClusterFirst, false, false},
+		{"pod network with ClusterFirstWithHostNet", false, corev1.DNSClusterFirstWithHostNet, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{HostNetwork: tt.hostNetwork, DNSPolicy: tt.policy}}
+			got, override := false, false
+			for _, issue := range checkDNS(pod) {
+				got = got || issue.Is(IssueDNSWithHostNetwork)
+				override = override || issue.Is(IssueDNSOverride)
+			}
+			if got != tt.want || override != tt.wantOverride {
+				t.Errorf("flagged = %v, override = %v, want %v, %v (issues %v)", got, override, tt.want, tt.wantOverride, checkDNS(pod))
+			}
+		})
+	}
+}
+
+func TestEnvHostnames(t *testing.T) {
+	values := []string{
+		"https://api.stripe.com/v1",
+		"db.payments.svc.cluster.local:5432",
+		"cache.payments:6379", // 同一命名空间的 name.namespace
+		"redis.shared",        // 其他命名空间的 name.namespace
+		"orders.shared.svc",
+		"com.example.Main", // Java 类名
+		"example.internal",
+		"pypi.org.", // 绝对域名不经过 search 域
+		"10.0.0.1:8080",
+		"hooks.slack.com",
+	}
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "payments"}}
+	container := corev1.Container{Name: "app"}
+	for i, v := range values {
+		container.Env = append(container.Env, corev1.EnvVar{Name: fmt.Sprintf("VAR_%d", i), Value: v})
+	}
+	pod.Spec.Containers = []corev1.Container{container}
+
+	cluster, external := envHostnames(pod)
+	if want := []string{"db.payments.svc.cluster.local", "cache.payments", "orders.shared.svc"}; !reflect.DeepEqual(cluster, want) {
+		t.Errorf("cluster hostnames = %v, want %v", cluster, want)
+	}
+	if want := []string{"api.stripe.com", "hooks.slack.com"}; !reflect.DeepEqual(external, want) {
+		t.Errorf("external hostnames = %v, want %v", external, want)
+	}
+	if !isExternalFQDN("pypi.org.") || !isExternalFQDN("registry.corp.") {
+		t.Error("trailing-dot FQDNs should be external")
+	}
+}
+
+func TestFindQuotaRollouts(t *testing.T) {
+	replicas := int32(6)
+	deploy := func(name, cpu string, updated int32) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: name},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{UpdatedReplicas: updated},
+		}
+		d.Spec.Template.Spec.Containers = []corev1.Container{{
+			Name:      "app",
+			Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+		}}
+		return d
+	}
+	data := &ClusterData{
+		Deployments: map[string]*appsv1.Deployment{
+			"shop/api":      deploy("api", "500m", 1),    // 还剩 5 个副本，需要 2500m
+			"shop/worker":   deploy("worker", "100m", 2), // 需要 400m，配额足够
+			"shop/checkout": deploy("checkout", "2", 6),  // 滚动更新已完成
+			"shop/gone":     nil,
+		},
+		ResourceQuotas: []corev1.ResourceQuota{{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "shop", Name: "compute"},
+			Status: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("4"), corev1.ResourcePods: resource.MustParse("50")},
+				Used: corev1.ResourceList{corev1.ResourceRequestsCPU: resource.MustParse("3"), corev1.ResourcePods: resource.MustParse("12")},
+			},
+		}},
+	}
+
+	got := findQuotaRollouts(data)
+	if len(got) != 1 {
+		t.Fatalf("rollouts = %+v, want only shop/api", got)
+	}
+	q := got[0]
+	if q.Deployment != "api" || q.Remaining != 5 || q.Fits != 2 || q.Quota != "compute" || q.Resource != corev1.ResourceRequestsCPU {
+		t.Errorf("rollout = %+v, want api with 5 remaining, 2 fitting in compute requests.cpu", q)
+	}
+	if available, needed := q.Available(), q.Needed(); available.String() != "1" || needed.String() != "2500m" {
+		t.Errorf("available/needed = %s/%s, want 1/2500m", available.String(), needed.String())
+	}
+}
+
+func TestCheckInitTimeout(t *testing.T) {
+	useTestClock(t)
+
+	always := corev1.ContainerRestartPolicyAlways
+	pod := func(started time.Duration) *corev1.Pod {
+		startTime := ago(started)
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{InitContainers: []corev1.Container{
+				{Name: "proxy", RestartPolicy: &always},
+				{Name: "migrate"},
+				{Name: "wait-for-db"},
+			}},
+			Status: corev1.PodStatus{
+				Phase:     corev1.PodPending,
+				StartTime: &startTime,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: "proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+					{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+					{Name: "wait-for-db", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+				},
+			},
+		}
+	}
+
+	want := []ConfigIssue{withDetail(IssueInitTimeout, "Init:1/2 for 25m")}
+	if got := checkInitTimeout(pod(25*time.Minute), 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues = %v, want %v", got, want)
+	}
+	if got := checkInitTimeout(pod(5*time.Minute), 0); got != nil {
+		t.Errorf("issues within the timeout = %v, want none", got)
+	}
+	// 文字不写死默认的 10 分钟，实际时长在细节中
+	want = []ConfigIssue{withDetail(IssueInitTimeout, "Init:1/2 for 5m")}
+	if got := checkInitTimeout(pod(5*time.Minute), 2*time.Minute); !reflect.DeepEqual(got, want) {
+		t.Errorf("issues with a 2m timeout = %v, want %v", got, want)
+	}
+
+	// 普通 init 容器都已完成，只剩 sidecar 在运行
+	done := pod(25 * time.Minute)
+	done.Status.InitContainerStatuses[2].State = corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}
+	if got := checkInitTimeout(done, 0); got != nil {
+		t.Errorf("issues with only the sidecar running = %v, want none", got)
+	}
+}
+
+func TestCheckSchedulingLatency(t *testing.T) {
+	useTestClock(t)
+
+	pod := func(created time.Duration, scheduled *time.Duration) *corev1.Pod {
+		p := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: ago(created)},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		if scheduled != nil {
+			p.Spec.NodeName = "node-a"
+			p.Status.Phase = corev1.PodRunning
+			p.Status.Conditions = []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: ago(0)},
+				{Type: corev1.PodScheduled, Status: corev1.ConditionTrue, LastTransitionTime: ago(*scheduled)},
+			}
+		}
+		return p
+	}
+	after := func(d time.Duration) *time.Duration { return &d }
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		timeout     time.Duration
+		wantLatency time.Duration
+		want        []ConfigIssue
+	}{
+		{
+			name:        "scheduled quickly",
+			pod:         pod(time.Hour, after(time.Hour-5*time.Second)),
+			wantLatency: 5 * time.Second,
+		},
+		{
+			name:        "scheduled after the default timeout",
+			pod:         pod(time.Hour, after(50*time.Minute)),
+			wantLatency: 10 * time.Minute,
+			want:        []ConfigIssue{withDetail(IssueHighSchedulingLatency, "scheduled after 10m, threshold 2m")},
+		},
+		{
+			name:        "custom timeout",
+			pod:         pod(time.Hour, after(50*time.Minute)),
+			timeout:     15 * time.Minute,
+			wantLatency: 10 * time.Minute,
+		},
+		{
+			name:        "still waiting",
+			pod:         pod(7*time.Minute, nil),
+			wantLatency: -1,
+			want:        []ConfigIssue{withDetail(IssueHighSchedulingLatency, "waiting for 7m, threshold 2m")},
+		},
+		{
+			name:        "waiting within the timeout",
+			pod:         pod(time.Minute, nil),
+			wantLatency: -1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latency, ok := SchedulingLatency(tt.pod)
+			if !ok {
+				latency = -1
+			}
+			if latency != tt.wantLatency {
+				t.Errorf("SchedulingLatency = %v, want %v", latency, tt.wantLatency)
+			}
+			if got := checkSchedulingLatency(tt.pod, tt.timeout); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLastActivity(t *testing.T) {
+	useTestClock(t)
+
+	event := func(reason string, at time.Duration) corev1.Event {
+		return corev1.Event{Reason: reason, LastTimestamp: ago(at)}
+	}
+	tests := []struct {
+		name    string
+		phase   corev1.PodPhase
+		reason  string
+		events  []corev1.Event
+		natural bool
+		want    string
+	}{
+		{
+			name:   "failed without reason",
+			phase:  corev1.PodFailed,
+			events: []corev1.Event{event("Scheduled", 8*time.Hour), event("NodeNotReady", 6*time.Hour)},
+			want:   "Failed, last activity 6h0m ago: NodeNotReady",
+		},
+		{
+			name:   "unknown",
+			phase:  corev1.PodUnknown,
+			events: []corev1.Event{event("NodeNotReady", 3*24*time.Hour)},
+			want:   "Pod status unknown, last activity 3d0h ago: NodeNotReady",
+		},
+		{
+			name:   "recent event",
+			phase:  corev1.PodFailed,
+			events: []corev1.Event{event("NodeNotReady", 20*time.Second)},
+			want:   "Failed, last activity 20s ago: NodeNotReady",
+		},
+		{
+			name:   "just now",
+			phase:  corev1.PodFailed,
+			events: []corev1.Event{event("NodeNotReady", time.Second)},
+			want:   "Failed, last activity just now: NodeNotReady",
+		},
+		{
+			name:    "natural age",
+			phase:   corev1.PodFailed,
+			events:  []corev1.Event{event("NodeNotReady", 6*time.Hour)},
+			natural: true,
+			want:    "Failed, last activity 6 hours ago: NodeNotReady",
+		},
+		{
+			name:   "failed with reason",
+			phase:  corev1.PodFailed,
+			reason: "Evicted",
+			events: []corev1.Event{event("Evicted", time.Hour)},
+			want:   "Evicted",
+		},
+		{name: "no events", phase: corev1.PodFailed, want: "Failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "husk", CreationTimestamp: ago(10 * time.Hour)},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+				Status:     corev1.PodStatus{Phase: tt.phase, Reason: tt.reason},
+			}
+			data := &ClusterData{PodEvents: map[string][]corev1.Event{"default/husk": tt.events}}
+			result := AnalyzePods(&corev1.PodList{Items: []corev1.Pod{*pod}}, Options{Cluster: data, NaturalAge: tt.natural})
+			if got := result.Pods[0].Reason; got != tt.want {
+				t.Errorf("Reason = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckInjectedEnvConflicts(t *testing.T) {
+	disabled := false
+	tests := []struct {
+		name         string
+		serviceLinks *bool
+		want         []Finding
+	}{
+		{
+			name: "service links enabled",
+			want: []Finding{{Issue: IssueEnvConflictWithInjected, Detail: "app: REDIS_SERVICE_HOST, KUBERNETES_SERVICE_PORT"}},
+		},
+		{
+			name:         "service links disabled",
+			serviceLinks: &disabled,
+			want:         []Finding{{Issue: IssueEnvConflictWithInjected, Detail: "app: KUBERNETES_SERVICE_PORT"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{
+				EnableServiceLinks: tt.serviceLinks,
+				Containers: []corev1.Container{{Name: "app", Env: []corev1.EnvVar{
+					{Name: "REDIS_SERVICE_HOST", Value: "redis.cache"},
+					{Name: "REDIS_URL", Value: "redis://redis.cache:6379"},
+					{Name: "KUBERNETES_SERVICE_PORT", Value: "443"},
+				}}},
+			}}
+			got := checkInjectedEnvConflicts(pod)
+			if len(got) != len(tt.want) || (len(got) == 1 && got[0] != tt.want[0]) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckTemplateHash(t *testing.T) {
+	controller := true
+	tests := []struct {
+		name   string
+		labels map[string]string
+		owner  string
+		want   bool
+	}{
+		{name: "matching hash", labels: map[string]string{"pod-template-hash": "7c79c4bf97"}, owner: "web-7c79c4bf97"},
+		{name: "corrupted hash", labels: map[string]string{"pod-template-hash": "5d8f6b9c44"}, owner: "web-7c79c4bf97", want: true},
+		{name: "bare ReplicaSet without label", owner: "web"},
+		{name: "no owner", labels: map[string]string{"pod-template-hash": "5d8f6b9c44"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+			if tt.owner != "" {
+				pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: tt.owner, Controller: &controller}}
+			}
+			findings := checkTemplateHash(pod)
+			if got := len(findings) == 1; got != tt.want {
+				t.Errorf("findings = %v, want flagged %v", findings, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckWebhookDisruption(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	cluster := admissionregistrationv1.ClusterScope
+	podRule := func(ops ...admissionregistrationv1.OperationType) admissionregistrationv1.RuleWithOperations {
+		return admissionregistrationv1.RuleWithOperations{
+			Operations: ops,
+			Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"pods"}},
+		}
+	}
+
+	tests := []struct {
+		name string
+		hook admissionregistrationv1.ValidatingWebhook
+		want string // 期望的细节，为空表示不应标记
+	}{
+		{
+			name: "delete on pods",
+			hook: admissionregistrationv1.ValidatingWebhook{Name: "guard.example.com", Rules: []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.Delete)}},
+			want: "guard.example.com in guards, failurePolicy Fail",
+		},
+		{
+			name: "all operations, policy ignore",
+			hook: admissionregistrationv1.ValidatingWebhook{Name: "audit.example.com", FailurePolicy: &ignore, Rules: []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.OperationAll)}},
+			want: "audit.example.com in guards, failurePolicy Ignore",
+		},
+		{
+			name: "create only",
+			hook: admissionregistrationv1.ValidatingWebhook{Name: "create.example.com", Rules: []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.Create)}},
+		},
+		{
+			name: "other resource",
+			hook: admissionregistrationv1.ValidatingWebhook{Name: "svc.example.com", Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{""}, APIVersions: []string{"v1"}, Resources: []string{"services"}},
+			}}},
+		},
+		{
+			name: "cluster scope",
+			hook: admissionregistrationv1.ValidatingWebhook{Name: "cluster.example.com", Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Delete},
+				Rule:       admissionregistrationv1.Rule{APIGroups: []string{"*"}, APIVersions: []string{"*"}, Resources: []string{"*"}, Scope: &cluster},
+			}}},
+		},
+		{
+			name: "namespace excluded",
+			hook: admissionregistrationv1.ValidatingWebhook{
+				Name:  "ns.example.com",
+				Rules: []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.Delete)},
+				NamespaceSelector: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+					Key: corev1.LabelMetadataName, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"default"},
+				}}},
+			},
+		},
+		{
+			name: "namespace label selected",
+			hook: admissionregistrationv1.ValidatingWebhook{
+				Name:              "team.example.com",
+				Rules:             []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.Delete)},
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "payments"}},
+			},
+			want: "team.example.com in guards, failurePolicy Fail",
+		},
+		{
+			name: "object selector not matching",
+			hook: admissionregistrationv1.ValidatingWebhook{
+				Name:           "obj.example.com",
+				Rules:          []admissionregistrationv1.RuleWithOperations{podRule(admissionregistrationv1.Delete)},
+				ObjectSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"protected": "true"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &ClusterData{
+				Namespaces: map[string]*corev1.Namespace{
+					"default": {ObjectMeta: metav1.ObjectMeta{Name: "default", Labels: map[string]string{"team": "payments"}}},
+				},
+				ValidatingWebhooks: []admissionregistrationv1.ValidatingWebhookConfiguration{{
+					ObjectMeta: metav1.ObjectMeta{Name: "guards"},
+					Webhooks:   []admissionregistrationv1.ValidatingWebhook{tt.hook},
+				}},
+			}
+			pod := runningPod("web")
+			pod.Namespace = "default"
+			issues := checkWebhookDisruption(pod, data)
+			if tt.want == "" {
+				if len(issues) != 0 {
+					t.Errorf("issues = %v, want none", issues)
+				}
+				return
+			}
+			if len(issues) != 1 || issues[0] != withDetail(IssueWebhookBlocksDeletion, tt.want) {
+				t.Errorf("issues = %v, want [%s (%s)]", issues, IssueWebhookBlocksDeletion, tt.want)
+			}
+		})
+	}
+}
+
+func TestMissingNodeLabel(t *testing.T) {
+	nodes := []corev1.Node{
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-a", Labels: map[string]string{"pool": "new-name", "zone": "a"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "node-b", Labels: map[string]string{"pool": "batch", "gpu": "true"}}},
+	}
+	affinity := func(terms ...corev1.NodeSelectorTerm) *corev1.Affinity {
+		return &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{NodeSelectorTerms: terms},
+		}}
+	}
+	term := func(key string, op corev1.NodeSelectorOperator, values ...string) corev1.NodeSelectorTerm {
+		return corev1.NodeSelectorTerm{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: key, Operator: op, Values: values}}}
+	}
+
+	tests := []struct {
+		name         string
+		nodeSelector map[string]string
+		affinity     *corev1.Affinity
+		unfetched    bool // 未获取节点
+		want         string
+		wantClosest  []string
+	}{
+		{
+			name:         "renamed pool",
+			nodeSelector: map[string]string{"pool": "old-name"},
+			want:         "Unschedulable: no node has label pool=old-name",
+			wantClosest:  []string{"new-name", "batch"},
+		},
+		{
+			name:         "missing key",
+			nodeSelector: map[string]string{"gpus": "true"},
+			want:         "Unschedulable: no node has label gpus",
+			wantClosest:  []string{"gpu", "pool", "zone"},
+		},
+		{
+			name:         "label exists",
+			nodeSelector: map[string]string{"pool": "batch"},
+			want:         "Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+		{
+			name:        "affinity with no matching value",
+			affinity:    affinity(term("zone", corev1.NodeSelectorOpIn, "b", "c")),
+			want:        "Unschedulable: no node has label zone in (b, c)",
+			wantClosest: []string{"a"},
+		},
+		{
+			name:     "one affinity term satisfiable",
+			affinity: affinity(term("zone", corev1.NodeSelectorOpIn, "b"), term("gpu", corev1.NodeSelectorOpExists)),
+			want:     "Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+		{
+			name:     "NotIn is not checked",
+			affinity: affinity(term("zone", corev1.NodeSelectorOpNotIn, "a")),
+			want:     "Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+		{
+			name:         "nodes not fetched",
+			nodeSelector: map[string]string{"pool": "old-name"},
+			unfetched:    true,
+			want:         "Unschedulable: 0/3 nodes are available: 3 Insufficient memory.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := pendingPod("web")
+			pod.Spec.NodeSelector = tt.nodeSelector
+			pod.Spec.Affinity = tt.affinity
+			data := &ClusterData{ClusterNodes: nodes}
+			if tt.unfetched {
+				data.ClusterNodes = nil
+			}
+			result := AnalyzePods(&corev1.PodList{Items: []corev1.Pod{*pod}}, Options{Cluster: data})
+			got := result.Pods[0]
+			if got.Reason != tt.want {
+				t.Errorf("Reason = %q, want %q", got.Reason, tt.want)
+			}
+			var closest []string
+			if got.MissingNodeLabel != nil {
+				closest = got.MissingNodeLabel.Closest
+			}
+			if strings.Join(closest, ",") != strings.Join(tt.wantClosest, ",") {
+				t.Errorf("Closest = %v, want %v", closest, tt.wantClosest)
+			}
+		})
+	}
+}
+
+func TestCheckDefaultDeny(t *testing.T) {
+	policy := func(namespace string, selector map[string]string, types []networkingv1.PolicyType, ingress []networkingv1.NetworkPolicyIngressRule, egress []networkingv1.NetworkPolicyEgressRule) networkingv1.NetworkPolicy {
+		return networkingv1.NetworkPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "p"},
+			Spec: networkingv1.NetworkPolicySpec{
+				PodSelector: metav1.LabelSelector{MatchLabels: selector},
+				PolicyTypes: types,
+				Ingress:     ingress,
+				Egress:      egress,
+			},
+		}
+	}
+	both := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+	allowAll := []networkingv1.NetworkPolicyIngressRule{{}}
+
+	tests := []struct {
+		name     string
+		policies []networkingv1.NetworkPolicy
+		want     []ConfigIssue
+	}{
+		{name: "no policies", want: []ConfigIssue{IssueNoDefaultDenyPolicy}},
+		{name: "deny all", policies: []networkingv1.NetworkPolicy{policy("default", nil, both, nil, nil)}},
+		{
+			name: "separate ingress and egress policies",
+			policies: []networkingv1.NetworkPolicy{
+				policy("default", nil, nil, nil, nil),
+				policy("default", nil, []networkingv1.PolicyType{networkingv1.PolicyTypeEgress}, nil, nil),
+			},
+		},
+		{
+			name:     "ingress only (policyTypes defaulted)",
+			policies: []networkingv1.NetworkPolicy{policy("default", nil, nil, nil, nil)},
+			want:     []ConfigIssue{withDetail(IssueNoDefaultDenyPolicy, "egress allowed")},
+		},
+		{
+			name:     "allow-all ingress rule",
+			policies: []networkingv1.NetworkPolicy{policy("default", nil, both, allowAll, nil)},
+			want:     []ConfigIssue{withDetail(IssueNoDefaultDenyPolicy, "ingress allowed")},
+		},
+		{
+			name:     "selects some pods only",
+			policies: []networkingv1.NetworkPolicy{policy("default", map[string]string{"app": "db"}, both, nil, nil)},
+			want:     []ConfigIssue{IssueNoDefaultDenyPolicy},
+		},
+		{
+			name:     "other namespace",
+			policies: []networkingv1.NetworkPolicy{policy("payments", nil, both, nil, nil)},
+			want:     []ConfigIssue{IssueNoDefaultDenyPolicy},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkDefaultDeny(runningPod("web"), defaultDenyByNamespace(tt.policies))
+			if len(got) != len(tt.want) {
+				t.Fatalf("issues = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("issues[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCheckServicePorts(t *testing.T) {
+	service := func(namespace string, selector map[string]string, ports ...corev1.ServicePort) corev1.Service {
+		return corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "web"},
+			Spec:       corev1.ServiceSpec{Selector: selector, Ports: ports},
+		}
+	}
+	app := map[string]string{"app": "web"}
+	declared := []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}, {Name: "metrics", ContainerPort: 9090}}
+
+	tests := []struct {
+		name     string
+		ports    []corev1.ContainerPort
+		services []corev1.Service
+		want     []ConfigIssue
+	}{
+		{
+			name:     "numeric targetPort matches",
+			ports:    declared,
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8080)})},
+		},
+		{
+			name:     "named targetPort resolves",
+			ports:    declared,
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")})},
+		},
+		{
+			name:     "unset targetPort defaults to port",
+			ports:    declared,
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 9090})},
+		},
+		{
+			name:     "numeric mismatch",
+			ports:    declared,
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8081)})},
+			want:     []ConfigIssue{withDetail(IssueServiceTargetPortMismatch, "service web port 80 targets 8081; container ports: 8080 (http), 9090 (metrics)")},
+		},
+		{
+			name:     "unknown port name",
+			ports:    declared,
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("grpc")})},
+			want:     []ConfigIssue{withDetail(IssueServiceTargetPortMismatch, "service web port 80 targets grpc; container ports: 8080 (http), 9090 (metrics)")},
+		},
+		{
+			name:     "protocol must match",
+			ports:    []corev1.ContainerPort{{ContainerPort: 53}},
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 53, Protocol: corev1.ProtocolUDP})},
+			want:     []ConfigIssue{withDetail(IssueServiceTargetPortMismatch, "service web port 53 targets 53/UDP; container ports: 53")},
+		},
+		{
+			name:     "no declared ports",
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8080)})},
+			want:     []ConfigIssue{withDetail(IssueServicePortUnverified, "service web port 80 targets 8080, no containerPorts declared")},
+		},
+		{
+			name:     "named targetPort without declared ports",
+			services: []corev1.Service{service("default", app, corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")})},
+			want:     []ConfigIssue{withDetail(IssueServiceTargetPortMismatch, "service web port 80 targets http; container ports: none")},
+		},
+		{
+			name:     "selector does not match",
+			ports:    declared,
+			services: []corev1.Service{service("default", map[string]string{"app": "db"}, corev1.ServicePort{Port: 5432})},
+		},
+		{
+			name:     "no selector",
+			ports:    declared,
+			services: []corev1.Service{service("default", nil, corev1.ServicePort{Port: 5432})},
+		},
+		{
+			name:     "other namespace",
+			ports:    declared,
+			services: []corev1.Service{service("payments", app, corev1.ServicePort{Port: 5432})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := runningPod("web")
+			pod.Labels = app
+			pod.Spec.Containers[0].Ports = tt.ports
+			got := checkServicePorts(pod, servicesByNamespace(tt.services)[pod.Namespace])
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckResourceEfficiency(t *testing.T) {
+	usage := func(cpu, memory string) map[string]corev1.ResourceList {
+		return map[string]corev1.ResourceList{"app": {
+			corev1.ResourceCPU:    resource.MustParse(cpu),
+			corev1.ResourceMemory: resource.MustParse(memory),
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		usage     map[string]corev1.ResourceList
+		requests  corev1.ResourceList
+		phase     corev1.PodPhase
+		want      []ConfigIssue
+		wantScore float64
+	}{
+		{
+			name:      "well sized",
+			usage:     usage("80m", "200Mi"),
+			wantScore: 0.8,
+		},
+		{
+			name:  "both over-provisioned",
+			usage: usage("4123456n", "10356Ki"),
+			want: []ConfigIssue{
+				withDetail(IssueOverProvisionedCPU, "using 5m of 100m requested (5%), threshold 10%"),
+				withDetail(IssueOverProvisionedMemory, "using 10Mi of 250Mi requested (4%), threshold 10%"),
+			},
+			wantScore: (0.05 + 10356.0/256000) / 2,
+		},
+		{
+			name:      "no memory request",
+			usage:     usage("5m", "10Mi"),
+			requests:  corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")},
+			want:      []ConfigIssue{withDetail(IssueOverProvisionedCPU, "using 5m of 100m requested (5%), threshold 10%")},
+			wantScore: 0.05,
+		},
+		{
+			name:      "no usage data",
+			usage:     map[string]corev1.ResourceList{},
+			wantScore: -1,
+		},
+		{
+			name:      "metrics not fetched",
+			wantScore: -1,
+		},
+		{
+			name:      "not running",
+			usage:     usage("0", "0"),
+			phase:     corev1.PodPending,
+			wantScore: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := runningPod("web")
+			pod.Spec.Containers[0].Resources.Requests = corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("100m"),
+				corev1.ResourceMemory: resource.MustParse("250Mi"),
+			}
+			if tt.requests != nil {
+				pod.Spec.Containers[0].Resources.Requests = tt.requests
+			}
+			if tt.phase != "" {
+				pod.Status.Phase = tt.phase
+			}
+			got := checkResourceEfficiency(pod, tt.usage, 0)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %q, want %q", got, tt.want)
+			}
+			if score := efficiencyScore(pod, tt.usage); math.Abs(score-tt.wantScore) > 1e-3 {
+				t.Errorf("score = %v, want %v", score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestCheckPodSecurityPolicies(t *testing.T) {
+	restricted := PodSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted"},
+		Spec: PodSecurityPolicySpec{
+			Volumes:                  []string{"configMap", "secret", "emptyDir", "projected"},
+			RunAsUser:                PSPRunAsUserStrategy{Rule: "MustRunAsNonRoot"},
+			AllowPrivilegeEscalation: ptr.To(false),
+			RequiredDropCapabilities: []corev1.Capability{"ALL"},
+		},
+	}
+	hostAccess := PodSecurityPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-access"},
+		Spec: PodSecurityPolicySpec{
+			Volumes:          []string{"*"},
+			HostNetwork:      true,
+			HostPorts:        []PSPHostPortRange{{Min: 8000, Max: 9000}},
+			RunAsUser:        PSPRunAsUserStrategy{Rule: "RunAsAny"},
+			AllowedHostPaths: []PSPAllowedHostPath{{PathPrefix: "/var/log", ReadOnly: true}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		mutate   func(pod *corev1.Pod)
+		policies []PodSecurityPolicy
+		want     []ConfigIssue
+	}{
+		{name: "no policies", mutate: func(pod *corev1.Pod) { pod.Spec.HostNetwork = true }},
+		{name: "defaults filled by the policy", policies: []PodSecurityPolicy{restricted}},
+		{
+			name:     "privileged root container",
+			policies: []PodSecurityPolicy{restricted},
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: ptr.To(true), RunAsUser: ptr.To[int64](0)}
+			},
+			want: []ConfigIssue{withDetail(IssuePSPViolation+": restricted", "app: privileged, app: allowPrivilegeEscalation, app: runAsUser 0")},
+		},
+		{
+			name:     "admitted by another policy",
+			policies: []PodSecurityPolicy{restricted, hostAccess},
+			mutate:   func(pod *corev1.Pod) { pod.Spec.HostNetwork = true },
+		},
+		{
+			name:     "closest policy is reported",
+			policies: []PodSecurityPolicy{restricted, hostAccess},
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.HostNetwork = true
+				pod.Spec.Containers[0].Ports = []corev1.ContainerPort{{ContainerPort: 80, HostPort: 80}}
+			},
+			want: []ConfigIssue{withDetail(IssuePSPViolation+": host-access", "app: hostPort 80")},
+		},
+		{
+			name:     "volumes and host paths",
+			policies: []PodSecurityPolicy{restricted, hostAccess},
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.Volumes = []corev1.Volume{
+					{Name: "logs", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log/app"}}},
+					{Name: "sock", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/logger"}}},
+				}
+				pod.Spec.Containers[0].VolumeMounts = []corev1.VolumeMount{{Name: "logs", MountPath: "/logs"}}
+			},
+			want: []ConfigIssue{withDetail(IssuePSPViolation+": host-access", "hostPath /var/log/app (must be readOnly), hostPath /var/logger")},
+		},
+		{
+			name:     "capabilities",
+			policies: []PodSecurityPolicy{restricted},
+			mutate: func(pod *corev1.Pod) {
+				pod.Spec.Volumes = []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "data"}}}}
+				pod.Spec.InitContainers = []corev1.Container{{
+					Name:            "setup",
+					SecurityContext: &corev1.SecurityContext{Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"ALL", "NET_ADMIN"}}},
+				}}
+			},
+			want: []ConfigIssue{withDetail(IssuePSPViolation+": restricted",
+				"volume data (persistentVolumeClaim), setup: capability ALL (must be dropped), setup: capability NET_ADMIN")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := runningPod("web")
+			if tt.mutate != nil {
+				tt.mutate(pod)
+			}
+			got := checkPodSecurityPolicies(pod, tt.policies)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issues = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPodSecurityPolicyFromUnstructured(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy/v1beta1",
+		"kind":       "PodSecurityPolicy",
+		"metadata":   map[string]interface{}{"name": "restricted"},
+		"spec": map[string]interface{}{
+			"privileged":               false,
+			"allowPrivilegeEscalation": false,
+			"volumes":                  []interface{}{"configMap", "secret"},
+			"hostPorts":                []interface{}{map[string]interface{}{"min": int64(8000), "max": int64(9000)}},
+			"runAsUser":                map[string]interface{}{"rule": "MustRunAs", "ranges": []interface{}{map[string]interface{}{"min": int64(1000), "max": int64(2000)}}},
+			"seLinux":                  map[string]interface{}{"rule": "RunAsAny"},
+		},
+	}}
+	got, err := PodSecurityPolicyFromUnstructured(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := PodSecurityPolicySpec{
+		Volumes:                  []string{"configMap", "secret"},
+		HostPorts:                []PSPHostPortRange{{Min: 8000, Max: 9000}},
+		RunAsUser:                PSPRunAsUserStrategy{Rule: "MustRunAs", Ranges: []PSPIDRange{{Min: 1000, Max: 2000}}},
+		AllowPrivilegeEscalation: ptr.To(false),
+	}
+	if got.Name != "restricted" || !reflect.DeepEqual(got.Spec, want) {
+		t.Errorf("got %s %+v, want restricted %+v", got.Name, got.Spec, want)
+	}
+}
+
+func TestECIInstances(t *testing.T) {
+	eci := func(name, id string, annotations map[string]string) corev1.Pod {
+		pod := runningPod(name)
+		pod.Spec.NodeName = "virtual-kubelet-cn-hangzhou-b"
+		pod.Annotations = annotations
+		if id != "" {
+			pod.Annotations[ECIPodAnnotation] = id
+		}
+		return *pod
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		eci("worker", "eci-2", map[string]string{ECIUseSpecsAnnotation: "2-4Gi,ecs.c6.large", ECIZoneAnnotation: "cn-hangzhou-b"}),
+		eci("api", "eci-1", map[string]string{ECISpecAnnotation: "ecs.g6.xlarge", ECIUseSpecsAnnotation: "4-16Gi"}),
+		eci("pending-id", "", map[string]string{}),
+		*runningPod("on-ecs"),
+	}}
+
+	got := ECIInstances(AnalyzePods(pods, Options{Providers: DefaultProviderRules}))
+	want := []ECIInstance{
+		{Namespace: "default", Pod: "api", InstanceID: "eci-1", Spec: "ecs.g6.xlarge"},
+		{Namespace: "default", Pod: "pending-id"},
+		{Namespace: "default", Pod: "worker", InstanceID: "eci-2", Zone: "cn-hangzhou-b", Spec: "2-4Gi,ecs.c6.large"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("instances = %+v, want %d", got, len(want))
+	}
+	for i := range want {
+		g := got[i]
+		g.Age, g.Status = "", ""
+		if g != want[i] {
+			t.Errorf("instances[%d] = %+v, want %+v", i, g, want[i])
+		}
+	}
+}
+
+func TestFindLivenessCascade(t *testing.T) {
+	controller := true
+	owned := func(name, kind, owner string) corev1.Pod {
+		pod := runningPod(name)
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: owner, Controller: &controller}}
+		return *pod
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		owned("api-1", "StatefulSet", "api"),
+		owned("api-2", "StatefulSet", "api"),
+		owned("worker-1", "DaemonSet", "worker"),
+		*runningPod("scratch"),
+	}}
+	kill := func(pod string, at time.Duration) corev1.Event {
+		return corev1.Event{
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Reason:         EventReasonKilling,
+			Message:        "Container app failed liveness probe, will be restarted",
+			LastTimestamp:  ago(at),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		events []corev1.Event
+		want   *LivenessCascade
+	}{
+		{
+			name:   "three workloads within the window",
+			events: []corev1.Event{kill("api-1", 50*time.Minute), kill("worker-1", 48*time.Minute), kill("api-2", 47*time.Minute), kill("scratch", 45*time.Minute)},
+			want: &LivenessCascade{
+				Start:     ago(50 * time.Minute).Time,
+				End:       ago(45 * time.Minute).Time,
+				Kills:     4,
+				Workloads: []string{"default/DaemonSet/worker", "default/Pod/scratch", "default/StatefulSet/api"},
+			},
+		},
+		{
+			name:   "restarts spread beyond the window",
+			events: []corev1.Event{kill("api-1", 50*time.Minute), kill("worker-1", 35*time.Minute), kill("scratch", 20*time.Minute)},
+		},
+		{
+			name:   "one workload restarting repeatedly",
+			events: []corev1.Event{kill("api-1", 5*time.Minute), kill("api-2", 4*time.Minute), kill("api-1", 3*time.Minute)},
+		},
+		{
+			name: "other Killing events and unknown pods are ignored",
+			events: []corev1.Event{
+				kill("api-1", 5*time.Minute), kill("worker-1", 4*time.Minute), kill("gone", 3*time.Minute),
+				{InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "scratch"}, Reason: EventReasonKilling, Message: "Stopping container app", LastTimestamp: ago(2 * time.Minute)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findLivenessCascade(pods, &ClusterData{KillingEvents: tt.events})
+			if tt.want == nil {
+				if got != nil {
+					t.Errorf("cascade = %+v, want none", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("cascade = nil, want %+v", tt.want)
+			}
+			if !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) || got.Kills != tt.want.Kills ||
+				strings.Join(got.Workloads, ",") != strings.Join(tt.want.Workloads, ",") {
+				t.Errorf("cascade = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSharedProbePorts(t *testing.T) {
+	httpProbe := func(port intstr.IntOrString) *corev1.Probe {
+		return &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Path: "/healthz", Port: port}}}
+	}
+	tcpProbe := func(port int32) *corev1.Probe {
+		return &corev1.Probe{ProbeHandler: corev1.ProbeHandler{TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt32(port)}}}
+	}
+	execProbe := &corev1.Probe{ProbeHandler: corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}}}
+
+	tests := []struct {
+		name       string
+		containers []corev1.Container
+		want       []Finding
+	}{
+		{
+			name: "two containers probe the same port",
+			containers: []corev1.Container{
+				{Name: "app", LivenessProbe: httpProbe(intstr.FromInt32(8080)), ReadinessProbe: httpProbe(intstr.FromInt32(8080))},
+				{Name: "sidecar", LivenessProbe: tcpProbe(8080)},
+			},
+			want: []Finding{{Issue: IssueSharedProbePort, Detail: "port 8080: app, sidecar"}},
+		},
+		{
+			name: "named port resolves to the same number",
+			containers: []corev1.Container{
+				{Name: "app", Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}}, ReadinessProbe: httpProbe(intstr.FromString("http"))},
+				{Name: "proxy", LivenessProbe: httpProbe(intstr.FromInt32(8080))},
+				{Name: "metrics", LivenessProbe: tcpProbe(9090)},
+			},
+			want: []Finding{{Issue: IssueSharedProbePort, Detail: "port 8080: app, proxy"}},
+		},
+		{
+			name: "several shared ports sorted",
+			containers: []corev1.Container{
+				{Name: "a", LivenessProbe: tcpProbe(9090), ReadinessProbe: tcpProbe(8080)},
+				{Name: "b", LivenessProbe: tcpProbe(9090), ReadinessProbe: tcpProbe(8080)},
+			},
+			want: []Finding{
+				{Issue: IssueSharedProbePort, Detail: "port 8080: a, b"},
+				{Issue: IssueSharedProbePort, Detail: "port 9090: a, b"},
+			},
+		},
+		{
+			name: "distinct ports",
+			containers: []corev1.Container{
+				{Name: "app", LivenessProbe: tcpProbe(8080)},
+				{Name: "sidecar", LivenessProbe: tcpProbe(15021)},
+			},
+		},
+		{
+			name: "liveness and readiness of one container",
+			containers: []corev1.Container{
+				{Name: "app", LivenessProbe: tcpProbe(8080), ReadinessProbe: httpProbe(intstr.FromInt32(8080))},
+			},
+		},
+		{
+			name: "exec probes and explicit hosts are ignored",
+			containers: []corev1.Container{
+				{Name: "app", LivenessProbe: tcpProbe(8080)},
+				{Name: "db", LivenessProbe: execProbe},
+				{Name: "checker", LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{Host: "10.0.0.1", Port: intstr.FromInt32(8080)}}}},
+			},
+		},
+		{
+			name: "unresolvable named port",
+			containers: []corev1.Container{
+				{Name: "app", LivenessProbe: tcpProbe(8080)},
+				{Name: "sidecar", LivenessProbe: httpProbe(intstr.FromString("http"))},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := &corev1.Pod{Spec: corev1.PodSpec{Containers: tt.containers}}
+			if got := checkSharedProbePorts(pod); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findings = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckShellAsPID1(t *testing.T) {
+	pod := &corev1.Pod{Spec: corev1.PodSpec{
+		Containers: []corev1.Container{
+			{Name: "app", Command: []string{"sh", "-c", "myapp --port 8080"}},
+			{Name: "worker", Command: []string{"/bin/bash"}, Args: []string{"-c", "worker"}},
+			{Name: "exec", Command: []string{"sh", "-c", "exec myapp"}},
+			{Name: "direct", Command: []string{"myapp"}, Args: []string{"-c", "config.yaml"}},
+			{Name: "image-default"},
+		},
+	}}
+	want := Finding{Issue: IssueShellAsPID1, Detail: "app, worker"}
+	if got := checkShellAsPID1(pod); len(got) != 1 || got[0] != want {
+		t.Errorf("findings = %v, want [%v]", got, want)
+	}
+
+	if got := checkShellAsPID1(runningPod("web")); got != nil {
+		t.Errorf("findings for a pod without a shell command = %v, want none", got)
+	}
+}
+
+func TestAnalyzeTemplates(t *testing.T) {
+	clean := runningPod("web")
+	noLimits := runningPod("worker")
+	noLimits.Spec.Containers[0].Resources.Limits = nil
+	templates := []WorkloadTemplate{
+		{Kind: "StatefulSet", Namespace: "default", Name: "db", Replicas: 3, Template: corev1.PodTemplateSpec{Spec: noLimits.Spec}},
+		{Kind: "Deployment", Namespace: "default", Name: "web", Replicas: 5, Template: corev1.PodTemplateSpec{Spec: clean.Spec}},
+		{Kind: "CronJob", Namespace: "batch", Name: "report", Replicas: -1, Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "data"}},
+			Spec:       noLimits.Spec,
+		}},
+	}
+
+	results := AnalyzeTemplates(templates, Options{CheckConfig: true, RequiredLabels: []string{"team"}})
+
+	var order []string
+	for _, r := range results {
+		order = append(order, r.Namespace+"/"+r.Kind+"/"+r.Name)
+	}
+	if want := []string{"batch/CronJob/report", "default/Deployment/web", "default/StatefulSet/db"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	report, web, db := results[0], results[1], results[2]
+	if report.Replicas != -1 || web.Replicas != 5 || db.Replicas != 3 {
+		t.Errorf("replicas = %d, %d, %d, want -1, 5, 3", report.Replicas, web.Replicas, db.Replicas)
+	}
+	// 模板的标签满足必需标签，只缺少 limits
+	if len(report.ConfigIssues) != 1 || !report.ConfigIssues[0].Is(IssueMissingLimits) {
+		t.Errorf("CronJob issues = %v, want only %q", report.ConfigIssues, IssueMissingLimits)
+	}
+	if want := []ConfigIssue{withDetail(IssueMissingRequiredLabels, "team")}; !reflect.DeepEqual(web.ConfigIssues, want) {
+		t.Errorf("Deployment issues = %v, want %v", web.ConfigIssues, want)
+	}
+	if len(db.ConfigIssues) != 2 {
+		t.Errorf("StatefulSet issues = %v, want missing limits and required labels", db.ConfigIssues)
+	}
+}
+
+func TestAnalyzeCronJobsLastRuns(t *testing.T) {
+	useTestClock(t)
+	cronJobs := &batchv1.CronJobList{Items: []batchv1.CronJob{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "sync"},
+		Spec:       batchv1.CronJobSpec{Schedule: "*/5 * * * *"},
+		Status:     batchv1.CronJobStatus{LastScheduleTime: ptr.To(ago(30 * time.Second)), LastSuccessfulTime: ptr.To(ago(30 * time.Second))},
+	}}}
+	jobs := &batchv1.JobList{Items: []batchv1.Job{{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "batch", Name: "sync-1", OwnerReferences: []metav1.OwnerReference{{Kind: "CronJob", Name: "sync", Controller: ptr.To(true)}}},
+		Status:     batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, LastTransitionTime: ago(5 * time.Minute)}}},
+	}}}
+
+	// LAST SUCCESS/LAST FAILURE 列只显示时长，不带 "ago"
+	results := AnalyzeCronJobs(cronJobs, jobs, &corev1.PodList{})
+	if len(results) != 1 || results[0].LastSuccess != "30s" || results[0].LastFailure != "5m" {
+		t.Errorf("results = %+v, want last success 30s and last failure 5m", results)
+	}
+}
+
+func TestAnalyzeTemplatesIdentity(t *testing.T) {
+	emptyDir := corev1.Volume{Name: "scratch", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}
+	// 名称本身没有超过限制，加上 Deployment 的 -<hash>-xxxxx 后缀后超过
+	longName := strings.Repeat("a", 40)
+	templates := []WorkloadTemplate{
+		{Kind: "Deployment", Namespace: "payments", Name: longName, Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}, Volumes: []corev1.Volume{emptyDir}},
+		}},
+		{Kind: "DaemonSet", Namespace: "payments", Name: "agent", Template: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "agent"}}, Volumes: []corev1.Volume{emptyDir}},
+		}},
+		{Kind: "CronJob", Namespace: "payments", Name: "report", Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{SafeToEvictAnnotation: "false"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "report"}}},
+		}},
+	}
+	opts := Options{
+		RequiredLabels:  []string{"team"},
+		CheckAutoscaler: true,
+		EnabledChecks:   []string{"name-length"},
+		Cluster: &ClusterData{Namespaces: map[string]*corev1.Namespace{
+			"payments": {ObjectMeta: metav1.ObjectMeta{Name: "payments", Labels: map[string]string{"team": "payments"}}},
+		}},
+	}
+
+	// 命名空间、名称和控制者来自工作负载：命名空间提供必需标签，名称过长，DaemonSet 不阻止缩容，CronJob 的 Pod 由 Job 控制
+	results := AnalyzeTemplates(templates, opts)
+	want := map[string][]ConfigIssue{
+		"agent":  nil,
+		"report": {IssueBatchNotEvictable},
+		longName: {withDetail(IssueNotSafeToEvict, "scratch"), withDetail(IssueNameTooLong, "generated pod names up to 57 characters")},
+	}
+	for _, r := range results {
+		if !reflect.DeepEqual(r.ConfigIssues, want[r.Name]) {
+			t.Errorf("%s %s issues = %v, want %v", r.Kind, r.Name, r.ConfigIssues, want[r.Name])
+		}
+	}
+	// 模板本身不会被修改
+	for _, tmpl := range templates {
+		if meta := tmpl.Template.ObjectMeta; meta.Namespace != "" || meta.Name != "" || meta.OwnerReferences != nil {
+			t.Errorf("%s template metadata = %+v, want it untouched", tmpl.Name, meta)
+		}
+	}
+}
+
+func TestFindDeploys(t *testing.T) {
+	useTestClock(t)
+	deploymentPod := func(name, hash string, created time.Duration) corev1.Pod {
+		pod := runningPod(name)
+		pod.CreationTimestamp = ago(created)
+		pod.Labels = map[string]string{"pod-template-hash": hash}
+		pod.OwnerReferences = []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-" + hash, Controller: ptr.To(true)}}
+		return *pod
+	}
+
+	// api：新一代 (bbb) 的最早 Pod 创建于 3 小时前，12 分钟后开始出问题；旧一代的 Pod 不影响发布时间
+	crashing := deploymentPod("api-bbb-2", "bbb", 2*time.Hour)
+	crashing.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: ago(3*time.Hour - 12*time.Minute)}}
+	crashing.Status.ContainerStatuses[0].Ready = false
+	pods := &corev1.PodList{Items: []corev1.Pod{
+		deploymentPod("api-aaa-1", "aaa", 48*time.Hour),
+		deploymentPod("api-bbb-1", "bbb", 3*time.Hour),
+		crashing,
+		*runningPod("bare"),
+	}}
+
+	results := AnalyzePods(pods, Options{DeployTimes: true})
+	if len(results.Deploys) != 1 {
+		t.Fatalf("deploys = %+v, want only api", results.Deploys)
+	}
+	d := results.Deploys[0]
+	if d.Namespace != "default" || d.Name != "api" || !d.DeployedAt.Equal(ago(3*time.Hour).Time) || d.Deployed != "3h0m ago" {
+		t.Errorf("deploy = %+v, want default/api deployed 3h0m ago", d)
+	}
+	if got, want := d.IssuesNote(), "issues began 12m after deploy"; got != want {
+		t.Errorf("IssuesNote() = %q, want %q", got, want)
+	}
+
+	// 问题早于发布
+	d.ProblemSince = d.DeployedAt.Add(-time.Minute)
+	if got, want := d.IssuesNote(), "issues predate deploy"; got != want {
+		t.Errorf("IssuesNote() = %q, want %q", got, want)
+	}
+}
+
+func TestAddonRulesFromConfig(t *testing.T) {
+	custom := []AddonRule{
+		{Name: "CNI", Namespace: "kube-system", Selectors: []labels.Set{{"app": "antrea"}}},
+		{Name: "ingress-nginx", Namespace: "ingress-nginx", Selectors: []labels.Set{{"app.kubernetes.io/name": "ingress-nginx"}}},
+	}
+	if err := ValidateAddonRules(custom); err != nil {
+		t.Fatalf("ValidateAddonRules() = %v", err)
+	}
+
+	var names []string
+	for _, rule := range MergeAddonRules(DefaultAddons, custom) {
+		names = append(names, rule.Name)
+	}
+	if want := []string{"CNI", "ingress-nginx", "CoreDNS", "kube-proxy", "metrics-server"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("merged = %v, want %v", names, want)
+	}
+
+	pods := &corev1.PodList{Items: []corev1.Pod{*runningPod("antrea-agent"), *runningPod("controller")}}
+	pods.Items[0].Namespace, pods.Items[0].Labels = "kube-system", map[string]string{"app": "antrea"}
+	pods.Items[1].Namespace, pods.Items[1].Labels = "ingress-nginx", map[string]string{"app.kubernetes.io/name": "ingress-nginx"}
+	statuses := AnalyzeAddons(pods, MergeAddonRules(DefaultAddons, custom))
+	if want := []AddonStatus{{Name: "CNI", Healthy: 1, Total: 1}, {Name: "ingress-nginx", Healthy: 1, Total: 1}}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+
+	invalid := []struct {
+		rules []AddonRule
+		want  string
+	}{
+		{[]AddonRule{{Selectors: []labels.Set{{"app": "x"}}}}, "addons[0]: name is required"},
+		{[]AddonRule{{Name: "x"}}, `addons[0] ("x"): at least one selector is required`},
+		{[]AddonRule{{Name: "x", Selectors: []labels.Set{{}}}}, `addons[0] ("x"): selectors[0] must not be empty`},
+		{[]AddonRule{custom[0], custom[0]}, `addons[1] ("CNI"): duplicate addon name`},
+	}
+	for _, tt := range invalid {
+		if err := ValidateAddonRules(tt.rules); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ValidateAddonRules(%v) = %v, want %q", tt.rules, err, tt.want)
+		}
+	}
+}
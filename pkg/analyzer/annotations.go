@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssueMissingCostAnnotation 表示 Pod 缺少成本分摊注解，多租户集群无法将费用归属到成本中心
+const IssueMissingCostAnnotation ConfigIssue = "Pod missing cost center annotation"
+
+// DefaultCostAnnotation 是默认要求的成本分摊注解
+const DefaultCostAnnotation = "billing/cost-center"
+
+// checkCostAnnotation 检查 Pod 是否设置了非空的成本分摊注解
+func checkCostAnnotation(pod *corev1.Pod, key string) []ConfigIssue {
+	if strings.TrimSpace(pod.Annotations[key]) != "" {
+		return nil
+	}
+	return []ConfigIssue{IssueMissingCostAnnotation + ConfigIssue(": "+key)}
+}
+
+// IssueAnnotationTooLarge 表示 Pod 注解值的总大小超过上限
+// etcd 默认限制单个对象约 1.5MB，operator 在注解中存放的大段 JSON 会使 Pod 更新接近该限制而失败
+const IssueAnnotationTooLarge ConfigIssue = "Pod annotation values exceed size limit"
+
+// DefaultMaxAnnotationSize 是 Pod 注解值总大小的默认上限（字节）
+const DefaultMaxAnnotationSize = 10240
+
+// checkAnnotationSize 累加 Pod 所有注解值的长度，超过 limit 时报告总大小和最大的注解
+func checkAnnotationSize(pod *corev1.Pod, limit int) []ConfigIssue {
+	if limit <= 0 {
+		limit = DefaultMaxAnnotationSize
+	}
+	total, largest, largestSize := 0, "", -1
+	for key, value := range pod.Annotations {
+		total += len(value)
+		// 大小相同时取键名较小的，使输出稳定
+		if len(value) > largestSize || (len(value) == largestSize && key < largest) {
+			largest, largestSize = key, len(value)
+		}
+	}
+	if total <= limit {
+		return nil
+	}
+	detail := fmt.Sprintf("%d bytes, limit %d; largest %s (%d bytes)", total, limit, largest, largestSize)
+	return []ConfigIssue{withDetail(IssueAnnotationTooLarge, detail)}
+}
+
+// MissingCostAnnotationKey 返回缺少成本分摊注解问题中的注解键
+func MissingCostAnnotationKey(issue ConfigIssue) string {
+	return strings.TrimPrefix(string(issue), string(IssueMissingCostAnnotation)+": ")
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SafeToEvictAnnotation 是 cluster autoscaler 判断缩容时能否驱逐 Pod 的注解
+const SafeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+// 与 cluster autoscaler 缩容相关的配置问题
+const (
+	IssueNotSafeToEvict    ConfigIssue = "Pod may block node scale-down (no safe-to-evict annotation)"
+	IssueBatchNotEvictable ConfigIssue = "Batch Job pod blocks node scale-down (safe-to-evict: \"false\")"
+)
+
+// checkAutoscalerEviction 检查 Pod 是否会阻止 cluster autoscaler 缩容节点
+// 使用 emptyDir/hostPath 的 Pod 默认不会被驱逐，需要显式声明 safe-to-evict；
+// 反过来，Job 的 Pod 可以重试，标记为 "false" 通常只会让节点无法回收；kind 是 Pod 控制者的类型
+func checkAutoscalerEviction(pod *corev1.Pod, kind string) []ConfigIssue {
+	if isTerminal(pod) {
+		return nil
+	}
+	// DaemonSet 的 Pod 不影响缩容
+	if kind == "DaemonSet" {
+		return nil
+	}
+
+	value, annotated := pod.Annotations[SafeToEvictAnnotation]
+	if kind == "Job" && value == "false" {
+		return []ConfigIssue{IssueBatchNotEvictable}
+	}
+	if annotated {
+		return nil
+	}
+	if volumes := localStorageVolumes(pod); len(volumes) > 0 {
+		return []ConfigIssue{withDetail(IssueNotSafeToEvict, strings.Join(volumes, ", "))}
+	}
+	return nil
+}
+
+// localStorageVolumes 返回 Pod 中 emptyDir 和 hostPath 类型的 volume 名称
+func localStorageVolumes(pod *corev1.Pod) []string {
+	var names []string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil || volume.HostPath != nil {
+			names = append(names, volume.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,309 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Finding 是 Check 发现的一个问题
+type Finding struct {
+	Issue  ConfigIssue
+	Detail string // 可选的细节，显示为 "Issue (Detail)"
+}
+
+// configIssue 将 Finding 转换为 ConfigIssue
+func (f Finding) configIssue() ConfigIssue {
+	if f.Detail == "" {
+		return f.Issue
+	}
+	return withDetail(f.Issue, f.Detail)
+}
+
+// findings 将 ConfigIssue 转换为 Finding，细节已经包含在 Issue 中
+func findings(issues []ConfigIssue) []Finding {
+	if len(issues) == 0 {
+		return nil
+	}
+	out := make([]Finding, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, Finding{Issue: issue})
+	}
+	return out
+}
+
+// CheckContext 是检查一个对象时的上下文：对象的身份、运行中的 Pod 和集群数据
+type CheckContext struct {
+	// Namespace/Name 是被检查对象的命名空间和名称：Pod，或 --templates 时的工作负载
+	Namespace string
+	Name      string
+	// OwnerKind/OwnerName 是对象的控制者（见 ResolveOwner）
+	// 工作负载模板的控制者是工作负载本身，CronJob 模板创建的 Pod 由 Job 控制，记为 Job
+	OwnerKind string
+	OwnerName string
+	// Pod 是运行中的 Pod，检查工作负载模板时为 nil
+	Pod *corev1.Pod
+	// Cluster 是预先获取的集群对象，没有获取时为 nil，检查应在数据缺失时跳过
+	Cluster *ClusterData
+
+	opts Options
+	// podNameSuffix 是检查工作负载模板时，控制器在工作负载名称后追加的 Pod 名称后缀长度（见 generatedPodNameSuffix）
+	podNameSuffix int
+	// 以下是按选项预先整理的数据，同一次分析的所有对象共用
+	providers    []ProviderRule
+	denies       map[string]defaultDeny
+	services     map[string][]*corev1.Service
+	fingerprints *templateFingerprints
+}
+
+// newCheckContext 根据选项创建一次分析共用的检查上下文，检查每个对象时复制一份并填写对象的身份
+func newCheckContext(opts Options) *CheckContext {
+	ctx := &CheckContext{
+		Cluster:      opts.Cluster,
+		opts:         opts,
+		providers:    opts.providerRules(),
+		fingerprints: newTemplateFingerprints(),
+	}
+	if cluster := opts.Cluster; cluster != nil {
+		if cluster.NetworkPolicies != nil {
+			ctx.denies = defaultDenyByNamespace(cluster.NetworkPolicies)
+		}
+		if cluster.Services != nil {
+			ctx.services = servicesByNamespace(cluster.Services)
+		}
+	}
+	return ctx
+}
+
+// Check 是一项针对 Pod 元数据和 PodSpec 的检查
+// 运行中的 Pod 和工作负载的 Pod 模板（--templates）都以元数据和 PodSpec 的形式交给检查，
+// 模板的元数据中通常没有命名空间和名称，对象的身份从 ctx 读取
+// 内置检查和外部包通过 RegisterCheck 注册的检查都实现它，可以按名称启用或关闭
+// AnalyzePods 会在多个 goroutine 中并发调用 CheckPod
+type Check interface {
+	// Name 返回检查的名称，在所有检查中唯一，如 "probes"
+	Name() string
+	// CheckPod 返回对象上发现的问题，没有问题时返回 nil
+	CheckPod(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []Finding
+}
+
+// funcCheck 是由名称和函数组成的 Check
+type funcCheck struct {
+	name  string
+	check func(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []Finding
+}
+
+func (c funcCheck) Name() string { return c.name }
+func (c funcCheck) CheckPod(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []Finding {
+	return c.check(ctx, meta, spec)
+}
+
+// NewCheck 用名称和检查函数创建 Check
+func NewCheck(name string, check func(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []Finding) Check {
+	return funcCheck{name: name, check: check}
+}
+
+// NewPodCheck 用名称和针对整个 Pod 的检查函数创建 Check，用于需要 Pod 状态的检查
+// 它只检查运行中的 Pod（ctx.Pod），检查工作负载模板时不运行
+func NewPodCheck(name string, check func(ctx *CheckContext, pod *corev1.Pod) []Finding) Check {
+	return NewCheck(name, func(ctx *CheckContext, _ *metav1.ObjectMeta, _ *corev1.PodSpec) []Finding {
+		if ctx.Pod == nil {
+			return nil
+		}
+		return check(ctx, ctx.Pod)
+	})
+}
+
+// builtinCheck 是内置检查
+type builtinCheck struct {
+	name string
+	// option 返回启用检查的开关，按名称启用时由 ApplyCheckNames 打开，使调用方获取检查依赖的集群数据
+	// 不依赖集群数据的检查为 nil，按名称启用时直接运行
+	option func(o *Options) *bool
+	// enabled 决定没有按名称指定时是否启用，为 nil 时跟随 option
+	enabled func(opts Options) bool
+	// template 表示检查只读取元数据和 PodSpec，也用于工作负载模板；否则只检查运行中的 Pod
+	template bool
+	check    func(ctx *CheckContext, pod *corev1.Pod) []Finding
+}
+
+// newCheck 将内置检查转换为 Check
+// 内置检查基于 Pod 实现，检查模板时读取由 meta 和 spec 组成的 Pod，身份从 ctx 读取
+func (c builtinCheck) newCheck() Check {
+	if !c.template {
+		return NewPodCheck(c.name, c.check)
+	}
+	return NewCheck(c.name, func(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []Finding {
+		if ctx.Pod != nil {
+			return c.check(ctx, ctx.Pod)
+		}
+		return c.check(ctx, &corev1.Pod{ObjectMeta: *meta, Spec: *spec})
+	})
+}
+
+// defaultEnabled 判断内置检查在没有按名称指定时是否启用
+func (c builtinCheck) defaultEnabled(opts Options) bool {
+	if c.enabled != nil {
+		return c.enabled(opts)
+	}
+	return *c.option(&opts)
+}
+
+var (
+	checksMu         sync.Mutex
+	registeredChecks []Check
+)
+
+// RegisterCheck 注册外部检查，注册的检查默认启用，可以通过 Options.DisabledChecks 按名称关闭
+// 应在 init 中调用；名称为空或与已有检查重名时 panic
+func RegisterCheck(check Check) {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	name := check.Name()
+	if name == "" {
+		panic("analyzer: RegisterCheck with empty name")
+	}
+	if knownCheck(name) {
+		panic(fmt.Sprintf("analyzer: RegisterCheck called twice for check %q", name))
+	}
+	registeredChecks = append(registeredChecks, check)
+}
+
+// knownCheck 判断名称是否属于内置或已注册的检查，调用方需持有 checksMu
+func knownCheck(name string) bool {
+	for _, check := range builtinChecks {
+		if check.name == name {
+			return true
+		}
+	}
+	for _, check := range registeredChecks {
+		if check.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckNames 返回所有内置和已注册检查的名称，按字母排序
+func CheckNames() []string {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	names := make([]string, 0, len(builtinChecks)+len(registeredChecks))
+	for _, check := range builtinChecks {
+		names = append(names, check.name)
+	}
+	for _, check := range registeredChecks {
+		names = append(names, check.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ValidateCheckNames 检查名称是否都是内置或已注册的检查
+func ValidateCheckNames(names []string) error {
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	for _, name := range names {
+		if !knownCheck(name) {
+			return fmt.Errorf("unknown check %q", name)
+		}
+	}
+	return nil
+}
+
+// ApplyCheckNames 将 EnabledChecks 中依赖集群数据的内置检查转换为对应的开关并从列表中移除
+// 应在获取集群数据之前调用，这样按名称启用的检查（如 pdb）也会获取所需的对象，
+// 之后因 API 不可用而关闭开关时检查也随之跳过
+func (o *Options) ApplyCheckNames() {
+	remaining := o.EnabledChecks[:0:0]
+	for _, name := range o.EnabledChecks {
+		applied := false
+		for _, check := range builtinChecks {
+			if check.name == name && check.option != nil {
+				*check.option(o) = true
+				applied = true
+			}
+		}
+		if !applied {
+			remaining = append(remaining, name)
+		}
+	}
+	o.EnabledChecks = remaining
+}
+
+// activeChecks 返回选项启用的检查：内置检查在前，注册的检查按注册顺序在后
+// 内置检查默认跟随对应的开关，注册的检查默认启用；EnabledChecks/DisabledChecks 按名称覆盖，同时出现时关闭优先
+func activeChecks(opts Options) []Check {
+	enabled := make(map[string]bool, len(opts.EnabledChecks))
+	for _, name := range opts.EnabledChecks {
+		enabled[name] = true
+	}
+	disabled := make(map[string]bool, len(opts.DisabledChecks))
+	for _, name := range opts.DisabledChecks {
+		disabled[name] = true
+	}
+
+	checksMu.Lock()
+	defer checksMu.Unlock()
+	var checks []Check
+	for _, check := range builtinChecks {
+		if !disabled[check.name] && (enabled[check.name] || check.defaultEnabled(opts)) {
+			checks = append(checks, check.newCheck())
+		}
+	}
+	for _, check := range registeredChecks {
+		if !disabled[check.Name()] {
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// checkRunner 对 Pod 依次执行一组检查并合并去重结果
+type checkRunner struct {
+	ctx    *CheckContext
+	checks []Check
+}
+
+// newCheckRunner 根据选项组装启用的检查
+func newCheckRunner(opts Options) *checkRunner {
+	return &checkRunner{ctx: newCheckContext(opts), checks: activeChecks(opts)}
+}
+
+// Run 对运行中的 Pod 执行所有检查
+func (r *checkRunner) Run(pod *corev1.Pod) []ConfigIssue {
+	if r == nil {
+		return nil
+	}
+	ctx := *r.ctx
+	ctx.Namespace, ctx.Name = pod.Namespace, pod.Name
+	ctx.OwnerKind, ctx.OwnerName = ResolveOwner(pod)
+	ctx.Pod = pod
+	return r.run(&ctx, &pod.ObjectMeta, &pod.Spec)
+}
+
+// RunTemplate 对工作负载的 Pod 模板执行所有检查，只需要 Pod 状态的检查不运行
+func (r *checkRunner) RunTemplate(t WorkloadTemplate) []ConfigIssue {
+	ctx := *r.ctx
+	ctx.Namespace, ctx.Name = t.Namespace, t.Name
+	ctx.OwnerKind, ctx.OwnerName = t.Kind, t.Name
+	if t.Kind == "CronJob" {
+		ctx.OwnerKind = "Job"
+	}
+	ctx.podNameSuffix = generatedPodNameSuffix(t.Kind)
+	return r.run(&ctx, &t.Template.ObjectMeta, &t.Template.Spec)
+}
+
+// run 依次执行检查，合并去重结果
+func (r *checkRunner) run(ctx *CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, check := range r.checks {
+		for _, f := range check.CheckPod(ctx, meta, spec) {
+			issues = appendIfNotExists(issues, f.configIssue())
+		}
+	}
+	return issues
+}
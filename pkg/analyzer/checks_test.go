@@ -0,0 +1,170 @@
+package analyzer_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// issueNoTelemetrySocket 是组织内部检查报告的问题
+const issueNoTelemetrySocket analyzer.ConfigIssue = "Pod does not mount the telemetry socket"
+
+// telemetrySocketCheck 演示外部包实现的检查：带 telemetry 标签的 Pod 必须挂载 /var/run/telemetry
+// 它只读取元数据和 PodSpec，因此同样检查 --templates 的工作负载模板
+type telemetrySocketCheck struct{}
+
+func (telemetrySocketCheck) Name() string { return "telemetry-socket" }
+
+func (telemetrySocketCheck) CheckPod(_ *analyzer.CheckContext, meta *metav1.ObjectMeta, spec *corev1.PodSpec) []analyzer.Finding {
+	if meta.Labels["example.com/telemetry"] != "required" {
+		return nil
+	}
+	var findings []analyzer.Finding
+	for _, c := range spec.Containers {
+		mounted := false
+		for _, m := range c.VolumeMounts {
+			if m.MountPath == "/var/run/telemetry" {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			findings = append(findings, analyzer.Finding{Issue: issueNoTelemetrySocket, Detail: c.Name})
+		}
+	}
+	return findings
+}
+
+func init() {
+	analyzer.RegisterCheck(telemetrySocketCheck{})
+}
+
+func TestRegisteredCheck(t *testing.T) {
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "web",
+			Labels:    map[string]string{"example.com/telemetry": "required"},
+		},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: "app"},
+			{Name: "agent", VolumeMounts: []corev1.VolumeMount{{Name: "telemetry", MountPath: "/var/run/telemetry"}}},
+		}},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{pod}}
+	want := analyzer.ConfigIssue("Pod does not mount the telemetry socket (app)")
+
+	tests := []struct {
+		name string
+		opts analyzer.Options
+		want bool
+	}{
+		{name: "enabled by default", want: true},
+		{name: "disabled by name", opts: analyzer.Options{DisabledChecks: []string{"telemetry-socket"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := analyzer.AnalyzePods(pods, tt.opts).Pods[0].ConfigIssues
+			got := len(issues) == 1 && issues[0] == want
+			if got != tt.want || (!tt.want && len(issues) != 0) {
+				t.Errorf("ConfigIssues = %v, want registered finding: %v", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNames(t *testing.T) {
+	names := analyzer.CheckNames()
+	for _, want := range []string{"init-timeout", "job", "pdb", "probes", "psp", "required-labels", "resources", "service-ports", "telemetry-socket", "termination-message"} {
+		found := false
+		for _, name := range names {
+			if name == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("CheckNames() = %v, missing %q", names, want)
+		}
+	}
+	if err := analyzer.ValidateCheckNames([]string{"probes", "telemetry-socket"}); err != nil {
+		t.Errorf("ValidateCheckNames() = %v, want nil", err)
+	}
+	if err := analyzer.ValidateCheckNames([]string{"nope"}); err == nil {
+		t.Error("ValidateCheckNames(nope) = nil, want error")
+	}
+}
+
+func TestApplyCheckNames(t *testing.T) {
+	opts := analyzer.Options{EnabledChecks: []string{"pdb", "probes", "telemetry-socket"}}
+	opts.ApplyCheckNames()
+	if !opts.CheckPDB {
+		t.Error("CheckPDB = false, want true so that PDBs are fetched")
+	}
+	// 不依赖集群数据的检查仍按名称启用
+	if want := []string{"probes", "telemetry-socket"}; !reflect.DeepEqual(opts.EnabledChecks, want) {
+		t.Errorf("EnabledChecks = %v, want %v", opts.EnabledChecks, want)
+	}
+}
+
+func TestDisableBuiltinCheck(t *testing.T) {
+	started := metav1.NewTime(time.Now().Add(-time.Hour))
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"},
+		Spec:       corev1.PodSpec{InitContainers: []corev1.Container{{Name: "migrate"}}, Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:                 corev1.PodPending,
+			StartTime:             &started,
+			InitContainerStatuses: []corev1.ContainerStatus{{Name: "migrate", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}}},
+		},
+	}
+	pods := &corev1.PodList{Items: []corev1.Pod{pod}}
+
+	if issues := analyzer.AnalyzePods(pods, analyzer.Options{CheckInitTimeout: true}).Pods[0].ConfigIssues; len(issues) != 1 {
+		t.Fatalf("ConfigIssues = %v, want the init timeout", issues)
+	}
+	opts := analyzer.Options{CheckInitTimeout: true, DisabledChecks: []string{"init-timeout"}}
+	if issues := analyzer.AnalyzePods(pods, opts).Pods[0].ConfigIssues; len(issues) != 0 {
+		t.Errorf("ConfigIssues = %v, want none with init-timeout disabled", issues)
+	}
+}
+
+// statusCheck 演示需要 Pod 状态的检查：通过 NewPodCheck 注册，只检查运行中的 Pod
+var statusCheck = analyzer.NewPodCheck("pending-pod", func(_ *analyzer.CheckContext, pod *corev1.Pod) []analyzer.Finding {
+	if pod.Status.Phase == corev1.PodPending {
+		return []analyzer.Finding{{Issue: "Pod is pending"}}
+	}
+	return nil
+})
+
+func TestRegisteredCheckTemplates(t *testing.T) {
+	templates := []analyzer.WorkloadTemplate{{
+		Kind:      "Deployment",
+		Namespace: "default",
+		Name:      "web",
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"example.com/telemetry": "required"}},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		},
+	}}
+	results := analyzer.AnalyzeTemplates(templates, analyzer.Options{})
+	if want := []analyzer.ConfigIssue{"Pod does not mount the telemetry socket (app)"}; !reflect.DeepEqual(results[0].ConfigIssues, want) {
+		t.Errorf("ConfigIssues = %v, want %v", results[0].ConfigIssues, want)
+	}
+
+	// 模板没有状态，NewPodCheck 创建的检查不运行
+	ctx := &analyzer.CheckContext{Namespace: "default", Name: "web", OwnerKind: "Deployment"}
+	if got := statusCheck.CheckPod(ctx, &metav1.ObjectMeta{}, &corev1.PodSpec{}); got != nil {
+		t.Errorf("findings for a template = %v, want none", got)
+	}
+	ctx.Pod = &corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodPending}}
+	if got := statusCheck.CheckPod(ctx, &ctx.Pod.ObjectMeta, &ctx.Pod.Spec); len(got) != 1 {
+		t.Errorf("findings for a pending pod = %v, want one", got)
+	}
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RetainedJobPodsThreshold 是 Job 保留的已结束 Pod 数量达到多少时，建议为其设置 ttlSecondsAfterFinished
+const RetainedJobPodsThreshold = 5
+
+// RetainedPodsThreshold 是一个命名空间保留的已结束 Pod 数量达到多少时，没有 --cleanup-plan 也给出清理建议
+const RetainedPodsThreshold = 50
+
+// RetainedPods 是一个命名空间中保留下来的已结束 Pod（Succeeded/Failed）
+// 这些 Pod 会拖慢列表请求，并让真正的问题淹没在大量空壳中
+type RetainedPods struct {
+	Namespace string
+	Succeeded int
+	Failed    int
+	Evicted   int // Failed 中被驱逐的 Pod
+}
+
+// Total 返回已结束 Pod 的总数
+func (r RetainedPods) Total() int {
+	return r.Succeeded + r.Failed
+}
+
+// HasExcessRetainedPods 判断是否有命名空间保留的已结束 Pod 达到 RetainedPodsThreshold
+func (r *AnalysisResult) HasExcessRetainedPods() bool {
+	for _, retained := range r.RetainedPods {
+		if retained.Total() >= RetainedPodsThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// RetainingJob 是保留了已结束 Pod 的 Job
+type RetainingJob struct {
+	Namespace string
+	Name      string
+	Pods      int // 保留的已结束 Pod 数量
+}
+
+// retainedPods 按命名空间统计已结束的 Pod，结果按命名空间排序
+func retainedPods(pods *corev1.PodList) []RetainedPods {
+	byNamespace := make(map[string]*RetainedPods)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isTerminal(pod) {
+			continue
+		}
+		r, ok := byNamespace[pod.Namespace]
+		if !ok {
+			r = &RetainedPods{Namespace: pod.Namespace}
+			byNamespace[pod.Namespace] = r
+		}
+		if pod.Status.Phase == corev1.PodSucceeded {
+			r.Succeeded++
+			continue
+		}
+		r.Failed++
+		if pod.Status.Reason == "Evicted" {
+			r.Evicted++
+		}
+	}
+
+	retained := make([]RetainedPods, 0, len(byNamespace))
+	for _, r := range byNamespace {
+		retained = append(retained, *r)
+	}
+	sort.Slice(retained, func(i, j int) bool {
+		return retained[i].Namespace < retained[j].Namespace
+	})
+	return retained
+}
+
+// RetainingJobs 返回保留的已结束 Pod 达到 RetainedJobPodsThreshold 的 Job 及其 Pod 数量
+func RetainingJobs(pods *corev1.PodList) []RetainingJob {
+	counts := make(map[RetainingJob]int)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !isTerminal(pod) {
+			continue
+		}
+		if kind, name := ResolveOwner(pod); kind == "Job" {
+			counts[RetainingJob{Namespace: pod.Namespace, Name: name}]++
+		}
+	}
+
+	var jobs []RetainingJob
+	for job, count := range counts {
+		if count >= RetainedJobPodsThreshold {
+			job.Pods = count
+			jobs = append(jobs, job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Pods != jobs[j].Pods {
+			return jobs[i].Pods > jobs[j].Pods
+		}
+		if jobs[i].Namespace != jobs[j].Namespace {
+			return jobs[i].Namespace < jobs[j].Namespace
+		}
+		return jobs[i].Name < jobs[j].Name
+	})
+	return jobs
+}
+
+// jobsWithoutTTL 从 RetainingJobs 中找出没有设置 ttlSecondsAfterFinished 的 Job
+// 需要 ClusterData 中的 Job，找不到的 Job 不报告
+func jobsWithoutTTL(pods *corev1.PodList, data *ClusterData) []RetainingJob {
+	var jobs []RetainingJob
+	for _, retaining := range RetainingJobs(pods) {
+		job := data.job(retaining.Namespace, retaining.Name)
+		if job != nil && job.Spec.TTLSecondsAfterFinished == nil {
+			jobs = append(jobs, retaining)
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssueStaleReadyCondition 表示 Pod 的 Ready 条件很久没有变化
+// 运行多个月从未变化的 Ready=True 条件可能说明探针间隔过长，或探针从未真正检查过应用
+// 细节中给出实际时长和阈值，如 "unchanged for 120 days, threshold 90 days"
+const IssueStaleReadyCondition ConfigIssue = "Ready condition has not transitioned for longer than --stale-condition-threshold"
+
+// DefaultStaleConditionThreshold 是 Ready 条件没有变化的默认时长上限
+const DefaultStaleConditionThreshold = 90 * 24 * time.Hour
+
+// checkStaleReadyCondition 检查 Ready=True 条件的 LastTransitionTime 是否早于 threshold，threshold 为 0 时使用默认值
+func checkStaleReadyCondition(pod *corev1.Pod, threshold time.Duration) []ConfigIssue {
+	if threshold <= 0 {
+		threshold = DefaultStaleConditionThreshold
+	}
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodReady || cond.Status != corev1.ConditionTrue || cond.LastTransitionTime.IsZero() {
+			continue
+		}
+		if age := Now().Sub(cond.LastTransitionTime.Time); age > threshold {
+			return []ConfigIssue{withDetail(IssueStaleReadyCondition, fmt.Sprintf("unchanged for %s, threshold %s", formatDays(age), formatDays(threshold)))}
+		}
+	}
+	return nil
+}
+
+// formatDays 将一天以上的时长按整天格式化，如 "120 days"，不足一天时退回 formatDuration
+func formatDays(d time.Duration) string {
+	if d < 24*time.Hour {
+		return formatDuration(d)
+	}
+	return pluralize(int(d.Hours()/24), "day")
+}
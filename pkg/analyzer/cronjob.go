@@ -0,0 +1,217 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CronJobStatus 表示 CronJob 的健康状态
+type CronJobStatus string
+
+const (
+	CronJobHealthy   CronJobStatus = "Healthy"
+	CronJobLate      CronJobStatus = "Late"
+	CronJobFailing   CronJobStatus = "Failing"
+	CronJobSuspended CronJobStatus = "Suspended"
+)
+
+// cronJobScheduleGrace 是判断调度是否延迟时允许的误差
+const cronJobScheduleGrace = 2 * time.Minute
+
+// CronJobAnalysis 包含单个 CronJob 的分析结果
+type CronJobAnalysis struct {
+	Name        string
+	Namespace   string
+	Schedule    string
+	Status      CronJobStatus
+	LastSuccess string // 上次成功完成距今的时间，如 "9h0m"，没有时为 "-"
+	LastFailure string // 上次失败距今的时间，如 "4m"，没有时为 "-"
+	Active      int    // 正在运行的 Job 数量
+	Reason      string // 状态说明
+}
+
+// AnalyzeCronJobs 根据 CronJob 及其 Job/Pod 分析调度健康状况
+func AnalyzeCronJobs(cronJobs *batchv1.CronJobList, jobs *batchv1.JobList, pods *corev1.PodList) []CronJobAnalysis {
+	now := Now()
+
+	// 按 CronJob 归类 Job
+	jobsByOwner := make(map[string][]*batchv1.Job)
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		ref := metav1.GetControllerOf(job)
+		if ref == nil || ref.Kind != "CronJob" {
+			continue
+		}
+		key := job.Namespace + "/" + ref.Name
+		jobsByOwner[key] = append(jobsByOwner[key], job)
+	}
+
+	// 按 Job 归类 Pod，用于获取失败原因
+	podsByJob := make(map[string][]*corev1.Pod)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		ref := metav1.GetControllerOf(pod)
+		if ref == nil || ref.Kind != "Job" {
+			continue
+		}
+		key := pod.Namespace + "/" + ref.Name
+		podsByJob[key] = append(podsByJob[key], pod)
+	}
+
+	results := make([]CronJobAnalysis, 0, len(cronJobs.Items))
+	for i := range cronJobs.Items {
+		cj := &cronJobs.Items[i]
+		results = append(results, analyzeCronJob(cj, jobsByOwner[cj.Namespace+"/"+cj.Name], podsByJob, now))
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Namespace != results[j].Namespace {
+			return results[i].Namespace < results[j].Namespace
+		}
+		return results[i].Name < results[j].Name
+	})
+	return results
+}
+
+// analyzeCronJob 分析单个 CronJob
+func analyzeCronJob(cj *batchv1.CronJob, jobs []*batchv1.Job, podsByJob map[string][]*corev1.Pod, now time.Time) CronJobAnalysis {
+	analysis := CronJobAnalysis{
+		Name:        cj.Name,
+		Namespace:   cj.Namespace,
+		Schedule:    cj.Spec.Schedule,
+		Status:      CronJobHealthy,
+		LastSuccess: "-",
+		LastFailure: "-",
+	}
+
+	// 找出最近一次成功和失败的 Job
+	var lastSuccess, lastFailure *time.Time
+	var failedJob *batchv1.Job
+	var activeJobs []*batchv1.Job
+	for _, job := range jobs {
+		finished, failed, at := jobFinished(job)
+		if !finished {
+			activeJobs = append(activeJobs, job)
+			continue
+		}
+		if failed {
+			if lastFailure == nil || at.After(*lastFailure) {
+				lastFailure = &at
+				failedJob = job
+			}
+		} else if lastSuccess == nil || at.After(*lastSuccess) {
+			lastSuccess = &at
+		}
+	}
+	// CronJob 状态中的 LastSuccessfulTime 在 Job 被清理后依然保留
+	if t := cj.Status.LastSuccessfulTime; t != nil && (lastSuccess == nil || t.After(*lastSuccess)) {
+		lastSuccess = &t.Time
+	}
+
+	// 列中只显示时长，如 "5m"，不带 "ago"
+	if lastSuccess != nil {
+		analysis.LastSuccess = formatDuration(now.Sub(*lastSuccess))
+	}
+	if lastFailure != nil {
+		analysis.LastFailure = formatDuration(now.Sub(*lastFailure))
+	}
+	analysis.Active = len(activeJobs)
+
+	// 暂停的 CronJob 只做信息展示
+	if cj.Spec.Suspend != nil && *cj.Spec.Suspend {
+		analysis.Status = CronJobSuspended
+		analysis.Reason = "Suspended"
+		return analysis
+	}
+
+	// 最近一次完成的运行失败
+	if lastFailure != nil && (lastSuccess == nil || lastFailure.After(*lastSuccess)) {
+		analysis.Status = CronJobFailing
+		analysis.Reason = "Last run failed"
+		if reason := jobFailureReason(failedJob, podsByJob); reason != "" {
+			analysis.Reason += ": " + reason
+		}
+		return analysis
+	}
+
+	schedule, err := parseCronSchedule(cj)
+	if err != nil {
+		analysis.Status = CronJobFailing
+		analysis.Reason = fmt.Sprintf("Invalid schedule: %v", err)
+		return analysis
+	}
+
+	// 正在运行的 Job 超过了下一次调度时间
+	for _, job := range activeJobs {
+		start := job.CreationTimestamp.Time
+		if job.Status.StartTime != nil {
+			start = job.Status.StartTime.Time
+		}
+		if next := schedule.Next(start); now.After(next.Add(cronJobScheduleGrace)) {
+			analysis.Status = CronJobLate
+			analysis.Reason = fmt.Sprintf("Job %s still running past schedule interval (started %s ago)", job.Name, formatDuration(now.Sub(start)))
+			return analysis
+		}
+	}
+
+	// 最近一次应该发生的调度没有出现
+	last := cj.CreationTimestamp.Time
+	if cj.Status.LastScheduleTime != nil {
+		last = cj.Status.LastScheduleTime.Time
+	}
+	if next := schedule.Next(last); now.After(next.Add(cronJobScheduleGrace)) {
+		analysis.Status = CronJobLate
+		analysis.Reason = fmt.Sprintf("Scheduled run missing (expected %s ago)", formatDuration(now.Sub(next)))
+	}
+
+	return analysis
+}
+
+// parseCronSchedule 解析 CronJob 的调度表达式，支持 spec.timeZone
+func parseCronSchedule(cj *batchv1.CronJob) (cron.Schedule, error) {
+	spec := cj.Spec.Schedule
+	if cj.Spec.TimeZone != nil && *cj.Spec.TimeZone != "" {
+		spec = "CRON_TZ=" + *cj.Spec.TimeZone + " " + spec
+	}
+	return cron.ParseStandard(spec)
+}
+
+// jobFinished 返回 Job 是否结束、是否失败以及结束时间
+func jobFinished(job *batchv1.Job) (finished bool, failed bool, at time.Time) {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return true, false, cond.LastTransitionTime.Time
+		case batchv1.JobFailed:
+			return true, true, cond.LastTransitionTime.Time
+		}
+	}
+	return false, false, time.Time{}
+}
+
+// jobFailureReason 从 Job 的条件或其 Pod 中提取失败原因
+func jobFailureReason(job *batchv1.Job, podsByJob map[string][]*corev1.Pod) string {
+	if job == nil {
+		return ""
+	}
+	for _, pod := range podsByJob[job.Namespace+"/"+job.Name] {
+		if pod.Status.Phase == corev1.PodFailed {
+			return getFailedReason(pod)
+		}
+	}
+	for _, cond := range job.Status.Conditions {
+		if cond.Type == batchv1.JobFailed && cond.Reason != "" {
+			return cond.Reason
+		}
+	}
+	return ""
+}
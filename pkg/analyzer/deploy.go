@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadDeploy 是 Deployment 最近一次发布的大致时间及其问题开始的时间
+type WorkloadDeploy struct {
+	Namespace string
+	Name      string
+	// DeployedAt 是当前 ReplicaSet（最新的 pod-template-hash）中最早的 Pod 的创建时间，近似为最近一次发布的时间
+	DeployedAt time.Time
+	// Deployed 是 DeployedAt 的易读形式，如 "3h ago"
+	Deployed string
+	// ProblemSince 是该工作负载有问题的 Pod 中最早的问题开始时间，没有问题时为零值
+	ProblemSince time.Time
+}
+
+// IssuesAfterDeploy 返回问题在发布之后多久开始，问题早于发布或没有问题时返回 false
+func (d WorkloadDeploy) IssuesAfterDeploy() (time.Duration, bool) {
+	if d.ProblemSince.IsZero() || d.ProblemSince.Before(d.DeployedAt) {
+		return 0, false
+	}
+	return d.ProblemSince.Sub(d.DeployedAt), true
+}
+
+// IssuesNote 返回问题与发布的关系，如 "issues began 12m after deploy"，问题早于发布时为 "issues predate deploy"，没有问题时为空
+func (d WorkloadDeploy) IssuesNote() string {
+	if d.ProblemSince.IsZero() {
+		return ""
+	}
+	after, ok := d.IssuesAfterDeploy()
+	if !ok {
+		return "issues predate deploy"
+	}
+	return "issues began " + formatDuration(after) + " after deploy"
+}
+
+// findDeploys 按 pod-template-hash 估算每个 Deployment 最近一次发布的时间，按命名空间和名称排序
+// 最早的 Pod 最新的那一代是当前的 ReplicaSet；analyses 与 pods 下标一一对应，用于取问题开始的时间
+func findDeploys(pods *corev1.PodList, analyses []PodAnalysis, formatTime func(time.Time) string) []WorkloadDeploy {
+	// generations 按 namespace/name 和 pod-template-hash 记录每一代最早的 Pod 创建时间
+	generations := make(map[string]map[string]time.Time)
+	problems := make(map[string]time.Time)
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, name := ResolveOwner(pod)
+		if kind != "Deployment" {
+			continue
+		}
+		key := pod.Namespace + "/" + name
+		hash := pod.Labels["pod-template-hash"]
+		if generations[key] == nil {
+			generations[key] = make(map[string]time.Time)
+		}
+		if created, ok := generations[key][hash]; !ok || pod.CreationTimestamp.Time.Before(created) {
+			generations[key][hash] = pod.CreationTimestamp.Time
+		}
+		if since := analyses[i].ProblemSince; !since.IsZero() && (problems[key].IsZero() || since.Before(problems[key])) {
+			problems[key] = since
+		}
+	}
+
+	deploys := make([]WorkloadDeploy, 0, len(generations))
+	for key, hashes := range generations {
+		var deployedAt time.Time
+		for _, created := range hashes {
+			if created.After(deployedAt) {
+				deployedAt = created
+			}
+		}
+		namespace, name, _ := strings.Cut(key, "/")
+		deploys = append(deploys, WorkloadDeploy{
+			Namespace:    namespace,
+			Name:         name,
+			DeployedAt:   deployedAt,
+			Deployed:     formatAgo(formatTime, deployedAt),
+			ProblemSince: problems[key],
+		})
+	}
+	sort.Slice(deploys, func(i, j int) bool {
+		if deploys[i].Namespace != deploys[j].Namespace {
+			return deploys[i].Namespace < deploys[j].Namespace
+		}
+		return deploys[i].Name < deploys[j].Name
+	})
+	return deploys
+}
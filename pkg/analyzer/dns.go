@@ -0,0 +1,172 @@
+package analyzer
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DNS 相关的配置问题（--check-dns，启发式检查）
+const (
+	IssueDNSOverride          ConfigIssue = "Pod overrides cluster DNS settings"
+	IssueDNSDefaultPolicy     ConfigIssue = "dnsPolicy: Default but pod references cluster Services"
+	IssueNdotsExternalLookups ConfigIssue = "Pod calls external hostnames with default ndots:5 (consider a dnsConfig ndots override)"
+	IssueDNSWithHostNetwork   ConfigIssue = "HostNetwork pod should use dnsPolicy: ClusterFirstWithHostNet"
+)
+
+// checkDNS 检查 Pod 的 DNS 配置
+func checkDNS(pod *corev1.Pod) []ConfigIssue {
+	var issues []ConfigIssue
+
+	policy := pod.Spec.DNSPolicy
+	if policy == "" {
+		policy = corev1.DNSClusterFirst
+	}
+
+	// 1. 报告覆盖了默认 DNS 设置的 Pod；hostNetwork Pod 使用 ClusterFirstWithHostNet 正是检查 2 的建议，不算覆盖
+	recommended := policy == corev1.DNSClusterFirst || pod.Spec.HostNetwork && policy == corev1.DNSClusterFirstWithHostNet
+	if !recommended || pod.Spec.DNSConfig != nil {
+		issues = append(issues, withDetail(IssueDNSOverride, "dnsPolicy: "+string(policy)))
+	}
+
+	// 2. hostNetwork Pod 使用 ClusterFirst 时回退到节点的 resolv.conf，绕过集群 DNS
+	if pod.Spec.HostNetwork && policy == corev1.DNSClusterFirst {
+		issues = append(issues, IssueDNSWithHostNetwork)
+	}
+
+	clusterRefs, externalRefs := envHostnames(pod)
+
+	// 3. dnsPolicy: Default 使用节点的 resolv.conf，无法解析集群内的 Service
+	if policy == corev1.DNSDefault && len(clusterRefs) > 0 {
+		issues = append(issues, withDetail(IssueDNSDefaultPolicy, strings.Join(clusterRefs, ", ")))
+	}
+
+	// 4. 默认 ndots:5 会让外部域名先经过所有 search 域查询
+	if (policy == corev1.DNSClusterFirst || policy == corev1.DNSClusterFirstWithHostNet) &&
+		len(externalRefs) > 0 && !hasNdotsOption(pod) {
+		detail := externalRefs[0]
+		if len(externalRefs) > 1 {
+			detail = fmt.Sprintf("%s and %d more", detail, len(externalRefs)-1)
+		}
+		issues = append(issues, withDetail(IssueNdotsExternalLookups, detail))
+	}
+
+	return issues
+}
+
+// hasNdotsOption 检查 Pod 是否通过 dnsConfig 设置了 ndots
+func hasNdotsOption(pod *corev1.Pod) bool {
+	if pod.Spec.DNSConfig == nil {
+		return false
+	}
+	for _, opt := range pod.Spec.DNSConfig.Options {
+		if opt.Name == "ndots" {
+			return true
+		}
+	}
+	return false
+}
+
+// envHostnames 从容器环境变量中提取主机名
+// 返回集群内 Service 域名和需要经过 search 域查询的外部域名（均已去重）；
+// 末尾带点的绝对域名不经过 search 域，不受 ndots 影响，不返回
+func envHostnames(pod *corev1.Pod) (clusterRefs, externalRefs []string) {
+	seen := make(map[string]bool)
+	for _, container := range pod.Spec.Containers {
+		for _, env := range container.Env {
+			host := hostFromValue(env.Value)
+			if host == "" || seen[host] {
+				continue
+			}
+			seen[host] = true
+
+			name := strings.TrimSuffix(host, ".")
+			switch {
+			case isClusterHostname(name, pod.Namespace):
+				clusterRefs = append(clusterRefs, name)
+			case isExternalFQDN(host) && name == host:
+				externalRefs = append(externalRefs, host)
+			}
+		}
+	}
+	return clusterRefs, externalRefs
+}
+
+// hostFromValue 从环境变量的值中提取主机名，支持 URL 和 host:port 形式，保留表示绝对域名的末尾的点
+func hostFromValue(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.ContainsAny(value, " ,;") {
+		return ""
+	}
+
+	if strings.Contains(value, "://") {
+		u, err := url.Parse(value)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+
+	host := value
+	if h, _, err := net.SplitHostPort(value); err == nil {
+		host = h
+	}
+	if strings.Contains(host, "/") {
+		return ""
+	}
+	return strings.ToLower(host)
+}
+
+// isClusterHostname 判断是否是集群内 Service 的域名：name.namespace.svc[.cluster.local]，
+// 或与 Pod 同一命名空间的 name.namespace 短名称
+func isClusterHostname(host, namespace string) bool {
+	if strings.HasSuffix(host, ".svc") || strings.HasSuffix(host, ".svc.cluster.local") || strings.Contains(host, ".svc.") {
+		return true
+	}
+	labels := strings.Split(host, ".")
+	return namespace != "" && len(labels) == 2 && labels[1] == namespace
+}
+
+// publicTLDs 是常见的公共顶级域，用于区分外部域名与 name.namespace 短名称、Java 类名等带点的值
+var publicTLDs = map[string]bool{
+	"com": true, "net": true, "org": true, "io": true, "dev": true, "app": true, "cloud": true,
+	"ai": true, "co": true, "info": true, "biz": true, "me": true, "xyz": true, "tech": true,
+	"gov": true, "edu": true, "cn": true, "uk": true, "de": true, "fr": true, "jp": true,
+	"kr": true, "in": true, "us": true, "eu": true, "nl": true, "au": true, "ca": true,
+	"br": true, "ru": true, "sg": true, "hk": true, "tw": true,
+}
+
+// isExternalFQDN 判断是否像一个外部的完整域名：末尾带点的绝对域名，或至少两段且顶级域是常见的公共顶级域
+// 以公共顶级域开头的三段以上的值（如 com.example.Main）是 Java 包名或类名，不是域名
+func isExternalFQDN(host string) bool {
+	absolute := strings.HasSuffix(host, ".")
+	host = strings.TrimSuffix(host, ".")
+	if net.ParseIP(host) != nil || isClusterHostname(host, "") {
+		return false
+	}
+
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	if absolute {
+		return true
+	}
+	if len(labels) > 2 && publicTLDs[labels[0]] {
+		return false
+	}
+	return publicTLDs[labels[len(labels)-1]]
+}
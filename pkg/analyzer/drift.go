@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 运行状态与声明不一致的配置问题
+const (
+	// IssueStaleImage 表示容器运行的镜像已不是镜像仓库中该 tag 的最新版本
+	IssueStaleImage ConfigIssue = "Container is running a stale image (newer digest available)"
+	// IssueSpecDrift 表示模板中声明的资源在运行中的容器上取值不同（准入 webhook 改写或原地调整大小）
+	IssueSpecDrift ConfigIssue = "Running pod spec differs from owner template"
+	// IssueTemplateDrift 表示 Pod 的镜像与创建它的 ReplicaSet 模板不一致
+	// 运行中的 Pod 只有镜像可以修改，通常是 kubectl edit pod 之类的直接修改，Pod 被重建后修改会丢失
+	IssueTemplateDrift ConfigIssue = "Pod drifted from controller template"
+)
+
+// checkStaleImage 比较容器运行的镜像 digest 与镜像仓库中同一 tag 当前的 digest
+// 已固定 digest 的镜像、运行时未上报 repo digest 或查询失败的镜像不检查
+func checkStaleImage(pod *corev1.Pod, container *corev1.Container, data *ClusterData) []ConfigIssue {
+	latest := data.remoteDigest(container.Image)
+	if latest == "" {
+		return nil
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != container.Name {
+			continue
+		}
+		running := RunningDigest(cs)
+		if running == "" || running == latest {
+			return nil
+		}
+		detail := "running " + shortDigest(running) + ", latest " + shortDigest(latest)
+		return []ConfigIssue{withDetail(IssueStaleImage, detail)}
+	}
+	return nil
+}
+
+// RunningDigest 从容器状态的 ImageID 中取出 repo digest，如 "docker-pullable://nginx@sha256:..."
+// 只有镜像 ID（本地配置的 sha256）时无法与镜像仓库比较，返回空字符串
+func RunningDigest(cs corev1.ContainerStatus) string {
+	if _, digest, ok := strings.Cut(cs.ImageID, "@"); ok {
+		return digest
+	}
+	return ""
+}
+
+// shortDigest 将 digest 缩短为 "sha256:" 加 12 位十六进制
+func shortDigest(digest string) string {
+	algorithm, hex, ok := strings.Cut(digest, ":")
+	if !ok || len(hex) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hex[:12]
+}
+
+// checkImageDrift 比较容器实际运行的镜像与 Deployment 模板中声明的镜像
+// 手动 kubectl set image 或部分完成的发布都会导致两者不一致；edited 中的容器已由 checkTemplateDrift 报告为直接修改，不重复报告
+func checkImageDrift(pod *corev1.Pod, deploy *appsv1.Deployment, edited map[string]bool) []ConfigIssue {
+	declared := make(map[string]string)
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		declared[c.Name] = c.Image
+	}
+
+	var issues []ConfigIssue
+	for _, cs := range pod.Status.ContainerStatuses {
+		want, ok := declared[cs.Name]
+		if !ok || cs.Image == "" || edited[cs.Name] {
+			continue
+		}
+		// 部分运行时只上报镜像 ID，无法比较
+		if strings.HasPrefix(cs.Image, "sha256:") {
+			continue
+		}
+		if normalizeImage(cs.Image) != normalizeImage(want) {
+			issues = append(issues, withDetail(IssueImageDrift, cs.Name+": running "+cs.Image+", spec "+want))
+		}
+	}
+	return issues
+}
+
+// checkSpecDrift 按容器名比较容器的 requests/limits 与 Deployment 模板中的声明
+// 只比较模板中声明的资源：LimitRange 或准入 webhook 补充的默认值不算漂移，改写已声明的值或原地调整大小才算
+func checkSpecDrift(pod *corev1.Pod, deploy *appsv1.Deployment) []ConfigIssue {
+	declared := make(map[string]corev1.ResourceRequirements)
+	for _, c := range deploy.Spec.Template.Spec.Containers {
+		declared[c.Name] = c.Resources
+	}
+
+	var issues []ConfigIssue
+	for _, c := range pod.Spec.Containers {
+		want, ok := declared[c.Name]
+		if !ok {
+			continue
+		}
+		diffs := resourceListDiff("requests", c.Resources.Requests, want.Requests)
+		diffs = append(diffs, resourceListDiff("limits", c.Resources.Limits, want.Limits)...)
+		if len(diffs) > 0 {
+			issues = append(issues, withDetail(IssueSpecDrift, c.Name+": "+strings.Join(diffs, ", ")))
+		}
+	}
+	return issues
+}
+
+// templateFingerprints 缓存 ReplicaSet 模板的指纹，同一 ReplicaSet 的所有 Pod 只计算一次
+type templateFingerprints struct {
+	mu     sync.Mutex
+	hashes map[string]uint64 // namespace/name@resourceVersion -> 指纹
+}
+
+// newTemplateFingerprints 创建空的模板指纹缓存
+func newTemplateFingerprints() *templateFingerprints {
+	return &templateFingerprints{hashes: make(map[string]uint64)}
+}
+
+// template 返回 ReplicaSet 模板的指纹
+func (f *templateFingerprints) template(rs *appsv1.ReplicaSet) uint64 {
+	key := rs.Namespace + "/" + rs.Name + "@" + rs.ResourceVersion
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	hash, ok := f.hashes[key]
+	if !ok {
+		hash = imagesFingerprint(rs.Spec.Template.Spec.Containers, nil)
+		f.hashes[key] = hash
+	}
+	return hash
+}
+
+// imagesFingerprint 计算容器名称和镜像的指纹，容器按名称排序
+// only 不为 nil 时只包含其中的容器，用于忽略准入时注入的 sidecar
+func imagesFingerprint(containers []corev1.Container, only map[string]bool) uint64 {
+	sorted := make([]corev1.Container, 0, len(containers))
+	for _, c := range containers {
+		if only == nil || only[c.Name] {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	h := fnv.New64a()
+	for _, c := range sorted {
+		h.Write([]byte(c.Name))
+		h.Write([]byte{0})
+		h.Write([]byte(normalizeImage(c.Image)))
+		h.Write([]byte{1})
+	}
+	return h.Sum64()
+}
+
+// checkTemplateDrift 比较 Pod 与创建它的 ReplicaSet 模板中的镜像，返回问题和被直接修改的容器
+// 运行中的 Pod 只有镜像可以修改，环境变量和资源的差异来自 LimitRange 或准入 webhook 的默认值，不属于直接修改；
+// 与 checkImageDrift 不同，比较的是 Pod 所属的那一代模板，发布过程中的旧 Pod 不会被误报；
+// 指纹相同时跳过逐个比较，只对不一致的 Pod 列出具体差异
+func checkTemplateDrift(pod *corev1.Pod, rs *appsv1.ReplicaSet, fingerprints *templateFingerprints) ([]ConfigIssue, map[string]bool) {
+	template := make(map[string]string)
+	names := make(map[string]bool)
+	for _, c := range rs.Spec.Template.Spec.Containers {
+		template[c.Name] = c.Image
+		names[c.Name] = true
+	}
+	if imagesFingerprint(pod.Spec.Containers, names) == fingerprints.template(rs) {
+		return nil, nil
+	}
+
+	var issues []ConfigIssue
+	edited := make(map[string]bool)
+	for _, c := range pod.Spec.Containers {
+		want := template[c.Name]
+		if want == "" || normalizeImage(c.Image) == normalizeImage(want) {
+			continue
+		}
+		edited[c.Name] = true
+		issues = append(issues, withDetail(IssueTemplateDrift, c.Name+": image "+c.Image+", template "+want))
+	}
+	return issues, edited
+}
+
+// resourceListDiff 列出模板中声明、运行值不同的资源，如 "limits.memory 512Mi, template 256Mi"
+// 模板没有声明的资源不比较，它们通常是准入时补充的默认值
+func resourceListDiff(field string, running, template corev1.ResourceList) []string {
+	names := make([]string, 0, len(template))
+	for name := range template {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	var diffs []string
+	for _, name := range names {
+		want := template[corev1.ResourceName(name)]
+		got, ok := running[corev1.ResourceName(name)]
+		if ok && got.Cmp(want) == 0 {
+			continue
+		}
+		gotStr := "unset"
+		if ok {
+			gotStr = got.String()
+		}
+		diffs = append(diffs, field+"."+name+" "+gotStr+", template "+want.String())
+	}
+	return diffs
+}
+
+// normalizeImage 将镜像引用规范化，使 "nginx" 与 "docker.io/library/nginx:latest" 可以比较
+func normalizeImage(image string) string {
+	name, suffix := image, ""
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, suffix = name[:i], name[i:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, suffix = name[:i], name[i:]
+	}
+	if suffix == "" {
+		suffix = ":latest"
+	}
+
+	// 第一段包含 "." 或 ":" 或为 localhost 时视为镜像仓库地址
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 || !(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+		name = "docker.io/" + name
+	}
+	return name + suffix
+}
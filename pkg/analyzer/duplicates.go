@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DuplicateExclusions 控制重复工作负载检测跳过哪些工作负载
+// 有些工作负载按设计在每个命名空间各部署一份，不应视为遗忘的副本
+type DuplicateExclusions struct {
+	// Kinds 是跳过的工作负载类型，如 DaemonSet
+	Kinds []string `json:"kinds,omitempty"`
+	// Names 是跳过的工作负载名称的 glob 模式，如 "*-operator"
+	Names []string `json:"names,omitempty"`
+}
+
+// DefaultDuplicateExclusions 是默认的排除规则：DaemonSet 和按命名空间安装的 operator
+var DefaultDuplicateExclusions = DuplicateExclusions{
+	Kinds: []string{"DaemonSet"},
+	Names: []string{"*-operator", "*-operator-controller-manager"},
+}
+
+// Validate 检查名称模式是否是合法的 glob
+func (e DuplicateExclusions) Validate() error {
+	for _, pattern := range e.Names {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid names entry %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// excludes 判断工作负载是否被排除
+func (e DuplicateExclusions) excludes(kind, name string) bool {
+	for _, k := range e.Kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	for _, pattern := range e.Names {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// DuplicateWorkload 是在多个命名空间中以相同名称或相同镜像出现的工作负载
+type DuplicateWorkload struct {
+	// MatchedBy 是 "name" 或 "image"
+	MatchedBy string
+	// Key 是相同的工作负载名称，或相同的镜像列表
+	Key       string
+	Instances []WorkloadInstance
+}
+
+// WorkloadInstance 是重复工作负载在某个命名空间中的一份
+type WorkloadInstance struct {
+	Namespace string
+	Kind      string
+	Name      string
+	Replicas  int    // 当前的 Pod 数量
+	Age       string // 最早的 Pod 的年龄
+}
+
+// workloadGroup 是同一工作负载的 Pod 汇总
+type workloadGroup struct {
+	instance WorkloadInstance
+	images   string
+	oldest   time.Time
+}
+
+// findDuplicateWorkloads 找出在多个命名空间中名称相同或镜像相同的工作负载
+// 按名称匹配到的工作负载不会再按镜像重复报告；没有所属工作负载的 Pod 不参与检测
+func findDuplicateWorkloads(pods *corev1.PodList, exclusions DuplicateExclusions, formatTime func(time.Time) string) []DuplicateWorkload {
+	groups := make(map[string]*workloadGroup)
+	var keys []string
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, name := ResolveOwner(pod)
+		if kind == "" || isTerminal(pod) || exclusions.excludes(kind, name) {
+			continue
+		}
+
+		key := pod.Namespace + "/" + kind + "/" + name
+		group, ok := groups[key]
+		if !ok {
+			group = &workloadGroup{
+				instance: WorkloadInstance{Namespace: pod.Namespace, Kind: kind, Name: name},
+				images:   podImages(pod),
+				oldest:   pod.CreationTimestamp.Time,
+			}
+			groups[key] = group
+			keys = append(keys, key)
+		}
+		group.instance.Replicas++
+		if pod.CreationTimestamp.Time.Before(group.oldest) {
+			group.oldest = pod.CreationTimestamp.Time
+		}
+	}
+	sort.Strings(keys)
+
+	byName := make(map[string][]*workloadGroup)
+	byImages := make(map[string][]*workloadGroup)
+	for _, key := range keys {
+		group := groups[key]
+		group.instance.Age = formatTime(group.oldest)
+		byName[group.instance.Name] = append(byName[group.instance.Name], group)
+		byImages[group.images] = append(byImages[group.images], group)
+	}
+
+	var duplicates []DuplicateWorkload
+	reported := make(map[*workloadGroup]bool)
+	for _, name := range sortedGroupKeys(byName) {
+		if instances := spanNamespaces(byName[name], reported); len(instances) > 0 {
+			duplicates = append(duplicates, DuplicateWorkload{MatchedBy: "name", Key: name, Instances: instances})
+		}
+	}
+	for _, images := range sortedGroupKeys(byImages) {
+		if instances := spanNamespaces(byImages[images], reported); len(instances) > 0 {
+			duplicates = append(duplicates, DuplicateWorkload{MatchedBy: "image", Key: images, Instances: instances})
+		}
+	}
+	return duplicates
+}
+
+// spanNamespaces 在未报告过的工作负载分布于多个命名空间时返回它们，并标记为已报告
+func spanNamespaces(groups []*workloadGroup, reported map[*workloadGroup]bool) []WorkloadInstance {
+	var remaining []*workloadGroup
+	namespaces := make(map[string]bool)
+	for _, group := range groups {
+		if !reported[group] {
+			remaining = append(remaining, group)
+			namespaces[group.instance.Namespace] = true
+		}
+	}
+	if len(namespaces) < 2 {
+		return nil
+	}
+
+	instances := make([]WorkloadInstance, 0, len(remaining))
+	for _, group := range remaining {
+		reported[group] = true
+		instances = append(instances, group.instance)
+	}
+	return instances
+}
+
+// podImages 返回 Pod 中应用容器的镜像列表（已排序去重）
+func podImages(pod *corev1.Pod) string {
+	seen := make(map[string]bool)
+	for _, c := range pod.Spec.Containers {
+		seen[c.Image] = true
+	}
+	return strings.Join(sortedNames(seen), ", ")
+}
+
+// sortedGroupKeys 返回分组 map 按字典序排序的键
+func sortedGroupKeys(m map[string][]*workloadGroup) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ECIInstance 是一个 ECI Pod 及其背后的 ECI 实例，用于与云控制台的实例列表对账
+type ECIInstance struct {
+	Namespace  string
+	Pod        string
+	InstanceID string // 没有实例 ID 注解时为空，Pod 仍然列出以便数量对得上
+	Zone       string
+	VSwitch    string
+	Spec       string
+	Age        string
+	Status     PodStatus
+}
+
+// eciPlacement 从注解中读取 ECI 实例的可用区、虚拟交换机和规格
+// 实际规格写在 eci-instance-spec 上，只声明了候选规格（eci-use-specs）时返回候选列表
+func eciPlacement(pod *corev1.Pod) (zone, vswitch, spec string) {
+	spec = pod.Annotations[ECISpecAnnotation]
+	if spec == "" {
+		spec = pod.Annotations[ECIUseSpecsAnnotation]
+	}
+	return pod.Annotations[ECIZoneAnnotation], pod.Annotations[ECIVSwitchAnnotation], spec
+}
+
+// ECIInstances 返回运行在 ECI 上或带有 ECI 实例 ID 的 Pod，按命名空间和名称排序
+func ECIInstances(result *AnalysisResult) []ECIInstance {
+	var instances []ECIInstance
+	for _, pod := range result.Pods {
+		if !pod.RunningOnECI && pod.ECIInstanceID == "" {
+			continue
+		}
+		instances = append(instances, ECIInstance{
+			Namespace:  pod.Namespace,
+			Pod:        pod.Name,
+			InstanceID: pod.ECIInstanceID,
+			Zone:       pod.ECIZone,
+			VSwitch:    pod.ECIVSwitch,
+			Spec:       pod.ECISpec,
+			Age:        pod.Age,
+			Status:     pod.Status,
+		})
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		if instances[i].Namespace != instances[j].Namespace {
+			return instances[i].Namespace < instances[j].Namespace
+		}
+		return instances[i].Pod < instances[j].Pod
+	})
+	return instances
+}
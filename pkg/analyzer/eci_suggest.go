@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ECI 候选评分权重，集中在这里便于调整
+const (
+	eciScoreJob         = 40 // Job/CronJob 创建的短生命周期 Pod
+	eciScoreBestEffort  = 20
+	eciScoreSmallPod    = 15 // 小规格的 Burstable Pod
+	eciScoreBusyNode    = 25 // 所在节点请求分配率过高
+	eciGoodFitThreshold = 40
+
+	eciSmallCPUMilli     = 2000
+	eciSmallMemBytes     = 4 << 30
+	eciBusyNodeThreshold = 0.8
+)
+
+// ECI 调度相关的标签和注解
+const (
+	ECIScheduleLabel   = "alibabacloud.com/eci"
+	ECIUseSpecsAnnoKey = "k8s.aliyun.com/eci-use-specs"
+)
+
+// ECICandidate 是适合迁移到 ECI 的 Pod
+type ECICandidate struct {
+	Rank      int
+	Namespace string
+	Name      string
+	OwnerKind string
+	OwnerName string
+	Score     int
+	Fit       string   // "good" 或 "possible"
+	Reasons   []string // 评分依据
+	// Labels/Annotations 是调度到 ECI 需要在 Pod 模板上添加的配置
+	Labels      map[string]string
+	Annotations map[string]string
+}
+
+// SuggestECICandidates 找出运行在普通节点上、适合迁移到 ECI 的 Pod，按评分排序
+// providers 为 nil 时使用 DefaultProviderRules 识别已在虚拟节点上的 Pod
+func SuggestECICandidates(pods *corev1.PodList, nodes map[string]*corev1.Node, providers []ProviderRule) []ECICandidate {
+	if providers == nil {
+		providers = DefaultProviderRules
+	}
+
+	// 节点分配率只计算一次
+	busyNodes := make(map[string]string)
+	for name, node := range nodes {
+		if node == nil {
+			continue
+		}
+		cpu, mem := nodeCommitment(node, pods)
+		if cpu >= eciBusyNodeThreshold || mem >= eciBusyNodeThreshold {
+			busyNodes[name] = fmt.Sprintf("node %s is %.0f%% CPU / %.0f%% memory committed", name, cpu*100, mem*100)
+		}
+	}
+
+	var candidates []ECICandidate
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !eciEligible(pod, nodes[pod.Spec.NodeName], providers) {
+			continue
+		}
+
+		c := ECICandidate{Namespace: pod.Namespace, Name: pod.Name}
+		c.OwnerKind, c.OwnerName = ResolveOwner(pod)
+		cpu, mem := podRequests(pod)
+
+		if c.OwnerKind == "Job" {
+			c.Score += eciScoreJob
+			c.Reasons = append(c.Reasons, "short-lived Job pod")
+		}
+		switch pod.Status.QOSClass {
+		case corev1.PodQOSBestEffort:
+			c.Score += eciScoreBestEffort
+			c.Reasons = append(c.Reasons, "BestEffort pod")
+		case corev1.PodQOSBurstable:
+			if cpu <= eciSmallCPUMilli && mem <= eciSmallMemBytes {
+				c.Score += eciScoreSmallPod
+				c.Reasons = append(c.Reasons, fmt.Sprintf("small burstable pod (%s CPU, %s memory)", formatCPU(cpu), formatMemory(mem)))
+			}
+		}
+		if reason, ok := busyNodes[pod.Spec.NodeName]; ok {
+			c.Score += eciScoreBusyNode
+			c.Reasons = append(c.Reasons, reason)
+		}
+		if c.Score == 0 {
+			continue
+		}
+
+		c.Fit = "possible"
+		if c.Score >= eciGoodFitThreshold {
+			c.Fit = "good"
+		}
+		c.Labels = map[string]string{ECIScheduleLabel: "true"}
+		c.Annotations = map[string]string{ECIUseSpecsAnnoKey: eciSpec(cpu, mem)}
+		candidates = append(candidates, c)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if candidates[i].Namespace != candidates[j].Namespace {
+			return candidates[i].Namespace < candidates[j].Namespace
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+	for i := range candidates {
+		candidates[i].Rank = i + 1
+	}
+	return candidates
+}
+
+// eciEligible 排除已在 ECI 上、已结束或 ECI 不支持的 Pod
+func eciEligible(pod *corev1.Pod, node *corev1.Node, providers []ProviderRule) bool {
+	if running, _, _ := detectECI(pod, node, providers); running {
+		return false
+	}
+	if isTerminal(pod) || pod.Spec.NodeName == "" {
+		return false
+	}
+	if kind, _ := ResolveOwner(pod); kind == "DaemonSet" {
+		return false
+	}
+	// ECI 不支持 hostNetwork 和 hostPath
+	if pod.Spec.HostNetwork {
+		return false
+	}
+	for _, v := range pod.Spec.Volumes {
+		if v.HostPath != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// eciSpec 根据请求生成 eci-use-specs 注解的值，如 "2-4Gi"
+// 没有请求的维度按最小规格 1 核 / 2Gi 计算
+func eciSpec(cpuMilli, memBytes int64) string {
+	cores := math.Max(1, math.Ceil(float64(cpuMilli)/1000))
+	gib := math.Max(2, math.Ceil(float64(memBytes)/(1<<30)))
+	return fmt.Sprintf("%.0f-%.0fGi", cores, gib)
+}
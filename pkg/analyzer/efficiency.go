@@ -0,0 +1,88 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 资源利用率相关的配置问题：实际用量远低于 requests，节点容量被预留却没有使用
+// 细节中给出用量、requests、实际比例和阈值，如 "using 5m of 500m requested (1%), threshold 10%"
+const (
+	IssueOverProvisionedCPU    ConfigIssue = "CPU usage below --efficiency-threshold of request (over-provisioned)"
+	IssueOverProvisionedMemory ConfigIssue = "Memory usage below --efficiency-threshold of request (over-provisioned)"
+)
+
+// DefaultEfficiencyThreshold 是用量占 requests 比例的默认下限
+const DefaultEfficiencyThreshold = 0.1
+
+// resourceUsage 汇总 Pod 中设置了该资源 requests、且有用量数据的容器的用量和 requests（毫单位）
+// 没有这样的容器时 ok 为 false；未设置 requests 的容器的用量不计入，避免把它们的用量算到别人的 requests 上
+func resourceUsage(pod *corev1.Pod, usage map[string]corev1.ResourceList, name corev1.ResourceName) (used, requested int64, ok bool) {
+	for _, c := range pod.Spec.Containers {
+		request, hasRequest := c.Resources.Requests[name]
+		current, hasUsage := usage[c.Name][name]
+		if !hasRequest || request.IsZero() || !hasUsage {
+			continue
+		}
+		used += current.MilliValue()
+		requested += request.MilliValue()
+		ok = true
+	}
+	return used, requested, ok
+}
+
+// efficiencyScore 返回 Pod 的 CPU 和内存用量占 requests 比例的平均值（只计算有数据的资源），都没有数据时为 -1
+func efficiencyScore(pod *corev1.Pod, usage map[string]corev1.ResourceList) float64 {
+	if usage == nil {
+		return -1
+	}
+	var sum float64
+	var count int
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		if used, requested, ok := resourceUsage(pod, usage, name); ok {
+			sum += float64(used) / float64(requested)
+			count++
+		}
+	}
+	if count == 0 {
+		return -1
+	}
+	return sum / float64(count)
+}
+
+// checkResourceEfficiency 检查运行中 Pod 的 CPU/内存用量是否低于 requests 的 threshold 比例，threshold 为 0 时使用默认值
+// 用量是 metrics-server 的单次采样，刚启动或负载有周期的 Pod 可能被误判
+func checkResourceEfficiency(pod *corev1.Pod, usage map[string]corev1.ResourceList, threshold float64) []ConfigIssue {
+	if pod.Status.Phase != corev1.PodRunning || usage == nil {
+		return nil
+	}
+	if threshold <= 0 {
+		threshold = DefaultEfficiencyThreshold
+	}
+
+	var issues []ConfigIssue
+	if used, requested, ok := resourceUsage(pod, usage, corev1.ResourceCPU); ok && float64(used) < threshold*float64(requested) {
+		detail := fmt.Sprintf("using %s of %s requested (%s), threshold %s", formatCPU(used), formatCPU(requested), formatPercent(float64(used)/float64(requested)), formatPercent(threshold))
+		issues = append(issues, withDetail(IssueOverProvisionedCPU, detail))
+	}
+	if used, requested, ok := resourceUsage(pod, usage, corev1.ResourceMemory); ok && float64(used) < threshold*float64(requested) {
+		detail := fmt.Sprintf("using %s of %s requested (%s), threshold %s", formatMemory(roundMebibytes(used/1000)), formatMemory(requested/1000), formatPercent(float64(used)/float64(requested)), formatPercent(threshold))
+		issues = append(issues, withDetail(IssueOverProvisionedMemory, detail))
+	}
+	return issues
+}
+
+// roundMebibytes 将 1Mi 以上的字节数舍入到 Mi，便于阅读 metrics-server 以 Ki 报告的用量
+func roundMebibytes(bytes int64) int64 {
+	const mi = 1 << 20
+	if bytes < mi {
+		return bytes
+	}
+	return (bytes + mi/2) / mi * mi
+}
+
+// formatPercent 将比例格式化为整数百分比，如 0.05 格式化为 "5%"
+func formatPercent(ratio float64) string {
+	return fmt.Sprintf("%.0f%%", ratio*100)
+}
@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssueEnvConflictWithInjected 表示容器自己定义了与 Service 发现变量同名模式的环境变量
+// kubelet 会为命名空间中的每个 Service 注入 <SERVICE>_SERVICE_HOST/_SERVICE_PORT，
+// 显式定义的同名变量会覆盖注入值，新建同名 Service 时也会悄悄改变应用读取到的地址
+const IssueEnvConflictWithInjected ConfigIssue = "App env var may conflict with K8s-injected service env var"
+
+// injectedEnvSuffixes 是 Service 发现环境变量的后缀
+var injectedEnvSuffixes = []string{"_SERVICE_HOST", "_SERVICE_PORT"}
+
+// checkInjectedEnvConflicts 检查容器的 env 中是否有形如 *_SERVICE_HOST/*_SERVICE_PORT 的变量
+// enableServiceLinks: false 时只有 KUBERNETES_SERVICE_* 仍会被注入
+func checkInjectedEnvConflicts(pod *corev1.Pod) []Finding {
+	serviceLinks := pod.Spec.EnableServiceLinks == nil || *pod.Spec.EnableServiceLinks
+
+	var findings []Finding
+	for _, container := range allContainers(pod) {
+		var names []string
+		for _, env := range container.Env {
+			if !hasAnySuffix(env.Name, injectedEnvSuffixes) {
+				continue
+			}
+			if !serviceLinks && !strings.HasPrefix(env.Name, "KUBERNETES_") {
+				continue
+			}
+			names = append(names, env.Name)
+		}
+		if len(names) > 0 {
+			findings = append(findings, Finding{Issue: IssueEnvConflictWithInjected, Detail: container.Name + ": " + strings.Join(names, ", ")})
+		}
+	}
+	return findings
+}
+
+// hasAnySuffix 判断 s 是否以任一后缀结尾
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
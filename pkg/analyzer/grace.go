@@ -0,0 +1,35 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssueShellAsPID1 表示容器以 "sh -c" 启动，shell 成为 PID 1
+// 大多数 shell 不会把 SIGTERM 转发给子进程，应用收不到信号，直到 terminationGracePeriodSeconds 到期被 SIGKILL
+const IssueShellAsPID1 ConfigIssue = "Container command may use shell as PID 1 (SIGTERM may not propagate)"
+
+// pid1Shells 是作为 PID 1 时不转发信号的 shell
+var pid1Shells = map[string]bool{"sh": true, "bash": true}
+
+// checkShellAsPID1 检查容器是否通过 "sh -c" / "bash -c" 启动应用，细节中列出对应的容器
+// command 和 args 合并判断（常见写法是 command: [sh] + args: [-c, ...]）；脚本以 exec 开头时 shell 会被替换，不报告
+func checkShellAsPID1(pod *corev1.Pod) []Finding {
+	var names []string
+	for _, container := range pod.Spec.Containers {
+		argv := append(append([]string{}, container.Command...), container.Args...)
+		if len(argv) < 3 || !pid1Shells[path.Base(argv[0])] || argv[1] != "-c" {
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(argv[2]), "exec ") {
+			continue
+		}
+		names = append(names, container.Name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []Finding{{Issue: IssueShellAsPID1, Detail: strings.Join(names, ", ")}}
+}
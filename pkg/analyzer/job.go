@@ -0,0 +1,22 @@
+package analyzer
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssueNoPodFailurePolicy 表示 Job 没有设置 podFailurePolicy，所有失败都按同样的方式计入 backoffLimit
+// 设置后可以忽略节点驱逐等中断、在不可重试的退出码上立即失败，而不是把重试次数耗在注定失败的 Pod 上
+const IssueNoPodFailurePolicy ConfigIssue = "Job has no podFailurePolicy (retries all failures uniformly)"
+
+// PodFailurePolicyMinMinorVersion 是 podFailurePolicy 默认可用的 Kubernetes 次版本号（1.26 起 beta 默认开启）
+const PodFailurePolicyMinMinorVersion = 26
+
+// checkPodFailurePolicy 检查 Pod 所属的 Job 是否设置了 podFailurePolicy
+// podFailurePolicy 要求 Pod 模板的 restartPolicy 为 Never，OnFailure 的 Job 不检查
+func checkPodFailurePolicy(job *batchv1.Job) []ConfigIssue {
+	if job.Spec.PodFailurePolicy != nil || job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueNoPodFailurePolicy, "job "+job.Name)}
+}
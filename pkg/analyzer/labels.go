@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// IssueMissingRequiredLabels 表示 Pod 和所在命名空间都没有配置文件要求的组织标签（如 team、oncall）
+const IssueMissingRequiredLabels ConfigIssue = "Missing required labels"
+
+// DefaultOwnerLabel 是 --owner-contact 默认读取的负责团队标签
+const DefaultOwnerLabel = "team"
+
+// PodLabel 返回 Pod 上的标签值，Pod 没有该标签时回退到所在命名空间的标签，都没有时返回空字符串
+// ns 为 nil（未获取到命名空间）时只读取 Pod 的标签
+func PodLabel(pod *corev1.Pod, ns *corev1.Namespace, key string) string {
+	if value := pod.Labels[key]; value != "" {
+		return value
+	}
+	if ns != nil {
+		return ns.Labels[key]
+	}
+	return ""
+}
+
+// ValidateLabelKeys 检查标签键是否合法，如 "team" 或 "example.com/oncall"
+func ValidateLabelKeys(keys []string) error {
+	for _, key := range keys {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// checkRequiredLabels 检查 Pod 是否带有所有要求的标签，Pod 上没有的标签可以由所在的命名空间 ns 提供
+func checkRequiredLabels(pod *corev1.Pod, ns *corev1.Namespace, required []string) []ConfigIssue {
+	var missing []string
+	for _, key := range required {
+		if PodLabel(pod, ns, key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueMissingRequiredLabels, strings.Join(missing, ", "))}
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EventReasonKilling 是 kubelet 停止容器时记录的事件原因，存活探针失败导致的重启也使用它
+const EventReasonKilling = "Killing"
+
+// 存活探针级联重启的判定条件
+const (
+	// LivenessCascadeWindow 是判定时使用的时间窗口
+	LivenessCascadeWindow = 10 * time.Minute
+	// LivenessCascadeMinWorkloads 是窗口内至少有多少个不同工作负载的 Pod 被存活探针重启
+	LivenessCascadeMinWorkloads = 3
+)
+
+// LivenessCascade 是短时间内多个工作负载的容器因存活探针失败而重启的情况
+// 通常说明存活探针检查了下游依赖，依赖故障时健康的 Pod 也被一起重启
+type LivenessCascade struct {
+	Start time.Time
+	End   time.Time
+	// Kills 是窗口内存活探针导致的重启次数
+	Kills int
+	// Workloads 是受影响的工作负载，格式为 "namespace/Kind/name"，按名称排序
+	Workloads []string
+}
+
+// probeKill 是一次归因到工作负载的存活探针重启
+type probeKill struct {
+	workload string
+	at       time.Time
+}
+
+// IsLivenessProbeKill 判断事件是否是 kubelet 因存活探针失败而重启容器
+// kubelet 的消息形如 "Container app failed liveness probe, will be restarted"
+func IsLivenessProbeKill(event *corev1.Event) bool {
+	return event.Reason == EventReasonKilling && strings.Contains(event.Message, "failed liveness probe")
+}
+
+// probeKills 将存活探针导致的重启归因到 Pod 所属的工作负载，按时间排序
+// 不属于被分析 Pod 的事件忽略；没有控制器的 Pod 按自身计为一个工作负载
+func probeKills(pods *corev1.PodList, events []corev1.Event) []probeKill {
+	workloads := make(map[string]string, len(pods.Items))
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		kind, name := ResolveOwner(pod)
+		if kind == "" {
+			kind, name = "Pod", pod.Name
+		}
+		workloads[pod.Namespace+"/"+pod.Name] = pod.Namespace + "/" + kind + "/" + name
+	}
+
+	var kills []probeKill
+	for i := range events {
+		event := &events[i]
+		if event.InvolvedObject.Kind != "Pod" || !IsLivenessProbeKill(event) {
+			continue
+		}
+		workload, ok := workloads[event.InvolvedObject.Namespace+"/"+event.InvolvedObject.Name]
+		if !ok {
+			continue
+		}
+		kills = append(kills, probeKill{workload: workload, at: eventTimestamp(event)})
+	}
+	sort.SliceStable(kills, func(i, j int) bool { return kills[i].at.Before(kills[j].at) })
+	return kills
+}
+
+// findLivenessCascade 用滑动窗口找出涉及工作负载最多的 LivenessCascadeWindow，
+// 达到 LivenessCascadeMinWorkloads 时返回该窗口，否则返回 nil
+func findLivenessCascade(pods *corev1.PodList, data *ClusterData) *LivenessCascade {
+	if data == nil {
+		return nil
+	}
+	kills := probeKills(pods, data.KillingEvents)
+
+	var best *LivenessCascade
+	counts := make(map[string]int)
+	left := 0
+	for right, kill := range kills {
+		counts[kill.workload]++
+		for kill.at.Sub(kills[left].at) > LivenessCascadeWindow {
+			if counts[kills[left].workload]--; counts[kills[left].workload] == 0 {
+				delete(counts, kills[left].workload)
+			}
+			left++
+		}
+		if len(counts) < LivenessCascadeMinWorkloads || (best != nil && len(counts) <= len(best.Workloads)) {
+			continue
+		}
+		best = &LivenessCascade{
+			Start:     kills[left].at,
+			End:       kill.at,
+			Kills:     right - left + 1,
+			Workloads: sortedKeys(counts),
+		}
+	}
+	return best
+}
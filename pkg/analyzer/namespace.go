@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// NamespaceTerminatingReason 是位于 Terminating 命名空间中的 Pod 的原因
+const NamespaceTerminatingReason = "Namespace terminating"
+
+// namespaceBlockingConditions 是说明命名空间删除被什么阻塞的条件
+var namespaceBlockingConditions = []corev1.NamespaceConditionType{
+	corev1.NamespaceContentRemaining,
+	corev1.NamespaceFinalizersRemaining,
+	corev1.NamespaceDeletionDiscoveryFailure,
+	corev1.NamespaceDeletionContentFailure,
+	corev1.NamespaceDeletionGVParsingFailure,
+}
+
+// TerminatingNamespace 是处于 Terminating 状态的命名空间
+// 其中的 Pod 被删除后无法重建，卡住的命名空间通常是某些资源的 finalizer 无人处理
+type TerminatingNamespace struct {
+	Name       string
+	Since      time.Time // 删除开始的时间（deletionTimestamp）
+	Age        string    // 删除已持续的时间，如 "12s"、"3h0m"，不带 "ago"
+	Finalizers []string  // metadata.finalizers 和 spec.finalizers
+	// Conditions 是阻塞删除的条件，如 "NamespaceContentRemaining: Some resources are remaining: pods. has 2 resource instances"
+	Conditions []string
+}
+
+// isTerminating 判断命名空间是否处于 Terminating 状态
+func isTerminating(ns *corev1.Namespace) bool {
+	return ns != nil && (ns.Status.Phase == corev1.NamespaceTerminating || ns.DeletionTimestamp != nil)
+}
+
+// terminatingNamespaces 返回 ClusterData 中处于 Terminating 状态的命名空间，按名称排序
+func terminatingNamespaces(data *ClusterData, format func(time.Time) string) []TerminatingNamespace {
+	if data == nil {
+		return nil
+	}
+	var namespaces []TerminatingNamespace
+	for _, ns := range data.Namespaces {
+		if !isTerminating(ns) {
+			continue
+		}
+		terminating := TerminatingNamespace{Name: ns.Name}
+		if ns.DeletionTimestamp != nil {
+			terminating.Since = ns.DeletionTimestamp.Time
+			terminating.Age = format(terminating.Since)
+		}
+		terminating.Finalizers = append(terminating.Finalizers, ns.Finalizers...)
+		for _, f := range ns.Spec.Finalizers {
+			terminating.Finalizers = append(terminating.Finalizers, string(f))
+		}
+		for _, condType := range namespaceBlockingConditions {
+			for _, cond := range ns.Status.Conditions {
+				if cond.Type == condType && cond.Status == corev1.ConditionTrue {
+					terminating.Conditions = append(terminating.Conditions, string(cond.Type)+": "+cond.Message)
+				}
+			}
+		}
+		namespaces = append(namespaces, terminating)
+	}
+	sort.Slice(namespaces, func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	})
+	return namespaces
+}
+
+// applyNamespaceTerminating 将命名空间的 Terminating 状态反映到 Pod 上
+// 健康的 Pod 标记为 Warning，已有的原因保留在后面
+func applyNamespaceTerminating(analysis *PodAnalysis, ns *corev1.Namespace) {
+	if !isTerminating(ns) {
+		return
+	}
+	if analysis.Status == StatusHealthy {
+		analysis.Status = StatusWarning
+		if ns.DeletionTimestamp != nil {
+			analysis.ProblemSince = ns.DeletionTimestamp.Time
+		}
+	}
+	if analysis.Reason == "" {
+		analysis.Reason = NamespaceTerminatingReason
+	} else {
+		analysis.Reason = NamespaceTerminatingReason + ": " + analysis.Reason
+	}
+}
+
+// NamespaceSummary 是单个命名空间中 Pod 的状态统计
+type NamespaceSummary struct {
+	Namespace string
+	Total     int
+	Healthy   int
+	Warning   int
+	Error     int
+	Pending   int
+	Restarts  int32
+}
+
+// SummarizeNamespaces 按命名空间统计分析结果，结果按名称排序
+// namespaces 中列出但没有 Pod 的命名空间也会出现在结果中，计数为 0
+func SummarizeNamespaces(result *AnalysisResult, namespaces []string) []NamespaceSummary {
+	byName := make(map[string]*NamespaceSummary)
+	get := func(name string) *NamespaceSummary {
+		if byName[name] == nil {
+			byName[name] = &NamespaceSummary{Namespace: name}
+		}
+		return byName[name]
+	}
+	for _, name := range namespaces {
+		get(name)
+	}
+	for _, pod := range result.Pods {
+		summary := get(pod.Namespace)
+		summary.Total++
+		summary.Restarts += pod.Restarts
+		switch pod.Status {
+		case StatusHealthy:
+			summary.Healthy++
+		case StatusWarning:
+			summary.Warning++
+		case StatusError:
+			summary.Error++
+		case StatusPending:
+			summary.Pending++
+		}
+	}
+
+	summaries := make([]NamespaceSummary, 0, len(byName))
+	for _, summary := range byName {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return summaries[i].Namespace < summaries[j].Namespace
+	})
+	return summaries
+}
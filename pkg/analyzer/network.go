@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// IssueNoDefaultDenyPolicy 表示 Pod 所在的命名空间没有 default-deny 的 NetworkPolicy
+// 没有策略选中的 Pod 允许所有入站和出站流量，default-deny 让放行的流量都需要显式声明
+const IssueNoDefaultDenyPolicy ConfigIssue = "Namespace has no default-deny NetworkPolicy"
+
+// defaultDeny 记录命名空间中是否有拒绝所有入站/出站流量的 NetworkPolicy
+type defaultDeny struct {
+	ingress bool
+	egress  bool
+}
+
+// defaultDenyByNamespace 按命名空间汇总 default-deny 策略
+// default-deny 策略的 podSelector 为空（选中所有 Pod），并且对应方向没有任何放行规则；
+// 未设置 policyTypes 时按 API 的默认值：总是包含 Ingress，有 egress 规则时才包含 Egress
+func defaultDenyByNamespace(policies []networkingv1.NetworkPolicy) map[string]defaultDeny {
+	denies := make(map[string]defaultDeny)
+	for i := range policies {
+		policy := &policies[i]
+		if len(policy.Spec.PodSelector.MatchLabels) > 0 || len(policy.Spec.PodSelector.MatchExpressions) > 0 {
+			continue
+		}
+		d := denies[policy.Namespace]
+		for _, policyType := range effectivePolicyTypes(policy) {
+			switch policyType {
+			case networkingv1.PolicyTypeIngress:
+				d.ingress = d.ingress || len(policy.Spec.Ingress) == 0
+			case networkingv1.PolicyTypeEgress:
+				d.egress = d.egress || len(policy.Spec.Egress) == 0
+			}
+		}
+		denies[policy.Namespace] = d
+	}
+	return denies
+}
+
+// effectivePolicyTypes 返回策略实际生效的方向
+func effectivePolicyTypes(policy *networkingv1.NetworkPolicy) []networkingv1.PolicyType {
+	if len(policy.Spec.PolicyTypes) > 0 {
+		return policy.Spec.PolicyTypes
+	}
+	types := []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	if len(policy.Spec.Egress) > 0 {
+		types = append(types, networkingv1.PolicyTypeEgress)
+	}
+	return types
+}
+
+// checkDefaultDeny 检查 Pod 所在命名空间的入站和出站方向是否都有 default-deny 策略，细节列出仍然放行的方向
+func checkDefaultDeny(pod *corev1.Pod, denies map[string]defaultDeny) []ConfigIssue {
+	d := denies[pod.Namespace]
+	switch {
+	case !d.ingress && !d.egress:
+		return []ConfigIssue{IssueNoDefaultDenyPolicy}
+	case !d.ingress:
+		return []ConfigIssue{withDetail(IssueNoDefaultDenyPolicy, "ingress allowed")}
+	case !d.egress:
+		return []ConfigIssue{withDetail(IssueNoDefaultDenyPolicy, "egress allowed")}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// nodePressureConditions 是会触发 kubelet 驱逐的节点压力条件
+var nodePressureConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+}
+
+// 节点状态相关的配置问题
+const (
+	IssueNodeCordoned ConfigIssue = "Pod is on cordoned node (unschedulable)"
+	// IssueScheduledAfterCordon 表示 Pod 在节点被 cordon 之后才被放到该节点上，维护时会被中途杀掉
+	IssueScheduledAfterCordon ConfigIssue = "Pod scheduled onto node after it was cordoned"
+	// IssueDaemonSetAfterCordon 是 DaemonSet Pod 的同类情况，DaemonSet 默认容忍 unschedulable，仅作提示
+	IssueDaemonSetAfterCordon ConfigIssue = "DaemonSet pod scheduled onto node after it was cordoned"
+)
+
+// checkNodeState 检查 Pod 所在节点的状态
+// 被 cordon 的节点上现有 Pod 仍在运行，但重建后无法再调度回来
+func checkNodeState(node *corev1.Node) []ConfigIssue {
+	if node == nil {
+		return nil
+	}
+	var issues []ConfigIssue
+	if node.Spec.Unschedulable {
+		issues = append(issues, IssueNodeCordoned)
+	}
+	return issues
+}
+
+// checkScheduledAfterCordon 检查 Pod 是否在节点被 cordon 之后才创建并调度到该节点
+// cordon 时间见 cordonTime，无法判断时不报告；
+// 细节中列出放行的容忍，没有匹配的容忍通常说明 Pod 直接指定了 nodeName 绕过了调度器
+func checkScheduledAfterCordon(pod *corev1.Pod, node *corev1.Node) []ConfigIssue {
+	if node == nil || !node.Spec.Unschedulable || isTerminal(pod) {
+		return nil
+	}
+	cordonedAt, ok := cordonTime(node)
+	if !ok || !pod.CreationTimestamp.After(cordonedAt) {
+		return nil
+	}
+
+	// 节点控制器添加的污点可能还没有出现，按它的固定形式匹配容忍
+	taint := &corev1.Taint{Key: corev1.TaintNodeUnschedulable, Effect: corev1.TaintEffectNoSchedule}
+	allowedBy := "no toleration, nodeName set directly"
+	for i := range pod.Spec.Tolerations {
+		if pod.Spec.Tolerations[i].ToleratesTaint(taint) {
+			allowedBy = "tolerates " + formatToleration(pod.Spec.Tolerations[i])
+			break
+		}
+	}
+
+	issue := IssueScheduledAfterCordon
+	if kind, _ := ResolveOwner(pod); kind == "DaemonSet" {
+		issue = IssueDaemonSetAfterCordon
+	}
+	return []ConfigIssue{withDetail(issue, fmt.Sprintf("node %s cordoned at %s, %s",
+		node.Name, cordonedAt.UTC().Format(time.RFC3339), allowedBy))}
+}
+
+// cordonTime 返回节点被 cordon 的时间
+// 节点控制器添加的 node.kubernetes.io/unschedulable 污点不带 timeAdded（只有 NoExecute 污点会设置），
+// 因此主要依据 managedFields 中管理 spec.unschedulable 的条目（如 kubectl cordon 的 kubectl-cordon）的更新时间；
+// 该条目还管理其他字段时时间可能晚于 cordon，只会少报。污点带 timeAdded 时优先使用
+func cordonTime(node *corev1.Node) (time.Time, bool) {
+	for _, taint := range node.Spec.Taints {
+		if taint.Key == corev1.TaintNodeUnschedulable && taint.TimeAdded != nil {
+			return taint.TimeAdded.Time, true
+		}
+	}
+	var latest time.Time
+	for _, entry := range node.ManagedFields {
+		if entry.Time == nil || entry.FieldsV1 == nil || !entry.Time.After(latest) {
+			continue
+		}
+		var fields struct {
+			Spec map[string]json.RawMessage `json:"f:spec"`
+		}
+		if json.Unmarshal(entry.FieldsV1.Raw, &fields) != nil {
+			continue
+		}
+		if _, ok := fields.Spec["f:unschedulable"]; ok {
+			latest = entry.Time.Time
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// formatToleration 将容忍格式化为 "key:Effect Operator"，空 key 的 Exists 容忍匹配所有污点
+func formatToleration(t corev1.Toleration) string {
+	key := t.Key
+	if key == "" {
+		key = "*"
+	}
+	if t.Effect != "" {
+		key += ":" + string(t.Effect)
+	}
+	op := string(t.Operator)
+	if op == "" {
+		op = string(corev1.TolerationOpEqual)
+	}
+	return key + " " + op
+}
+
+// OnCordonedNode 判断 Pod 是否被标记为运行在已 cordon 的节点上
+func (a PodAnalysis) OnCordonedNode() bool {
+	for _, issue := range a.ConfigIssues {
+		if issue.Is(IssueNodeCordoned) {
+			return true
+		}
+	}
+	return false
+}
+
+// activeNodePressure 返回节点当前生效的压力条件
+// 节点 NotReady 时 kubelet 不再更新条件，残留的压力条件不可信；
+// 压力条件的心跳早于节点最近一次变为 Ready 时同样视为过期
+func activeNodePressure(node *corev1.Node) []string {
+	if node == nil {
+		return nil
+	}
+
+	var ready *corev1.NodeCondition
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			ready = &node.Status.Conditions[i]
+			break
+		}
+	}
+	if ready == nil || ready.Status != corev1.ConditionTrue {
+		return nil
+	}
+
+	var pressures []string
+	for _, condType := range nodePressureConditions {
+		for _, cond := range node.Status.Conditions {
+			if cond.Type != condType || cond.Status != corev1.ConditionTrue {
+				continue
+			}
+			seen := cond.LastHeartbeatTime
+			if seen.IsZero() {
+				seen = cond.LastTransitionTime
+			}
+			if seen.Before(&ready.LastTransitionTime) {
+				continue
+			}
+			pressures = append(pressures, string(condType))
+		}
+	}
+	return pressures
+}
+
+// applyNodePressure 将节点压力反映到 Pod 上
+// BestEffort/Burstable Pod 会在节点压力下优先被驱逐，健康的此类 Pod 标记为 Warning
+func applyNodePressure(analysis *PodAnalysis, pod *corev1.Pod, node *corev1.Node) {
+	analysis.NodePressure = activeNodePressure(node)
+	if len(analysis.NodePressure) == 0 || analysis.Status != StatusHealthy {
+		return
+	}
+	if pod.Status.QOSClass == corev1.PodQOSGuaranteed {
+		return
+	}
+	analysis.Status = StatusWarning
+	analysis.Reason = fmt.Sprintf("node has %s — eviction risk", strings.Join(analysis.NodePressure, ", "))
+}
+
+// pressuredNodes 汇总分析结果中处于压力状态的节点：节点 -> 压力条件
+func pressuredNodes(pods []PodAnalysis) map[string][]string {
+	var nodes map[string][]string
+	for _, pod := range pods {
+		if len(pod.NodePressure) == 0 {
+			continue
+		}
+		if nodes == nil {
+			nodes = make(map[string][]string)
+		}
+		nodes[pod.NodeName] = pod.NodePressure
+	}
+	return nodes
+}
@@ -0,0 +1,202 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxClosestLabels 是 MissingNodeLabel.Closest 最多列出的候选数量
+const maxClosestLabels = 3
+
+// MissingNodeLabel 是 Pending Pod 通过 nodeSelector 或必需的 nodeAffinity 选择、但集群中没有任何节点带有的标签
+// 节点池改名后，仍引用旧名称的工作负载会一直 Pending，调度器只给出笼统的节点不足信息
+type MissingNodeLabel struct {
+	Key string
+	// Values 是要求的取值，为空表示没有任何节点带有该键
+	Values []string
+	// Closest 是与要求的取值最接近的现有取值；没有节点带有该键时是最接近的现有键
+	Closest []string
+}
+
+// String 返回标签的可读形式，如 "pool=old-name"、"zone in (a, b)"，只缺少键时返回键
+func (m *MissingNodeLabel) String() string {
+	switch len(m.Values) {
+	case 0:
+		return m.Key
+	case 1:
+		return m.Key + "=" + m.Values[0]
+	default:
+		return m.Key + " in (" + strings.Join(m.Values, ", ") + ")"
+	}
+}
+
+// SelectsNodeLabels 判断 Pod 是否尚未调度，且通过 nodeSelector 或必需的 nodeAffinity 按标签选择节点
+// 只有这类 Pod 需要获取集群中的全部节点
+func SelectsNodeLabels(pod *corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" {
+		return false
+	}
+	return len(pod.Spec.NodeSelector) > 0 || len(requiredNodeSelectorTerms(pod)) > 0
+}
+
+// requiredNodeSelectorTerms 返回 Pod 必需的 nodeAffinity 条件，条件之间是“或”的关系
+func requiredNodeSelectorTerms(pod *corev1.Pod) []corev1.NodeSelectorTerm {
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return nil
+	}
+	return affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+}
+
+// nodeLabelIndex 是集群中所有节点的标签：键 -> 取值集合
+type nodeLabelIndex map[string]map[string]bool
+
+// newNodeLabelIndex 汇总节点上的标签
+func newNodeLabelIndex(nodes []corev1.Node) nodeLabelIndex {
+	index := make(nodeLabelIndex)
+	for _, node := range nodes {
+		for k, v := range node.Labels {
+			if index[k] == nil {
+				index[k] = make(map[string]bool)
+			}
+			index[k][v] = true
+		}
+	}
+	return index
+}
+
+// missing 返回所有节点都不带有的标签，values 中任一取值存在即视为存在；标签存在时返回 nil
+func (idx nodeLabelIndex) missing(key string, values ...string) *MissingNodeLabel {
+	existing, ok := idx[key]
+	if !ok {
+		return &MissingNodeLabel{Key: key, Closest: closestStrings(key, sortedKeys(idx))}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	for _, v := range values {
+		if existing[v] {
+			return nil
+		}
+	}
+	return &MissingNodeLabel{Key: key, Values: values, Closest: closestStrings(values[0], sortedKeys(existing))}
+}
+
+// missingNodeLabel 找出 Pending Pod 要求、但没有任何节点带有的标签
+// nodeSelector 的每个标签都必须存在；nodeAffinity 的多个条件中只要有一个的标签都存在即可，
+// 全部条件都缺少标签时报告第一个条件缺少的标签。只检查 In 和 Exists，其他操作符不能由标签是否存在判断
+func missingNodeLabel(pod *corev1.Pod, nodes []corev1.Node) *MissingNodeLabel {
+	index := newNodeLabelIndex(nodes)
+	for _, key := range sortedKeys(pod.Spec.NodeSelector) {
+		if m := index.missing(key, pod.Spec.NodeSelector[key]); m != nil {
+			return m
+		}
+	}
+
+	var first *MissingNodeLabel
+	for _, term := range requiredNodeSelectorTerms(pod) {
+		m := index.missingInTerm(term)
+		if m == nil {
+			return nil
+		}
+		if first == nil {
+			first = m
+		}
+	}
+	return first
+}
+
+// missingInTerm 返回 nodeAffinity 条件中第一个没有节点带有的标签
+func (idx nodeLabelIndex) missingInTerm(term corev1.NodeSelectorTerm) *MissingNodeLabel {
+	for _, expr := range term.MatchExpressions {
+		var m *MissingNodeLabel
+		switch expr.Operator {
+		case corev1.NodeSelectorOpIn:
+			m = idx.missing(expr.Key, expr.Values...)
+		case corev1.NodeSelectorOpExists:
+			m = idx.missing(expr.Key)
+		}
+		if m != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// applyMissingNodeLabel 为因标签无节点匹配而无法调度的 Pod 给出明确的原因
+func applyMissingNodeLabel(analysis *PodAnalysis, pod *corev1.Pod, cluster *ClusterData) {
+	if cluster == nil || cluster.ClusterNodes == nil || analysis.Status != StatusPending {
+		return
+	}
+	if !strings.HasPrefix(analysis.Reason, "Unschedulable") || !SelectsNodeLabels(pod) {
+		return
+	}
+	if m := missingNodeLabel(pod, cluster.ClusterNodes); m != nil {
+		analysis.MissingNodeLabel = m
+		analysis.Reason = "Unschedulable: no node has label " + m.String()
+	}
+}
+
+// HasMissingNodeLabels 判断是否有 Pod 选择了没有任何节点带有的标签
+func (r *AnalysisResult) HasMissingNodeLabels() bool {
+	for _, pod := range r.Pods {
+		if pod.MissingNodeLabel != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// closestStrings 返回 candidates 中与 target 编辑距离最小的若干个，距离相同时按字母排序
+func closestStrings(target string, candidates []string) []string {
+	type scored struct {
+		value    string
+		distance int
+	}
+	scoredCandidates := make([]scored, 0, len(candidates))
+	for _, c := range candidates {
+		scoredCandidates = append(scoredCandidates, scored{c, editDistance(target, c)})
+	}
+	sort.SliceStable(scoredCandidates, func(i, j int) bool {
+		return scoredCandidates[i].distance < scoredCandidates[j].distance
+	})
+
+	var closest []string
+	for i := 0; i < len(scoredCandidates) && i < maxClosestLabels; i++ {
+		closest = append(closest, scoredCandidates[i].value)
+	}
+	return closest
+}
+
+// editDistance 返回两个字符串之间的 Levenshtein 距离
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+// sortedKeys 返回 map 的键，按字母排序
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssuePreemptionCandidate 表示 Pod 可能被等待调度的高优先级 Pod 抢占驱逐
+const IssuePreemptionCandidate ConfigIssue = "Preemption candidate"
+
+// Preemption 是一个正在抢占节点的 Pending Pod 及其可能的受害者
+type Preemption struct {
+	Namespace     string
+	Name          string
+	Priority      int32
+	NominatedNode string
+	Victims       []PreemptionVictim
+}
+
+// PreemptionVictim 是提名节点上优先级更低、可能被驱逐的 Pod
+type PreemptionVictim struct {
+	Namespace string
+	Name      string
+	Priority  int32
+}
+
+// NominatedNode 返回 Pending Pod 的提名节点，调度器为其发起抢占时才会设置
+func NominatedNode(pod *corev1.Pod) string {
+	if pod.Status.Phase != corev1.PodPending {
+		return ""
+	}
+	return pod.Status.NominatedNodeName
+}
+
+// podPriority 返回 Pod 的优先级，未设置时为 0
+func podPriority(pod *corev1.Pod) int32 {
+	if pod.Spec.Priority == nil {
+		return 0
+	}
+	return *pod.Spec.Priority
+}
+
+// isPreemptionEvent 判断事件是否与抢占相关
+// 调度器在 FailedScheduling 消息中给出 "preemption: ..." 的结果，抢占成功后受害者会收到 Preempted 事件
+func isPreemptionEvent(event corev1.Event) bool {
+	if event.Reason == "Preempted" || event.Reason == "Preempting" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(event.Message), "preemption")
+}
+
+// findPreemptions 找出有提名节点和抢占事件的 Pending Pod，并列出提名节点上优先级更低的 Pod
+func findPreemptions(pods *corev1.PodList, data *ClusterData) []Preemption {
+	var preemptions []Preemption
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		node := NominatedNode(pod)
+		if node == "" {
+			continue
+		}
+		if pod.Spec.PreemptionPolicy != nil && *pod.Spec.PreemptionPolicy == corev1.PreemptNever {
+			continue
+		}
+
+		preempting := false
+		for _, event := range data.podEvents(pod.Namespace, pod.Name) {
+			if isPreemptionEvent(event) {
+				preempting = true
+				break
+			}
+		}
+		if !preempting {
+			continue
+		}
+
+		p := Preemption{
+			Namespace:     pod.Namespace,
+			Name:          pod.Name,
+			Priority:      podPriority(pod),
+			NominatedNode: node,
+		}
+		nodePods := data.nodePods(node)
+		for j := range nodePods {
+			victim := &nodePods[j]
+			if isTerminal(victim) || victim.UID == pod.UID || podPriority(victim) >= p.Priority {
+				continue
+			}
+			p.Victims = append(p.Victims, PreemptionVictim{
+				Namespace: victim.Namespace,
+				Name:      victim.Name,
+				Priority:  podPriority(victim),
+			})
+		}
+
+		// 调度器优先驱逐优先级最低的 Pod
+		sort.SliceStable(p.Victims, func(a, b int) bool {
+			return p.Victims[a].Priority < p.Victims[b].Priority
+		})
+		preemptions = append(preemptions, p)
+	}
+	return preemptions
+}
+
+// markPreemptionVictims 为被分析的 Pod 中的抢占受害者添加标记
+func markPreemptionVictims(pods []PodAnalysis, preemptions []Preemption) {
+	index := make(map[string]int, len(pods))
+	for i, pod := range pods {
+		index[pod.Namespace+"/"+pod.Name] = i
+	}
+
+	for _, p := range preemptions {
+		for _, victim := range p.Victims {
+			i, ok := index[victim.Namespace+"/"+victim.Name]
+			if !ok {
+				continue
+			}
+			detail := fmt.Sprintf("by %s/%s, priority %d > %d", p.Namespace, p.Name, p.Priority, victim.Priority)
+			pods[i].ConfigIssues = appendIfNotExists(pods[i].ConfigIssues, withDetail(IssuePreemptionCandidate, detail))
+		}
+	}
+}
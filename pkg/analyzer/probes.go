@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IssueSharedProbePort 表示多个容器的存活或就绪探针探测同一个端口
+// 同一 Pod 的容器共享网络命名空间，端口只属于其中一个容器，其他容器的探针实际上检查的是那个容器
+const IssueSharedProbePort ConfigIssue = "Multiple containers use same probe port (may test wrong container)"
+
+// checkSharedProbePorts 检查是否有多个容器的 liveness/readiness 探针使用同一个端口，细节中列出端口和容器，如 "port 8080: app, sidecar"
+// 同一容器的存活和就绪探针使用同一端口是正常的；指定了 host 的 HTTP 探针探测的是别的地址，不计入
+func checkSharedProbePorts(pod *corev1.Pod) []Finding {
+	containersByPort := make(map[int32][]string)
+	for i := range pod.Spec.Containers {
+		c := &pod.Spec.Containers[i]
+		seen := make(map[int32]bool)
+		for _, probe := range []*corev1.Probe{c.LivenessProbe, c.ReadinessProbe} {
+			port, ok := probePort(c, probe)
+			if !ok || seen[port] {
+				continue
+			}
+			seen[port] = true
+			containersByPort[port] = append(containersByPort[port], c.Name)
+		}
+	}
+
+	ports := make([]int32, 0, len(containersByPort))
+	for port, names := range containersByPort {
+		if len(names) > 1 {
+			ports = append(ports, port)
+		}
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var findings []Finding
+	for _, port := range ports {
+		detail := fmt.Sprintf("port %d: %s", port, strings.Join(containersByPort[port], ", "))
+		findings = append(findings, Finding{Issue: IssueSharedProbePort, Detail: detail})
+	}
+	return findings
+}
+
+// probePort 返回 HTTP、TCP 或 gRPC 探针探测的端口号，命名端口按容器自身声明的端口解析
+// exec 探针、指定了 host 的 HTTP 探针和无法解析的命名端口返回 false
+func probePort(c *corev1.Container, probe *corev1.Probe) (int32, bool) {
+	if probe == nil {
+		return 0, false
+	}
+	var port intstr.IntOrString
+	switch {
+	case probe.HTTPGet != nil:
+		if probe.HTTPGet.Host != "" {
+			return 0, false
+		}
+		port = probe.HTTPGet.Port
+	case probe.TCPSocket != nil:
+		if probe.TCPSocket.Host != "" {
+			return 0, false
+		}
+		port = probe.TCPSocket.Port
+	case probe.GRPC != nil:
+		return probe.GRPC.Port, true
+	default:
+		return 0, false
+	}
+
+	if port.Type == intstr.Int {
+		return port.IntVal, port.IntVal > 0
+	}
+	for _, p := range c.Ports {
+		if p.Name == port.StrVal {
+			return p.ContainerPort, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,171 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// ProviderRule 描述如何识别运行在某种虚拟节点（如 ECI、virtual-kubelet 的各种实现）上的 Pod
+// 任意一项条件满足即视为匹配
+type ProviderRule struct {
+	Name string `json:"name"`
+
+	// Annotations 是 Pod 上存在即匹配的注解
+	Annotations []string `json:"annotations,omitempty"`
+	// NodeLabels 是节点标签，值为空时只要求键存在
+	NodeLabels map[string]string `json:"nodeLabels,omitempty"`
+	// NodeNamePatterns 是节点名称的 glob 模式（不区分大小写）
+	NodeNamePatterns []string `json:"nodeNamePatterns,omitempty"`
+	// Taints 是节点 taint 的键
+	Taints []string `json:"taints,omitempty"`
+}
+
+// DefaultProviderRules 是内置的虚拟节点识别规则
+var DefaultProviderRules = []ProviderRule{
+	{
+		Name:        "eci",
+		Annotations: []string{ECIPodAnnotation},
+		// 阿里云 ECI 节点名称通常包含 virtual-kubelet 或 eci
+		// 内置规则不使用节点标签和 taint，避免默认情况下逐个获取节点
+		NodeNamePatterns: []string{"*virtual-kubelet*", "eci-*", "*-eci-*", "*virtual-node*"},
+	},
+}
+
+// MergeProviderRules 将自定义规则与内置规则合并
+// 与内置规则同名的自定义规则会替换内置规则，其余自定义规则排在内置规则之前优先匹配
+func MergeProviderRules(builtin, custom []ProviderRule) []ProviderRule {
+	overridden := make(map[string]bool)
+	for _, rule := range custom {
+		overridden[rule.Name] = true
+	}
+
+	merged := append([]ProviderRule(nil), custom...)
+	for _, rule := range builtin {
+		if !overridden[rule.Name] {
+			merged = append(merged, rule)
+		}
+	}
+	return merged
+}
+
+// ValidateProviderRules 校验规则，错误信息中包含出错规则的位置和名称
+func ValidateProviderRules(rules []ProviderRule) error {
+	seen := make(map[string]bool)
+	for i, rule := range rules {
+		entry := fmt.Sprintf("providers[%d]", i)
+		if rule.Name == "" {
+			return fmt.Errorf("%s: name is required", entry)
+		}
+		entry = fmt.Sprintf("%s (%q)", entry, rule.Name)
+		if seen[rule.Name] {
+			return fmt.Errorf("%s: duplicate provider name", entry)
+		}
+		seen[rule.Name] = true
+
+		if len(rule.Annotations) == 0 && len(rule.NodeLabels) == 0 && len(rule.NodeNamePatterns) == 0 && len(rule.Taints) == 0 {
+			return fmt.Errorf("%s: at least one of annotations, nodeLabels, nodeNamePatterns or taints is required", entry)
+		}
+		for _, pattern := range rule.NodeNamePatterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid nodeNamePatterns entry %q: %v", entry, pattern, err)
+			}
+		}
+		for key := range rule.NodeLabels {
+			if key == "" {
+				return fmt.Errorf("%s: nodeLabels key must not be empty", entry)
+			}
+		}
+	}
+	return nil
+}
+
+// ProviderRulesNeedNodes 判断规则是否需要节点对象（节点标签或 taint）
+func ProviderRulesNeedNodes(rules []ProviderRule) bool {
+	for _, rule := range rules {
+		if len(rule.NodeLabels) > 0 || len(rule.Taints) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// matchProvider 返回第一个匹配 Pod 的规则名称和匹配原因
+// node 为 nil 时跳过节点标签和 taint 条件
+func matchProvider(pod *corev1.Pod, node *corev1.Node, rules []ProviderRule) (provider, reason string) {
+	for _, rule := range rules {
+		if reason := rule.match(pod, node); reason != "" {
+			return rule.Name, reason
+		}
+	}
+	return "", ""
+}
+
+// ExplainProviderDetection 逐条说明规则对 Pod 的匹配结果，用于排查识别错误
+func ExplainProviderDetection(pod *corev1.Pod, node *corev1.Node, rules []ProviderRule) []string {
+	var lines []string
+	if node == nil && pod.Spec.NodeName != "" && ProviderRulesNeedNodes(rules) {
+		lines = append(lines, fmt.Sprintf("node %s not available: nodeLabels/taints conditions skipped", pod.Spec.NodeName))
+	}
+	matched := false
+	for _, rule := range rules {
+		reason := rule.match(pod, node)
+		switch {
+		case reason == "":
+			lines = append(lines, fmt.Sprintf("rule %q: no match", rule.Name))
+		case matched:
+			lines = append(lines, fmt.Sprintf("rule %q: would match (%s), shadowed by an earlier rule", rule.Name, reason))
+		default:
+			lines = append(lines, fmt.Sprintf("rule %q: MATCHED (%s)", rule.Name, reason))
+			matched = true
+		}
+	}
+	if !matched {
+		lines = append(lines, "result: not on a virtual node")
+	}
+	return lines
+}
+
+// match 返回规则匹配 Pod 的原因，不匹配时返回空字符串
+func (r ProviderRule) match(pod *corev1.Pod, node *corev1.Node) string {
+	for _, key := range r.Annotations {
+		if pod.Annotations[key] != "" {
+			return "annotation " + key
+		}
+	}
+
+	nodeName := strings.ToLower(pod.Spec.NodeName)
+	if nodeName != "" {
+		for _, pattern := range r.NodeNamePatterns {
+			if ok, _ := path.Match(strings.ToLower(pattern), nodeName); ok {
+				return fmt.Sprintf("node name %s matches %q", pod.Spec.NodeName, pattern)
+			}
+		}
+	}
+
+	if node == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(r.NodeLabels))
+	for key := range r.NodeLabels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := node.Labels[key]
+		if ok && (r.NodeLabels[key] == "" || r.NodeLabels[key] == value) {
+			return fmt.Sprintf("node label %s=%s", key, value)
+		}
+	}
+	for _, key := range r.Taints {
+		for _, taint := range node.Spec.Taints {
+			if taint.Key == key {
+				return "node taint " + key
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,295 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// IssuePSPViolation 表示没有任何 PodSecurityPolicy 允许 Pod 的 spec，问题后面是最接近允许该 Pod 的策略名称
+// 已运行的 Pod 是按创建时的策略准入的，策略收紧后重建（滚动更新、驱逐）时会被拒绝
+const IssuePSPViolation ConfigIssue = "Pod spec violates PodSecurityPolicy"
+
+// PSPRemovedMinorVersion 是移除 PodSecurityPolicy 的 Kubernetes 次版本号（1.25）
+const PSPRemovedMinorVersion = 25
+
+// PodSecurityPolicy 是 policy/v1beta1 PodSecurityPolicy 中与准入相关的部分
+// client-go 已不再包含该类型，字段名与 API 一致，可以直接从 unstructured 对象转换
+type PodSecurityPolicy struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              PodSecurityPolicySpec `json:"spec"`
+}
+
+// PodSecurityPolicySpec 是 PodSecurityPolicy 中会拒绝 Pod 的字段
+// seLinux、supplementalGroups、fsGroup、runAsGroup、sysctl 等不检查：它们大多在 Pod 未设置时由策略填充默认值
+type PodSecurityPolicySpec struct {
+	Privileged               bool                 `json:"privileged,omitempty"`
+	DefaultAddCapabilities   []corev1.Capability  `json:"defaultAddCapabilities,omitempty"`
+	RequiredDropCapabilities []corev1.Capability  `json:"requiredDropCapabilities,omitempty"`
+	AllowedCapabilities      []corev1.Capability  `json:"allowedCapabilities,omitempty"`
+	Volumes                  []string             `json:"volumes,omitempty"`
+	HostNetwork              bool                 `json:"hostNetwork,omitempty"`
+	HostPorts                []PSPHostPortRange   `json:"hostPorts,omitempty"`
+	HostPID                  bool                 `json:"hostPID,omitempty"`
+	HostIPC                  bool                 `json:"hostIPC,omitempty"`
+	RunAsUser                PSPRunAsUserStrategy `json:"runAsUser"`
+	ReadOnlyRootFilesystem   bool                 `json:"readOnlyRootFilesystem,omitempty"`
+	AllowPrivilegeEscalation *bool                `json:"allowPrivilegeEscalation,omitempty"`
+	AllowedHostPaths         []PSPAllowedHostPath `json:"allowedHostPaths,omitempty"`
+}
+
+// PSPHostPortRange 是允许的 hostPort 范围（包含两端）
+type PSPHostPortRange struct {
+	Min int32 `json:"min"`
+	Max int32 `json:"max"`
+}
+
+// PSPRunAsUserStrategy 是 runAsUser 的规则：MustRunAs（在 Ranges 中）、MustRunAsNonRoot 或 RunAsAny
+type PSPRunAsUserStrategy struct {
+	Rule   string       `json:"rule"`
+	Ranges []PSPIDRange `json:"ranges,omitempty"`
+}
+
+// PSPIDRange 是允许的 UID 范围（包含两端）
+type PSPIDRange struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// PSPAllowedHostPath 是允许的 hostPath 前缀，ReadOnly 时只能只读挂载
+type PSPAllowedHostPath struct {
+	PathPrefix string `json:"pathPrefix,omitempty"`
+	ReadOnly   bool   `json:"readOnly,omitempty"`
+}
+
+// PodSecurityPolicyFromUnstructured 将 API 返回的 PodSecurityPolicy 对象转换为 PodSecurityPolicy
+func PodSecurityPolicyFromUnstructured(obj *unstructured.Unstructured) (PodSecurityPolicy, error) {
+	var psp PodSecurityPolicy
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &psp)
+	return psp, err
+}
+
+// checkPodSecurityPolicies 检查是否有 PodSecurityPolicy 允许 Pod 的 spec，都不允许时报告违反项最少的策略
+// 不检查 RBAC：实际只有 Pod 的创建者或 ServiceAccount 有 use 权限的策略才会参与准入；
+// 没有任何策略时通常说明未启用 PodSecurityPolicy 准入，不报告问题
+func checkPodSecurityPolicies(pod *corev1.Pod, policies []PodSecurityPolicy) []ConfigIssue {
+	if len(policies) == 0 {
+		return nil
+	}
+
+	var closest string
+	var closestViolations []string
+	for i := range policies {
+		violations := pspViolations(pod, &policies[i].Spec)
+		if len(violations) == 0 {
+			return nil
+		}
+		name := policies[i].Name
+		if closest == "" || len(violations) < len(closestViolations) || (len(violations) == len(closestViolations) && name < closest) {
+			closest, closestViolations = name, violations
+		}
+	}
+	issue := ConfigIssue(string(IssuePSPViolation) + ": " + closest)
+	return []ConfigIssue{withDetail(issue, strings.Join(closestViolations, ", "))}
+}
+
+// pspViolations 返回 Pod 违反策略的地方，如 "hostNetwork"、"app: privileged"
+// 策略会为未设置的字段填充默认值（如 runAsUser、allowPrivilegeEscalation），因此只有 Pod 显式设置了冲突的值才算违反
+func pspViolations(pod *corev1.Pod, spec *PodSecurityPolicySpec) []string {
+	var violations []string
+	if pod.Spec.HostNetwork && !spec.HostNetwork {
+		violations = append(violations, "hostNetwork")
+	}
+	if pod.Spec.HostPID && !spec.HostPID {
+		violations = append(violations, "hostPID")
+	}
+	if pod.Spec.HostIPC && !spec.HostIPC {
+		violations = append(violations, "hostIPC")
+	}
+	violations = append(violations, pspVolumeViolations(pod, spec)...)
+
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	for i := range containers {
+		for _, v := range pspContainerViolations(pod, &containers[i], spec) {
+			violations = append(violations, containers[i].Name+": "+v)
+		}
+	}
+	return violations
+}
+
+// pspContainerViolations 返回容器违反策略的地方
+func pspContainerViolations(pod *corev1.Pod, c *corev1.Container, spec *PodSecurityPolicySpec) []string {
+	var violations []string
+	sc := c.SecurityContext
+	if sc == nil {
+		sc = &corev1.SecurityContext{}
+	}
+
+	privileged := sc.Privileged != nil && *sc.Privileged
+	if privileged && !spec.Privileged {
+		violations = append(violations, "privileged")
+	}
+	if spec.AllowPrivilegeEscalation != nil && !*spec.AllowPrivilegeEscalation &&
+		(privileged || (sc.AllowPrivilegeEscalation != nil && *sc.AllowPrivilegeEscalation)) {
+		violations = append(violations, "allowPrivilegeEscalation")
+	}
+	if spec.ReadOnlyRootFilesystem && sc.ReadOnlyRootFilesystem != nil && !*sc.ReadOnlyRootFilesystem {
+		violations = append(violations, "readOnlyRootFilesystem: false")
+	}
+
+	if sc.Capabilities != nil {
+		for _, capability := range sc.Capabilities.Add {
+			switch {
+			case containsCapability(spec.RequiredDropCapabilities, capability):
+				violations = append(violations, fmt.Sprintf("capability %s (must be dropped)", capability))
+			case !containsCapability(spec.AllowedCapabilities, capability) && !containsCapability(spec.AllowedCapabilities, "*") &&
+				!containsCapability(spec.DefaultAddCapabilities, capability):
+				violations = append(violations, fmt.Sprintf("capability %s", capability))
+			}
+		}
+	}
+
+	for _, port := range c.Ports {
+		if port.HostPort != 0 && !hostPortAllowed(spec.HostPorts, port.HostPort) {
+			violations = append(violations, fmt.Sprintf("hostPort %d", port.HostPort))
+		}
+	}
+
+	if v := runAsUserViolation(pod, sc, spec.RunAsUser); v != "" {
+		violations = append(violations, v)
+	}
+	return violations
+}
+
+// runAsUserViolation 检查容器的有效 runAsUser/runAsNonRoot（容器优先，其次是 Pod）是否符合策略，未设置时由策略填充，不算违反
+func runAsUserViolation(pod *corev1.Pod, sc *corev1.SecurityContext, strategy PSPRunAsUserStrategy) string {
+	uid, nonRoot := sc.RunAsUser, sc.RunAsNonRoot
+	if psc := pod.Spec.SecurityContext; psc != nil {
+		if uid == nil {
+			uid = psc.RunAsUser
+		}
+		if nonRoot == nil {
+			nonRoot = psc.RunAsNonRoot
+		}
+	}
+
+	switch strategy.Rule {
+	case "MustRunAsNonRoot":
+		if uid != nil && *uid == 0 {
+			return "runAsUser 0"
+		}
+		if nonRoot != nil && !*nonRoot {
+			return "runAsNonRoot: false"
+		}
+	case "MustRunAs":
+		if uid != nil && !uidAllowed(strategy.Ranges, *uid) {
+			return fmt.Sprintf("runAsUser %d", *uid)
+		}
+	}
+	return ""
+}
+
+// pspVolumeViolations 检查卷类型和 hostPath 路径是否被策略允许
+func pspVolumeViolations(pod *corev1.Pod, spec *PodSecurityPolicySpec) []string {
+	allowed := make(map[string]bool, len(spec.Volumes))
+	for _, v := range spec.Volumes {
+		allowed[v] = true
+	}
+
+	var violations []string
+	for _, volume := range pod.Spec.Volumes {
+		if volumeType := volumeSourceType(volume.VolumeSource); !allowed["*"] && !allowed[volumeType] {
+			violations = append(violations, fmt.Sprintf("volume %s (%s)", volume.Name, volumeType))
+			continue
+		}
+		if volume.HostPath == nil || len(spec.AllowedHostPaths) == 0 {
+			continue
+		}
+		readOnly, ok := hostPathAllowed(spec.AllowedHostPaths, volume.HostPath.Path)
+		switch {
+		case !ok:
+			violations = append(violations, "hostPath "+volume.HostPath.Path)
+		case readOnly && !volumeMountedReadOnly(pod, volume.Name):
+			violations = append(violations, "hostPath "+volume.HostPath.Path+" (must be readOnly)")
+		}
+	}
+	return violations
+}
+
+// volumeSourceType 返回卷的类型，即 VolumeSource 中设置的字段名，与策略 volumes 中的名称一致（如 hostPath、configMap）
+func volumeSourceType(source corev1.VolumeSource) string {
+	fields, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&source)
+	if err != nil {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// hostPathAllowed 判断 hostPath 是否以某个允许的前缀开头（按路径段匹配，/foo 允许 /foo/bar，不允许 /foobar）
+// 匹配的前缀中任意一个不要求只读即可读写挂载
+func hostPathAllowed(allowed []PSPAllowedHostPath, hostPath string) (readOnly, ok bool) {
+	hostPath = path.Clean(hostPath)
+	readOnly = true
+	for _, a := range allowed {
+		prefix := path.Clean(a.PathPrefix)
+		if hostPath != prefix && !strings.HasPrefix(hostPath, strings.TrimSuffix(prefix, "/")+"/") {
+			continue
+		}
+		ok = true
+		readOnly = readOnly && a.ReadOnly
+	}
+	return readOnly, ok
+}
+
+// volumeMountedReadOnly 判断卷在所有容器中是否都是只读挂载
+func volumeMountedReadOnly(pod *corev1.Pod, volume string) bool {
+	for _, c := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, m := range c.VolumeMounts {
+			if m.Name == volume && !m.ReadOnly {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// hostPortAllowed 判断 hostPort 是否在允许的范围内
+func hostPortAllowed(ranges []PSPHostPortRange, port int32) bool {
+	for _, r := range ranges {
+		if port >= r.Min && port <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// uidAllowed 判断 UID 是否在允许的范围内
+func uidAllowed(ranges []PSPIDRange, uid int64) bool {
+	for _, r := range ranges {
+		if uid >= r.Min && uid <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// containsCapability 判断能力列表中是否包含指定能力
+func containsCapability(capabilities []corev1.Capability, capability corev1.Capability) bool {
+	for _, c := range capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// IssuePullSecretExpiring 表示镜像拉取凭证已过期或即将过期
+const IssuePullSecretExpiring ConfigIssue = "Image pull secret expired or near expiry"
+
+// IssueInvalidImagePullSecretType 表示 imagePullSecrets 引用的 Secret 不是 docker 凭证类型，kubelet 会忽略它
+const IssueInvalidImagePullSecretType ConfigIssue = "ImagePullSecret has incorrect type"
+
+// pullSecretExpiryWarning 是提前告警的时间窗口
+const pullSecretExpiryWarning = time.Hour
+
+// DefaultPullSecretExpiryAnnotation 是刷新任务记录凭证过期时间（RFC3339）的默认注解
+const DefaultPullSecretExpiryAnnotation = "refresh-after"
+
+// ImagePullSecrets 返回 Pod 引用的 imagePullSecrets 名称（已去重）
+func ImagePullSecrets(pod *corev1.Pod) []string {
+	seen := make(map[string]bool)
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		seen[ref.Name] = true
+	}
+	return sortedNames(seen)
+}
+
+// checkPullSecrets 检查 Pod 引用的 dockerconfigjson Secret 是否已过期或即将过期
+// 只输出 registry 地址和过期时间，不输出凭证内容
+func checkPullSecrets(pod *corev1.Pod, data *ClusterData, annotation string, now time.Time) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, name := range ImagePullSecrets(pod) {
+		secret := data.secret(pod.Namespace, name)
+		if secret == nil {
+			continue
+		}
+		for _, exp := range pullSecretExpiries(secret, annotation) {
+			if exp.at.Sub(now) > pullSecretExpiryWarning {
+				continue
+			}
+			state := "expires"
+			if !exp.at.After(now) {
+				state = "expired"
+			}
+			issues = append(issues, withDetail(IssuePullSecretExpiring,
+				fmt.Sprintf("%s: %s %s %s", name, exp.registry, state, exp.at.UTC().Format(time.RFC3339))))
+		}
+	}
+	return issues
+}
+
+// checkPullSecretTypes 检查 imagePullSecrets 引用的 Secret 是否为 kubernetes.io/dockerconfigjson 或 kubernetes.io/dockercfg 类型
+// 误建为 Opaque 的 Secret 会被 kubelet 忽略，镜像拉取以 ImagePullBackOff 失败且没有明确原因；不存在的 Secret 不在这里报告
+func checkPullSecretTypes(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, name := range ImagePullSecrets(pod) {
+		secret := data.secret(pod.Namespace, name)
+		if secret == nil {
+			continue
+		}
+		switch secret.Type {
+		case corev1.SecretTypeDockerConfigJson, corev1.SecretTypeDockercfg:
+			continue
+		}
+		secretType := string(secret.Type)
+		if secretType == "" {
+			secretType = string(corev1.SecretTypeOpaque)
+		}
+		issues = append(issues, withDetail(IssueInvalidImagePullSecretType, fmt.Sprintf("%s: %s", name, secretType)))
+	}
+	return issues
+}
+
+// registryExpiry 是某个 registry 凭证的过期时间
+type registryExpiry struct {
+	registry string
+	at       time.Time
+}
+
+// dockerConfigJSON 是 .dockerconfigjson 的结构
+type dockerConfigJSON struct {
+	Auths map[string]struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+		Auth     string `json:"auth"`
+	} `json:"auths"`
+}
+
+// pullSecretExpiries 从 Secret 中提取各 registry 凭证的过期时间
+// 注解优先；否则识别 ECR 令牌（password 是包含 expiration 的 base64 JSON）和 JWT 令牌（exp 声明）
+func pullSecretExpiries(secret *corev1.Secret, annotation string) []registryExpiry {
+	if secret.Type != corev1.SecretTypeDockerConfigJson {
+		return nil
+	}
+	var config dockerConfigJSON
+	if err := json.Unmarshal(secret.Data[corev1.DockerConfigJsonKey], &config); err != nil {
+		return nil
+	}
+
+	var annotated time.Time
+	if value := secret.Annotations[annotation]; annotation != "" && value != "" {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			annotated = t
+		}
+	}
+
+	var expiries []registryExpiry
+	for registry, auth := range config.Auths {
+		at := annotated
+		if at.IsZero() {
+			password := auth.Password
+			if password == "" {
+				if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil {
+					if _, p, ok := strings.Cut(string(decoded), ":"); ok {
+						password = p
+					}
+				}
+			}
+			at = tokenExpiry(password)
+		}
+		if !at.IsZero() {
+			expiries = append(expiries, registryExpiry{registry: registry, at: at})
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool { return expiries[i].registry < expiries[j].registry })
+	return expiries
+}
+
+// tokenExpiry 从 ECR 或 JWT 形式的令牌中解析过期时间，无法识别时返回零值
+func tokenExpiry(token string) time.Time {
+	var claims struct {
+		Expiration int64 `json:"expiration"` // ECR
+		Exp        int64 `json:"exp"`        // JWT
+	}
+
+	payload := token
+	if parts := strings.Split(token, "."); len(parts) == 3 {
+		payload = parts[1]
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawURLEncoding, base64.URLEncoding} {
+		decoded, err := enc.DecodeString(payload)
+		if err != nil || json.Unmarshal(decoded, &claims) != nil {
+			continue
+		}
+		switch {
+		case claims.Expiration > 0:
+			return time.Unix(claims.Expiration, 0)
+		case claims.Exp > 0:
+			return time.Unix(claims.Exp, 0)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,145 @@
+package analyzer
+
+import (
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// QuotaRollout 是进行中的 Deployment 滚动更新，新模板剩余的 Pod 超出了命名空间剩余的配额
+// 新 Pod 会因配额 Pending，旧 Pod 仍在运行，滚动更新停在一半
+type QuotaRollout struct {
+	Namespace  string
+	Deployment string
+	// Remaining 是还没有更新到新模板的副本数（spec.replicas - status.updatedReplicas）
+	Remaining int
+	// Fits 是剩余配额还能容纳的新 Pod 数，小于 Remaining
+	Fits int
+
+	// Quota 和 Resource 是限制最紧的 ResourceQuota 及其中的资源，如 requests.cpu
+	Quota    string
+	Resource corev1.ResourceName
+	// PerPod 是新模板每个 Pod 占用的该资源，Hard/Used 是配额的上限和已用量
+	PerPod resource.Quantity
+	Hard   resource.Quantity
+	Used   resource.Quantity
+}
+
+// Available 返回配额剩余的量，已超出时为 0
+func (q QuotaRollout) Available() resource.Quantity {
+	available := q.Hard.DeepCopy()
+	available.Sub(q.Used)
+	if available.Sign() < 0 {
+		return resource.Quantity{Format: available.Format}
+	}
+	return available
+}
+
+// Needed 返回剩余副本需要的总量
+func (q QuotaRollout) Needed() resource.Quantity {
+	needed := q.PerPod.DeepCopy()
+	needed.Mul(int64(q.Remaining))
+	return needed
+}
+
+// findQuotaRollouts 找出剩余副本会耗尽命名空间配额的滚动更新，按命名空间和名称排序
+// 只检查被分析 Pod 所属、已获取的 Deployment；配额按 status.used 计算，不考虑旧 Pod 结束后释放的量
+func findQuotaRollouts(data *ClusterData) []QuotaRollout {
+	if data == nil || len(data.ResourceQuotas) == 0 {
+		return nil
+	}
+
+	var rollouts []QuotaRollout
+	for _, deploy := range data.Deployments {
+		if deploy == nil {
+			continue
+		}
+		remaining := int(deploymentReplicas(deploy) - deploy.Status.UpdatedReplicas)
+		if remaining <= 0 {
+			continue
+		}
+		usage := templateQuotaUsage(&deploy.Spec.Template.Spec)
+
+		var tightest *QuotaRollout
+		for i := range data.ResourceQuotas {
+			quota := &data.ResourceQuotas[i]
+			if quota.Namespace != deploy.Namespace {
+				continue
+			}
+			for name, hard := range quota.Status.Hard {
+				perPod, ok := usage[name]
+				if !ok || perPod.Sign() <= 0 {
+					continue
+				}
+				candidate := QuotaRollout{
+					Namespace:  deploy.Namespace,
+					Deployment: deploy.Name,
+					Remaining:  remaining,
+					Quota:      quota.Name,
+					Resource:   name,
+					PerPod:     perPod,
+					Hard:       hard,
+					Used:       quota.Status.Used[name],
+				}
+				available := candidate.Available()
+				candidate.Fits = int(available.MilliValue() / perPod.MilliValue())
+				if candidate.Fits >= remaining {
+					continue
+				}
+				if tightest == nil || candidate.Fits < tightest.Fits ||
+					(candidate.Fits == tightest.Fits && candidate.Quota+"/"+string(candidate.Resource) < tightest.Quota+"/"+string(tightest.Resource)) {
+					tightest = &candidate
+				}
+			}
+		}
+		if tightest != nil {
+			rollouts = append(rollouts, *tightest)
+		}
+	}
+
+	sort.Slice(rollouts, func(i, j int) bool {
+		if rollouts[i].Namespace != rollouts[j].Namespace {
+			return rollouts[i].Namespace < rollouts[j].Namespace
+		}
+		return rollouts[i].Deployment < rollouts[j].Deployment
+	})
+	return rollouts
+}
+
+// deploymentReplicas 返回 Deployment 期望的副本数，未设置时为 1
+func deploymentReplicas(deploy *appsv1.Deployment) int32 {
+	if deploy.Spec.Replicas == nil {
+		return 1
+	}
+	return *deploy.Spec.Replicas
+}
+
+// templateQuotaUsage 返回按模板创建的每个 Pod 计入 ResourceQuota 的量
+// requests 与调度器的算法一致（见 podRequests），limits 按同样的方式计算
+func templateQuotaUsage(spec *corev1.PodSpec) corev1.ResourceList {
+	cpuMilli, memBytes := podRequests(&corev1.Pod{Spec: *spec})
+	requestsCPU := *resource.NewMilliQuantity(cpuMilli, resource.DecimalSI)
+	requestsMemory := *resource.NewQuantity(memBytes, resource.BinarySI)
+
+	var limitsCPU, limitsMemory int64
+	for _, c := range spec.Containers {
+		limitsCPU += c.Resources.Limits.Cpu().MilliValue()
+		limitsMemory += c.Resources.Limits.Memory().Value()
+	}
+	for _, c := range spec.InitContainers {
+		limitsCPU = max(limitsCPU, c.Resources.Limits.Cpu().MilliValue())
+		limitsMemory = max(limitsMemory, c.Resources.Limits.Memory().Value())
+	}
+
+	return corev1.ResourceList{
+		corev1.ResourcePods:           *resource.NewQuantity(1, resource.DecimalSI),
+		corev1.ResourceCPU:            requestsCPU,
+		corev1.ResourceMemory:         requestsMemory,
+		corev1.ResourceRequestsCPU:    requestsCPU,
+		corev1.ResourceRequestsMemory: requestsMemory,
+		corev1.ResourceLimitsCPU:      *resource.NewMilliQuantity(limitsCPU, resource.DecimalSI),
+		corev1.ResourceLimitsMemory:   *resource.NewQuantity(limitsMemory, resource.BinarySI),
+	}
+}
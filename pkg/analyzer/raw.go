@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 可以原样附加到分析结果中的 Pod 字段
+const (
+	RawConditions        = "conditions"        // status.conditions
+	RawContainerStatuses = "containerStatuses" // status.containerStatuses
+	RawLabels            = "labels"            // metadata.labels
+	RawAnnotations       = "annotations"       // metadata.annotations（按前缀过滤）
+)
+
+// RawSections 是所有可选的原始数据段
+var RawSections = []string{RawConditions, RawContainerStatuses, RawLabels, RawAnnotations}
+
+// DefaultRawAnnotationMaxLength 是原始注解值的默认长度上限，超出部分被截断
+const DefaultRawAnnotationMaxLength = 256
+
+// RawTruncatedMarker 附加在被截断的注解值后面
+const RawTruncatedMarker = "...[truncated]"
+
+// RawOptions 控制附加到每个 Pod 分析结果中的原始数据
+type RawOptions struct {
+	// Sections 是要附加的数据段，取值见 RawSections，为空时不附加
+	Sections []string
+	// AnnotationPrefixes 是注解键的前缀白名单，为空时包含所有注解
+	AnnotationPrefixes []string
+	// MaxAnnotationLength 是注解值的长度上限，0 时使用 DefaultRawAnnotationMaxLength
+	MaxAnnotationLength int
+}
+
+// RawPod 是从源 Pod 原样复制的数据，未选择的数据段为空
+type RawPod struct {
+	Conditions        []corev1.PodCondition
+	ContainerStatuses []corev1.ContainerStatus
+	Labels            map[string]string
+	Annotations       map[string]string
+}
+
+// ValidateRawSections 检查数据段名称是否有效
+func ValidateRawSections(sections []string) error {
+	for _, section := range sections {
+		valid := false
+		for _, known := range RawSections {
+			if section == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown raw section %q (available: %s)", section, strings.Join(RawSections, ", "))
+		}
+	}
+	return nil
+}
+
+// rawPod 按选项从 Pod 中复制原始数据，没有选择任何数据段时返回 nil
+func rawPod(pod *corev1.Pod, opts RawOptions) *RawPod {
+	if len(opts.Sections) == 0 {
+		return nil
+	}
+	raw := &RawPod{}
+	for _, section := range opts.Sections {
+		switch section {
+		case RawConditions:
+			raw.Conditions = pod.Status.Conditions
+		case RawContainerStatuses:
+			raw.ContainerStatuses = pod.Status.ContainerStatuses
+		case RawLabels:
+			raw.Labels = pod.Labels
+		case RawAnnotations:
+			raw.Annotations = rawAnnotations(pod.Annotations, opts)
+		}
+	}
+	return raw
+}
+
+// rawAnnotations 按前缀白名单过滤注解，并截断过长的值
+// kubectl.kubernetes.io/last-applied-configuration 之类的注解可能有几十 KB
+func rawAnnotations(annotations map[string]string, opts RawOptions) map[string]string {
+	maxLength := opts.MaxAnnotationLength
+	if maxLength <= 0 {
+		maxLength = DefaultRawAnnotationMaxLength
+	}
+
+	var out map[string]string
+	for key, value := range annotations {
+		if !hasAnyPrefix(key, opts.AnnotationPrefixes) {
+			continue
+		}
+		if len(value) > maxLength {
+			// 在字符边界截断，避免产生无效的 UTF-8
+			cut := maxLength
+			for cut > 0 && !utf8.RuneStart(value[cut]) {
+				cut--
+			}
+			value = value[:cut] + RawTruncatedMarker
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// hasAnyPrefix 判断 s 是否以任一前缀开头，没有前缀时视为匹配
+func hasAnyPrefix(s string, prefixes []string) bool {
+	if len(prefixes) == 0 {
+		return true
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,274 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConfigMap/Secret 引用相关的配置问题
+const (
+	// IssueEnvVarConflict 表示多个 envFrom 来源定义了同名环境变量（后者覆盖前者）
+	IssueEnvVarConflict ConfigIssue = "Environment variable collision across envFrom sources"
+
+	// IssueConfigMapMutable 表示 Pod 挂载或 envFrom 引用的 ConfigMap 可以在运行时被修改
+	IssueConfigMapMutable ConfigIssue = "Referenced ConfigMap is not immutable"
+
+	// IssueSecretMutable 表示 Pod 挂载或 envFrom 引用的 Secret 可以在运行时被修改，难以审计
+	IssueSecretMutable ConfigIssue = "Referenced Secret is not immutable"
+)
+
+// ReferencedConfigMaps 返回 Pod 通过 envFrom、env 和 volume 引用的 ConfigMap 名称（已去重）
+func ReferencedConfigMaps(pod *corev1.Pod) []string {
+	seen := make(map[string]bool)
+	for _, container := range allContainers(pod) {
+		for _, from := range container.EnvFrom {
+			if from.ConfigMapRef != nil {
+				seen[from.ConfigMapRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.ConfigMapKeyRef != nil {
+				seen[env.ValueFrom.ConfigMapKeyRef.Name] = true
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			seen[volume.ConfigMap.Name] = true
+		}
+	}
+	return sortedNames(seen)
+}
+
+// ReferencedSecrets 返回 Pod 通过 envFrom、env 和 volume 引用的 Secret 名称（已去重）
+func ReferencedSecrets(pod *corev1.Pod) []string {
+	seen := make(map[string]bool)
+	for _, container := range allContainers(pod) {
+		for _, from := range container.EnvFrom {
+			if from.SecretRef != nil {
+				seen[from.SecretRef.Name] = true
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				seen[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil {
+			seen[volume.Secret.SecretName] = true
+		}
+	}
+	return sortedNames(seen)
+}
+
+// checkConfigMapImmutability 检查 Pod 通过 volume 和 envFrom 引用的 ConfigMap 是否设置了 immutable
+// 可变的 ConfigMap 在运行时被修改会造成无声的配置漂移；未获取到的 ConfigMap 会被跳过
+func checkConfigMapImmutability(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	seen := make(map[string]bool)
+	for _, container := range allContainers(pod) {
+		for _, from := range container.EnvFrom {
+			if from.ConfigMapRef != nil {
+				seen[from.ConfigMapRef.Name] = true
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			seen[volume.ConfigMap.Name] = true
+		}
+	}
+
+	var issues []ConfigIssue
+	for _, name := range sortedNames(seen) {
+		cm := data.configMap(pod.Namespace, name)
+		if cm != nil && (cm.Immutable == nil || !*cm.Immutable) {
+			issues = append(issues, withDetail(IssueConfigMapMutable, name))
+		}
+	}
+	return issues
+}
+
+// checkSecretImmutability 检查 Pod 通过 volume 和 envFrom 引用的 Secret 是否设置了 immutable
+// 未获取到的 Secret 会被跳过
+func checkSecretImmutability(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	seen := make(map[string]bool)
+	for _, container := range allContainers(pod) {
+		for _, from := range container.EnvFrom {
+			if from.SecretRef != nil {
+				seen[from.SecretRef.Name] = true
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil {
+			seen[volume.Secret.SecretName] = true
+		}
+	}
+
+	var issues []ConfigIssue
+	for _, name := range sortedNames(seen) {
+		secret := data.secret(pod.Namespace, name)
+		if secret != nil && (secret.Immutable == nil || !*secret.Immutable) {
+			issues = append(issues, withDetail(IssueSecretMutable, name))
+		}
+	}
+	return issues
+}
+
+// checkEnvFromConflicts 展开容器的 envFrom 来源，找出被多个来源定义的变量名
+// 只展开已获取到的 ConfigMap/Secret，未获取的来源会被跳过
+func checkEnvFromConflicts(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, container := range pod.Spec.Containers {
+		if len(container.EnvFrom) < 2 {
+			continue
+		}
+
+		sources := make(map[string][]string)
+		for _, from := range container.EnvFrom {
+			var source string
+			var keys []string
+			switch {
+			case from.ConfigMapRef != nil:
+				cm := data.configMap(pod.Namespace, from.ConfigMapRef.Name)
+				if cm == nil {
+					continue
+				}
+				source = "configmap/" + cm.Name
+				for k := range cm.Data {
+					keys = append(keys, k)
+				}
+				for k := range cm.BinaryData {
+					keys = append(keys, k)
+				}
+			case from.SecretRef != nil:
+				secret := data.secret(pod.Namespace, from.SecretRef.Name)
+				if secret == nil {
+					continue
+				}
+				source = "secret/" + secret.Name
+				for k := range secret.Data {
+					keys = append(keys, k)
+				}
+			default:
+				continue
+			}
+
+			for _, k := range keys {
+				name := from.Prefix + k
+				sources[name] = append(sources[name], source)
+			}
+		}
+
+		var conflicts []string
+		for name, from := range sources {
+			if len(from) > 1 {
+				conflicts = append(conflicts, fmt.Sprintf("%s from %s", name, strings.Join(from, ", ")))
+			}
+		}
+		sort.Strings(conflicts)
+		for _, conflict := range conflicts {
+			issues = append(issues, withDetail(IssueEnvVarConflict, container.Name+": "+conflict))
+		}
+	}
+	return issues
+}
+
+// allContainers 返回 Pod 的 init 容器和普通容器
+func allContainers(pod *corev1.Pod) []corev1.Container {
+	containers := make([]corev1.Container, 0, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	containers = append(containers, pod.Spec.InitContainers...)
+	return append(containers, pod.Spec.Containers...)
+}
+
+// sortedNames 返回集合中排序后的名称
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ConfigHashAnnotation 是常见的 Pod 模板注解，保存 ConfigMap 内容的哈希以便变更时触发滚动更新
+const ConfigHashAnnotation = "checksum/config"
+
+// IssueStaleConfigHash 表示 ConfigMap 已修改但 Pod 没有重启
+const IssueStaleConfigHash ConfigIssue = "Config hash mismatch: ConfigMap may have changed without pod restart"
+
+// checkConfigHash 比较 checksum/config 注解与 Pod 引用的 ConfigMap 的 SHA256
+// 注解的生成方式因部署工具而异（如 Helm 对渲染后的模板求哈希），
+// 因此只有在哈希不匹配且 ConfigMap 在 Pod 创建之后被修改过时才报告
+func checkConfigHash(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	want := pod.Annotations[ConfigHashAnnotation]
+	if want == "" {
+		return nil
+	}
+
+	var changed []string
+	for _, name := range ReferencedConfigMaps(pod) {
+		cm := data.configMap(pod.Namespace, name)
+		if cm == nil {
+			continue
+		}
+		if configMapHash(cm) == want {
+			return nil
+		}
+		if lastModified(&cm.ObjectMeta).After(pod.CreationTimestamp.Time) {
+			changed = append(changed, cm.Name)
+		}
+	}
+
+	if len(changed) == 0 {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueStaleConfigHash, "updated after pod start: "+strings.Join(changed, ", "))}
+}
+
+// configMapHash 计算 ConfigMap 数据的 SHA256（按键排序后序列化）
+func configMapHash(cm *corev1.ConfigMap) string {
+	h := sha256.New()
+	keys := make([]string, 0, len(cm.Data))
+	for k := range cm.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=%s\n", k, cm.Data[k])
+	}
+
+	keys = keys[:0]
+	for k := range cm.BinaryData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, "%s=", k)
+		h.Write(cm.BinaryData[k])
+		h.Write([]byte("\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// lastModified 根据 managedFields 估算对象最后一次修改的时间
+func lastModified(meta *metav1.ObjectMeta) time.Time {
+	latest := meta.CreationTimestamp.Time
+	for _, entry := range meta.ManagedFields {
+		if entry.Time != nil && entry.Time.After(latest) {
+			latest = entry.Time.Time
+		}
+	}
+	return latest
+}
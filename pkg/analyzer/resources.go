@@ -0,0 +1,91 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// IssueCPUBurstRatioTooHigh 表示容器的 CPU limit 远高于 request
+// 调度只看 request，大量这样的容器被放到同一节点后，负载上来时会被严重限流
+const IssueCPUBurstRatioTooHigh ConfigIssue = "CPU limit far above request (high burst ratio)"
+
+// DefaultCPUBurstRatio 是 --cpu-burst-ratio 的默认值
+const DefaultCPUBurstRatio = 50.0
+
+// checkCPUBurst 检查容器的 CPU limit/request 比例是否超过 maxRatio
+// 只设置 limit 时 request 默认等于 limit，因此只检查两者都显式设置的容器
+func checkCPUBurst(container *corev1.Container, maxRatio float64) []ConfigIssue {
+	request, hasRequest := container.Resources.Requests[corev1.ResourceCPU]
+	limit, hasLimit := container.Resources.Limits[corev1.ResourceCPU]
+	if !hasRequest || !hasLimit || request.MilliValue() == 0 {
+		return nil
+	}
+
+	ratio := float64(limit.MilliValue()) / float64(request.MilliValue())
+	if ratio <= maxRatio {
+		return nil
+	}
+	detail := fmt.Sprintf("%.0fx: request %s, limit %s", ratio, request.String(), limit.String())
+	return []ConfigIssue{withDetail(IssueCPUBurstRatioTooHigh, detail)}
+}
+
+// podRequests 计算 Pod 的有效资源请求（CPU 毫核，内存字节）
+// 与调度器一致：取普通容器请求之和与最大 init 容器请求中的较大值，再加上 overhead
+func podRequests(pod *corev1.Pod) (cpuMilli, memBytes int64) {
+	for _, c := range pod.Spec.Containers {
+		cpuMilli += c.Resources.Requests.Cpu().MilliValue()
+		memBytes += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if v := c.Resources.Requests.Cpu().MilliValue(); v > cpuMilli {
+			cpuMilli = v
+		}
+		if v := c.Resources.Requests.Memory().Value(); v > memBytes {
+			memBytes = v
+		}
+	}
+	if pod.Spec.Overhead != nil {
+		cpuMilli += pod.Spec.Overhead.Cpu().MilliValue()
+		memBytes += pod.Spec.Overhead.Memory().Value()
+	}
+	return cpuMilli, memBytes
+}
+
+// nodeCommitment 计算节点上已分配请求占可分配资源的比例（CPU、内存）
+// 只统计传入的 Pod，因此在单命名空间模式下会偏低
+func nodeCommitment(node *corev1.Node, pods *corev1.PodList) (cpuRatio, memRatio float64) {
+	allocCPU := node.Status.Allocatable.Cpu().MilliValue()
+	allocMem := node.Status.Allocatable.Memory().Value()
+	if allocCPU == 0 || allocMem == 0 {
+		return 0, 0
+	}
+
+	var cpu, mem int64
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if pod.Spec.NodeName != node.Name || isTerminal(pod) {
+			continue
+		}
+		c, m := podRequests(pod)
+		cpu += c
+		mem += m
+	}
+	return float64(cpu) / float64(allocCPU), float64(mem) / float64(allocMem)
+}
+
+// isTerminal 判断 Pod 是否已经结束，结束的 Pod 不再占用节点资源
+func isTerminal(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// formatCPU 将毫核格式化为易读的形式
+func formatCPU(milli int64) string {
+	return resource.NewMilliQuantity(milli, resource.DecimalSI).String()
+}
+
+// formatMemory 将字节格式化为易读的形式
+func formatMemory(bytes int64) string {
+	return resource.NewQuantity(bytes, resource.BinarySI).String()
+}
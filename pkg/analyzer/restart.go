@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// HighRestartThreshold 是 Pod 重启次数超过多少时视为频繁重启
+const HighRestartThreshold = 10
+
+// IssueBarePodRestarting 表示没有控制器管理的 Pod 在 restartPolicy: Always 下反复重启
+// 这样的 Pod 永远不会进入 Failed，不会被垃圾回收，也不会被调度到其他节点
+const IssueBarePodRestarting ConfigIssue = "Unmanaged pod with restartPolicy Always keeps restarting"
+
+// restartPolicy 返回 Pod 生效的重启策略，未设置时为 Always
+func restartPolicy(pod *corev1.Pod) corev1.RestartPolicy {
+	if pod.Spec.RestartPolicy == "" {
+		return corev1.RestartPolicyAlways
+	}
+	return pod.Spec.RestartPolicy
+}
+
+// checkBarePodRestarts 检查没有控制器的 Pod 是否在 restartPolicy: Always 下频繁重启
+func checkBarePodRestarts(pod *corev1.Pod) []Finding {
+	if kind, _ := ResolveOwner(pod); kind != "" || pod.Annotations[corev1.MirrorPodAnnotationKey] != "" {
+		return nil
+	}
+	if restartPolicy(pod) != corev1.RestartPolicyAlways {
+		return nil
+	}
+	var restarts int32
+	for _, cs := range pod.Status.ContainerStatuses {
+		restarts += cs.RestartCount
+	}
+	if restarts <= HighRestartThreshold {
+		return nil
+	}
+	return []Finding{{Issue: IssueBarePodRestarting, Detail: fmt.Sprintf("%d restarts, never rescheduled or garbage-collected", restarts)}}
+}
+
+// JobRestartSemantics 说明 Job Pod 的重启次数在该重启策略下的含义
+func JobRestartSemantics(policy corev1.RestartPolicy) string {
+	switch policy {
+	case corev1.RestartPolicyOnFailure:
+		return "containers restart in place; restarts count toward the Job's backoffLimit"
+	case corev1.RestartPolicyNever:
+		return "failed attempts become new pods; this pod's restart count stays 0"
+	default:
+		return "not a valid Job restartPolicy; Jobs accept only OnFailure or Never"
+	}
+}
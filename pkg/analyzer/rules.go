@@ -0,0 +1,503 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// checkConfigEnabled 表示检查随 --check-config 启用
+func checkConfigEnabled(opts Options) bool {
+	return opts.CheckConfig
+}
+
+// builtinChecks 是内置的检查，按顺序执行：容器级别的检查在前，--check-config 的 Pod 级别检查在最后
+// 标记 template 的检查只读取元数据和 PodSpec，也用于 --templates 的工作负载模板
+var builtinChecks = []builtinCheck{
+	{
+		name:     "cpu-burst",
+		enabled:  func(opts Options) bool { return opts.CheckCPUBurst },
+		template: true,
+		check: containerIssues(func(ctx *CheckContext, pod *corev1.Pod, container *corev1.Container) []ConfigIssue {
+			maxRatio := ctx.opts.MaxCPUBurstRatio
+			if maxRatio <= 0 {
+				maxRatio = DefaultCPUBurstRatio
+			}
+			return checkCPUBurst(container, maxRatio)
+		}),
+	},
+	{
+		name:   "stale-images",
+		option: func(o *Options) *bool { return &o.CheckStaleImages },
+		check: containerIssues(func(ctx *CheckContext, pod *corev1.Pod, container *corev1.Container) []ConfigIssue {
+			return checkStaleImage(pod, container, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "vulnerabilities",
+		option: func(o *Options) *bool { return &o.CheckVulnerabilities },
+		check: containerIssues(func(ctx *CheckContext, pod *corev1.Pod, container *corev1.Container) []ConfigIssue {
+			return checkVulnerabilities(pod, container, ctx.Cluster)
+		}),
+	},
+
+	{name: "resources", enabled: checkConfigEnabled, template: true, check: podFindings(checkResources)},
+	{name: "probes", enabled: checkConfigEnabled, template: true, check: podFindings(checkProbes)},
+	{name: "termination-message", enabled: checkConfigEnabled, check: podFindings(checkTerminationMessages)},
+	{name: "termination-message-policy", enabled: checkConfigEnabled, template: true, check: podFindings(checkTerminationMessagePolicy)},
+	{name: "restart-policy", enabled: checkConfigEnabled, check: podFindings(checkBarePodRestarts)},
+	{name: "env-injection", enabled: checkConfigEnabled, template: true, check: podFindings(checkInjectedEnvConflicts)},
+	{name: "template-hash", enabled: checkConfigEnabled, check: podFindings(checkTemplateHash)},
+	{name: "stdin-tty", enabled: checkConfigEnabled, template: true, check: podFindings(checkInteractiveContainers)},
+	{name: "security-context-conflict", enabled: checkConfigEnabled, template: true, check: podFindings(checkSecurityContextConflict)},
+	{name: "shell-pid1", enabled: func(opts Options) bool { return opts.CheckGrace }, template: true, check: podFindings(checkShellAsPID1)},
+	{name: "shared-probe-port", enabled: func(opts Options) bool { return opts.CheckProbes }, template: true, check: podFindings(checkSharedProbePorts)},
+
+	{
+		name:   "topology",
+		option: func(o *Options) *bool { return &o.CheckTopology },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkVirtualNodeZone(pod, ctx.Cluster, ctx.providers)
+		}),
+	},
+	{
+		name:   "pdb",
+		option: func(o *Options) *bool { return &o.CheckPDB },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkPDBCoverage(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "network-policy",
+		option: func(o *Options) *bool { return &o.CheckNetworkPolicy },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if ctx.denies == nil {
+				return nil
+			}
+			return checkDefaultDeny(pod, ctx.denies)
+		}),
+	},
+	{
+		name:   "webhooks",
+		option: func(o *Options) *bool { return &o.CheckWebhooks },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkWebhookDisruption(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "drift",
+		option: func(o *Options) *bool { return &o.CheckDrift },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			// 直接修改的镜像只按 ReplicaSet 模板报告一次
+			var templateDrift []ConfigIssue
+			var edited map[string]bool
+			if rs := ctx.Cluster.replicaSet(pod.Namespace, ControllerReplicaSet(pod)); rs != nil {
+				templateDrift, edited = checkTemplateDrift(pod, rs, ctx.fingerprints)
+			}
+			var issues []ConfigIssue
+			if kind, name := ResolveOwner(pod); kind == "Deployment" {
+				if deploy := ctx.Cluster.deployment(pod.Namespace, name); deploy != nil {
+					issues = append(checkImageDrift(pod, deploy, edited), checkSpecDrift(pod, deploy)...)
+				}
+			}
+			return append(issues, templateDrift...)
+		}),
+	},
+	{
+		name:   "job",
+		option: func(o *Options) *bool { return &o.CheckJob },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			kind, name := ResolveOwner(pod)
+			if kind != "Job" {
+				return nil
+			}
+			if job := ctx.Cluster.job(pod.Namespace, name); job != nil {
+				return checkPodFailurePolicy(job)
+			}
+			return nil
+		}),
+	},
+	{
+		name:   "configmaps",
+		option: func(o *Options) *bool { return &o.CheckConfigMaps },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkConfigMapImmutability(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "secrets",
+		option: func(o *Options) *bool { return &o.CheckSecrets },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkSecretImmutability(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "config-hash",
+		option: func(o *Options) *bool { return &o.CheckConfigHash },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkConfigHash(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "pull-secrets",
+		option: func(o *Options) *bool { return &o.CheckRefs },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkPullSecrets(pod, ctx.Cluster, ctx.opts.PullSecretExpiryAnnotation, Now())
+		}),
+	},
+	{
+		name:     "required-labels",
+		enabled:  func(opts Options) bool { return len(opts.RequiredLabels) > 0 },
+		template: true,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if len(ctx.opts.RequiredLabels) == 0 {
+				return nil
+			}
+			return checkRequiredLabels(pod, ctx.Cluster.namespace(ctx.Namespace), ctx.opts.RequiredLabels)
+		}),
+	},
+	{
+		name:    "init-timeout",
+		enabled: func(opts Options) bool { return opts.CheckInitTimeout },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkInitTimeout(pod, ctx.opts.InitTimeout)
+		}),
+	},
+	{
+		name:    "scheduling-latency",
+		enabled: func(opts Options) bool { return opts.CheckSchedulingTimeout },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkSchedulingLatency(pod, ctx.opts.SchedulingTimeout)
+		}),
+	},
+	{
+		name:   "psp",
+		option: func(o *Options) *bool { return &o.CheckPSP },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if ctx.Cluster == nil {
+				return nil
+			}
+			return checkPodSecurityPolicies(pod, ctx.Cluster.PodSecurityPolicies)
+		}),
+	},
+	{
+		name:   "efficiency",
+		option: func(o *Options) *bool { return &o.CheckResourceEfficiency },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if ctx.Cluster == nil || ctx.Cluster.PodUsage == nil {
+				return nil
+			}
+			return checkResourceEfficiency(pod, ctx.Cluster.podUsage(pod), ctx.opts.EfficiencyThreshold)
+		}),
+	},
+	{
+		name:   "pull-secret-type",
+		option: func(o *Options) *bool { return &o.CheckPullSecretType },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkPullSecretTypes(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "storage",
+		option: func(o *Options) *bool { return &o.CheckStorage },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return append(checkLocalStorage(pod, ctx.Cluster), checkVolumeOperations(pod, ctx.Cluster)...)
+		}),
+	},
+	{
+		name:     "cost-annotation",
+		enabled:  func(opts Options) bool { return opts.CheckAnnotationPolicy },
+		template: true,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkCostAnnotation(pod, ctx.opts.costAnnotation())
+		}),
+	},
+	{
+		name:     "autoscaler-eviction",
+		enabled:  func(opts Options) bool { return opts.CheckAutoscaler },
+		template: true,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkAutoscalerEviction(pod, ctx.OwnerKind)
+		}),
+	},
+	{name: "security", enabled: func(opts Options) bool { return opts.CheckSecurity }, template: true, check: podIssues(checkSecurity)},
+	{
+		name:   "startup-order",
+		option: func(o *Options) *bool { return &o.CheckStartupOrder },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkServiceDependencies(pod, ctx.Cluster)
+		}),
+	},
+	{
+		name:   "service-ports",
+		option: func(o *Options) *bool { return &o.CheckServicePorts },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if ctx.services == nil {
+				return nil
+			}
+			return checkServicePorts(pod, ctx.services[pod.Namespace])
+		}),
+	},
+	{
+		name:   "node-unschedulable",
+		option: func(o *Options) *bool { return &o.CheckNodeUnschedulable },
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			node := ctx.Cluster.node(pod.Spec.NodeName)
+			return append(checkNodeState(node), checkScheduledAfterCordon(pod, node)...)
+		}),
+	},
+	{name: "dns", enabled: func(opts Options) bool { return opts.CheckDNS }, template: true, check: podIssues(checkDNS)},
+
+	// Pod 级别的配置检查
+	{name: "kata-overhead", enabled: checkConfigEnabled, template: true, check: podIssues(checkKataOverhead)},
+	{name: "finalizers", enabled: checkConfigEnabled, template: true, check: podIssues(checkFinalizers)},
+	{
+		name:     "name-length",
+		enabled:  checkConfigEnabled,
+		template: true,
+		check: contextIssues(func(ctx *CheckContext, _ *corev1.Pod) []ConfigIssue {
+			return checkNameLength(ctx.Name, ctx.podNameSuffix)
+		}),
+	},
+	{name: "default-container", enabled: checkConfigEnabled, template: true, check: podIssues(checkDefaultContainer)},
+	{name: "sequential-init", enabled: checkConfigEnabled, template: true, check: podIssues(checkSequentialInitContainers)},
+	{
+		name:     "annotation-size",
+		enabled:  checkConfigEnabled,
+		template: true,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkAnnotationSize(pod, ctx.opts.MaxAnnotationSize)
+		}),
+	},
+	{
+		name:    "stale-ready",
+		enabled: checkConfigEnabled,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkStaleReadyCondition(pod, ctx.opts.StaleConditionThreshold)
+		}),
+	},
+	{
+		name:    "selector-mismatch",
+		enabled: checkConfigEnabled,
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			if selectorMismatch(pod, ctx.Cluster) {
+				return []ConfigIssue{IssueSelectorMismatch}
+			}
+			return nil
+		}),
+	},
+	{
+		// 需要展开 envFrom 引用的对象，因此依赖 --check-configmaps / --check-secrets
+		name: "envfrom-conflicts",
+		enabled: func(opts Options) bool {
+			return opts.CheckConfig && (opts.CheckConfigMaps || opts.CheckSecrets)
+		},
+		check: contextIssues(func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue {
+			return checkEnvFromConflicts(pod, ctx.Cluster)
+		}),
+	},
+}
+
+// podFindings 将只需要 Pod 的检查函数包装为内置检查
+func podFindings(check func(pod *corev1.Pod) []Finding) func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+	return func(_ *CheckContext, pod *corev1.Pod) []Finding {
+		return check(pod)
+	}
+}
+
+// podIssues 将只需要 Pod、返回 ConfigIssue 的检查函数包装为内置检查
+func podIssues(check func(pod *corev1.Pod) []ConfigIssue) func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+	return func(_ *CheckContext, pod *corev1.Pod) []Finding {
+		return findings(check(pod))
+	}
+}
+
+// contextIssues 将需要集群数据或选项、返回 ConfigIssue 的检查函数包装为内置检查
+func contextIssues(check func(ctx *CheckContext, pod *corev1.Pod) []ConfigIssue) func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+	return func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+		return findings(check(ctx, pod))
+	}
+}
+
+// containerIssues 对 Pod 的每个容器执行容器级别的检查
+func containerIssues(check func(ctx *CheckContext, pod *corev1.Pod, container *corev1.Container) []ConfigIssue) func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+	return func(ctx *CheckContext, pod *corev1.Pod) []Finding {
+		var issues []ConfigIssue
+		for i := range pod.Spec.Containers {
+			issues = append(issues, check(ctx, pod, &pod.Spec.Containers[i])...)
+		}
+		return findings(issues)
+	}
+}
+
+// checkResources 检查容器的资源请求和限制
+// init 容器也会检查：它们按顺序执行，资源不足时会无声地阻塞 Pod 启动
+func checkResources(pod *corev1.Pod) []Finding {
+	var findings []Finding
+	for _, c := range pod.Spec.Containers {
+		if len(c.Resources.Requests) == 0 {
+			findings = append(findings, Finding{Issue: IssueMissingRequests})
+		}
+		if len(c.Resources.Limits) == 0 {
+			findings = append(findings, Finding{Issue: IssueMissingLimits})
+		}
+	}
+	for _, init := range pod.Spec.InitContainers {
+		if len(init.Resources.Requests) == 0 {
+			findings = append(findings, Finding{Issue: IssueMissingRequests, Detail: "init container: " + init.Name})
+		}
+		if len(init.Resources.Limits) == 0 {
+			findings = append(findings, Finding{Issue: IssueMissingLimits, Detail: "init container: " + init.Name})
+		}
+	}
+	return findings
+}
+
+// checkProbes 检查容器是否配置了存活或就绪探针
+func checkProbes(pod *corev1.Pod) []Finding {
+	for _, c := range pod.Spec.Containers {
+		if c.LivenessProbe == nil && c.ReadinessProbe == nil {
+			return []Finding{{Issue: IssueNoProbe}}
+		}
+	}
+	return nil
+}
+
+// checkTerminationMessages 检查重启过的容器是否留下了终止消息，没有时排查会缺少关键上下文
+func checkTerminationMessages(pod *corev1.Pod) []Finding {
+	for i := range pod.Spec.Containers {
+		if missingTerminationMessage(pod, &pod.Spec.Containers[i]) {
+			return []Finding{{Issue: IssueNoTerminationMessage}}
+		}
+	}
+	return nil
+}
+
+// checkTerminationMessagePolicy 检查容器是否设置了 terminationMessagePolicy: FallbackToLogsOnError，细节中列出未设置的容器
+// 终止消息是 API 中唯一结构化的退出信息，FallbackToLogsOnError 在程序没有写终止日志时用最后的日志代替
+func checkTerminationMessagePolicy(pod *corev1.Pod) []Finding {
+	var names []string
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			if c.TerminationMessagePolicy != corev1.TerminationMessageFallbackToLogsOnError {
+				names = append(names, c.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []Finding{{Issue: IssueNoTerminationMessagePolicy, Detail: strings.Join(names, ", ")}}
+}
+
+// checkInteractiveContainers 检查容器是否设置了 stdin 或 tty，细节中列出对应的容器
+// 临时容器（kubectl debug）本来就是交互式的，不检查
+func checkInteractiveContainers(pod *corev1.Pod) []Finding {
+	var stdin, tty []string
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, c := range containers {
+			if c.Stdin {
+				stdin = append(stdin, c.Name)
+			}
+			if c.TTY {
+				tty = append(tty, c.Name)
+			}
+		}
+	}
+
+	var findings []Finding
+	if len(stdin) > 0 {
+		findings = append(findings, Finding{Issue: IssueStdinEnabled, Detail: strings.Join(stdin, ", ")})
+	}
+	if len(tty) > 0 {
+		findings = append(findings, Finding{Issue: IssueTTYEnabled, Detail: strings.Join(tty, ", ")})
+	}
+	return findings
+}
+
+// missingTerminationMessage 判断重启过的容器是否没有留下终止消息
+func missingTerminationMessage(pod *corev1.Pod, container *corev1.Container) bool {
+	if container.TerminationMessagePolicy == corev1.TerminationMessageFallbackToLogsOnError {
+		return false
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != container.Name || cs.RestartCount == 0 {
+			continue
+		}
+		if cs.LastTerminationState.Terminated != nil && firstLine(cs.LastTerminationState.Terminated.Message) != "" {
+			return false
+		}
+		if cs.State.Terminated != nil && firstLine(cs.State.Terminated.Message) != "" {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// checkPDBCoverage 检查 Deployment/StatefulSet 的 Pod 是否被 PodDisruptionBudget 覆盖
+func checkPDBCoverage(pod *corev1.Pod, cluster *ClusterData) []ConfigIssue {
+	kind, _ := ResolveOwner(pod)
+	if kind != "Deployment" && kind != "StatefulSet" {
+		return nil
+	}
+	if len(cluster.matchingPDBs(pod)) == 0 {
+		return []ConfigIssue{IssueNoPDB}
+	}
+	return nil
+}
+
+// checkVulnerabilities 检查容器镜像的 HIGH/CRITICAL 漏洞数量
+func checkVulnerabilities(pod *corev1.Pod, container *corev1.Container, cluster *ClusterData) []ConfigIssue {
+	if cluster == nil {
+		return nil
+	}
+	if count := cluster.Vulnerabilities[pod.Namespace+"/"+pod.Name+"/"+container.Name]; count > 0 {
+		return []ConfigIssue{withDetail(IssueHighSeverityCVE, fmt.Sprintf("%s: %d found", container.Name, count))}
+	}
+	return nil
+}
+
+// checkKataOverhead 检查 Kata Pod 是否声明了 overhead
+// Kata 的 VM shim 有额外开销，需要通过 RuntimeClass overhead 让调度器感知
+func checkKataOverhead(pod *corev1.Pod) []ConfigIssue {
+	if isKataRuntime(pod) && pod.Spec.Overhead == nil {
+		return []ConfigIssue{IssueMissingKataOverhead}
+	}
+	return nil
+}
+
+// checkFinalizers 检查自定义 finalizer，其控制器崩溃时 Pod 会一直卡在 Terminating
+func checkFinalizers(pod *corev1.Pod) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, finalizer := range pod.Finalizers {
+		if !standardPodFinalizers[finalizer] {
+			issues = append(issues, ConfigIssue(string(IssuePodFinalizer)+": "+finalizer))
+		}
+	}
+	return issues
+}
+
+// checkNameLength 检查 Pod 名称是否超过 DNS 相关的长度限制
+// 检查工作负载模板时 name 是工作负载名称，Pod 名称按控制器追加的后缀长度 suffix 估算，细节中给出估算的长度
+// 生成的名称会被截断到 DNS label 的长度以内，估算值也以此为上限
+// 容器名称由 API server 校验为 DNS label，不会超过限制，不需要检查
+func checkNameLength(name string, suffix int) []ConfigIssue {
+	length := len(name) + suffix
+	if length <= maxSafePodNameLen {
+		return nil
+	}
+	if suffix > 0 {
+		return []ConfigIssue{withDetail(IssueNameTooLong, fmt.Sprintf("generated pod names up to %d characters", min(length, maxDNSLabelLen)))}
+	}
+	return []ConfigIssue{IssueNameTooLong}
+}
+
+// checkDefaultContainer 检查多容器 Pod 是否设置了默认容器，否则 kubectl logs/exec 必须显式指定 -c
+func checkDefaultContainer(pod *corev1.Pod) []ConfigIssue {
+	if len(pod.Spec.Containers) > 1 && pod.Annotations[DefaultContainerAnnotation] == "" {
+		return []ConfigIssue{IssueMissingDefaultContainer}
+	}
+	return nil
+}
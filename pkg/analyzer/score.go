@@ -0,0 +1,86 @@
+package analyzer
+
+import "sort"
+
+// 问题评分权重，集中在这里便于调整
+const (
+	scoreError   = 1000
+	scoreWarning = 500
+	scoreUnknown = 400
+	scorePending = 300
+
+	scorePerRestart    = 10
+	maxScoredRestarts  = 50 // 重启次数超过此值不再加分，避免老 Pod 压过新故障
+	scorePerProblemHr  = 5
+	maxScoredProblemHr = 7 * 24
+)
+
+// ScoredPod 是带有问题评分的 Pod
+type ScoredPod struct {
+	Rank  int
+	Score int
+	Pod   PodAnalysis
+}
+
+// ProblemScore 计算 Pod 的问题严重程度评分，健康的 Pod 为 0
+// 状态决定基础分（Error > Warning > Unknown > Pending），再按重启次数和问题持续时间加权
+func ProblemScore(pod PodAnalysis) int {
+	score := 0
+	switch pod.Status {
+	case StatusError:
+		score = scoreError
+	case StatusWarning:
+		score = scoreWarning
+	case StatusUnknown:
+		score = scoreUnknown
+	case StatusPending:
+		score = scorePending
+	default:
+		return 0
+	}
+
+	restarts := int(pod.Restarts)
+	if restarts > maxScoredRestarts {
+		restarts = maxScoredRestarts
+	}
+	score += restarts * scorePerRestart
+
+	if !pod.ProblemSince.IsZero() {
+		hours := int(Now().Sub(pod.ProblemSince).Hours())
+		if hours > maxScoredProblemHr {
+			hours = maxScoredProblemHr
+		}
+		score += hours * scorePerProblemHr
+	}
+
+	return score
+}
+
+// TopProblems 返回评分最高的 n 个问题 Pod
+// 评分相同时按命名空间和名称排序，保证结果稳定
+func TopProblems(result *AnalysisResult, n int) []ScoredPod {
+	var scored []ScoredPod
+	for _, pod := range result.Pods {
+		if score := ProblemScore(pod); score > 0 {
+			scored = append(scored, ScoredPod{Score: score, Pod: pod})
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		if scored[i].Pod.Namespace != scored[j].Pod.Namespace {
+			return scored[i].Pod.Namespace < scored[j].Pod.Namespace
+		}
+		return scored[i].Pod.Name < scored[j].Pod.Name
+	})
+
+	if n > 0 && len(scored) > n {
+		scored = scored[:n]
+	}
+	for i := range scored {
+		scored[i].Rank = i + 1
+	}
+	return scored
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 安全相关的配置问题
+const (
+	IssueSecretInEnv ConfigIssue = "Secret consumed via environment variables"
+
+	// 是否以 root 运行取决于镜像的 USER，从 Pod spec 无法确定，因此区分"一定"和"可能"
+	IssueRunsAsRoot       ConfigIssue = "Container runs as root (runAsUser: 0)"
+	IssueRootNotPrevented ConfigIssue = "Container may run as root (securityContext does not prevent it)"
+
+	// 容器继承 Pod 级的 runAsUser: 0 却要求 runAsNonRoot，kubelet 会拒绝启动（CreateContainerConfigError）
+	IssueSecurityContextConflict ConfigIssue = "Contradictory security context: pod RunAsUser=0 but container RunAsNonRoot=true"
+)
+
+// checkSecurity 执行 --check-security 启用的安全检查
+func checkSecurity(pod *corev1.Pod) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, container := range allContainers(pod) {
+		if names := envSecrets(pod, &container); len(names) > 0 {
+			noun := "secrets"
+			if len(names) == 1 {
+				noun = "secret"
+			}
+			issues = append(issues, withDetail(IssueSecretInEnv,
+				fmt.Sprintf("%s: %d %s: %s", container.Name, len(names), noun, strings.Join(names, ", "))))
+		}
+	}
+	issues = append(issues, checkRunAsRoot(pod)...)
+	return issues
+}
+
+// checkRunAsRoot 根据 securityContext 判断容器是否以 root 运行
+// 显式的 runAsUser: 0 一定是 root；未设置 runAsUser 且未要求 runAsNonRoot 时取决于镜像，只对已启动的容器报告
+func checkRunAsRoot(pod *corev1.Pod) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, container := range pod.Spec.Containers {
+		runAsUser, runAsNonRoot := effectiveRunAs(pod, &container)
+		switch {
+		case runAsUser != nil && *runAsUser == 0:
+			issues = append(issues, withDetail(IssueRunsAsRoot, container.Name))
+		case runAsUser == nil && (runAsNonRoot == nil || !*runAsNonRoot) && containerStarted(pod, container.Name):
+			issues = append(issues, withDetail(IssueRootNotPrevented, container.Name))
+		}
+	}
+	return issues
+}
+
+// checkSecurityContextConflict 检查容器是否在继承 Pod 级 runAsUser: 0 的同时设置了 runAsNonRoot: true
+// 容器自己设置了 runAsUser 时不继承 Pod 级的值，不算冲突；细节中列出对应的容器
+func checkSecurityContextConflict(pod *corev1.Pod) []Finding {
+	sc := pod.Spec.SecurityContext
+	if sc == nil || sc.RunAsUser == nil || *sc.RunAsUser != 0 {
+		return nil
+	}
+	var names []string
+	for _, container := range allContainers(pod) {
+		csc := container.SecurityContext
+		if csc == nil || csc.RunAsUser != nil || csc.RunAsNonRoot == nil || !*csc.RunAsNonRoot {
+			continue
+		}
+		names = append(names, container.Name)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return []Finding{{Issue: IssueSecurityContextConflict, Detail: strings.Join(names, ", ")}}
+}
+
+// effectiveRunAs 返回容器生效的 runAsUser 和 runAsNonRoot，容器级设置覆盖 Pod 级设置
+func effectiveRunAs(pod *corev1.Pod, container *corev1.Container) (runAsUser *int64, runAsNonRoot *bool) {
+	if sc := pod.Spec.SecurityContext; sc != nil {
+		runAsUser, runAsNonRoot = sc.RunAsUser, sc.RunAsNonRoot
+	}
+	if sc := container.SecurityContext; sc != nil {
+		if sc.RunAsUser != nil {
+			runAsUser = sc.RunAsUser
+		}
+		if sc.RunAsNonRoot != nil {
+			runAsNonRoot = sc.RunAsNonRoot
+		}
+	}
+	return runAsUser, runAsNonRoot
+}
+
+// containerStarted 判断容器是否已经启动过
+func containerStarted(pod *corev1.Pod, name string) bool {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name != name {
+			continue
+		}
+		return cs.State.Running != nil || cs.State.Terminated != nil || cs.LastTerminationState.Terminated != nil
+	}
+	return false
+}
+
+// envSecrets 返回容器通过 env/envFrom 使用的 Secret 名称（已去重，不含值）
+// 容器同时以 volume 挂载的 Secret 不重复计入
+func envSecrets(pod *corev1.Pod, container *corev1.Container) []string {
+	seen := make(map[string]bool)
+	for _, from := range container.EnvFrom {
+		if from.SecretRef != nil {
+			seen[from.SecretRef.Name] = true
+		}
+	}
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+			seen[env.ValueFrom.SecretKeyRef.Name] = true
+		}
+	}
+	if len(seen) == 0 {
+		return nil
+	}
+
+	secretVolumes := make(map[string]string) // volume 名称 -> Secret 名称
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil {
+			secretVolumes[volume.Name] = volume.Secret.SecretName
+		}
+	}
+	for _, mount := range container.VolumeMounts {
+		if name, ok := secretVolumes[mount.Name]; ok {
+			delete(seen, name)
+		}
+	}
+	return sortedNames(seen)
+}
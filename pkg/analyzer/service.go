@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// IssueServiceTargetPortMismatch 表示选中 Pod 的 Service 的 targetPort 不对应 Pod 声明的任何容器端口
+// 数字 targetPort 没有容器端口匹配、或命名 targetPort 在容器端口名称中找不到时，流量会被转发到没有监听的端口
+const IssueServiceTargetPortMismatch ConfigIssue = "Service targetPort matches no container port"
+
+// IssueServicePortUnverified 表示 Pod 没有声明任何 containerPort，无法核对 Service 的数字 targetPort
+// containerPort 只是声明，未声明的端口照样可以监听，因此只作为提示
+const IssueServicePortUnverified ConfigIssue = "Service targetPort cannot be verified"
+
+// ServicePortDetail 返回 Service 端口问题的细节，如 "service web port 80 targets 8081; container ports: 8080 (http)"
+func ServicePortDetail(issue ConfigIssue) string {
+	for _, base := range []ConfigIssue{IssueServiceTargetPortMismatch, IssueServicePortUnverified} {
+		if issue.Is(base) {
+			return strings.TrimSuffix(strings.TrimPrefix(string(issue), string(base)+" ("), ")")
+		}
+	}
+	return ""
+}
+
+// servicesByNamespace 按命名空间汇总有选择器的 Service，没有选择器的 Service 的 endpoint 由用户维护，不检查
+func servicesByNamespace(services []corev1.Service) map[string][]*corev1.Service {
+	byNamespace := make(map[string][]*corev1.Service)
+	for i := range services {
+		svc := &services[i]
+		if len(svc.Spec.Selector) == 0 {
+			continue
+		}
+		byNamespace[svc.Namespace] = append(byNamespace[svc.Namespace], svc)
+	}
+	return byNamespace
+}
+
+// checkServicePorts 检查选中 Pod 的 Service 的每个端口的 targetPort 是否对应 Pod 的容器端口
+// 未设置 targetPort 时与 port 相同；命名端口按容器端口名称解析，协议也需要一致（默认 TCP）
+// 容器端口包括普通容器和 sidecar 形式（restartPolicy: Always）的 init 容器声明的端口
+func checkServicePorts(pod *corev1.Pod, services []*corev1.Service) []ConfigIssue {
+	var ports []corev1.ContainerPort
+	for _, c := range pod.Spec.Containers {
+		ports = append(ports, c.Ports...)
+	}
+	for _, init := range pod.Spec.InitContainers {
+		if init.RestartPolicy != nil && *init.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			ports = append(ports, init.Ports...)
+		}
+	}
+
+	var issues []ConfigIssue
+	for _, svc := range services {
+		if !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		for _, sp := range svc.Spec.Ports {
+			target := sp.TargetPort
+			if target.Type == intstr.Int && target.IntVal == 0 {
+				target.IntVal = sp.Port
+			}
+			protocol := portProtocol(sp.Protocol)
+			targetText := formatPort(target.String(), protocol)
+
+			if target.Type == intstr.Int && len(ports) == 0 {
+				issues = append(issues, withDetail(IssueServicePortUnverified,
+					fmt.Sprintf("service %s port %d targets %s, no containerPorts declared", svc.Name, sp.Port, targetText)))
+				continue
+			}
+			if !containerPortMatches(ports, target.String(), target.Type == intstr.Int, protocol) {
+				issues = append(issues, withDetail(IssueServiceTargetPortMismatch,
+					fmt.Sprintf("service %s port %d targets %s; container ports: %s", svc.Name, sp.Port, targetText, formatContainerPorts(ports))))
+			}
+		}
+	}
+	return issues
+}
+
+// containerPortMatches 判断容器端口中是否有与 target（数字或名称）和协议都一致的端口
+func containerPortMatches(ports []corev1.ContainerPort, target string, numeric bool, protocol corev1.Protocol) bool {
+	for _, p := range ports {
+		if portProtocol(p.Protocol) != protocol {
+			continue
+		}
+		if (numeric && strconv.Itoa(int(p.ContainerPort)) == target) || (!numeric && p.Name == target) {
+			return true
+		}
+	}
+	return false
+}
+
+// portProtocol 返回端口的协议，未设置时为 TCP
+func portProtocol(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return protocol
+}
+
+// formatPort 格式化端口，非 TCP 的协议附加在后面，如 53/UDP
+func formatPort(port string, protocol corev1.Protocol) string {
+	if protocol == corev1.ProtocolTCP {
+		return port
+	}
+	return port + "/" + string(protocol)
+}
+
+// formatContainerPorts 格式化容器端口列表，如 8080 (http), 9090 (metrics)，没有声明时为 none
+func formatContainerPorts(ports []corev1.ContainerPort) string {
+	if len(ports) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(ports))
+	for _, p := range ports {
+		part := formatPort(strconv.Itoa(int(p.ContainerPort)), portProtocol(p.Protocol))
+		if p.Name != "" {
+			part += " (" + p.Name + ")"
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ", ")
+}
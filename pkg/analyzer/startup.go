@@ -0,0 +1,234 @@
+package analyzer
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// 启动相关的配置问题
+const (
+	// IssueServiceEndpointNotReady 表示 Pod 依赖的 Service 没有就绪的 endpoint
+	IssueServiceEndpointNotReady ConfigIssue = "Dependent service has no ready endpoints"
+	// IssueSequentialInitContainers 表示多个看起来互不依赖的等待/检查类 init 容器被串行执行
+	IssueSequentialInitContainers ConfigIssue = "Init containers may be parallelizable (see sidecarContainers feature)"
+	// IssueInitTimeout 表示 Pending 的 Pod 停在 init 容器阶段超过 --init-timeout，细节中给出实际时长，如 "Init:1/2 for 25m"
+	IssueInitTimeout ConfigIssue = "Pod has been initializing longer than --init-timeout"
+)
+
+// DefaultInitTimeout 是 --check-pod-stuck-in-init 默认允许的初始化时长
+const DefaultInitTimeout = 10 * time.Minute
+
+// IssueHighSchedulingLatency 表示 Pod 在调度队列中等待过久，通常说明资源紧张或调度器过载
+// 细节中给出实际耗时和阈值，如 "scheduled after 10m, threshold 2m"
+const IssueHighSchedulingLatency ConfigIssue = "Pod took longer than --scheduling-timeout to schedule"
+
+// DefaultSchedulingTimeout 是 --check-pod-scheduling-timeout 默认允许的调度耗时
+const DefaultSchedulingTimeout = 2 * time.Minute
+
+// waitInitPatterns 是等待依赖或做前置检查的 init 容器常见的命名片段
+var waitInitPatterns = []string{"wait-for-", "check-"}
+
+// ServiceDependency 是从环境变量推断出的 Service 依赖
+type ServiceDependency struct {
+	Namespace string
+	Name      string
+	EnvVar    string // 声明该依赖的环境变量
+}
+
+// ServiceDependencies 从形如 DEPENDENT_SERVICE_HOST 的环境变量推断 Pod 依赖的 Service（启发式）
+// 只识别集群内的 Service 名称：svc、svc.ns 或 svc.ns.svc[.cluster.local]，可带协议和端口
+func ServiceDependencies(pod *corev1.Pod) []ServiceDependency {
+	var deps []ServiceDependency
+	seen := make(map[string]bool)
+	for _, container := range allContainers(pod) {
+		for _, env := range container.Env {
+			if !strings.HasSuffix(env.Name, "_HOST") || env.Value == "" {
+				continue
+			}
+			name, namespace, ok := parseServiceHost(env.Value)
+			if !ok {
+				continue
+			}
+			if namespace == "" {
+				namespace = pod.Namespace
+			}
+			key := namespace + "/" + name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			deps = append(deps, ServiceDependency{Namespace: namespace, Name: name, EnvVar: env.Name})
+		}
+	}
+	return deps
+}
+
+// parseServiceHost 从主机名中解析 Service 名称和命名空间，不像集群内 Service 时返回 false
+func parseServiceHost(value string) (name, namespace string, ok bool) {
+	host := value
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?"); i >= 0 {
+		host = host[:i]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" || host == "localhost" || net.ParseIP(host) != nil {
+		return "", "", false
+	}
+
+	parts := strings.Split(strings.TrimSuffix(host, "."), ".")
+	switch {
+	case len(parts) == 1:
+		name = parts[0]
+	case len(parts) == 2:
+		name, namespace = parts[0], parts[1]
+	case parts[2] == "svc":
+		name, namespace = parts[0], parts[1]
+	default:
+		// 其他多段域名视为外部地址
+		return "", "", false
+	}
+	if len(validation.IsDNS1035Label(name)) > 0 {
+		return "", "", false
+	}
+	if namespace != "" && len(validation.IsDNS1123Label(namespace)) > 0 {
+		return "", "", false
+	}
+	return name, namespace, true
+}
+
+// checkServiceDependencies 检查 Pod 依赖的 Service 是否有就绪的 endpoint
+// 未获取到 Endpoints（如 Service 不存在）的依赖会被跳过
+func checkServiceDependencies(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, dep := range ServiceDependencies(pod) {
+		endpoints := data.endpoints(dep.Namespace, dep.Name)
+		if endpoints == nil || hasReadyAddress(endpoints) {
+			continue
+		}
+		detail := dep.Name
+		if dep.Namespace != pod.Namespace {
+			detail = dep.Namespace + "/" + dep.Name
+		}
+		issues = append(issues, withDetail(IssueServiceEndpointNotReady, detail+" via "+dep.EnvVar))
+	}
+	return issues
+}
+
+// hasReadyAddress 判断 Endpoints 中是否有就绪的地址
+func hasReadyAddress(endpoints *corev1.Endpoints) bool {
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// checkSequentialInitContainers 检查是否有多个等待/检查类的 init 容器（启发式，按名称识别）
+// init 容器严格按顺序执行，彼此独立的等待会逐个累加启动时间；sidecar 形式（restartPolicy: Always）的不计入
+func checkSequentialInitContainers(pod *corev1.Pod) []ConfigIssue {
+	var names []string
+	for _, init := range pod.Spec.InitContainers {
+		if init.RestartPolicy != nil && *init.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			continue
+		}
+		for _, pattern := range waitInitPatterns {
+			if strings.Contains(init.Name, pattern) {
+				names = append(names, init.Name)
+				break
+			}
+		}
+	}
+	if len(names) < 2 {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueSequentialInitContainers, strings.Join(names, ", "))}
+}
+
+// checkInitTimeout 检查 Pending 的 Pod 是否在 init 容器阶段停留超过 timeout，timeout 为 0 时使用默认值
+// 从 status.startTime（kubelet 开始处理 Pod 的时间）算起；sidecar 形式（restartPolicy: Always）的 init 容器一直运行，不计入
+func checkInitTimeout(pod *corev1.Pod, timeout time.Duration) []ConfigIssue {
+	if pod.Status.Phase != corev1.PodPending || pod.Status.StartTime == nil {
+		return nil
+	}
+	if timeout <= 0 {
+		timeout = DefaultInitTimeout
+	}
+
+	sidecars := make(map[string]bool)
+	for _, init := range pod.Spec.InitContainers {
+		if init.RestartPolicy != nil && *init.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			sidecars[init.Name] = true
+		}
+	}
+	total, done := 0, 0
+	for _, cs := range pod.Status.InitContainerStatuses {
+		if sidecars[cs.Name] {
+			continue
+		}
+		total++
+		if t := cs.State.Terminated; t != nil && t.ExitCode == 0 {
+			done++
+		}
+	}
+	if total == 0 || done == total {
+		return nil
+	}
+
+	elapsed := Now().Sub(pod.Status.StartTime.Time)
+	if elapsed <= timeout {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueInitTimeout, fmt.Sprintf("Init:%d/%d for %s", done, total, formatDuration(elapsed)))}
+}
+
+// SchedulingLatency 返回从创建 Pod 到调度成功的耗时，取 PodScheduled=True 条件中最早的 LastTransitionTime
+// 还没有调度成功，或缺少创建时间、条件时间时 ok 为 false
+func SchedulingLatency(pod *corev1.Pod) (latency time.Duration, ok bool) {
+	if pod.CreationTimestamp.IsZero() {
+		return 0, false
+	}
+	var scheduled time.Time
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type != corev1.PodScheduled || cond.Status != corev1.ConditionTrue || cond.LastTransitionTime.IsZero() {
+			continue
+		}
+		if scheduled.IsZero() || cond.LastTransitionTime.Time.Before(scheduled) {
+			scheduled = cond.LastTransitionTime.Time
+		}
+	}
+	if scheduled.IsZero() {
+		return 0, false
+	}
+	return max(scheduled.Sub(pod.CreationTimestamp.Time), 0), true
+}
+
+// checkSchedulingLatency 检查 Pod 的调度耗时是否超过 timeout，timeout 为 0 时使用默认值
+// 还在等待调度的 Pending Pod 按已等待的时长计算；没有 PodScheduled 条件、直接指定了节点的 Pod 不报告
+func checkSchedulingLatency(pod *corev1.Pod, timeout time.Duration) []ConfigIssue {
+	if timeout <= 0 {
+		timeout = DefaultSchedulingTimeout
+	}
+	latency, ok := SchedulingLatency(pod)
+	detail := "scheduled after " + formatDuration(latency)
+	if !ok {
+		if pod.Status.Phase != corev1.PodPending || pod.Spec.NodeName != "" || pod.CreationTimestamp.IsZero() {
+			return nil
+		}
+		latency = Now().Sub(pod.CreationTimestamp.Time)
+		detail = "waiting for " + formatDuration(latency)
+	}
+	if latency <= timeout {
+		return nil
+	}
+	return []ConfigIssue{withDetail(IssueHighSchedulingLatency, detail+", threshold "+formatDuration(timeout))}
+}
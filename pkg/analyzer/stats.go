@@ -0,0 +1,57 @@
+package analyzer
+
+import "time"
+
+// 一次运行的各个阶段
+const (
+	PhaseConnect = "connect" // 创建客户端
+	PhaseList    = "list"    // 获取 Pod 列表
+	PhaseEnrich  = "enrich"  // 探测可选检查并获取 Node、ReplicaSet 等额外数据
+	PhaseAnalyze = "analyze" // 分析 Pod
+	PhasePrint   = "print"   // 打印结果
+)
+
+// PhaseDuration 是一个阶段的耗时
+type PhaseDuration struct {
+	Name     string
+	Duration time.Duration
+}
+
+// RunStats 是一次运行的统计信息（--stats），用于编写基准测试脚本
+type RunStats struct {
+	// Phases 是按执行顺序排列的各阶段耗时
+	Phases []PhaseDuration
+	// Requests 是按类型统计的 API 请求数，如 "list pods" -> 1
+	Requests map[string]int
+	// BytesReceived 是 API 响应体的总字节数，不经过 HTTP 的客户端（如 demo）为 0
+	BytesReceived int64
+	// Pods 是分析的 Pod 数量
+	Pods int
+}
+
+// Duration 返回所有阶段的总耗时
+func (s *RunStats) Duration() time.Duration {
+	var total time.Duration
+	for _, phase := range s.Phases {
+		total += phase.Duration
+	}
+	return total
+}
+
+// TotalRequests 返回 API 请求总数
+func (s *RunStats) TotalRequests() int {
+	total := 0
+	for _, n := range s.Requests {
+		total += n
+	}
+	return total
+}
+
+// PodsPerSecond 返回每秒处理的 Pod 数量，总耗时为 0 时返回 0
+func (s *RunStats) PodsPerSecond() float64 {
+	d := s.Duration()
+	if d <= 0 {
+		return 0
+	}
+	return float64(s.Pods) / d.Seconds()
+}
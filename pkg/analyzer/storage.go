@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 节点本地存储相关的配置问题，这类 Pod 被固定在节点上，节点故障时数据会丢失
+const (
+	IssueHostPathWritable       ConfigIssue = "Pod writes to a hostPath volume (data pinned to node)"
+	IssueHostPathReadOnly       ConfigIssue = "Pod mounts a read-only hostPath volume"
+	IssueLocalPersistentVolume  ConfigIssue = "Pod uses a local PersistentVolume (data pinned to node)"
+	IssueReplicasPinnedSameNode ConfigIssue = "All replicas pinned to the same node by local storage"
+)
+
+// IssueUnboundPVC 表示 Pod 引用的 PVC 还没有绑定 PV，Pod 会一直 Pending
+const IssueUnboundPVC ConfigIssue = "Referenced PVC is not Bound (phase: Pending)"
+
+// PersistentVolumeClaims 返回 Pod 引用的 PVC 名称（已去重）
+func PersistentVolumeClaims(pod *corev1.Pod) []string {
+	seen := make(map[string]bool)
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			seen[volume.PersistentVolumeClaim.ClaimName] = true
+		}
+	}
+	return sortedNames(seen)
+}
+
+// checkLocalStorage 检查 Pod 是否通过 hostPath 或 local PV 使用节点本地存储
+// 所有容器都以只读方式挂载的 hostPath 严重程度较低
+func checkLocalStorage(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	node := pod.Spec.NodeName
+	if node == "" {
+		node = "unscheduled"
+	}
+
+	var issues []ConfigIssue
+	for _, volume := range pod.Spec.Volumes {
+		switch {
+		case volume.HostPath != nil:
+			mounted, readOnly := volumeMountMode(pod, volume.Name)
+			if !mounted {
+				continue
+			}
+			base := IssueHostPathWritable
+			if readOnly {
+				base = IssueHostPathReadOnly
+			}
+			issues = append(issues, withDetail(base, volume.HostPath.Path+" on node "+node))
+
+		case volume.PersistentVolumeClaim != nil:
+			claim := volume.PersistentVolumeClaim.ClaimName
+			pvc := data.persistentVolumeClaim(pod.Namespace, claim)
+			if pvc == nil {
+				continue
+			}
+			if pvc.Status.Phase == corev1.ClaimPending {
+				issues = append(issues, withDetail(IssueUnboundPVC, claim+", storage class: "+storageClassName(pvc)))
+				continue
+			}
+			if pvc.Spec.VolumeName == "" {
+				continue
+			}
+			if pv := data.persistentVolume(pvc.Spec.VolumeName); pv != nil && pv.Spec.Local != nil {
+				issues = append(issues, withDetail(IssueLocalPersistentVolume, claim+" on node "+node))
+			}
+		}
+	}
+	return issues
+}
+
+// storageClassName 返回 PVC 请求的 StorageClass，未指定时使用集群默认值，空字符串表示不使用 StorageClass
+func storageClassName(pvc *corev1.PersistentVolumeClaim) string {
+	switch {
+	case pvc.Spec.StorageClassName == nil:
+		return "<default>"
+	case *pvc.Spec.StorageClassName == "":
+		return "<none>"
+	default:
+		return *pvc.Spec.StorageClassName
+	}
+}
+
+// volumeMountMode 返回 volume 是否被容器挂载，以及是否所有挂载都是只读的
+func volumeMountMode(pod *corev1.Pod, volumeName string) (mounted, readOnly bool) {
+	readOnly = true
+	for _, container := range allContainers(pod) {
+		for _, mount := range container.VolumeMounts {
+			if mount.Name != volumeName {
+				continue
+			}
+			mounted = true
+			if !mount.ReadOnly {
+				readOnly = false
+			}
+		}
+	}
+	return mounted, mounted && readOnly
+}
+
+// checkLocalStoragePinning 检查同一工作负载的副本是否被本地存储全部固定在同一个节点上
+// 只读 hostPath 不计入；只有副本数大于 1 时才会标记
+func checkLocalStoragePinning(pods []PodAnalysis) {
+	groups := make(map[string][]int)
+	for i, pod := range pods {
+		if pod.OwnerKind == "" || pod.NodeName == "" {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.OwnerKind + "/" + pod.OwnerName
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, indexes := range groups {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		node := pods[indexes[0]].NodeName
+		pinned := true
+		for _, i := range indexes {
+			if pods[i].NodeName != node || !pinnedByLocalStorage(pods[i]) {
+				pinned = false
+				break
+			}
+		}
+		if !pinned {
+			continue
+		}
+
+		for _, i := range indexes {
+			pods[i].ConfigIssues = appendIfNotExists(pods[i].ConfigIssues, withDetail(IssueReplicasPinnedSameNode, node))
+		}
+	}
+}
+
+// pinnedByLocalStorage 判断 Pod 是否因可写的本地存储被固定在节点上
+func pinnedByLocalStorage(pod PodAnalysis) bool {
+	for _, issue := range pod.ConfigIssues {
+		if issue.Is(IssueHostPathWritable) || issue.Is(IssueLocalPersistentVolume) {
+			return true
+		}
+	}
+	return false
+}
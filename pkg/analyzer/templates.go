@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// WorkloadTemplate 是工作负载（Deployment、StatefulSet、DaemonSet 或 CronJob）的 Pod 模板
+type WorkloadTemplate struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Replicas 是期望的副本数，DaemonSet 为期望调度的节点数，CronJob 没有副本数，为 -1
+	Replicas int32
+	// Template 是 Pod 模板：元数据和 PodSpec
+	Template corev1.PodTemplateSpec
+}
+
+// generatedPodNameSuffix 返回控制器为工作负载创建 Pod 时在工作负载名称后追加的最长后缀长度
+// Deployment: -<pod-template-hash>-xxxxx，CronJob: -<调度时间>-xxxxx，StatefulSet: -<序号>，其余: -xxxxx
+func generatedPodNameSuffix(kind string) int {
+	switch kind {
+	case "Deployment":
+		return len("-0123456789-xxxxx")
+	case "CronJob":
+		return len("-29000000-xxxxx")
+	case "StatefulSet":
+		return len("-0")
+	case "DaemonSet", "ReplicaSet", "Job":
+		return len("-xxxxx")
+	}
+	return 0
+}
+
+// WorkloadAnalysis 是对一个工作负载 Pod 模板的检查结果
+type WorkloadAnalysis struct {
+	Kind         string
+	Namespace    string
+	Name         string
+	Replicas     int32
+	ConfigIssues []ConfigIssue
+}
+
+// AnalyzeTemplates 对工作负载的 Pod 模板执行配置、安全和策略检查，每个工作负载报告一次，按命名空间、类型和名称排序
+// 检查与分析运行中 Pod 时使用同一组实现，但只运行只依赖 PodSpec 和元数据的检查（见 templateOptions），
+// 模板的元数据原样交给检查，工作负载的命名空间、名称和类型通过 CheckContext 传递
+func AnalyzeTemplates(templates []WorkloadTemplate, opts Options) []WorkloadAnalysis {
+	checks := newCheckRunner(opts.templateOptions())
+	results := make([]WorkloadAnalysis, 0, len(templates))
+	for _, t := range templates {
+		results = append(results, WorkloadAnalysis{
+			Kind:         t.Kind,
+			Namespace:    t.Namespace,
+			Name:         t.Name,
+			Replicas:     t.Replicas,
+			ConfigIssues: checks.RunTemplate(t),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Name < b.Name
+	})
+	return results
+}
+
+// templateOptions 返回只保留模板检查的选项
+// 依赖 Pod 状态、所在节点或其他集群对象的检查（拓扑、PDB、漂移、存储、节点状态等）对模板没有意义，不启用
+func (o Options) templateOptions() Options {
+	return Options{
+		CheckConfig:           o.CheckConfig,
+		CheckDNS:              o.CheckDNS,
+		CheckCPUBurst:         o.CheckCPUBurst,
+		MaxCPUBurstRatio:      o.MaxCPUBurstRatio,
+		MaxAnnotationSize:     o.MaxAnnotationSize,
+		CheckAnnotationPolicy: o.CheckAnnotationPolicy,
+		CostAnnotation:        o.CostAnnotation,
+		CheckAutoscaler:       o.CheckAutoscaler,
+		CheckSecurity:         o.CheckSecurity,
+		CheckGrace:            o.CheckGrace,
+		CheckProbes:           o.CheckProbes,
+		RequiredLabels:        o.RequiredLabels,
+		EnabledChecks:         o.EnabledChecks,
+		DisabledChecks:        o.DisabledChecks,
+		Cluster:               o.Cluster,
+	}
+}
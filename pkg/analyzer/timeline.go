@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TimelineEvent 是 Pod 生命周期中的一个阶段
+type TimelineEvent struct {
+	Name    string
+	Time    time.Time
+	Since   time.Duration // 距上一个阶段的时间，第一个阶段为 0
+	Slowest bool          // 是否是耗时最长的一跳
+}
+
+// PodTimeline 根据 condition 转换时间和容器启动时间还原 Pod 的生命周期
+// created → scheduled → initialized → containers started → ready，已终止的 Pod 还包含终止阶段
+// 尚未到达的阶段会被省略
+func PodTimeline(pod *corev1.Pod) []TimelineEvent {
+	var events []TimelineEvent
+	add := func(name string, t time.Time) {
+		if !t.IsZero() {
+			events = append(events, TimelineEvent{Name: name, Time: t})
+		}
+	}
+
+	add("created", pod.CreationTimestamp.Time)
+	add("scheduled", conditionTime(pod, corev1.PodScheduled))
+	add("initialized", conditionTime(pod, corev1.PodInitialized))
+	add("containers started", containersStartedTime(pod))
+	add("ready", conditionTime(pod, corev1.PodReady))
+	if pod.DeletionTimestamp != nil {
+		add("terminating", pod.DeletionTimestamp.Time)
+	}
+	add("terminated", containersFinishedTime(pod))
+
+	// condition 的时间精度只有秒，且 Ready 可能在重启后更新，出现倒序时按时间修正为 0
+	slowest := -1
+	for i := 1; i < len(events); i++ {
+		if d := events[i].Time.Sub(events[i-1].Time); d > 0 {
+			events[i].Since = d
+		}
+		if slowest < 0 || events[i].Since > events[slowest].Since {
+			slowest = i
+		}
+	}
+	if slowest > 0 && events[slowest].Since > 0 {
+		events[slowest].Slowest = true
+	}
+	return events
+}
+
+// conditionTime 返回指定 condition 变为 True 的时间，未满足时返回零值
+func conditionTime(pod *corev1.Pod, condType corev1.PodConditionType) time.Time {
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == condType && cond.Status == corev1.ConditionTrue {
+			return cond.LastTransitionTime.Time
+		}
+	}
+	return time.Time{}
+}
+
+// containersStartedTime 返回所有容器都已启动的时间（最晚的启动时间），有容器未启动时返回零值
+func containersStartedTime(pod *corev1.Pod) time.Time {
+	if len(pod.Status.ContainerStatuses) == 0 {
+		return time.Time{}
+	}
+	var latest time.Time
+	for _, cs := range pod.Status.ContainerStatuses {
+		var started time.Time
+		switch {
+		case cs.State.Running != nil:
+			started = cs.State.Running.StartedAt.Time
+		case cs.State.Terminated != nil:
+			started = cs.State.Terminated.StartedAt.Time
+		}
+		if started.IsZero() {
+			return time.Time{}
+		}
+		if started.After(latest) {
+			latest = started
+		}
+	}
+	return latest
+}
+
+// containersFinishedTime 返回已终止 Pod 中最后一个容器结束的时间，Pod 未终止时返回零值
+func containersFinishedTime(pod *corev1.Pod) time.Time {
+	if !isTerminal(pod) {
+		return time.Time{}
+	}
+	var latest time.Time
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Terminated != nil && cs.State.Terminated.FinishedAt.After(latest) {
+			latest = cs.State.Terminated.FinishedAt.Time
+		}
+	}
+	return latest
+}
@@ -0,0 +1,128 @@
+package analyzer
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 节点可用区标签
+const (
+	ZoneLabelKey       = "topology.kubernetes.io/zone"
+	LegacyZoneLabelKey = "failure-domain.beta.kubernetes.io/zone"
+)
+
+// IssueVirtualNodeNoZone 表示 Pod 声明了按可用区分布，但所在的虚拟节点没有可用区标签
+const IssueVirtualNodeNoZone ConfigIssue = "Virtual node has no zone label, zone spread is ineffective"
+
+// nodeZone 返回节点所在的可用区，未知时返回空字符串
+func nodeZone(node *corev1.Node) string {
+	if node == nil {
+		return ""
+	}
+	if zone := node.Labels[ZoneLabelKey]; zone != "" {
+		return zone
+	}
+	return node.Labels[LegacyZoneLabelKey]
+}
+
+// checkZoneSpread 检查同一 Deployment 的副本是否全部落在同一个可用区
+// 只有当所有已调度副本的可用区都已知且副本数大于 1 时才会标记
+func checkZoneSpread(pods []PodAnalysis) {
+	groups := make(map[string][]int)
+	for i, pod := range pods {
+		if pod.OwnerKind != "Deployment" || pod.NodeName == "" {
+			continue
+		}
+		key := pod.Namespace + "/" + pod.OwnerName
+		groups[key] = append(groups[key], i)
+	}
+
+	for _, indexes := range groups {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		zone := pods[indexes[0]].Zone
+		sameZone := zone != ""
+		for _, i := range indexes[1:] {
+			if pods[i].Zone != zone {
+				sameZone = false
+				break
+			}
+		}
+		if !sameZone {
+			continue
+		}
+
+		for _, i := range indexes {
+			pods[i].ConfigIssues = appendIfNotExists(pods[i].ConfigIssues, IssueAllReplicasSameZone)
+		}
+	}
+}
+
+// podZone 返回 Pod 所在的可用区，ECI Pod 在节点没有可用区标签时使用 eci-zone-id 注解
+func podZone(pod *corev1.Pod, cluster *ClusterData, providers []ProviderRule) string {
+	node := cluster.node(pod.Spec.NodeName)
+	zone := nodeZone(node)
+	if zone == "" {
+		if runningOnECI, _, _ := detectECI(pod, node, providers); runningOnECI {
+			zone = pod.Annotations[ECIZoneAnnotation]
+		}
+	}
+	return zone
+}
+
+// checkVirtualNodeZone 检查声明了按可用区分布的 ECI Pod 所在的虚拟节点是否有可用区标签
+// 虚拟节点缺少可用区标签时，调度器会把它们当成同一个拓扑域；调度器只看节点标签，
+// 所以 eci-zone-id 注解不能消除这个问题，只作为实例实际所在的可用区放进细节
+func checkVirtualNodeZone(pod *corev1.Pod, cluster *ClusterData, providers []ProviderRule) []ConfigIssue {
+	node := cluster.node(pod.Spec.NodeName)
+	runningOnECI, _, _ := detectECI(pod, node, providers)
+	if !runningOnECI || nodeZone(node) != "" || !declaresZoneSpread(pod) {
+		return nil
+	}
+	if zone := pod.Annotations[ECIZoneAnnotation]; zone != "" {
+		return []ConfigIssue{withDetail(IssueVirtualNodeNoZone, "instance zone "+zone)}
+	}
+	return []ConfigIssue{IssueVirtualNodeNoZone}
+}
+
+// declaresZoneSpread 检查 Pod 是否声明了基于可用区的分布约束或亲和性
+func declaresZoneSpread(pod *corev1.Pod) bool {
+	for _, c := range pod.Spec.TopologySpreadConstraints {
+		if isZoneKey(c.TopologyKey) {
+			return true
+		}
+	}
+
+	affinity := pod.Spec.Affinity
+	if affinity == nil {
+		return false
+	}
+	if a := affinity.PodAntiAffinity; a != nil {
+		for _, term := range a.RequiredDuringSchedulingIgnoredDuringExecution {
+			if isZoneKey(term.TopologyKey) {
+				return true
+			}
+		}
+		for _, term := range a.PreferredDuringSchedulingIgnoredDuringExecution {
+			if isZoneKey(term.PodAffinityTerm.TopologyKey) {
+				return true
+			}
+		}
+	}
+	if a := affinity.NodeAffinity; a != nil && a.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		for _, term := range a.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			for _, expr := range term.MatchExpressions {
+				if isZoneKey(expr.Key) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isZoneKey 判断拓扑键是否表示可用区
+func isZoneKey(key string) bool {
+	return key == ZoneLabelKey || key == LegacyZoneLabelKey
+}
@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"sort"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+)
+
+// ToV1 将内部的分析结果转换为稳定的 v1 类型
+// Pod 按 namespace/name 排序，JSON 和 YAML 输出的顺序一致，对同一集群的两次运行可以直接 diff
+func (r *AnalysisResult) ToV1() *analysisv1.AnalysisResult {
+	out := &analysisv1.AnalysisResult{
+		Pods: make([]analysisv1.PodAnalysis, 0, len(r.Pods)),
+		Summary: analysisv1.Summary{
+			TotalPods:         r.TotalPods,
+			HealthyPods:       r.HealthyPods,
+			WarningPods:       r.WarningPods,
+			ErrorPods:         r.ErrorPods,
+			PendingPods:       r.PendingPods,
+			TotalRestarts:     r.TotalRestarts,
+			ConfigIssueCount:  r.ConfigIssueCount,
+			RunningOnECICount: r.RunningOnECICount,
+			HasECIConfigCount: r.HasECIConfigCount,
+			AdmissionFailures: r.AdmissionFailures,
+			PressuredNodes:    r.PressuredNodes,
+			Containers:        analysisv1.ContainerSummary(r.Containers),
+		},
+	}
+
+	for _, pod := range r.Pods {
+		out.Pods = append(out.Pods, pod.ToV1())
+	}
+	sort.SliceStable(out.Pods, func(i, j int) bool {
+		if out.Pods[i].Namespace != out.Pods[j].Namespace {
+			return out.Pods[i].Namespace < out.Pods[j].Namespace
+		}
+		return out.Pods[i].Name < out.Pods[j].Name
+	})
+	for _, p := range r.Preemptions {
+		preemption := analysisv1.Preemption{
+			Namespace:         p.Namespace,
+			Name:              p.Name,
+			Priority:          p.Priority,
+			NominatedNodeName: p.NominatedNode,
+		}
+		for _, v := range p.Victims {
+			preemption.Victims = append(preemption.Victims, analysisv1.PreemptionVictim(v))
+		}
+		out.Preemptions = append(out.Preemptions, preemption)
+	}
+	for _, d := range r.Duplicates {
+		duplicate := analysisv1.DuplicateWorkload{MatchedBy: d.MatchedBy, Key: d.Key}
+		for _, instance := range d.Instances {
+			duplicate.Instances = append(duplicate.Instances, analysisv1.WorkloadInstance(instance))
+		}
+		out.Duplicates = append(out.Duplicates, duplicate)
+	}
+	for _, retained := range r.RetainedPods {
+		out.RetainedPods = append(out.RetainedPods, analysisv1.RetainedPods(retained))
+	}
+	for _, job := range r.JobsWithoutTTL {
+		out.JobsWithoutTTL = append(out.JobsWithoutTTL, analysisv1.RetainingJob(job))
+	}
+	for _, skipped := range r.SkippedChecks {
+		out.SkippedChecks = append(out.SkippedChecks, analysisv1.SkippedCheck(skipped))
+	}
+	for _, ns := range r.TerminatingNamespaces {
+		terminating := analysisv1.TerminatingNamespace{Name: ns.Name, Age: ns.Age, Finalizers: ns.Finalizers, Conditions: ns.Conditions}
+		if !ns.Since.IsZero() {
+			since := ns.Since
+			terminating.Since = &since
+		}
+		out.TerminatingNamespaces = append(out.TerminatingNamespaces, terminating)
+	}
+	if c := r.LivenessCascade; c != nil {
+		out.LivenessCascade = &analysisv1.LivenessCascade{Start: c.Start, End: c.End, Kills: c.Kills, Workloads: c.Workloads}
+	}
+	for _, q := range r.QuotaRollouts {
+		out.QuotaRollouts = append(out.QuotaRollouts, analysisv1.QuotaRollout{
+			Namespace:     q.Namespace,
+			Deployment:    q.Deployment,
+			RemainingPods: q.Remaining,
+			FitsPods:      q.Fits,
+			Quota:         q.Quota,
+			Resource:      string(q.Resource),
+			PerPod:        q.PerPod.String(),
+			Hard:          q.Hard.String(),
+			Used:          q.Used.String(),
+		})
+	}
+	for _, d := range r.Deploys {
+		deploy := analysisv1.WorkloadDeploy{Namespace: d.Namespace, Name: d.Name, DeployedAt: d.DeployedAt}
+		if !d.ProblemSince.IsZero() {
+			since := d.ProblemSince
+			deploy.ProblemSince = &since
+		}
+		if after, ok := d.IssuesAfterDeploy(); ok {
+			seconds := after.Seconds()
+			deploy.IssuesAfterDeploySeconds = &seconds
+		}
+		out.Deploys = append(out.Deploys, deploy)
+	}
+	out.Stats = r.Stats.toV1()
+	return out
+}
+
+// toV1 将运行统计转换为 v1 类型，耗时以秒表示
+func (s *RunStats) toV1() *analysisv1.RunStats {
+	if s == nil {
+		return nil
+	}
+	out := &analysisv1.RunStats{
+		DurationSeconds: s.Duration().Seconds(),
+		Requests:        s.Requests,
+		TotalRequests:   s.TotalRequests(),
+		BytesReceived:   s.BytesReceived,
+		Pods:            s.Pods,
+		PodsPerSecond:   s.PodsPerSecond(),
+	}
+	for _, phase := range s.Phases {
+		out.Phases = append(out.Phases, analysisv1.Phase{Name: phase.Name, Seconds: phase.Duration.Seconds()})
+	}
+	return out
+}
+
+// ToV1 将单个 Pod 的分析结果转换为稳定的 v1 类型
+func (p PodAnalysis) ToV1() analysisv1.PodAnalysis {
+	out := analysisv1.PodAnalysis{
+		Name:             p.Name,
+		Namespace:        p.Namespace,
+		Status:           analysisv1.PodStatus(p.Status),
+		Phase:            string(p.Phase),
+		Reason:           p.Reason,
+		TotalContainers:  len(p.ContainerInfo),
+		Restarts:         p.Restarts,
+		Age:              p.Age,
+		RunningTime:      p.RunningTime,
+		Containers:       make([]analysisv1.ContainerAnalysis, 0, len(p.ContainerInfo)),
+		RunningOnECI:     p.RunningOnECI,
+		HasECIConfig:     p.HasECIConfig,
+		ECIInstanceID:    p.ECIInstanceID,
+		ECIZone:          p.ECIZone,
+		ECIVSwitch:       p.ECIVSwitch,
+		ECISpec:          p.ECISpec,
+		Provider:         p.Provider,
+		NodeName:         p.NodeName,
+		Zone:             p.Zone,
+		OwnerKind:        p.OwnerKind,
+		OwnerName:        p.OwnerName,
+		MainContainer:    p.MainContainer,
+		OwnerContact:     p.OwnerContact,
+		AdmissionFailure: p.AdmissionFailure,
+		NodePressure:     p.NodePressure,
+
+		NominatedNodeName: p.NominatedNode,
+		DetailsOmitted:    p.DetailsOmitted,
+		RestartPolicy:     string(p.RestartPolicy),
+		Raw:               p.Raw.toV1(),
+	}
+	if p.SchedulingLatency >= 0 {
+		seconds := p.SchedulingLatency.Seconds()
+		out.SchedulingLatencySeconds = &seconds
+	}
+	if p.EfficiencyScore >= 0 {
+		score := p.EfficiencyScore
+		out.EfficiencyScore = &score
+	}
+	if m := p.MissingNodeLabel; m != nil {
+		out.MissingNodeLabel = &analysisv1.MissingNodeLabel{Key: m.Key, Values: m.Values, Closest: m.Closest}
+	}
+
+	for _, issue := range p.ConfigIssues {
+		out.ConfigIssues = append(out.ConfigIssues, analysisv1.ConfigIssue(issue))
+	}
+	for _, c := range p.ContainerInfo {
+		if c.Ready {
+			out.ReadyContainers++
+		}
+		out.Containers = append(out.Containers, analysisv1.ContainerAnalysis{
+			Name:               c.Name,
+			Ready:              c.Ready,
+			RestartCount:       c.RestartCount,
+			LastTermination:    c.LastTermination,
+			TerminationMessage: c.TerminationMessage,
+			State:              c.State,
+			WaitingReason:      c.WaitingReason,
+		})
+	}
+	if !p.ProblemSince.IsZero() {
+		since := p.ProblemSince
+		out.ProblemSince = &since
+	}
+	if p.PDBDisruptionsAllowed >= 0 {
+		allowed := p.PDBDisruptionsAllowed
+		out.PDBDisruptionsAllowed = &allowed
+	}
+	return out
+}
+
+// ToV1 将排名中的 Pod 转换为稳定的 v1 类型
+func (s ScoredPod) ToV1() analysisv1.ScoredPod {
+	return analysisv1.ScoredPod{Rank: s.Rank, Score: s.Score, Pod: s.Pod.ToV1()}
+}
+
+// ToV1 将时间线中的一个阶段转换为稳定的 v1 类型
+func (e TimelineEvent) ToV1() analysisv1.TimelineEvent {
+	return analysisv1.TimelineEvent{Name: e.Name, Time: e.Time, SinceSeconds: e.Since.Seconds(), Slowest: e.Slowest}
+}
+
+// ToV1 将 ECI 迁移候选转换为稳定的 v1 类型
+func (c ECICandidate) ToV1() analysisv1.ECICandidate {
+	return analysisv1.ECICandidate{
+		Rank:        c.Rank,
+		Namespace:   c.Namespace,
+		Name:        c.Name,
+		OwnerKind:   c.OwnerKind,
+		OwnerName:   c.OwnerName,
+		Score:       c.Score,
+		Fit:         c.Fit,
+		Reasons:     c.Reasons,
+		Labels:      c.Labels,
+		Annotations: c.Annotations,
+	}
+}
+
+// ToV1 将 ECI 实例转换为稳定的 v1 类型
+func (e ECIInstance) ToV1() analysisv1.ECIInstance {
+	return analysisv1.ECIInstance{
+		Namespace:  e.Namespace,
+		Pod:        e.Pod,
+		InstanceID: e.InstanceID,
+		Zone:       e.Zone,
+		VSwitch:    e.VSwitch,
+		Spec:       e.Spec,
+		Age:        e.Age,
+		Status:     analysisv1.PodStatus(e.Status),
+	}
+}
+
+// toV1 将原始数据转换为 v1 类型，条件和容器状态保留 Kubernetes API 的 JSON 形式
+func (r *RawPod) toV1() *analysisv1.RawPod {
+	if r == nil {
+		return nil
+	}
+	out := &analysisv1.RawPod{Labels: r.Labels, Annotations: r.Annotations}
+	if r.Conditions != nil {
+		out.Conditions, _ = json.Marshal(r.Conditions)
+	}
+	if r.ContainerStatuses != nil {
+		out.ContainerStatuses, _ = json.Marshal(r.ContainerStatuses)
+	}
+	return out
+}
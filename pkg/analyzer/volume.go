@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// 卷操作相关的配置问题：Pod 已调度但卡在 ContainerCreating，事件表明卷无法挂接或正在等待扩容
+const (
+	IssueVolumeAttachLimit      ConfigIssue = "Node reached its volume attach limit"
+	IssueVolumeExpansionPending ConfigIssue = "Volume expansion pending on node"
+)
+
+// attachableVolumesPrefix 是节点 allocatable 中按卷插件统计的可挂接卷数量的资源名前缀，如 attachable-volumes-aws-ebs
+const attachableVolumesPrefix = "attachable-volumes-"
+
+// 卷挂接数达到节点上限时 FailedAttachVolume/FailedMount 事件中的关键字（统一转为小写比较）
+var attachLimitSignatures = []string{
+	"exceed max volume count",
+	"max volume count exceeded",
+	"attachment limit",
+}
+
+// 卷扩容还没有在节点上完成时 FailedMount 事件中的关键字（统一转为小写比较）
+var expansionPendingSignatures = []string{
+	"nodeexpandvolume",
+	"filesystemresizepending",
+	"volume expansion",
+}
+
+// VolumeAttachDetail 返回挂接数上限问题的细节，如 "node node-1: 25 attached, allocatable attachable-volumes-aws-ebs: 25"
+func VolumeAttachDetail(issue ConfigIssue) string {
+	if !issue.Is(IssueVolumeAttachLimit) {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(string(issue), string(IssueVolumeAttachLimit)+" ("), ")")
+}
+
+// StuckOnVolumes 判断 Pod 是否已调度、使用了 PVC/CSI 卷，且仍有容器处于 ContainerCreating
+// 有 init 容器的 Pod 在卷挂载完成前，容器的等待原因是 PodInitializing，此时要求还没有 init 容器启动过
+// 只有这些 Pod 才需要获取事件和节点来排查卷操作，避免为所有 Pod 产生额外请求
+func StuckOnVolumes(pod *corev1.Pod) bool {
+	if pod.Spec.NodeName == "" || pod.Status.Phase != corev1.PodPending || !usesAttachableVolumes(pod) {
+		return false
+	}
+	initializing := len(pod.Spec.InitContainers) > 0
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.State.Running != nil || status.State.Terminated != nil {
+			initializing = false
+		}
+	}
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, status := range statuses {
+		if status.State.Waiting == nil {
+			continue
+		}
+		if reason := status.State.Waiting.Reason; reason == "ContainerCreating" || initializing && reason == "PodInitializing" {
+			return true
+		}
+	}
+	return false
+}
+
+// usesAttachableVolumes 判断 Pod 是否有需要挂接到节点的卷：PVC、通用临时卷或 CSI 内联卷
+func usesAttachableVolumes(pod *corev1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil || volume.Ephemeral != nil || volume.CSI != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// checkVolumeOperations 从卡在卷操作的 Pod 的 FailedAttachVolume/FailedMount 事件中识别挂接数上限和等待扩容
+// 挂接数上限附带节点 allocatable 中的可挂接卷数量和已挂接的卷数量，每种问题只报告一次
+func checkVolumeOperations(pod *corev1.Pod, data *ClusterData) []ConfigIssue {
+	if !StuckOnVolumes(pod) {
+		return nil
+	}
+
+	var attachLimit, expansion bool
+	for _, event := range data.podEvents(pod.Namespace, pod.Name) {
+		if event.Reason != "FailedAttachVolume" && event.Reason != "FailedMount" {
+			continue
+		}
+		message := strings.ToLower(event.Message)
+		attachLimit = attachLimit || containsSignature(message, attachLimitSignatures)
+		expansion = expansion || containsSignature(message, expansionPendingSignatures)
+	}
+
+	var issues []ConfigIssue
+	if attachLimit {
+		issues = append(issues, withDetail(IssueVolumeAttachLimit, attachedVolumesDetail(pod.Spec.NodeName, data.node(pod.Spec.NodeName))))
+	}
+	if expansion {
+		issues = append(issues, withDetail(IssueVolumeExpansionPending, "node "+pod.Spec.NodeName))
+	}
+	return issues
+}
+
+// attachedVolumesDetail 描述节点的可挂接卷数量和已挂接的卷数量，如 "node node-1: 25 attached, allocatable attachable-volumes-aws-ebs: 25"
+// CSI 驱动的上限记录在同名的 CSINode 中而不是节点 allocatable，此时只给出已挂接的数量
+func attachedVolumesDetail(nodeName string, node *corev1.Node) string {
+	if node == nil {
+		return "node " + nodeName
+	}
+	detail := fmt.Sprintf("node %s: %d attached", nodeName, len(node.Status.VolumesAttached))
+
+	var limits []string
+	for name, quantity := range node.Status.Allocatable {
+		if strings.HasPrefix(string(name), attachableVolumesPrefix) {
+			limits = append(limits, fmt.Sprintf("%s: %d", name, quantity.Value()))
+		}
+	}
+	if len(limits) == 0 {
+		return detail + ", limit in CSINode " + nodeName
+	}
+	sort.Strings(limits)
+	return detail + ", allocatable " + strings.Join(limits, ", ")
+}
+
+// containsSignature 判断 s 是否包含 signatures 中的任意一个
+func containsSignature(s string, signatures []string) bool {
+	for _, signature := range signatures {
+		if strings.Contains(s, signature) {
+			return true
+		}
+	}
+	return false
+}
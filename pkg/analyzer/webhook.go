@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// IssueWebhookBlocksDeletion 表示删除该 Pod 的请求会经过校验 DELETE 操作的 ValidatingWebhook
+// webhook 拒绝请求或不可用（failurePolicy: Fail）时，驱逐、滚动更新和节点排空都会卡在这个 Pod 上
+const IssueWebhookBlocksDeletion ConfigIssue = "ValidatingWebhook may block pod deletion"
+
+// checkWebhookDisruption 检查是否有 ValidatingWebhook 的规则和选择器匹配该 Pod 的删除
+// webhook 的 matchConditions 是 CEL 表达式，这里不做求值，匹配结果可能偏多
+func checkWebhookDisruption(pod *corev1.Pod, cluster *ClusterData) []ConfigIssue {
+	if cluster == nil || len(cluster.ValidatingWebhooks) == 0 {
+		return nil
+	}
+
+	nsLabels := labels.Set{corev1.LabelMetadataName: pod.Namespace}
+	if ns := cluster.namespace(pod.Namespace); ns != nil {
+		for k, v := range ns.Labels {
+			nsLabels[k] = v
+		}
+	}
+
+	var issues []ConfigIssue
+	for _, config := range cluster.ValidatingWebhooks {
+		for _, hook := range config.Webhooks {
+			if !interceptsPodDeletion(hook.Rules) {
+				continue
+			}
+			if !labelSelectorMatches(hook.NamespaceSelector, nsLabels) || !labelSelectorMatches(hook.ObjectSelector, pod.Labels) {
+				continue
+			}
+			policy := admissionregistrationv1.Fail
+			if hook.FailurePolicy != nil {
+				policy = *hook.FailurePolicy
+			}
+			issues = append(issues, withDetail(IssueWebhookBlocksDeletion,
+				fmt.Sprintf("%s in %s, failurePolicy %s", hook.Name, config.Name, policy)))
+		}
+	}
+	return issues
+}
+
+// interceptsPodDeletion 判断 webhook 规则是否包含对 core/v1 pods 的 DELETE 操作
+func interceptsPodDeletion(rules []admissionregistrationv1.RuleWithOperations) bool {
+	for _, rule := range rules {
+		if !containsAny(rule.Operations, admissionregistrationv1.Delete, admissionregistrationv1.OperationAll) {
+			continue
+		}
+		if rule.Scope != nil && *rule.Scope == admissionregistrationv1.ClusterScope {
+			continue
+		}
+		if containsAny(rule.APIGroups, "", "*") &&
+			containsAny(rule.APIVersions, "v1", "*") &&
+			containsAny(rule.Resources, "pods", "*", "*/*") {
+			return true
+		}
+	}
+	return false
+}
+
+// labelSelectorMatches 判断选择器是否匹配标签，未设置或为空的选择器匹配所有对象
+func labelSelectorMatches(selector *metav1.LabelSelector, set labels.Set) bool {
+	if selector == nil {
+		return true
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return false
+	}
+	return s.Matches(set)
+}
+
+// containsAny 判断 values 中是否包含任一候选值
+func containsAny[T comparable](values []T, candidates ...T) bool {
+	for _, v := range values {
+		for _, c := range candidates {
+			if v == c {
+				return true
+			}
+		}
+	}
+	return false
+}
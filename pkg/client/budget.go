@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"sync"
+)
+
+// DefaultRequestBudget 是一次运行中默认允许的额外请求数
+const DefaultRequestBudget = 200
+
+// ErrBudgetExhausted 表示额外请求的配额已用完，请求没有发出
+var ErrBudgetExhausted = errors.New("request budget exhausted")
+
+// Budget 限制一次运行中为单个 Pod 补充信息而发起的额外请求（事件、PVC/PV、漏洞报告等）
+// 有大量问题 Pod 时，这些请求会成倍增加 API Server 的压力；Budget 可以被并发使用
+type Budget struct {
+	mu     sync.Mutex
+	limit  int
+	used   int
+	denied int
+}
+
+// NewBudget 创建允许 limit 个额外请求的配额，limit <= 0 表示不限制
+func NewBudget(limit int) *Budget {
+	return &Budget{limit: limit}
+}
+
+// take 占用一个请求配额，配额用完时返回 ErrBudgetExhausted
+// nil 的 Budget 不做限制
+func (b *Budget) take() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limit > 0 && b.used >= b.limit {
+		b.denied++
+		return ErrBudgetExhausted
+	}
+	b.used++
+	return nil
+}
+
+// Limit 返回配额上限，0 表示不限制
+func (b *Budget) Limit() int {
+	if b == nil || b.limit < 0 {
+		return 0
+	}
+	return b.limit
+}
+
+// Used 返回已发出的额外请求数
+func (b *Budget) Used() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Denied 返回因配额用完而被拒绝的请求数
+func (b *Budget) Denied() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.denied
+}
@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+)
+
+// 能力不可用的原因
+const (
+	ReasonAPINotFound = "API not found"
+	ReasonForbidden   = "forbidden"
+)
+
+// Capability 是可选检查依赖的 API 资源和动词
+type Capability struct {
+	Group    string
+	Version  string
+	Resource string
+	Verb     string
+	// ClusterWide 表示需要在所有命名空间（或集群级资源）上执行该动词
+	ClusterWide bool
+}
+
+// GroupVersion 返回资源所在的 API 组版本，如 "policy/v1"，核心组为 "v1"
+func (c Capability) GroupVersion() string {
+	return schema.GroupVersion{Group: c.Group, Version: c.Version}.String()
+}
+
+// ProbeCapability 检查当前身份能否在命名空间中使用该能力，结果在本次运行中缓存
+// 返回空字符串表示可用，否则返回 ReasonAPINotFound 或 ReasonForbidden；
+// 探测本身出错时视为可用，由具体功能在请求失败时给出警告
+func (c *Client) ProbeCapability(ctx context.Context, namespace string, capability Capability) string {
+	if capability.ClusterWide {
+		namespace = ""
+	}
+	key := capability.GroupVersion() + "/" + capability.Resource + "/" + capability.Verb + "/" + namespace
+
+	c.mu.Lock()
+	reason, ok := c.capabilityCache[key]
+	c.mu.Unlock()
+	if ok {
+		return reason
+	}
+
+	reason = c.probeCapability(ctx, namespace, capability)
+
+	c.mu.Lock()
+	c.capabilityCache[key] = reason
+	c.mu.Unlock()
+	return reason
+}
+
+// probeCapability 先通过 discovery 确认 API 存在，再通过 SelfSubjectAccessReview 确认权限
+func (c *Client) probeCapability(ctx context.Context, namespace string, capability Capability) string {
+	resources, err := c.clientset.Discovery().ServerResourcesForGroupVersion(capability.GroupVersion())
+	if apierrors.IsNotFound(err) {
+		return ReasonAPINotFound
+	}
+	if err == nil {
+		found := false
+		for _, r := range resources.APIResources {
+			if r.Name == capability.Resource {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ReasonAPINotFound
+		}
+	}
+
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      capability.Verb,
+				Group:     capability.Group,
+				Resource:  capability.Resource,
+			},
+		},
+	}
+	result, err := c.clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return ""
+	}
+	if !result.Status.Allowed {
+		return ReasonForbidden
+	}
+	return ""
+}
+
+// ServerMinorVersion 返回 API Server 的 Kubernetes 次版本号，如 v1.28.3 返回 28
+// 托管集群的版本常带后缀（如 v1.28.3-eks-a5df82a），按 GitVersion 解析；主版本不是 1 或无法解析时返回错误
+func (c *Client) ServerMinorVersion() (int, error) {
+	info, err := c.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return 0, err
+	}
+	v, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return 0, err
+	}
+	if v.Major() != 1 {
+		return 0, fmt.Errorf("unsupported server version %s", info.GitVersion)
+	}
+	return int(v.Minor()), nil
+}
@@ -2,19 +2,55 @@ package client
 
 import (
 	"context"
+	"net/http"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
+// registryTimeout 是单次镜像仓库请求的超时时间
+const registryTimeout = 10 * time.Second
+
 // Client 封装了 Kubernetes 客户端操作
 type Client struct {
-	clientset *kubernetes.Clientset
+	clientset kubernetes.Interface
+	dynamic   dynamic.Interface // 用于访问 CRD 等非内置资源
+
+	// 缓存已获取的对象，避免同一对象重复请求
+	mu              sync.Mutex
+	nodeCache       map[string]*corev1.Node
+	namespaceCache  map[string]*corev1.Namespace
+	replicaSetCache map[string]*appsv1.ReplicaSet            // 以 namespace/name 为键
+	deploymentCache map[string]*appsv1.Deployment            // 以 namespace/name 为键
+	configMapCache  map[string]*corev1.ConfigMap             // 以 namespace/name 为键
+	secretCache     map[string]*corev1.Secret                // 以 namespace/name 为键
+	endpointsCache  map[string]*corev1.Endpoints             // 以 namespace/name 为键
+	pvcCache        map[string]*corev1.PersistentVolumeClaim // 以 namespace/name 为键
+	pvCache         map[string]*corev1.PersistentVolume
+	digestCache     map[string]string // 镜像 -> registry 中 tag 当前的 digest
+	capabilityCache map[string]string // 能力 -> 不可用的原因，可用时为空字符串
+
+	// registryClient 用于直接访问镜像仓库，不经过 API Server
+	registryClient *http.Client
+
+	// budget 限制按 Pod 发起的额外请求，为 nil 时不限制
+	budget *Budget
+
+	// stats 统计发往 API Server 的请求
+	stats *RequestStats
 }
 
 // NewClient 创建一个新的 Kubernetes 客户端
@@ -24,13 +60,59 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	stats := NewRequestStats()
+	config.Wrap(stats.WrapTransport)
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Client{clientset: clientset}, nil
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewClientFromInterfaces(clientset, dynamicClient)
+	c.stats = stats
+	return c, nil
+}
+
+// NewClientFromInterfaces 使用已有的 clientset 创建客户端，用于 demo 模式等不连接真实集群的场景
+func NewClientFromInterfaces(clientset kubernetes.Interface, dynamicClient dynamic.Interface) *Client {
+	return &Client{
+		clientset:       clientset,
+		dynamic:         dynamicClient,
+		nodeCache:       make(map[string]*corev1.Node),
+		namespaceCache:  make(map[string]*corev1.Namespace),
+		replicaSetCache: make(map[string]*appsv1.ReplicaSet),
+		deploymentCache: make(map[string]*appsv1.Deployment),
+		configMapCache:  make(map[string]*corev1.ConfigMap),
+		secretCache:     make(map[string]*corev1.Secret),
+		endpointsCache:  make(map[string]*corev1.Endpoints),
+		pvcCache:        make(map[string]*corev1.PersistentVolumeClaim),
+		pvCache:         make(map[string]*corev1.PersistentVolume),
+		digestCache:     make(map[string]string),
+		capabilityCache: make(map[string]string),
+		registryClient:  &http.Client{Timeout: registryTimeout},
+		stats:           NewRequestStats(),
+	}
+}
+
+// SetBudget 设置额外请求的配额，GetEvents、GetPodsOnNode、GetPersistentVolumeClaim、
+// GetPersistentVolume 和 GetVulnerabilityReport 的每次实际请求都会占用一个配额
+func (c *Client) SetBudget(budget *Budget) {
+	c.budget = budget
+}
+
+// Budget 返回当前的额外请求配额，未设置时为 nil
+func (c *Client) Budget() *Budget {
+	return c.budget
+}
+
+// Stats 返回请求统计；NewClientFromInterfaces 创建的客户端不经过 HTTP，由调用方通过 Record 计数
+func (c *Client) Stats() *RequestStats {
+	return c.stats
 }
 
 // buildConfig 构建 Kubernetes 配置
@@ -62,6 +144,51 @@ func (c *Client) GetPods(ctx context.Context, namespace string) (*corev1.PodList
 	return c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
 }
 
+// GetPodsOnNode 获取指定节点上所有命名空间的 Pod
+func (c *Client) GetPodsOnNode(ctx context.Context, nodeName string) (*corev1.PodList, error) {
+	if err := c.budget.take(); err != nil {
+		return nil, err
+	}
+	return c.clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + nodeName,
+	})
+}
+
+// GetNamespaces 获取所有命名空间，结果会填充 GetNamespace 的缓存
+func (c *Client) GetNamespaces(ctx context.Context) (*corev1.NamespaceList, error) {
+	list, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	for i := range list.Items {
+		c.namespaceCache[list.Items[i].Name] = &list.Items[i]
+	}
+	c.mu.Unlock()
+	return list, nil
+}
+
+// GetNamespace 获取单个命名空间，结果会被缓存
+func (c *Client) GetNamespace(ctx context.Context, name string) (*corev1.Namespace, error) {
+	c.mu.Lock()
+	ns, ok := c.namespaceCache[name]
+	c.mu.Unlock()
+	if ok {
+		return ns, nil
+	}
+
+	ns, err := c.clientset.CoreV1().Namespaces().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.namespaceCache[name] = ns
+	c.mu.Unlock()
+	return ns, nil
+}
+
 // GetPod 获取单个 Pod
 func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Pod, error) {
 	return c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
@@ -69,8 +196,267 @@ func (c *Client) GetPod(ctx context.Context, namespace, name string) (*corev1.Po
 
 // GetEvents 获取指定 Pod 的事件
 func (c *Client) GetEvents(ctx context.Context, namespace, podName string) (*corev1.EventList, error) {
+	if err := c.budget.take(); err != nil {
+		return nil, err
+	}
 	fieldSelector := "involvedObject.name=" + podName
 	return c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
 		FieldSelector: fieldSelector,
 	})
 }
+
+// GetEventsByReason 获取指定命名空间中指定原因的事件，namespace 为空时获取所有命名空间
+// 每次运行只调用一次，不占用额外请求配额
+func (c *Client) GetEventsByReason(ctx context.Context, namespace, reason string) (*corev1.EventList, error) {
+	return c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: "reason=" + reason,
+	})
+}
+
+// GetCronJobs 获取指定命名空间的所有 CronJob
+func (c *Client) GetCronJobs(ctx context.Context, namespace string) (*batchv1.CronJobList, error) {
+	return c.clientset.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetDeployments 获取指定命名空间的所有 Deployment
+func (c *Client) GetDeployments(ctx context.Context, namespace string) (*appsv1.DeploymentList, error) {
+	return c.clientset.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetStatefulSets 获取指定命名空间的所有 StatefulSet
+func (c *Client) GetStatefulSets(ctx context.Context, namespace string) (*appsv1.StatefulSetList, error) {
+	return c.clientset.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetDaemonSets 获取指定命名空间的所有 DaemonSet
+func (c *Client) GetDaemonSets(ctx context.Context, namespace string) (*appsv1.DaemonSetList, error) {
+	return c.clientset.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetJobs 获取指定命名空间的所有 Job
+func (c *Client) GetJobs(ctx context.Context, namespace string) (*batchv1.JobList, error) {
+	return c.clientset.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetJob 获取单个 Job
+func (c *Client) GetJob(ctx context.Context, namespace, name string) (*batchv1.Job, error) {
+	return c.clientset.BatchV1().Jobs(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// GetPodDisruptionBudgets 获取指定命名空间的所有 PodDisruptionBudget
+func (c *Client) GetPodDisruptionBudgets(ctx context.Context, namespace string) (*policyv1.PodDisruptionBudgetList, error) {
+	return c.clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetResourceQuotas 获取指定命名空间的所有 ResourceQuota
+func (c *Client) GetResourceQuotas(ctx context.Context, namespace string) (*corev1.ResourceQuotaList, error) {
+	return c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetNetworkPolicies 获取指定命名空间的所有 NetworkPolicy
+func (c *Client) GetNetworkPolicies(ctx context.Context, namespace string) (*networkingv1.NetworkPolicyList, error) {
+	return c.clientset.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetServices 获取指定命名空间的所有 Service
+func (c *Client) GetServices(ctx context.Context, namespace string) (*corev1.ServiceList, error) {
+	return c.clientset.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
+}
+
+// GetValidatingWebhooks 获取集群中所有 ValidatingWebhookConfiguration
+func (c *Client) GetValidatingWebhooks(ctx context.Context) (*admissionregistrationv1.ValidatingWebhookConfigurationList, error) {
+	return c.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+}
+
+// GetNode 获取单个 Node，结果会被缓存
+func (c *Client) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
+	c.mu.Lock()
+	node, ok := c.nodeCache[name]
+	c.mu.Unlock()
+	if ok {
+		return node, nil
+	}
+
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.nodeCache[name] = node
+	c.mu.Unlock()
+	return node, nil
+}
+
+// ListNodes 获取集群中的所有 Node，并写入 GetNode 使用的缓存
+func (c *Client) ListNodes(ctx context.Context) (*corev1.NodeList, error) {
+	nodes, err := c.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	for i := range nodes.Items {
+		c.nodeCache[nodes.Items[i].Name] = &nodes.Items[i]
+	}
+	c.mu.Unlock()
+	return nodes, nil
+}
+
+// GetReplicaSet 获取单个 ReplicaSet，结果会被缓存
+func (c *Client) GetReplicaSet(ctx context.Context, namespace, name string) (*appsv1.ReplicaSet, error) {
+	key := namespace + "/" + name
+
+	c.mu.Lock()
+	rs, ok := c.replicaSetCache[key]
+	c.mu.Unlock()
+	if ok {
+		return rs, nil
+	}
+
+	rs, err := c.clientset.AppsV1().ReplicaSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.replicaSetCache[key] = rs
+	c.mu.Unlock()
+	return rs, nil
+}
+
+// GetDeployment 获取单个 Deployment，结果会被缓存
+func (c *Client) GetDeployment(ctx context.Context, namespace, name string) (*appsv1.Deployment, error) {
+	key := namespace + "/" + name
+
+	c.mu.Lock()
+	deploy, ok := c.deploymentCache[key]
+	c.mu.Unlock()
+	if ok {
+		return deploy, nil
+	}
+
+	deploy, err := c.clientset.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.deploymentCache[key] = deploy
+	c.mu.Unlock()
+	return deploy, nil
+}
+
+// GetConfigMap 获取单个 ConfigMap，结果会被缓存
+func (c *Client) GetConfigMap(ctx context.Context, namespace, name string) (*corev1.ConfigMap, error) {
+	key := namespace + "/" + name
+
+	c.mu.Lock()
+	cm, ok := c.configMapCache[key]
+	c.mu.Unlock()
+	if ok {
+		return cm, nil
+	}
+
+	cm, err := c.clientset.CoreV1().ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.configMapCache[key] = cm
+	c.mu.Unlock()
+	return cm, nil
+}
+
+// GetSecret 获取单个 Secret，结果会被缓存
+func (c *Client) GetSecret(ctx context.Context, namespace, name string) (*corev1.Secret, error) {
+	key := namespace + "/" + name
+
+	c.mu.Lock()
+	secret, ok := c.secretCache[key]
+	c.mu.Unlock()
+	if ok {
+		return secret, nil
+	}
+
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.secretCache[key] = secret
+	c.mu.Unlock()
+	return secret, nil
+}
+
+// GetEndpoints 获取 Service 的 Endpoints，结果会被缓存
+func (c *Client) GetEndpoints(ctx context.Context, namespace, serviceName string) (*corev1.Endpoints, error) {
+	key := namespace + "/" + serviceName
+
+	c.mu.Lock()
+	endpoints, ok := c.endpointsCache[key]
+	c.mu.Unlock()
+	if ok {
+		return endpoints, nil
+	}
+
+	endpoints, err := c.clientset.CoreV1().Endpoints(namespace).Get(ctx, serviceName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.endpointsCache[key] = endpoints
+	c.mu.Unlock()
+	return endpoints, nil
+}
+
+// GetPersistentVolumeClaim 获取单个 PersistentVolumeClaim，结果会被缓存
+func (c *Client) GetPersistentVolumeClaim(ctx context.Context, namespace, name string) (*corev1.PersistentVolumeClaim, error) {
+	key := namespace + "/" + name
+
+	c.mu.Lock()
+	pvc, ok := c.pvcCache[key]
+	c.mu.Unlock()
+	if ok {
+		return pvc, nil
+	}
+	if err := c.budget.take(); err != nil {
+		return nil, err
+	}
+
+	pvc, err := c.clientset.CoreV1().PersistentVolumeClaims(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.pvcCache[key] = pvc
+	c.mu.Unlock()
+	return pvc, nil
+}
+
+// GetPersistentVolume 获取单个 PersistentVolume，结果会被缓存
+func (c *Client) GetPersistentVolume(ctx context.Context, name string) (*corev1.PersistentVolume, error) {
+	c.mu.Lock()
+	pv, ok := c.pvCache[name]
+	c.mu.Unlock()
+	if ok {
+		return pv, nil
+	}
+	if err := c.budget.take(); err != nil {
+		return nil, err
+	}
+
+	pv, err := c.clientset.CoreV1().PersistentVolumes().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.pvCache[name] = pv
+	c.mu.Unlock()
+	return pv, nil
+}
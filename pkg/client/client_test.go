@@ -0,0 +1,568 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func newFakeClient(objects ...runtime.Object) (*Client, *fake.Clientset) {
+	clientset := fake.NewClientset(objects...)
+	return NewClientFromInterfaces(clientset, dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())), clientset
+}
+
+func pod(namespace, name string) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+}
+
+func podNames(pods []corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, p := range pods {
+		names = append(names, p.Namespace+"/"+p.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestGetPods(t *testing.T) {
+	c, _ := newFakeClient(
+		pod("default", "web-1"),
+		pod("default", "web-2"),
+		pod("kube-system", "coredns-1"),
+	)
+
+	tests := []struct {
+		name      string
+		namespace string
+		want      []string
+	}{
+		{"single namespace", "default", []string{"default/web-1", "default/web-2"}},
+		{"all namespaces", "", []string{"default/web-1", "default/web-2", "kube-system/coredns-1"}},
+		{"empty namespace", "staging", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pods, err := c.GetPods(context.Background(), tt.namespace)
+			if err != nil {
+				t.Fatalf("GetPods() error = %v", err)
+			}
+			got := podNames(pods.Items)
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetPods(%q) = %v, want %v", tt.namespace, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GetPods(%q) = %v, want %v", tt.namespace, got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestGetPod(t *testing.T) {
+	c, _ := newFakeClient(pod("default", "web-1"))
+
+	got, err := c.GetPod(context.Background(), "default", "web-1")
+	if err != nil {
+		t.Fatalf("GetPod() error = %v", err)
+	}
+	if got.Name != "web-1" || got.Namespace != "default" {
+		t.Errorf("GetPod() = %s/%s, want default/web-1", got.Namespace, got.Name)
+	}
+
+	if _, err := c.GetPod(context.Background(), "kube-system", "web-1"); err == nil {
+		t.Error("GetPod() in wrong namespace: expected NotFound error")
+	}
+}
+
+func TestGetEvents(t *testing.T) {
+	event := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: "web-1.17c"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "web-1"},
+		Reason:         "BackOff",
+	}
+	c, clientset := newFakeClient(event)
+
+	// fake clientset 不会应用 field selector，这里检查请求中携带的 selector
+	var listed []k8stesting.ListActionImpl
+	clientset.PrependReactor("list", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listed = append(listed, action.(k8stesting.ListActionImpl))
+		return false, nil, nil
+	})
+
+	events, err := c.GetEvents(context.Background(), "default", "web-1")
+	if err != nil {
+		t.Fatalf("GetEvents() error = %v", err)
+	}
+	if len(events.Items) != 1 || events.Items[0].Reason != "BackOff" {
+		t.Errorf("GetEvents() = %v, want the BackOff event", events.Items)
+	}
+
+	if len(listed) != 1 {
+		t.Fatalf("expected 1 list call, got %d", len(listed))
+	}
+	if ns := listed[0].GetNamespace(); ns != "default" {
+		t.Errorf("listed events in namespace %q, want default", ns)
+	}
+	if got, want := listed[0].GetListRestrictions().Fields.String(), "involvedObject.name=web-1"; got != want {
+		t.Errorf("field selector = %q, want %q", got, want)
+	}
+}
+
+// writeKubeconfig 写入一个指向 server 的最小 kubeconfig 并返回路径
+func writeKubeconfig(t *testing.T, dir, server string) string {
+	t.Helper()
+
+	content := `apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: ` + server + `
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+users:
+- name: test
+  user:
+    token: test
+current-context: test
+`
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuildConfigPrecedence(t *testing.T) {
+	tmp := t.TempDir()
+	explicit := writeKubeconfig(t, filepath.Join(tmp, "explicit"), "https://explicit.example.com")
+	fromEnv := writeKubeconfig(t, filepath.Join(tmp, "env"), "https://env.example.com")
+
+	homeWithConfig := filepath.Join(tmp, "home")
+	writeKubeconfig(t, filepath.Join(homeWithConfig, ".kube"), "https://home.example.com")
+	emptyHome := filepath.Join(tmp, "empty-home")
+	if err := os.MkdirAll(emptyHome, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		kubeconfig string
+		home       string
+		wantHost   string
+		wantErr    error
+	}{
+		{
+			name:       "explicit path wins",
+			path:       explicit,
+			kubeconfig: fromEnv,
+			home:       homeWithConfig,
+			wantHost:   "https://explicit.example.com",
+		},
+		{
+			name:       "KUBECONFIG over default path",
+			kubeconfig: fromEnv,
+			home:       homeWithConfig,
+			wantHost:   "https://env.example.com",
+		},
+		{
+			name:     "default path",
+			home:     homeWithConfig,
+			wantHost: "https://home.example.com",
+		},
+		{
+			name:    "falls back to in-cluster",
+			home:    emptyHome,
+			wantErr: rest.ErrNotInCluster,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+			t.Setenv("HOME", tt.home)
+			t.Setenv("KUBERNETES_SERVICE_HOST", "")
+			t.Setenv("KUBERNETES_SERVICE_PORT", "")
+
+			config, err := buildConfig(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("buildConfig() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildConfig() error = %v", err)
+			}
+			if config.Host != tt.wantHost {
+				t.Errorf("buildConfig() host = %q, want %q", config.Host, tt.wantHost)
+			}
+		})
+	}
+}
+
+func TestBudget(t *testing.T) {
+	c, clientset := newFakeClient(pod("default", "web-1"))
+	c.SetBudget(NewBudget(2))
+
+	calls := 0
+	clientset.PrependReactor("list", "events", func(k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		return false, nil, nil
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetEvents(context.Background(), "default", "web-1"); err != nil {
+			t.Fatalf("GetEvents() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := c.GetEvents(context.Background(), "default", "web-1"); !errors.Is(err, ErrBudgetExhausted) {
+		t.Fatalf("GetEvents() over budget error = %v, want %v", err, ErrBudgetExhausted)
+	}
+
+	if calls != 2 {
+		t.Errorf("API calls = %d, want 2 (the denied request must not be sent)", calls)
+	}
+	if b := c.Budget(); b.Used() != 2 || b.Denied() != 1 || b.Limit() != 2 {
+		t.Errorf("budget used/denied/limit = %d/%d/%d, want 2/1/2", b.Used(), b.Denied(), b.Limit())
+	}
+
+	// 不限制的配额和未设置配额都不会拒绝请求
+	for _, budget := range []*Budget{NewBudget(0), nil} {
+		c.SetBudget(budget)
+		for i := 0; i < 3; i++ {
+			if _, err := c.GetEvents(context.Background(), "default", "web-1"); err != nil {
+				t.Fatalf("GetEvents() with budget %v error = %v", budget, err)
+			}
+		}
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image                           string
+		wantRegistry, wantRepo, wantTag string
+	}{
+		{"nginx", "registry-1.docker.io", "library/nginx", "latest"},
+		{"nginx:1.27", "registry-1.docker.io", "library/nginx", "1.27"},
+		{"bitnami/redis:7.2", "registry-1.docker.io", "bitnami/redis", "7.2"},
+		{"docker.io/library/nginx:1.27", "registry-1.docker.io", "library/nginx", "1.27"},
+		{"registry.cn-hangzhou.aliyuncs.com/shop/api:v2", "registry.cn-hangzhou.aliyuncs.com", "shop/api", "v2"},
+		{"localhost:5000/app", "localhost:5000", "app", "latest"},
+		{"nginx@sha256:abc", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			registry, repo, tag := parseImageReference(tt.image)
+			if registry != tt.wantRegistry || repo != tt.wantRepo || tag != tt.wantTag {
+				t.Errorf("parseImageReference(%q) = %q, %q, %q, want %q, %q, %q",
+					tt.image, registry, repo, tag, tt.wantRegistry, tt.wantRepo, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestGetRemoteImageDigest(t *testing.T) {
+	const digest = "sha256:2f1e5b3c4d6a7e8f9a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e2f"
+
+	var manifestRequests int
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			if got := r.URL.Query().Get("scope"); got != "repository:shop/api:pull" {
+				t.Errorf("token scope = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"token":"anonymous"}`))
+		case "/v2/shop/api/manifests/v2":
+			manifestRequests++
+			if r.Method != http.MethodHead {
+				t.Errorf("manifest request method = %s, want HEAD", r.Method)
+			}
+			if r.Header.Get("Authorization") != "Bearer anonymous" {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test",scope="repository:shop/api:pull"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, _ := newFakeClient()
+	c.registryClient = server.Client()
+	host := strings.TrimPrefix(server.URL, "https://")
+
+	for i := 0; i < 2; i++ {
+		got, err := c.GetRemoteImageDigest(context.Background(), host+"/shop/api:v2")
+		if err != nil {
+			t.Fatalf("GetRemoteImageDigest() error = %v", err)
+		}
+		if got != digest {
+			t.Errorf("GetRemoteImageDigest() = %q, want %q", got, digest)
+		}
+	}
+	// 第一次请求 401 后带 token 重试，第二次命中缓存
+	if manifestRequests != 2 {
+		t.Errorf("manifest requests = %d, want 2", manifestRequests)
+	}
+
+	if _, err := c.GetRemoteImageDigest(context.Background(), host+"/shop/missing:v1"); err == nil {
+		t.Error("GetRemoteImageDigest() for a missing image: expected error")
+	}
+	if got, err := c.GetRemoteImageDigest(context.Background(), "nginx@"+digest); got != "" || err != nil {
+		t.Errorf("GetRemoteImageDigest() for a pinned image = %q, %v, want no lookup", got, err)
+	}
+}
+
+func TestProbeCapability(t *testing.T) {
+	pdbs := Capability{Group: "policy", Version: "v1", Resource: "poddisruptionbudgets", Verb: "list"}
+	secrets := Capability{Version: "v1", Resource: "secrets", Verb: "get"}
+	nodes := Capability{Version: "v1", Resource: "nodes", Verb: "get", ClusterWide: true}
+
+	c, clientset := newFakeClient()
+	clientset.Resources = []*metav1.APIResourceList{{
+		GroupVersion: "v1",
+		APIResources: []metav1.APIResource{{Name: "secrets"}, {Name: "nodes"}},
+	}}
+
+	// 只允许读取节点
+	var reviews []authorizationv1.ResourceAttributes
+	clientset.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		reviews = append(reviews, *review.Spec.ResourceAttributes)
+		review.Status.Allowed = review.Spec.ResourceAttributes.Resource == "nodes"
+		return true, review, nil
+	})
+
+	tests := []struct {
+		name       string
+		capability Capability
+		want       string
+	}{
+		{"group not served", pdbs, ReasonAPINotFound},
+		{"forbidden", secrets, ReasonForbidden},
+		{"allowed", nodes, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.ProbeCapability(context.Background(), "default", tt.capability); got != tt.want {
+				t.Errorf("ProbeCapability() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// 结果被缓存，集群级能力不带命名空间
+	c.ProbeCapability(context.Background(), "default", secrets)
+	if len(reviews) != 2 {
+		t.Fatalf("access reviews = %d, want 2", len(reviews))
+	}
+	if reviews[1].Namespace != "" {
+		t.Errorf("cluster-wide review namespace = %q, want empty", reviews[1].Namespace)
+	}
+}
+
+func TestGetPodMetrics(t *testing.T) {
+	podMetrics := func(namespace, name string, containers ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "metrics.k8s.io/v1beta1",
+			"kind":       "PodMetrics",
+			"metadata":   map[string]interface{}{"namespace": namespace, "name": name},
+			"containers": containers,
+		}}
+	}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podMetricsGVR: "PodMetricsList"})
+	// PodMetrics 的资源名无法从 kind 推断（podmetricses），需要按 GVR 创建
+	for _, obj := range []*unstructured.Unstructured{
+		podMetrics("default", "web",
+			map[string]interface{}{"name": "app", "usage": map[string]interface{}{"cpu": "12m", "memory": "64Mi"}},
+			map[string]interface{}{"name": "proxy", "usage": map[string]interface{}{"cpu": "bogus", "memory": "16Mi"}},
+		),
+		podMetrics("payments", "ledger"),
+	} {
+		if _, err := dynamicClient.Resource(podMetricsGVR).Namespace(obj.GetNamespace()).Create(context.Background(), obj, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := NewClientFromInterfaces(fake.NewClientset(), dynamicClient)
+
+	got, err := c.GetPodMetrics(context.Background(), "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]ContainerUsage{
+		"default/web": {
+			"app":   {corev1.ResourceCPU: resource.MustParse("12m"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+			"proxy": {corev1.ResourceMemory: resource.MustParse("16Mi")},
+		},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for pod, containers := range want {
+		for name, usage := range containers {
+			for resourceName, q := range usage {
+				if actual := got[pod][name][resourceName]; actual.Cmp(q) != 0 {
+					t.Errorf("%s/%s %s = %s, want %s", pod, name, resourceName, actual.String(), q.String())
+				}
+			}
+			if len(got[pod][name]) != len(usage) {
+				t.Errorf("%s/%s usage = %v, want %v", pod, name, got[pod][name], usage)
+			}
+		}
+	}
+}
+
+func TestGetPodSecurityPolicies(t *testing.T) {
+	psp := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "policy/v1beta1",
+		"kind":       "PodSecurityPolicy",
+		"metadata":   map[string]interface{}{"name": "restricted"},
+		"spec":       map[string]interface{}{"privileged": false},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{podSecurityPolicyGVR: "PodSecurityPolicyList"}, psp)
+	c := NewClientFromInterfaces(fake.NewClientset(), dynamicClient)
+
+	list, err := c.GetPodSecurityPolicies(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "restricted" {
+		t.Errorf("got %v, want [restricted]", list.Items)
+	}
+}
+
+func TestServerMinorVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		gitVersion string
+		want       int
+		wantErr    bool
+	}{
+		{"release", "v1.28.3", 28, false},
+		{"managed suffix", "v1.26.15-eks-a5df82a", 26, false},
+		{"unknown major", "v2.0.0", 0, true},
+		{"unparsable", "master", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, clientset := newFakeClient()
+			clientset.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: tt.gitVersion}
+			got, err := c.ServerMinorVersion()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ServerMinorVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ServerMinorVersion() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestStats(t *testing.T) {
+	bodies := map[string]string{
+		"/api/v1/namespaces/default/pods": `{"kind":"PodList","apiVersion":"v1","items":[]}`,
+		"/api/v1/nodes/node-1":            `{"kind":"Node","apiVersion":"v1","metadata":{"name":"node-1"}}`,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, bodies[r.URL.Path])
+	}))
+	defer srv.Close()
+
+	stats := NewRequestStats()
+	config := &rest.Config{Host: srv.URL}
+	config.Wrap(stats.WrapTransport)
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := clientset.CoreV1().Pods("default").List(ctx, metav1.ListOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := clientset.CoreV1().Nodes().Get(ctx, "node-1", metav1.GetOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]int{"list pods": 2, "get nodes": 1}
+	if got := stats.Requests(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Requests() = %v, want %v", got, want)
+	}
+	if got := stats.Total(); got != 3 {
+		t.Errorf("Total() = %d, want 3", got)
+	}
+	wantBytes := int64(2*len(bodies["/api/v1/namespaces/default/pods"]) + len(bodies["/api/v1/nodes/node-1"]))
+	if got := stats.BytesReceived(); got != wantBytes {
+		t.Errorf("BytesReceived() = %d, want %d", got, wantBytes)
+	}
+}
+
+func TestRequestKind(t *testing.T) {
+	tests := []struct {
+		method, url string
+		want        string
+	}{
+		{http.MethodGet, "/api/v1/namespaces/default/pods", "list pods"},
+		{http.MethodGet, "/api/v1/pods?limit=500", "list pods"},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/web", "get pods"},
+		{http.MethodGet, "/api/v1/namespaces/default/pods/web/log", "get pods/log"},
+		{http.MethodGet, "/api/v1/namespaces/legacy", "get namespaces"},
+		{http.MethodGet, "/api/v1/nodes/node-1", "get nodes"},
+		{http.MethodGet, "/apis/apps/v1/namespaces/default/replicasets/web-5d8f", "get replicasets"},
+		{http.MethodGet, "/apis/policy/v1/poddisruptionbudgets?watch=true", "watch poddisruptionbudgets"},
+		{http.MethodPost, "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews", "create selfsubjectaccessreviews"},
+		{http.MethodGet, "/api", "discovery"},
+		{http.MethodGet, "/apis/apps/v1", "discovery"},
+		{http.MethodGet, "/version", "get /version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.url, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.url, nil)
+			if got := requestKind(req); got != tt.want {
+				t.Errorf("requestKind() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
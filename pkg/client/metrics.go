@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// metrics-server 提供的 PodMetrics 资源
+var podMetricsGVR = schema.GroupVersionResource{
+	Group:    "metrics.k8s.io",
+	Version:  "v1beta1",
+	Resource: "pods",
+}
+
+// ContainerUsage 是 Pod 中各容器当前的 CPU/内存用量，按容器名称索引
+// 定义为别名，结果可以直接作为 analyzer.ClusterData.PodUsage 使用
+type ContainerUsage = map[string]corev1.ResourceList
+
+// GetPodMetrics 获取指定命名空间中 Pod 的当前用量（metrics-server 的采样值），按 namespace/name 索引
+// 无法解析的用量会被跳过
+func (c *Client) GetPodMetrics(ctx context.Context, namespace string) (map[string]ContainerUsage, error) {
+	list, err := c.dynamic.Resource(podMetricsGVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]ContainerUsage, len(list.Items))
+	for _, item := range list.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		pod := make(ContainerUsage, len(containers))
+		for _, entry := range containers {
+			container, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			values, _, _ := unstructured.NestedStringMap(container, "usage")
+			resources := make(corev1.ResourceList, len(values))
+			for key, value := range values {
+				if q, err := resource.ParseQuantity(value); err == nil {
+					resources[corev1.ResourceName(key)] = q
+				}
+			}
+			pod[name] = resources
+		}
+		usage[item.GetNamespace()+"/"+item.GetName()] = pod
+	}
+	return usage, nil
+}
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PodSecurityPolicy 资源，Kubernetes 1.25 起已移除，client-go 中也不再有对应的类型
+var podSecurityPolicyGVR = schema.GroupVersionResource{
+	Group:    "policy",
+	Version:  "v1beta1",
+	Resource: "podsecuritypolicies",
+}
+
+// GetPodSecurityPolicies 获取集群中所有 PodSecurityPolicy
+func (c *Client) GetPodSecurityPolicies(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	return c.dynamic.Resource(podSecurityPolicyGVR).List(ctx, metav1.ListOptions{})
+}
@@ -0,0 +1,177 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// manifestMediaTypes 是查询 tag 时接受的 manifest 类型
+// 包含多架构的 index/manifest list，这样得到的 digest 与节点按 tag 拉取时记录的一致
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// GetRemoteImageDigest 通过 Registry HTTP API v2 查询镜像 tag 当前指向的 digest，结果会被缓存
+// 只支持匿名访问（必要时获取匿名 Bearer token）；已固定 digest 的镜像返回空字符串
+func (c *Client) GetRemoteImageDigest(ctx context.Context, image string) (string, error) {
+	registry, repository, tag := parseImageReference(image)
+	if tag == "" {
+		return "", nil
+	}
+
+	c.mu.Lock()
+	digest, ok := c.digestCache[image]
+	c.mu.Unlock()
+	if ok {
+		return digest, nil
+	}
+
+	manifestURL := fmt.Sprintf("https://%s/v2/%s/manifests/%s", registry, repository, tag)
+	resp, err := c.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		token, err := c.anonymousToken(ctx, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", image, err)
+		}
+		if resp, err = c.headManifest(ctx, manifestURL, token); err != nil {
+			return "", err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: registry returned %s", image, resp.Status)
+	}
+
+	digest = resp.Header.Get("Docker-Content-Digest")
+	if digest == "" {
+		return "", fmt.Errorf("%s: registry did not return a digest", image)
+	}
+
+	c.mu.Lock()
+	c.digestCache[image] = digest
+	c.mu.Unlock()
+	return digest, nil
+}
+
+// headManifest 对 manifest 发起 HEAD 请求，HEAD 不计入 Docker Hub 的拉取次数
+func (c *Client) headManifest(ctx context.Context, manifestURL, token string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := c.registryClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// anonymousToken 根据 WWW-Authenticate 中的 Bearer challenge 获取匿名 token
+func (c *Client) anonymousToken(ctx context.Context, challenge string) (string, error) {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return "", fmt.Errorf("unsupported registry auth %q", scheme)
+	}
+
+	var realm string
+	query := url.Values{}
+	for _, param := range splitChallengeParams(params) {
+		key, value, _ := strings.Cut(param, "=")
+		value = strings.Trim(value, `"`)
+		switch strings.TrimSpace(key) {
+		case "realm":
+			realm = value
+		case "service", "scope":
+			query.Set(strings.TrimSpace(key), value)
+		}
+	}
+	if realm == "" {
+		return "", fmt.Errorf("registry auth challenge has no realm")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.registryClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned %s (private images are not supported)", resp.Status)
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	return body.AccessToken, nil
+}
+
+// splitChallengeParams 按逗号拆分 challenge 参数，忽略引号内的逗号（如 scope 中的 "pull,push"）
+func splitChallengeParams(params string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range params {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				parts = append(parts, params[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, params[start:])
+}
+
+// parseImageReference 将镜像引用拆分为 registry 地址、仓库和 tag
+// 与 docker 的规则一致：第一段包含 "." 或 ":" 或为 localhost 时才是 registry，否则是 Docker Hub；
+// 镜像已固定 digest 时 tag 为空
+func parseImageReference(image string) (registry, repository, tag string) {
+	if strings.Contains(image, "@") {
+		return "", "", ""
+	}
+
+	registry = "registry-1.docker.io"
+	repository = image
+	if first, rest, ok := strings.Cut(image, "/"); ok && (strings.ContainsAny(first, ".:") || first == "localhost") {
+		registry, repository = first, rest
+		if registry == "docker.io" || registry == "index.docker.io" {
+			registry = "registry-1.docker.io"
+		}
+	}
+
+	tag = "latest"
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository, tag = repository[:i], repository[i+1:]
+	}
+	if registry == "registry-1.docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	return registry, repository, tag
+}
@@ -0,0 +1,143 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// RequestStats 统计发往 API Server 的请求，可以并发使用
+type RequestStats struct {
+	mu       sync.Mutex
+	requests map[string]int // 请求类型 -> 次数，如 "list pods"
+	bytes    atomic.Int64
+}
+
+// NewRequestStats 创建空的请求统计
+func NewRequestStats() *RequestStats {
+	return &RequestStats{requests: make(map[string]int)}
+}
+
+// Record 记录一次请求，kind 形如 "<verb> <resource>"
+func (s *RequestStats) Record(kind string) {
+	s.mu.Lock()
+	s.requests[kind]++
+	s.mu.Unlock()
+}
+
+// Requests 返回按类型统计的请求次数的副本
+func (s *RequestStats) Requests() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make(map[string]int, len(s.requests))
+	for kind, n := range s.requests {
+		out[kind] = n
+	}
+	return out
+}
+
+// Total 返回请求总数
+func (s *RequestStats) Total() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, n := range s.requests {
+		total += n
+	}
+	return total
+}
+
+// BytesReceived 返回已读取的响应体字节数
+func (s *RequestStats) BytesReceived() int64 {
+	return s.bytes.Load()
+}
+
+// WrapTransport 返回统计请求的 RoundTripper，用作 rest.Config.WrapTransport
+func (s *RequestStats) WrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &statsRoundTripper{stats: s, next: rt}
+}
+
+// statsRoundTripper 在请求经过时计数，并统计响应体的大小
+type statsRoundTripper struct {
+	stats *RequestStats
+	next  http.RoundTripper
+}
+
+func (t *statsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.stats.Record(requestKind(req))
+	resp, err := t.next.RoundTrip(req)
+	if err == nil && resp.Body != nil {
+		// ContentLength 在分块或压缩的响应中为 -1，按实际读取的字节数统计
+		resp.Body = &countingBody{ReadCloser: resp.Body, bytes: &t.stats.bytes}
+	}
+	return resp, err
+}
+
+// countingBody 统计从响应体中读取的字节数
+type countingBody struct {
+	io.ReadCloser
+	bytes *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
+
+// requestKind 从请求的方法和路径得出请求类型，如 "list pods"、"get nodes"、"create selfsubjectaccessreviews"
+// 路径形如 /api/v1/namespaces/{ns}/pods/{name} 或 /apis/{group}/{version}/{resource}，
+// 不带资源的路径（/api、/apis/{group}/{version}）视为 discovery
+func requestKind(req *http.Request) string {
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var rest []string
+	switch {
+	case len(parts) >= 2 && parts[0] == "api":
+		rest = parts[2:]
+	case len(parts) >= 3 && parts[0] == "apis":
+		rest = parts[3:]
+	case len(parts) >= 1 && (parts[0] == "api" || parts[0] == "apis"):
+		return "discovery"
+	default:
+		return strings.ToLower(req.Method) + " " + req.URL.Path
+	}
+	if len(rest) == 0 {
+		return "discovery"
+	}
+	// namespaces/{ns}/{resource} 是命名空间内的资源，namespaces/{name} 是命名空间本身
+	if rest[0] == "namespaces" && len(rest) >= 3 {
+		rest = rest[2:]
+	}
+
+	resource := rest[0]
+	if len(rest) >= 3 {
+		resource += "/" + rest[2]
+	}
+	named := len(rest) >= 2
+
+	var verb string
+	switch req.Method {
+	case http.MethodGet:
+		switch {
+		case req.URL.Query().Get("watch") == "true":
+			verb = "watch"
+		case named:
+			verb = "get"
+		default:
+			verb = "list"
+		}
+	case http.MethodPost:
+		verb = "create"
+	case http.MethodPut:
+		verb = "update"
+	case http.MethodPatch:
+		verb = "patch"
+	case http.MethodDelete:
+		verb = "delete"
+	default:
+		verb = strings.ToLower(req.Method)
+	}
+	return verb + " " + resource
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Trivy operator 的 VulnerabilityReport 资源
+var vulnerabilityReportGVR = schema.GroupVersionResource{
+	Group:    "aquasecurity.github.io",
+	Version:  "v1alpha1",
+	Resource: "vulnerabilityreports",
+}
+
+// VulnerabilitySummary 是 VulnerabilityReport 中的漏洞统计
+type VulnerabilitySummary struct {
+	Critical int64
+	High     int64
+}
+
+// VulnerabilityReportsAvailable 检查集群中是否安装了 Trivy operator 的 CRD
+func (c *Client) VulnerabilityReportsAvailable() (bool, error) {
+	resources, err := c.clientset.Discovery().ServerResourcesForGroupVersion(vulnerabilityReportGVR.GroupVersion().String())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, r := range resources.APIResources {
+		if r.Name == vulnerabilityReportGVR.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// GetVulnerabilityReport 获取工作负载中指定容器的漏洞统计
+// kind/name 是 Trivy 扫描的资源（通常是 ReplicaSet、StatefulSet 等 Pod 的直接控制者），
+// 没有对应报告时返回 nil
+func (c *Client) GetVulnerabilityReport(ctx context.Context, namespace, kind, name, container string) (*VulnerabilitySummary, error) {
+	if err := c.budget.take(); err != nil {
+		return nil, err
+	}
+
+	selector := labels.Set{
+		"trivy-operator.resource.kind":  kind,
+		"trivy-operator.resource.name":  name,
+		"trivy-operator.container.name": container,
+	}.String()
+
+	list, err := c.dynamic.Resource(vulnerabilityReportGVR).Namespace(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(list.Items) == 0 {
+		return nil, nil
+	}
+
+	report := list.Items[0].Object
+	critical, _, _ := unstructured.NestedInt64(report, "report", "summary", "criticalCount")
+	high, _, _ := unstructured.NestedInt64(report, "report", "summary", "highCount")
+	return &VulnerabilitySummary{Critical: critical, High: high}, nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// Config 是 kubectl-podview 的配置文件内容
+type Config struct {
+	// Providers 是自定义的虚拟节点识别规则，与内置规则合并，同名规则覆盖内置规则
+	Providers []analyzer.ProviderRule `json:"providers,omitempty"`
+
+	// Addons 是自定义的集群核心组件识别规则，与内置规则合并，同名规则覆盖内置规则
+	Addons []analyzer.AddonRule `json:"addons,omitempty"`
+
+	// DuplicateExclusions 替换 --find-duplicates 的默认排除规则（DaemonSet 和 *-operator）
+	DuplicateExclusions *analyzer.DuplicateExclusions `json:"duplicateExclusions,omitempty"`
+
+	// CostAllocationDocs 是内部成本分摊文档的链接，附在缺少成本注解的建议中
+	CostAllocationDocs string `json:"costAllocationDocs,omitempty"`
+
+	// EnabledChecks/DisabledChecks 按名称启用或关闭检查，与 --enable-check/--disable-check 合并
+	EnabledChecks  []string `json:"enabledChecks,omitempty"`
+	DisabledChecks []string `json:"disabledChecks,omitempty"`
+
+	// RequiredLabels 是每个 Pod 必须带有的组织标签，如 team、oncall；Pod 上没有时读取所在命名空间的标签
+	RequiredLabels []string `json:"requiredLabels,omitempty"`
+	// OwnerLabel 是 --owner-contact 显示的负责团队标签，为空时使用 team
+	OwnerLabel string `json:"ownerLabel,omitempty"`
+}
+
+// DefaultPath 返回默认的配置文件路径，如 ~/.config/kubectl-podview/config.yaml
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kubectl-podview", "config.yaml")
+}
+
+// Load 读取并校验配置文件
+// path 为空时读取默认路径，默认路径下没有配置文件时返回空配置
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+		if path == "" {
+			return &Config{}, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	cfg := &Config{}
+	if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := analyzer.ValidateProviderRules(cfg.Providers); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := analyzer.ValidateAddonRules(cfg.Addons); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if err := analyzer.ValidateCheckNames(cfg.EnabledChecks); err != nil {
+		return nil, fmt.Errorf("invalid config %s: enabledChecks: %w", path, err)
+	}
+	if err := analyzer.ValidateCheckNames(cfg.DisabledChecks); err != nil {
+		return nil, fmt.Errorf("invalid config %s: disabledChecks: %w", path, err)
+	}
+	if err := analyzer.ValidateLabelKeys(cfg.RequiredLabels); err != nil {
+		return nil, fmt.Errorf("invalid config %s: requiredLabels: %w", path, err)
+	}
+	if cfg.OwnerLabel != "" {
+		if err := analyzer.ValidateLabelKeys([]string{cfg.OwnerLabel}); err != nil {
+			return nil, fmt.Errorf("invalid config %s: ownerLabel: %w", path, err)
+		}
+	}
+	if cfg.DuplicateExclusions != nil {
+		if err := cfg.DuplicateExclusions.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid config %s: duplicateExclusions: %w", path, err)
+		}
+	}
+	return cfg, nil
+}
+
+// OwnerContactLabel 返回 --owner-contact 读取的标签
+func (c *Config) OwnerContactLabel() string {
+	if c.OwnerLabel == "" {
+		return analyzer.DefaultOwnerLabel
+	}
+	return c.OwnerLabel
+}
+
+// ProviderRules 返回与内置规则合并后的虚拟节点识别规则
+func (c *Config) ProviderRules() []analyzer.ProviderRule {
+	return analyzer.MergeProviderRules(analyzer.DefaultProviderRules, c.Providers)
+}
+
+// AddonRules 返回与内置规则合并后的集群核心组件识别规则
+func (c *Config) AddonRules() []analyzer.AddonRule {
+	return analyzer.MergeAddonRules(analyzer.DefaultAddons, c.Addons)
+}
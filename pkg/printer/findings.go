@@ -0,0 +1,788 @@
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// finding 是一种有稳定 ID 的发现：内置配置问题或 Pod 状态原因
+// 表格行尾的标记、建议列表和 explain 子命令都由 findings 表驱动，说明文字不会各自漂移
+type finding struct {
+	// id 是稳定的标识：配置问题为 kebab-case（如 missing-limits），状态原因沿用 Kubernetes 的原因名（如 CrashLoopBackOff）
+	id string
+	// aliases 是 explain 同样接受的其他名称，如 ErrImagePull
+	aliases []string
+	// issue 是对应的配置问题，按 ConfigIssue.Is 匹配；状态原因为空
+	issue analyzer.ConfigIssue
+	// tag 是表格行尾标记中的简写，只用于配置问题，多个问题可以共用
+	tag string
+
+	// about、why 和 detect 说明问题是什么、为什么重要、podview 如何发现它
+	about  string
+	why    string
+	detect string
+	// fix 是通用的修复建议，也是建议列表中的默认文本
+	fix string
+	// recommend 非 nil 时为建议列表生成针对具体 Pod 的文本，代替 fix
+	recommend func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string
+	// quiet 的问题只是提示，修复建议只在 explain 中给出，不加入建议列表
+	quiet bool
+}
+
+// recommendation 返回 Pod 上的发现在建议列表中的文本
+func (f *finding) recommendation(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+	if f.recommend != nil {
+		return f.recommend(p, pod, issue)
+	}
+	return f.fix
+}
+
+// vpaRecommendation 返回用量远低于 requests 时的建议：调低 requests，长期如此时交给 VPA 推荐
+func vpaRecommendation(pod analyzer.PodAnalysis, resource string) string {
+	target := "pod " + pod.Name
+	if pod.OwnerKind != "" && pod.OwnerName != "" {
+		target = pod.OwnerKind + "/" + pod.OwnerName
+	}
+	return "Lower " + resource + " requests of " + target + " towards observed usage - if it stays this low, let a VerticalPodAutoscaler (updateMode: \"Off\") recommend requests from history"
+}
+
+// findings 是所有内置的发现：配置问题在前，按 ConfigIssue.Is 依次匹配；状态原因在后
+var findings = []finding{
+	{
+		id: "missing-requests", issue: analyzer.IssueMissingRequests, tag: "req",
+		about:  "A container or init container sets no CPU or memory request.",
+		why:    "The scheduler places pods by their requests. Without them pods land on nodes that are already full and are the first to be evicted under memory pressure.",
+		detect: "--check-config (check \"resources\"): every container and init container must set both requests.",
+		fix:    "Set resource requests to enable proper scheduling",
+	},
+	{
+		id: "missing-limits", issue: analyzer.IssueMissingLimits, tag: "lim",
+		about:  "A container or init container sets no CPU or memory limit.",
+		why:    "A container without limits can use all free memory and CPU on its node and starve its neighbours.",
+		detect: "--check-config (check \"resources\"): every container and init container must set both limits.",
+		fix:    "Set resource limits to prevent resource exhaustion",
+	},
+	{
+		id: "missing-probe", issue: analyzer.IssueNoProbe, tag: "probe",
+		about:  "A container has neither a liveness nor a readiness probe.",
+		why:    "Without probes Kubernetes only notices a dead process. Hung applications keep receiving traffic and are never restarted.",
+		detect: "--check-config (check \"probes\"): every container must define a liveness or readiness probe.",
+		fix:    "Add liveness/readiness probes for better health checking",
+	},
+	{
+		id: "no-termination-message", issue: analyzer.IssueNoTerminationMessage, tag: "termmsg",
+		about:  "A container restarted but its last termination left no message in the pod status.",
+		why:    "The reason for the crash is only in the previous container's logs, which are gone after the next restart.",
+		detect: "--check-config (check \"termination-message\"): a restarted container whose last terminated state has an empty message.",
+		fix:    "Set terminationMessagePolicy: FallbackToLogsOnError so crash output is kept in the pod status",
+	},
+	{
+		id: "no-termination-message-policy", issue: analyzer.IssueNoTerminationMessagePolicy, tag: "termpolicy",
+		about:  "A container keeps the default terminationMessagePolicy: File.",
+		why:    "Applications rarely write /dev/termination-log, so crashes show no message in kubectl describe or in podview.",
+		detect: "--check-config (check \"termination-message-policy\"): containers without terminationMessagePolicy: FallbackToLogsOnError.",
+		fix:    "Set terminationMessagePolicy: FallbackToLogsOnError so crash output is kept in the pod status",
+	},
+	{
+		id: "same-zone", issue: analyzer.IssueAllReplicasSameZone, tag: "zone",
+		about:  "All running replicas of a Deployment are in one availability zone.",
+		why:    "A single zone outage takes down every replica at once.",
+		detect: "--check-topology: groups pods by Deployment and compares the topology.kubernetes.io/zone labels of their nodes.",
+		fix:    "Add topologySpreadConstraints on topology.kubernetes.io/zone to spread replicas across zones",
+	},
+	{
+		id: "virtual-node-zone", issue: analyzer.IssueVirtualNodeNoZone, tag: "vnode-zone",
+		about:  "The pod asks to be spread across zones but runs on a virtual node without a zone label.",
+		why:    "The scheduler cannot count the pod towards any zone, so the spread constraint does not protect the workload.",
+		detect: "--check-topology (check \"topology\"): pods with a zone topologySpreadConstraint on a virtual node that has no topology.kubernetes.io/zone label.",
+		fix:    "Label virtual nodes with topology.kubernetes.io/zone (one virtual node per zone) so zone spread constraints apply",
+	},
+	{
+		id: "selector-mismatch", issue: analyzer.IssueSelectorMismatch, tag: "selector",
+		about:  "The pod's labels no longer match the selector of the ReplicaSet that owns it.",
+		why:    "The ReplicaSet stops counting the pod and starts a replacement, while the orphan keeps running and may still receive traffic.",
+		detect: "--check-config (check \"selector-mismatch\"): compares the pod labels with the selector of its owning ReplicaSet.",
+		fix:    "Restore the labels of pods that no longer match their ReplicaSet selector, or delete the orphaned pods",
+	},
+	{
+		id: "template-hash-mismatch", issue: analyzer.IssueTemplateHashMismatch, tag: "hash",
+		about:  "The pod-template-hash label differs from the hash suffix of the owning ReplicaSet.",
+		why:    "Deployments use the label to tell old and new pods apart, so rollouts and kubectl rollout status miscount the pod.",
+		detect: "--check-config (check \"template-hash\"): compares the label with the owning ReplicaSet's name.",
+		fix:    "Restore the pod-template-hash label to the ReplicaSet's hash suffix, or delete the pod so the ReplicaSet recreates it",
+	},
+	{
+		id: "stdin-enabled", issue: analyzer.IssueStdinEnabled, tag: "stdin",
+		about:  "A container has stdin: true.",
+		why:    "Interactive flags are usually left over from debugging and keep a stdin stream open on the node.",
+		detect: "--check-config (check \"stdin-tty\"): containers with stdin: true.",
+		fix:    "Remove stdin/tty from production container specs - use kubectl debug for interactive sessions instead",
+	},
+	{
+		id: "tty-enabled", issue: analyzer.IssueTTYEnabled, tag: "tty",
+		about:  "A container has tty: true.",
+		why:    "Interactive flags are usually left over from debugging, and a TTY changes how applications buffer and format their logs.",
+		detect: "--check-config (check \"stdin-tty\"): containers with tty: true.",
+		fix:    "Remove stdin/tty from production container specs - use kubectl debug for interactive sessions instead",
+	},
+	{
+		id: "no-pdb", issue: analyzer.IssueNoPDB, tag: "pdb",
+		about:  "No PodDisruptionBudget selects the pod.",
+		why:    "Node drains and cluster upgrades may evict every replica of the workload at the same time.",
+		detect: "--check-pdb (check \"pdb\"): matches the PodDisruptionBudgets of the namespace against the labels of Deployment and StatefulSet pods.",
+		fix:    "Create a PodDisruptionBudget for production workloads to limit voluntary disruptions",
+	},
+	{
+		id: "kata-overhead", issue: analyzer.IssueMissingKataOverhead, tag: "kata",
+		about:  "A Kata Containers pod has no pod overhead.",
+		why:    "The VM shim uses memory and CPU that the scheduler does not see, so nodes are overcommitted.",
+		detect: "--check-config (check \"kata-overhead\"): pods using a Kata runtime class without spec.overhead.",
+		fix:    "Define overhead on the Kata RuntimeClass so the scheduler accounts for the VM shim",
+	},
+	{
+		id: "high-severity-cve", issue: analyzer.IssueHighSeverityCVE, tag: "cve",
+		about:  "A container image has HIGH or CRITICAL vulnerabilities.",
+		why:    "Known vulnerabilities in running images are the easiest way into a cluster.",
+		detect: "--check-vulnerabilities (check \"vulnerabilities\"): reads the VulnerabilityReports written by the Trivy operator for each container.",
+		fix:    "Rebuild images with patched base layers: kubectl get vulnerabilityreports -n <namespace>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Rebuild images with patched base layers: kubectl get vulnerabilityreports -n " + pod.Namespace
+		},
+	},
+	{
+		id: "image-drift", issue: analyzer.IssueImageDrift, tag: "image-drift",
+		about:  "A container runs a different image than its Deployment specifies.",
+		why:    "The running code is not what was deployed, usually after a manual kubectl set image or an edited pod.",
+		detect: "--check-config-drift (check \"drift\"): compares container images with the owning Deployment's template.",
+		fix:    "Re-apply the Deployment or restart the rollout to converge drifted images: kubectl rollout restart deployment/<name>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Re-apply the Deployment or restart the rollout to converge drifted images: kubectl rollout restart deployment/" + pod.OwnerName
+		},
+	},
+	{
+		id: "spec-drift", issue: analyzer.IssueSpecDrift, tag: "spec-drift",
+		about:  "Resources declared in the Deployment template have different values on the running pod.",
+		why:    "Something rewrites the pod on admission or resizes it in place, so the template no longer describes what actually runs.",
+		detect: "--check-config-drift (check \"drift\"): compares the requests and limits the owning Deployment's template declares with the container's values. Defaults added for undeclared resources, e.g. by a LimitRange, are ignored.",
+		fix:    "Compare the pod with its Deployment template (kubectl get pod -o yaml) - a mutating webhook or in-place resize may be rewriting resources; declare the intended values in the template",
+	},
+	{
+		id: "template-drift", issue: analyzer.IssueTemplateDrift, tag: "tmpl-drift",
+		about:  "A container's image was edited on the live pod and no longer matches its ReplicaSet template.",
+		why:    "Live edits to the pod are lost as soon as the pod is recreated.",
+		detect: "--check-config-drift (check \"drift\"): compares container images with the ReplicaSet template the pod was created from. The image is the only container field that can be edited on a running pod.",
+		fix:    "Move live edits into the workload template - the pod differs from its ReplicaSet template and the changes are lost when it is recreated",
+	},
+	{
+		id: "stale-image", issue: analyzer.IssueStaleImage, tag: "stale-image",
+		about:  "A container runs an older digest of its image tag than the registry serves.",
+		why:    "The pod runs different code than a freshly started replica would, depending on when its node pulled the tag.",
+		detect: "--check-stale-images (check \"stale-images\"): compares the digest each container runs with the digest the registry currently serves for the same tag.",
+		fix:    "Pin images by digest, or use imagePullPolicy: Always with mutable tags and roll out again to pick up the current digest",
+	},
+	{
+		id: "annotation-size", issue: analyzer.IssueAnnotationTooLarge, tag: "annotations",
+		about:  "The pod's annotation values add up to more than --max-annotation-size bytes.",
+		why:    "Large pods slow down every watch on pods and approach the etcd object size limit.",
+		detect: "--check-config (check \"annotation-size\"): sums the length of all annotation values.",
+		fix:    "Move large annotation payloads into a ConfigMap or CRD - oversized pods approach the etcd object size limit and updates start failing",
+	},
+	{
+		id: "missing-cost-annotation", issue: analyzer.IssueMissingCostAnnotation, tag: "cost",
+		about:  "The pod has no cost center annotation.",
+		why:    "Spend on the pod cannot be attributed to a team or budget.",
+		detect: "--check-annotation-policy (check \"cost-annotation\"): the annotation named by --require-cost-annotation must be set and not empty.",
+		fix:    "Add the cost center annotation to pod templates so spend can be attributed to a cost center",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			rec := "Add the " + analyzer.MissingCostAnnotationKey(issue) + " annotation to pod templates so spend can be attributed to a cost center"
+			if p.CostAllocationDocs != "" {
+				rec += " - see " + p.CostAllocationDocs
+			}
+			return rec
+		},
+	},
+	{
+		id: "no-pod-failure-policy", issue: analyzer.IssueNoPodFailurePolicy, tag: "pfp",
+		about:  "The pod's Job has no podFailurePolicy.",
+		why:    "Node drains and non-retriable exit codes use up backoffLimit just like real failures.",
+		detect: "--check-job (check \"job\"): reads the owning Job of each Job pod.",
+		fix:    "Add a podFailurePolicy to the Job to ignore disruptions (DisruptionTarget) and fail fast on non-retriable exit codes instead of spending backoffLimit on them",
+	},
+	{
+		id: "bare-pod-restarting", issue: analyzer.IssueBarePodRestarting, tag: "restart",
+		about:  "A pod without an owner uses restartPolicy: Always and keeps restarting.",
+		why:    "Nothing recreates a bare pod if its node goes away, and the crash loop never ends on its own.",
+		detect: "--check-config (check \"restart-policy\"): pods without owner references whose containers have restarted.",
+		fix:    "Run crash-prone workloads under a Deployment (or a Job with restartPolicy: OnFailure) - bare pods are never rescheduled: kubectl delete pod <pod> -n <namespace>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Run crash-prone workloads under a Deployment (or a Job with restartPolicy: OnFailure) - bare pods are never rescheduled: kubectl delete pod " + pod.Name + " -n " + pod.Namespace
+		},
+	},
+	{
+		id: "unbound-pvc", issue: analyzer.IssueUnboundPVC, tag: "pvc",
+		about:  "The pod references a PersistentVolumeClaim that is not Bound.",
+		why:    "The pod stays Pending until the claim is bound.",
+		detect: "--check-storage (check \"storage\"): reads each referenced PVC and reports its phase and storage class.",
+		fix:    "Check that the PVC's StorageClass exists and can provision, or that a matching PV is available: kubectl describe pvc -n <namespace>; kubectl get storageclass,pv",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Check that the PVC's StorageClass exists and can provision, or that a matching PV is available: kubectl describe pvc -n " + pod.Namespace + "; kubectl get storageclass,pv"
+		},
+	},
+	{
+		id: "volume-attach-limit", issue: analyzer.IssueVolumeAttachLimit, tag: "attach-limit",
+		about:  "The pod is stuck in ContainerCreating because its node already has as many volumes attached as it allows.",
+		why:    "Every cloud instance type caps the number of attached disks. Once the node is full, new volumes never attach and the pod waits forever with only FailedAttachVolume events to show for it.",
+		detect: "--check-storage (check \"storage\"): for scheduled pods with PVC or CSI volumes still in ContainerCreating (or PodInitializing before any init container started), reads the pod's FailedAttachVolume/FailedMount events for the attach-limit message; the detail shows the node's attached volume count and its attachable-volumes allocatable (CSI drivers report the limit in the CSINode object instead).",
+		fix:    "Reschedule the pod to a node with free attach slots, or raise the limit with a larger instance type or the CSI driver's volume-attach-limit setting",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Cordon " + pod.NodeName + " and delete the pod so it reschedules to a node with free attach slots (" + analyzer.VolumeAttachDetail(issue) + "), or raise the limit with a larger instance type or the CSI driver's volume-attach-limit setting: kubectl get csinode " + pod.NodeName + " -o yaml"
+		},
+	},
+	{
+		id: "volume-expansion-pending", issue: analyzer.IssueVolumeExpansionPending, tag: "resize",
+		about:  "The pod is stuck in ContainerCreating while the node finishes expanding one of its volumes.",
+		why:    "After a PVC is resized, the file system is grown on the node at mount time. If that step fails the volume never mounts and the pod stays in ContainerCreating.",
+		detect: "--check-storage (check \"storage\"): for scheduled pods with PVC or CSI volumes still in ContainerCreating (or PodInitializing before any init container started), reads the pod's FailedMount events for NodeExpandVolume or FileSystemResizePending messages.",
+		fix:    "Check the PVC conditions and the CSI node plugin logs, then delete the pod to retry the mount",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Check the PVC conditions and the CSI node plugin logs on " + pod.NodeName + ", then delete the pod to retry the mount: kubectl describe pvc -n " + pod.Namespace
+		},
+	},
+	{
+		id: "hostpath-writable", issue: analyzer.IssueHostPathWritable, tag: "hostpath",
+		about:  "The pod mounts a hostPath volume read-write.",
+		why:    "The data lives on one node and is lost, or left behind, when the pod moves.",
+		detect: "--check-storage (check \"storage\"): hostPath volumes mounted without readOnly by any container.",
+		fix:    "Data on hostPath/local PVs is lost if the node dies - use network-backed storage or replicate at the application level",
+	},
+	{
+		id: "hostpath-readonly", issue: analyzer.IssueHostPathReadOnly, tag: "hostpath-ro",
+		about:  "The pod mounts a hostPath volume read-only.",
+		why:    "Read-only mounts are common for agents that read node files. They are listed so hostPath use can be reviewed.",
+		detect: "--check-storage (check \"storage\"): hostPath volumes that every container mounts with readOnly: true.",
+		fix:    "Make sure the pod really needs the node's files, for example a log or metrics agent",
+		quiet:  true,
+	},
+	{
+		id: "local-pv", issue: analyzer.IssueLocalPersistentVolume, tag: "local-pv",
+		about:  "The pod uses a local PersistentVolume.",
+		why:    "The volume and the pod are tied to one node. If the node dies, the data is gone.",
+		detect: "--check-storage (check \"storage\"): follows each PVC to its PV and reports local volumes and their node.",
+		fix:    "Data on hostPath/local PVs is lost if the node dies - use network-backed storage or replicate at the application level",
+	},
+	{
+		id: "replicas-pinned", issue: analyzer.IssueReplicasPinnedSameNode, tag: "pinned",
+		about:  "All replicas of a workload depend on local storage of the same node.",
+		why:    "One node failure takes down the whole workload, and it cannot be rescheduled elsewhere.",
+		detect: "--check-storage: groups pods with hostPath or local PV storage by owner and node.",
+		fix:    "Spread replicas across nodes - all replicas of the workload depend on local storage of one node",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Spread replicas across nodes - all replicas of " + pod.OwnerKind + "/" + pod.OwnerName + " depend on local storage of node " + pod.NodeName
+		},
+	},
+	{
+		id: "webhook-blocks-deletion", issue: analyzer.IssueWebhookBlocksDeletion, tag: "webhook",
+		about:  "A validating webhook with failurePolicy: Fail intercepts DELETE of the pod.",
+		why:    "If the webhook rejects the request or is unreachable, the pod cannot be deleted, which also blocks drains and rollouts.",
+		detect: "--check-webhook (check \"webhooks\"): matches ValidatingWebhookConfigurations for pods/DELETE against the pod and its namespace.",
+		fix:    "Make sure webhooks validating pod DELETE are highly available, or narrow their rules/selectors - a rejecting or unreachable webhook with failurePolicy Fail leaves pods undeletable: kubectl get validatingwebhookconfigurations",
+	},
+	{
+		id: "no-default-deny", issue: analyzer.IssueNoDefaultDenyPolicy, tag: "netpol",
+		about:  "The pod's namespace has no default-deny NetworkPolicy for ingress or egress.",
+		why:    "Any pod in the cluster can reach the pod, and the pod can reach anything.",
+		detect: "--check-network-policy (check \"network-policy\"): looks for a policy with an empty podSelector and no rules for each direction; the detail names a direction left open.",
+		fix:    "Add a default-deny NetworkPolicy (podSelector: {}, policyTypes: [Ingress, Egress], no rules) to the namespace, then allow required traffic explicitly",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Add a default-deny NetworkPolicy (podSelector: {}, policyTypes: [Ingress, Egress], no rules) to namespace " + pod.Namespace + ", then allow required traffic explicitly"
+		},
+	},
+	{
+		id: "env-conflict-injected", issue: analyzer.IssueEnvConflictWithInjected, tag: "env-inject",
+		about:  "An application env var looks like a service link variable that Kubernetes injects.",
+		why:    "Kubernetes sets <SERVICE>_SERVICE_HOST/_PORT for every Service in the namespace, so the app may read the wrong value.",
+		detect: "--check-config (check \"env-injection\"): env vars ending in _SERVICE_HOST or _SERVICE_PORT while enableServiceLinks is on.",
+		fix:    "Rename env vars ending in _SERVICE_HOST/_SERVICE_PORT, or set enableServiceLinks: false and use Service DNS names",
+	},
+	{
+		id: "env-var-conflict", issue: analyzer.IssueEnvVarConflict, tag: "env-conflict",
+		about:  "Two envFrom sources of a container define the same key.",
+		why:    "The last source silently wins, so the value depends on the order of the list.",
+		detect: "--check-config with --check-configmaps or --check-secrets (check \"envfrom-conflicts\"): reads the referenced ConfigMaps and Secrets and compares their keys.",
+		fix:    "Rename colliding keys or set an envFrom prefix - the last envFrom source silently wins",
+	},
+	{
+		id: "custom-finalizer", issue: analyzer.IssuePodFinalizer, tag: "finalizer",
+		about:  "The pod has a finalizer that is not built into Kubernetes.",
+		why:    "If the controller that owns the finalizer is gone, the pod hangs in Terminating forever.",
+		detect: "--check-config (check \"finalizers\"): lists pod finalizers that Kubernetes does not manage itself.",
+		fix:    "Verify the controller responsible for the finalizer is running",
+	},
+	{
+		id: "missing-default-container", issue: analyzer.IssueMissingDefaultContainer, tag: "default-ctr",
+		about:  "A multi-container pod has no kubectl.kubernetes.io/default-container annotation.",
+		why:    "kubectl logs and exec pick the first container, which is often a sidecar.",
+		detect: "--check-config (check \"default-container\"): pods with more than one container and no annotation.",
+		fix:    "Set the kubectl.kubernetes.io/default-container annotation on multi-container pods so kubectl logs/exec pick the main container",
+	},
+	{
+		id: "name-too-long", issue: analyzer.IssueNameTooLong, tag: "name",
+		about:  "The pod name is longer than some integrations allow.",
+		why:    "Service meshes and other tools append suffixes to pod names and fail when the result exceeds DNS limits.",
+		detect: "--check-config (check \"name-length\"): pod names over 53 characters. With --templates, the pod name is estimated as the workload name plus the suffix its controller adds (e.g. -<hash>-xxxxx for a Deployment).",
+		fix:    "Shorten the workload name - pod names over 53 characters leave no room for suffixes added by meshes and other integrations",
+	},
+	{
+		id: "dns-override", issue: analyzer.IssueDNSOverride, tag: "dns",
+		about:  "The pod changes the default DNS setup: a dnsPolicy other than ClusterFirst, or a dnsConfig.",
+		why:    "This is often intended. It is listed so DNS problems on the pod can be traced back to the override.",
+		detect: "--check-dns (check \"dns\"): pods whose dnsPolicy is not ClusterFirst (an unset policy counts as ClusterFirst) or that set dnsConfig.",
+		fix:    "Make sure the override is intended; pods that talk to cluster Services need dnsPolicy: ClusterFirst",
+		quiet:  true,
+	},
+	{
+		id: "dns-default-policy", issue: analyzer.IssueDNSDefaultPolicy, tag: "dns-policy",
+		about:  "The pod uses dnsPolicy: Default but refers to cluster Services.",
+		why:    "Default uses the node's resolver, which cannot resolve *.svc.cluster.local names.",
+		detect: "--check-dns (check \"dns\"): dnsPolicy: Default and env var values that contain cluster Service hostnames.",
+		fix:    "Use dnsPolicy: ClusterFirst for pods that talk to cluster Services",
+	},
+	{
+		id: "ndots-external", issue: analyzer.IssueNdotsExternalLookups, tag: "ndots",
+		about:  "The pod calls external hostnames with the default ndots:5.",
+		why:    "Every external lookup first tries all search domains, multiplying DNS queries and latency.",
+		detect: "--check-dns (check \"dns\"): hostnames with a common public TLD (e.g. .com, .io) in env var values, and no ndots option in dnsConfig. name.namespace short names, Java class names and trailing-dot names are not counted.",
+		fix:    "Set dnsConfig.options ndots: \"2\" (or use trailing-dot FQDNs) to cut DNS lookups for external hosts",
+	},
+	{
+		id: "hostnetwork-dns", issue: analyzer.IssueDNSWithHostNetwork, tag: "hostnet-dns",
+		about:  "A hostNetwork pod uses dnsPolicy: ClusterFirst.",
+		why:    "Kubernetes falls back to the node's resolver for hostNetwork pods with ClusterFirst, so cluster Service names do not resolve.",
+		detect: "--check-dns (check \"dns\"): pods with hostNetwork: true whose dnsPolicy is ClusterFirst or unset.",
+		fix:    "Set dnsPolicy: ClusterFirstWithHostNet on hostNetwork pods so they resolve cluster Services",
+	},
+	{
+		id: "node-cordoned", issue: analyzer.IssueNodeCordoned, tag: "cordon",
+		about:  "The pod runs on a cordoned node.",
+		why:    "The node is usually about to be drained; the pod will be evicted and may not fit anywhere else.",
+		detect: "--check-node-unschedulable (check \"node-unschedulable\"): reads the pod's node and checks spec.unschedulable.",
+		fix:    "Pods on cordoned nodes will not be rescheduled there - drain the node or uncordon it: kubectl uncordon <node>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Pods on cordoned node " + pod.NodeName + " will not be rescheduled there - drain it or uncordon: kubectl uncordon " + pod.NodeName
+		},
+	},
+	{
+		id: "scheduled-after-cordon", issue: analyzer.IssueScheduledAfterCordon, tag: "cordon",
+		about:  "The pod was scheduled onto its node after the node was cordoned.",
+		why:    "Broad tolerations let workloads onto nodes under maintenance, where they are evicted again.",
+		detect: "--check-node-unschedulable (check \"node-unschedulable\"): compares the pod's creation time with the time spec.unschedulable was last set, from the node's managedFields (e.g. kubectl-cordon). Skipped when that time is unknown.",
+		fix:    "Drop broad tolerations (operator: Exists without a key, or node.kubernetes.io/unschedulable) from workloads that should stay off nodes under maintenance, then drain: kubectl drain <node> --ignore-daemonsets",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Drop broad tolerations (operator: Exists without a key, or node.kubernetes.io/unschedulable) from workloads that should stay off nodes under maintenance, then drain: kubectl drain " + pod.NodeName + " --ignore-daemonsets"
+		},
+	},
+	{
+		id: "daemonset-after-cordon", issue: analyzer.IssueDaemonSetAfterCordon, tag: "cordon",
+		about:  "A DaemonSet pod was scheduled onto its node after the node was cordoned.",
+		why:    "This is expected: DaemonSet pods tolerate cordons. It is listed so the node's maintenance state is visible.",
+		detect: "--check-node-unschedulable (check \"node-unschedulable\"): same as scheduled-after-cordon, for pods owned by a DaemonSet.",
+		fix:    "DaemonSet pods tolerate cordons by design - they stop when the node goes down for maintenance",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "DaemonSet pods tolerate cordons by design - they will stop when " + pod.NodeName + " goes down for maintenance"
+		},
+	},
+	{
+		id: "preemption-candidate", issue: analyzer.IssuePreemptionCandidate, tag: "preempt",
+		about:  "The pod may be evicted to make room for a pending higher-priority pod.",
+		why:    "Preemption ignores PodDisruptionBudgets when it has to, so the workload may lose capacity without warning.",
+		detect: "--check-preemption: reads the nominated nodes of pending pods and lists lower-priority pods on them.",
+		fix:    "Give workloads that must not be preempted a higher PriorityClass, or add capacity so high-priority pods fit without evictions",
+	},
+	{
+		id: "pull-secret-expiring", issue: analyzer.IssuePullSecretExpiring, tag: "pull-secret",
+		about:  "An image pull secret of the pod has expired or is about to.",
+		why:    "New pulls fail with ImagePullBackOff once the token expires, which shows up only when pods are rescheduled.",
+		detect: "--check-refs (check \"pull-secrets\"): reads the expiry annotation (--pull-secret-expiry-annotation) on each imagePullSecret.",
+		fix:    "Check the job that refreshes image pull secrets - pulls will fail with ImagePullBackOff once the token expires",
+	},
+	{
+		id: "invalid-pull-secret-type", issue: analyzer.IssueInvalidImagePullSecretType, tag: "pull-secret-type",
+		about:  "An imagePullSecret is not of type kubernetes.io/dockerconfigjson or kubernetes.io/dockercfg.",
+		why:    "The kubelet silently ignores such secrets, so private images cannot be pulled.",
+		detect: "--check-image-pull-secret-validity (check \"pull-secret-type\"): reads each imagePullSecret and checks its type.",
+		fix:    "Recreate image pull secrets with kubectl create secret docker-registry - kubelet ignores Opaque secrets listed in imagePullSecrets",
+	},
+	{
+		id: "missing-required-labels", issue: analyzer.IssueMissingRequiredLabels, tag: "labels",
+		about:  "Neither the pod nor its namespace has one of the labels listed in requiredLabels.",
+		why:    "Without ownership labels on-call cannot tell who owns a failing workload.",
+		detect: "requiredLabels in the config file (check \"required-labels\"): each label must be set on the pod or, failing that, its namespace.",
+		fix:    "Add the required ownership labels to the pod template or its namespace so on-call knows who owns the workload",
+	},
+	{
+		id: "stale-ready", issue: analyzer.IssueStaleReadyCondition, tag: "stale-ready",
+		about:  "The pod's Ready condition has been True without a transition for longer than --stale-condition-threshold.",
+		why:    "A readiness probe that never fails over months may not check the application at all.",
+		detect: "--check-config (check \"stale-ready\"): the lastTransitionTime of the Ready=True condition.",
+		fix:    "Check the readiness probe period - a Ready condition that never transitions may mean the probe does not really check the application",
+	},
+	{
+		id: "init-timeout", issue: analyzer.IssueInitTimeout, tag: "init-timeout",
+		about:  "The pod has been running its init containers for longer than --init-timeout.",
+		why:    "The pod never starts its app containers; init containers usually wait for a dependency that is down.",
+		detect: "--check-pod-stuck-in-init (check \"init-timeout\"): Pending pods whose start time is older than the timeout and whose init containers have not finished.",
+		fix:    "Check the logs of the running init container (kubectl logs <pod> -c <init>) and whether the dependency it waits for is up",
+	},
+	{
+		id: "slow-scheduling", issue: analyzer.IssueHighSchedulingLatency, tag: "sched",
+		about:  "The pod took longer than --scheduling-timeout from creation to being scheduled, or is still waiting that long.",
+		why:    "Time in the scheduling queue delays every rollout and scale-up. It points at resource contention or an overloaded scheduler before the pod even starts.",
+		detect: "--check-pod-scheduling-timeout (check \"scheduling-latency\"): the time from creation to the earliest PodScheduled=True transition; unscheduled Pending pods count the time waited so far. -o wide shows the latency in the SCHED column.",
+		fix:    "Check for pending pods competing for the same nodes (kubectl get events --field-selector reason=FailedScheduling) and add capacity, or look at scheduler latency if the cluster has free resources",
+	},
+	{
+		id: "service-port-mismatch", issue: analyzer.IssueServiceTargetPortMismatch, tag: "svc-port",
+		about:  "A Service selecting the pod targets a port that none of the pod's containers declares.",
+		why:    "The Service sends traffic to a port nothing listens on, so requests fail with connection refused even though the pod looks Ready.",
+		detect: "--check-service-ports (check \"service-ports\"): for each Service whose selector matches the pod, resolves targetPort (the port itself when unset) by number or container port name and protocol; the detail names the Service, the port it expects and the ports the containers declare.",
+		fix:    "Change the Service targetPort to a declared containerPort or port name, or declare the port the application listens on in the container spec",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Point the Service targetPort at a declared containerPort or port name (" + analyzer.ServicePortDetail(issue) + ")"
+		},
+	},
+	{
+		id: "service-port-unverified", issue: analyzer.IssueServicePortUnverified, tag: "svc-port-unverified",
+		about:  "A Service selecting the pod targets a port number, but the pod declares no containerPorts to compare it with.",
+		why:    "containerPorts are informational, so the Service may well work, but a typo in targetPort goes unnoticed until traffic fails.",
+		detect: "--check-service-ports (check \"service-ports\"): numeric targetPorts on pods without any containerPorts; named targetPorts cannot resolve without declared ports and count as a mismatch instead.",
+		fix:    "Declare the ports the containers listen on (containerPort and name) so Service targetPorts can be checked, and refer to them by name",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Declare containerPorts so the Service targetPort can be checked (" + analyzer.ServicePortDetail(issue) + ")"
+		},
+	},
+	{
+		id: "psp-violation", issue: analyzer.IssuePSPViolation, tag: "psp",
+		about:  "No PodSecurityPolicy in the cluster admits the pod's spec as it is now.",
+		why:    "Running pods were admitted under the policies of the day. Once policies are tightened, the next rollout, eviction or reschedule recreates the pod and admission rejects it.",
+		detect: "--check-psp (check \"psp\", clusters before Kubernetes 1.25): evaluates each PodSecurityPolicy against privileged mode, host namespaces and ports, volume types, allowed host paths, capabilities, privilege escalation, read-only root filesystem and runAsUser. Fields a policy defaults when unset do not count. RBAC use permissions are not checked. The issue names the closest policy and the detail lists what it rejects.",
+		fix:    "Change the pod spec to fit an existing PodSecurityPolicy, or grant the workload's ServiceAccount use of a policy that admits it; plan the move to Pod Security Admission, since PodSecurityPolicy is removed in Kubernetes 1.25",
+	},
+	{
+		id: "over-provisioned-cpu", issue: analyzer.IssueOverProvisionedCPU, tag: "cpu-idle",
+		about:  "The pod's containers use far less CPU than they request.",
+		why:    "Requests reserve node capacity whether it is used or not, so idle requests block other pods from scheduling and drive up node count and cost.",
+		detect: "--check-pod-resource-efficiency (check \"efficiency\"): compares the current metrics-server sample with the CPU requests of containers that set one; flagged below --efficiency-threshold (default 10%). A single sample can miss peaks, so look at usage over time before cutting requests.",
+		fix:    "Lower CPU requests towards observed usage, or run the Vertical Pod Autoscaler in recommendation mode (updateMode: \"Off\") to size them from history",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return vpaRecommendation(pod, "CPU")
+		},
+	},
+	{
+		id: "over-provisioned-memory", issue: analyzer.IssueOverProvisionedMemory, tag: "mem-idle",
+		about:  "The pod's containers use far less memory than they request.",
+		why:    "Requested memory is reserved on the node even when unused, so other pods cannot schedule there.",
+		detect: "--check-pod-resource-efficiency (check \"efficiency\"): compares the current metrics-server sample with the memory requests of containers that set one; flagged below --efficiency-threshold (default 10%). Caches and startup peaks are not visible in a single sample.",
+		fix:    "Lower memory requests towards observed usage plus headroom, or run the Vertical Pod Autoscaler in recommendation mode (updateMode: \"Off\") to size them from history",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return vpaRecommendation(pod, "memory")
+		},
+	},
+	{
+		id: "configmap-mutable", issue: analyzer.IssueConfigMapMutable, tag: "cm-mutable",
+		about:  "The pod references a ConfigMap that is not immutable.",
+		why:    "Edits reach mounted files without a rollout, so pods run different configs and a bad edit cannot be rolled back.",
+		detect: "--check-configmaps (check \"configmaps\"): reads each referenced ConfigMap.",
+		fix:    "Set immutable: true on ConfigMaps and roll out changes as new ConfigMaps to avoid silent config drift",
+	},
+	{
+		id: "secret-mutable", issue: analyzer.IssueSecretMutable, tag: "secret-mutable",
+		about:  "The pod references a Secret that is not immutable.",
+		why:    "Anyone who can edit the Secret changes the credentials of running pods without a rollout.",
+		detect: "--check-secrets (check \"secrets\"): reads each referenced Secret.",
+		fix:    "Set immutable: true on Secrets to prevent accidental modification",
+	},
+	{
+		id: "config-hash-mismatch", issue: analyzer.IssueStaleConfigHash, tag: "config-hash",
+		about:  "The config hash annotation on the pod does not match the ConfigMap it was computed from.",
+		why:    "The ConfigMap changed after the pod started, so the pod still runs the old config.",
+		detect: "--check-config-hash (check \"config-hash\"): compares the checksum/config annotation with the referenced ConfigMaps; reported only when a ConfigMap was modified after the pod was created.",
+		fix:    "Restart workloads whose ConfigMaps changed: kubectl rollout restart <workload>",
+	},
+	{
+		id: "secret-in-env", issue: analyzer.IssueSecretInEnv, tag: "secret-env",
+		about:  "A container reads a Secret through environment variables.",
+		why:    "Env values end up in crash dumps, child processes and kubectl describe output.",
+		detect: "--check-security (check \"security\"): env entries with secretKeyRef and envFrom entries with secretRef.",
+		fix:    "Mount Secrets as files instead of env vars - env values leak into crash dumps and kubectl describe",
+	},
+	{
+		id: "runs-as-root", issue: analyzer.IssueRunsAsRoot, tag: "root",
+		about:  "A container runs with runAsUser: 0.",
+		why:    "A process escaping the container is root on the node.",
+		detect: "--check-security (check \"security\"): the effective runAsUser of each container, container settings overriding the pod's.",
+		fix:    "Run containers as a non-root user - set runAsUser to a non-zero UID",
+	},
+	{
+		id: "root-not-prevented", issue: analyzer.IssueRootNotPrevented, tag: "maybe-root",
+		about:  "A container sets neither runAsUser nor runAsNonRoot: true.",
+		why:    "Whether it runs as root depends on the image, and many images default to root.",
+		detect: "--check-security (check \"security\"): started containers without runAsUser and without runAsNonRoot.",
+		fix:    "Set securityContext.runAsNonRoot: true so images that default to root are rejected",
+	},
+	{
+		id: "security-context-conflict", issue: analyzer.IssueSecurityContextConflict, tag: "sc-conflict",
+		about:  "A container sets runAsNonRoot: true but inherits runAsUser: 0 from the pod.",
+		why:    "The kubelet refuses to start the container, and the pod stays in CreateContainerConfigError.",
+		detect: "--check-config (check \"security-context-conflict\"): compares pod and container security contexts.",
+		fix:    "Set a non-zero runAsUser on containers with runAsNonRoot: true, or drop runAsUser: 0 from the pod securityContext - the kubelet refuses to start them",
+	},
+	{
+		id: "shell-pid1", issue: analyzer.IssueShellAsPID1, tag: "shell-pid1",
+		about:  "A container starts its app through sh -c, so the shell is PID 1.",
+		why:    "Most shells do not forward SIGTERM, so the app is killed with SIGKILL after terminationGracePeriodSeconds instead of shutting down cleanly.",
+		detect: "--check-grace (check \"shell-pid1\"): command and args that run a shell with -c and do not exec the app.",
+		fix:    "Start the app with exec (sh -c \"exec myapp\") or use the exec form command: [myapp] so it receives SIGTERM",
+	},
+	{
+		id: "shared-probe-port", issue: analyzer.IssueSharedProbePort, tag: "probe-port",
+		about:  "Two or more containers in the pod point their liveness or readiness probes at the same port.",
+		why:    "Containers in a pod share one network namespace, so only one of them owns the port. The other containers' probes check that container instead of themselves: they stay Ready while broken, or restart because a neighbour is.",
+		detect: "--check-probes (check \"shared-probe-port\"): HTTP, TCP and gRPC liveness/readiness ports per container, named ports resolved against the container's own ports; probes with an explicit host are ignored. The detail lists the port and the containers probing it.",
+		fix:    "Give each container a probe on a port it listens on itself, or an exec probe, and drop the probes that test another container",
+	},
+	{
+		id: "dependency-not-ready", issue: analyzer.IssueServiceEndpointNotReady, tag: "deps",
+		about:  "A Service the pod depends on has no ready endpoints.",
+		why:    "The pod will fail or wait at startup until the dependency is up.",
+		detect: "--check-startup-order (check \"startup-order\"): Services named in env vars and init containers, checked against their Endpoints.",
+		fix:    "Check that dependent Services have ready backends: kubectl get endpoints -n <namespace>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Check that dependent Services have ready backends: kubectl get endpoints -n " + pod.Namespace
+		},
+	},
+	{
+		id: "sequential-init", issue: analyzer.IssueSequentialInitContainers, tag: "init-seq",
+		about:  "The pod has several init containers that wait for or check dependencies.",
+		why:    "Init containers run one after another, so independent waits add up to the pod's startup time.",
+		detect: "--check-config (check \"sequential-init\"): two or more non-sidecar init containers whose names look like dependency waits (wait-for-*, check-*).",
+		fix:    "Merge independent wait-for/check init containers into one that waits on all dependencies concurrently - init containers run one after another",
+	},
+	{
+		id: "cpu-burst-ratio", issue: analyzer.IssueCPUBurstRatioTooHigh, tag: "burst",
+		about:  "A container's CPU limit is far above its CPU request.",
+		why:    "The scheduler only sees requests, so nodes packed with low-request pods throttle under load.",
+		detect: "--check-cpu-burst (check \"cpu-burst\"): the ratio of CPU limit to request is above --cpu-burst-ratio (default 50).",
+		fix:    "Raise CPU requests closer to typical usage - the scheduler only sees requests, so nodes packed with low-request pods throttle under load",
+	},
+	{
+		id: "not-safe-to-evict", issue: analyzer.IssueNotSafeToEvict, tag: "evict",
+		about:  "The pod uses local storage and has no cluster-autoscaler safe-to-evict annotation.",
+		why:    "The cluster autoscaler will not remove the pod's node, so the cluster does not scale down.",
+		detect: "--check-autoscaler (check \"autoscaler-eviction\"): running pods not owned by a DaemonSet that have emptyDir or hostPath volumes and no safe-to-evict annotation.",
+		fix:    "Annotate pods whose emptyDir/hostPath data is disposable with cluster-autoscaler.kubernetes.io/safe-to-evict: \"true\" so their nodes can be scaled down",
+	},
+	{
+		id: "batch-not-evictable", issue: analyzer.IssueBatchNotEvictable, tag: "evict",
+		about:  "A Job pod is annotated safe-to-evict: \"false\".",
+		why:    "Failed Job pods are retried anyway, and the annotation keeps the node from scaling down.",
+		detect: "--check-autoscaler (check \"autoscaler-eviction\"): Job pods with the annotation set to \"false\".",
+		fix:    "Remove safe-to-evict: \"false\" from Job pods - failed pods are retried, and the annotation keeps nodes from scaling down",
+	},
+
+	// 状态原因
+	{
+		id:     "admission-rejected",
+		about:  "The kubelet rejected the pod at admission, for example OutOfpods or UnexpectedAdmissionError.",
+		why:    "The node is out of pod capacity or a device plugin failed. Rejected pods stay around as Failed husks.",
+		detect: "Failed pods whose reason is an admission failure; --failed-husks lists them per node.",
+		fix:    "Delete the Failed husks with kubectl delete pods --field-selector=status.phase=Failed -n <namespace> (this removes every Failed pod there - review them first with kubectl podview --failed-husks)",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Check pod capacity and device plugins on node " + pod.NodeName + " (pods rejected at admission: " + pod.Reason + ")"
+		},
+	},
+	{
+		id:     "pod-error",
+		about:  "The pod is in the Error status: it failed, or a container terminated with an error.",
+		why:    "The workload is not running.",
+		detect: "Pods whose phase is Failed or whose containers terminated with a non-zero exit code.",
+		fix:    "Check pod events: kubectl describe pod <pod>",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Check pod events: kubectl describe pod " + pod.Name
+		},
+	},
+	{
+		id:     "missing-node-label",
+		about:  "The pod selects a node label that no node has.",
+		why:    "The pod stays Pending until the selector is fixed or a node gets the label; adding capacity does not help.",
+		detect: "Unschedulable pods whose nodeSelector or required nodeAffinity names a label missing from every node, with the closest existing keys or values.",
+		fix:    "Update the nodeSelector/nodeAffinity of the workload to a label that exists on nodes",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return missingNodeLabelRecommendation(pod, pod.MissingNodeLabel)
+		},
+	},
+	{
+		id:     "Unschedulable",
+		about:  "The scheduler found no node that fits the pod.",
+		why:    "The pod stays Pending until resources free up or a node matches its constraints.",
+		detect: "Pending pods whose PodScheduled condition has reason Unschedulable.",
+		fix:    "Check node resources and taints",
+	},
+	{
+		id:      "ImagePullBackOff",
+		aliases: []string{"ErrImagePull"},
+		about:   "The kubelet cannot pull a container image.",
+		why:     "The pod stays Pending; the kubelet retries with increasing backoff.",
+		detect:  "Pending pods with a container waiting in ErrImagePull or ImagePullBackOff.",
+		fix:     "Verify image name and pull secrets",
+	},
+	{
+		id:     "high-restarts",
+		about:  "The pod's containers restarted more than 10 times.",
+		why:    "Frequent restarts mean the application keeps dying, even when the pod looks Running between crashes.",
+		detect: "Warning pods whose total container restart count is above 10.",
+		fix:    "Investigate high restart count - check logs: kubectl logs <pod> --previous",
+		recommend: func(p *Printer, pod analyzer.PodAnalysis, issue analyzer.ConfigIssue) string {
+			return "Investigate high restart count - check logs: kubectl logs " + pod.Name + containerFlag(pod) + " --previous"
+		},
+	},
+	{
+		id:     "CrashLoopBackOff",
+		about:  "A container keeps exiting and the kubelet waits longer before each restart.",
+		why:    "The workload is down or running with fewer replicas while the container crashes.",
+		detect: "Warning pods with a container waiting in CrashLoopBackOff.",
+		fix:    "Container keeps crashing - check application logs and resource limits",
+	},
+}
+
+// issueFinding 返回配置问题对应的发现，没有对应的（如注册的检查）返回 nil
+func issueFinding(issue analyzer.ConfigIssue) *finding {
+	for i := range findings {
+		if f := &findings[i]; f.issue != "" && issue.Is(f.issue) {
+			return f
+		}
+	}
+	return nil
+}
+
+// lookupFinding 按 ID 或别名查找发现，不区分大小写
+func lookupFinding(id string) *finding {
+	for i := range findings {
+		f := &findings[i]
+		if strings.EqualFold(f.id, id) {
+			return f
+		}
+		for _, alias := range f.aliases {
+			if strings.EqualFold(alias, id) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// FindingIDs 返回所有发现的 ID，配置问题在前，状态原因在后
+func FindingIDs() []string {
+	ids := make([]string, 0, len(findings))
+	for _, f := range findings {
+		ids = append(ids, f.id)
+	}
+	return ids
+}
+
+// toV1 将发现转换为稳定的 v1 类型
+func (f *finding) toV1() analysisv1.Finding {
+	return analysisv1.Finding{
+		ID:          f.id,
+		Aliases:     f.aliases,
+		Issue:       analysisv1.ConfigIssue(f.issue),
+		Tag:         f.tag,
+		Description: f.about,
+		Why:         f.why,
+		Detection:   f.detect,
+		Remediation: f.fix,
+	}
+}
+
+// UnknownFindingError 表示 explain 的 ID 不对应任何发现
+type UnknownFindingError struct {
+	ID string
+}
+
+func (e *UnknownFindingError) Error() string {
+	return fmt.Sprintf("unknown finding %q", e.ID)
+}
+
+// PrintExplain 打印一个发现的说明：是什么、为什么重要、如何检测和如何修复
+// ID 未知时打印所有可用的 ID 并返回 *UnknownFindingError
+func (p *Printer) PrintExplain(id string) error {
+	f := lookupFinding(id)
+	if f == nil {
+		p.PrintFindingIDs()
+		return &UnknownFindingError{ID: id}
+	}
+
+	title := f.id
+	if f.issue != "" {
+		title += ": " + string(f.issue)
+	}
+	fmt.Fprintln(p.out, colorBold+title+colorReset)
+	if f.tag != "" {
+		fmt.Fprintf(p.out, "  Table marker: ⚙ %s\n", f.tag)
+	}
+	if len(f.aliases) > 0 {
+		fmt.Fprintf(p.out, "  Also known as: %s\n", strings.Join(f.aliases, ", "))
+	}
+	for _, section := range []struct{ heading, text string }{
+		{"What it is", f.about},
+		{"Why it matters", f.why},
+		{"How podview detects it", f.detect},
+		{"How to fix it", f.fix},
+	} {
+		fmt.Fprintln(p.out)
+		fmt.Fprintln(p.out, section.heading+":")
+		fmt.Fprintln(p.out, "  "+section.text)
+	}
+	return nil
+}
+
+// PrintExplainJSON 以 JSON 输出一个发现，id 为空时输出全部发现
+func (p *Printer) PrintExplainJSON(id string) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	if id == "" {
+		out := make([]analysisv1.Finding, 0, len(findings))
+		for i := range findings {
+			out = append(out, findings[i].toV1())
+		}
+		return enc.Encode(out)
+	}
+	f := lookupFinding(id)
+	if f == nil {
+		return &UnknownFindingError{ID: id}
+	}
+	return enc.Encode(f.toV1())
+}
+
+// PrintFindingIDs 按配置问题和状态原因分组列出所有可用的 ID
+func (p *Printer) PrintFindingIDs() {
+	var issues, reasons []string
+	for _, f := range findings {
+		if f.issue != "" {
+			issues = append(issues, f.id)
+		} else {
+			reasons = append(reasons, f.id)
+		}
+	}
+	fmt.Fprintln(p.out, "Config issues:")
+	for _, id := range issues {
+		fmt.Fprintln(p.out, "  "+id)
+	}
+	fmt.Fprintln(p.out, "Pod status reasons:")
+	for _, id := range reasons {
+		fmt.Fprintln(p.out, "  "+id)
+	}
+}
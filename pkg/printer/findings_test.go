@@ -0,0 +1,118 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+)
+
+// TestFindingsCatalog 检查每个内置配置问题都有自己的发现，且所有发现的 ID 唯一、说明完整
+func TestFindingsCatalog(t *testing.T) {
+	for _, issue := range allConfigIssues {
+		f := issueFinding(issue)
+		if f == nil {
+			t.Errorf("issue %q has no finding", issue)
+			continue
+		}
+		if f.issue != issue {
+			t.Errorf("issue %q matches finding %s of %q", issue, f.id, f.issue)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		for _, id := range append([]string{f.id}, f.aliases...) {
+			if seen[id] {
+				t.Errorf("duplicate finding ID %q", id)
+			}
+			seen[id] = true
+		}
+		if f.about == "" || f.why == "" || f.detect == "" || f.fix == "" {
+			t.Errorf("finding %s is missing part of its explanation", f.id)
+		}
+		if (f.issue == "") != (f.tag == "") {
+			t.Errorf("finding %s: config issues need a tag, status reasons must not have one", f.id)
+		}
+	}
+}
+
+func TestPrintExplain(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    []string
+		notWant []string
+		wantErr bool
+	}{
+		{
+			name: "config issue",
+			id:   "missing-limits",
+			want: []string{
+				"missing-limits: Missing resource limits",
+				"Table marker: ⚙ lim",
+				"What it is:", "Why it matters:", "How podview detects it:", "How to fix it:",
+				"--check-config",
+				"Set resource limits to prevent resource exhaustion",
+			},
+		},
+		{
+			name:    "status reason",
+			id:      "CrashLoopBackOff",
+			want:    []string{"CrashLoopBackOff", "Container keeps crashing"},
+			notWant: []string{"Table marker"},
+		},
+		{
+			name: "alias, any case",
+			id:   "errimagepull",
+			want: []string{"ImagePullBackOff", "Also known as: ErrImagePull", "Verify image name and pull secrets"},
+		},
+		{
+			name:    "unknown ID lists the available ones",
+			id:      "no-such-thing",
+			want:    []string{"Config issues:", "  missing-limits", "Pod status reasons:", "  CrashLoopBackOff"},
+			notWant: []string{"What it is:"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := NewPrinter(&buf).PrintExplain(tt.id)
+			var unknown *UnknownFindingError
+			if got := errors.As(err, &unknown); got != tt.wantErr {
+				t.Fatalf("PrintExplain(%q) error = %v, want unknown finding error %v", tt.id, err, tt.wantErr)
+			}
+			assertContains(t, buf.String(), tt.want)
+			assertNotContains(t, buf.String(), tt.notWant)
+		})
+	}
+}
+
+func TestPrintExplainJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewPrinter(&buf).PrintExplainJSON("no-pdb"); err != nil {
+		t.Fatal(err)
+	}
+	var got analysisv1.Finding
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal: %v\n%s", err, buf.String())
+	}
+	if got.ID != "no-pdb" || got.Tag != "pdb" || got.Remediation == "" {
+		t.Errorf("got %+v", got)
+	}
+
+	buf.Reset()
+	if err := NewPrinter(&buf).PrintExplainJSON(""); err != nil {
+		t.Fatal(err)
+	}
+	var all []analysisv1.Finding
+	if err := json.Unmarshal(buf.Bytes(), &all); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(all) != len(findings) {
+		t.Errorf("got %d findings, want %d", len(all), len(findings))
+	}
+}
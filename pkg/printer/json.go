@@ -0,0 +1,64 @@
+package printer
+
+import (
+	"encoding/json"
+	"io"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// JSONPrinter 以 JSON 输出分析结果，字段见 analysisv1.AnalysisResult，不含颜色和提示文字
+type JSONPrinter struct {
+	out io.Writer
+}
+
+// NewJSONPrinter 创建一个新的 JSONPrinter
+func NewJSONPrinter(out io.Writer) *JSONPrinter {
+	return &JSONPrinter{out: out}
+}
+
+// PrintAnalysis 输出完整的分析结果：Pod 及其容器、配置问题、ECI 信息和汇总计数，Pod 按 namespace/name 排序
+func (p *JSONPrinter) PrintAnalysis(results *analyzer.AnalysisResult) error {
+	return p.encode(results.ToV1())
+}
+
+// PrintTopProblems 按排名顺序输出 --top-problems 的结果
+func (p *JSONPrinter) PrintTopProblems(pods []analyzer.ScoredPod) error {
+	out := make([]analysisv1.ScoredPod, 0, len(pods))
+	for _, pod := range pods {
+		out = append(out, pod.ToV1())
+	}
+	return p.encode(out)
+}
+
+// PrintECICandidates 按排名顺序输出 --suggest-eci 的结果
+func (p *JSONPrinter) PrintECICandidates(candidates []analyzer.ECICandidate) error {
+	out := make([]analysisv1.ECICandidate, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.ToV1())
+	}
+	return p.encode(out)
+}
+
+// PrintPodDetail 输出 kubectl podview detail 的单个 Pod 及其时间线
+func (p *JSONPrinter) PrintPodDetail(pod analyzer.PodAnalysis, timeline []analyzer.TimelineEvent) error {
+	return p.encode(podDetail(pod, timeline))
+}
+
+// podDetail 将单个 Pod 及其时间线转换为 JSON 和 YAML 共用的 v1 类型
+func podDetail(pod analyzer.PodAnalysis, timeline []analyzer.TimelineEvent) analysisv1.PodDetail {
+	out := analysisv1.PodDetail{Pod: pod.ToV1(), Timeline: make([]analysisv1.TimelineEvent, 0, len(timeline))}
+	for _, event := range timeline {
+		out.Timeline = append(out.Timeline, event.ToV1())
+	}
+	return out
+}
+
+// encode 以缩进的 JSON 输出 v，"<" 等字符保持原样，不转义为 \u003c，便于直接阅读
+func (p *JSONPrinter) encode(v any) error {
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
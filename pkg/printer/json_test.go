@@ -0,0 +1,77 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// roundTripTests 是 JSON 和 YAML 输出共用的往返测试用例
+func roundTripTests() []struct {
+	name    string
+	results *analyzer.AnalysisResult
+} {
+	withIssues := crashingPod("default", "worker")
+	withIssues.ConfigIssues = allConfigIssues
+	withIssues.ContainerInfo = []analyzer.ContainerAnalysis{
+		{Name: "app", RestartCount: 12, State: "waiting", WaitingReason: "CrashLoopBackOff", LastTermination: "Error (exit code 1)"},
+		{Name: "sidecar", Ready: true, State: "running"},
+	}
+
+	return []struct {
+		name    string
+		results *analyzer.AnalysisResult
+	}{
+		{name: "empty", results: &analyzer.AnalysisResult{}},
+		{name: "golden pods", results: goldenPods()},
+		{name: "all config issues", results: newResult(withIssues)},
+	}
+}
+
+func TestJSONPrinterRoundTrip(t *testing.T) {
+	tests := roundTripTests()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewJSONPrinter(&buf).PrintAnalysis(tt.results); err != nil {
+				t.Fatalf("PrintAnalysis failed: %v", err)
+			}
+			out := buf.String()
+			if strings.Contains(out, "\x1b[") {
+				t.Errorf("output contains ANSI escape codes:\n%s", out)
+			}
+
+			var got analysisv1.AnalysisResult
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("output is not valid JSON: %v\n%s", err, out)
+			}
+			if want := tt.results.ToV1(); !reflect.DeepEqual(&got, want) {
+				t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", got, *want)
+			}
+		})
+	}
+}
+
+func TestJSONPrinterFields(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewJSONPrinter(&buf).PrintAnalysis(goldenPods()); err != nil {
+		t.Fatalf("PrintAnalysis failed: %v", err)
+	}
+	assertContains(t, buf.String(), []string{
+		`"pods": [`,
+		`"readyContainers": 0`,
+		`"totalContainers": 1`,
+		`"restarts": 12`,
+		`"configIssues": [`,
+		`"runningOnECI": true`,
+		`"schedulingLatencySeconds": 2`,
+		`"summary": {`,
+		`"totalPods": 8`,
+	})
+}
@@ -1,10 +1,15 @@
 package printer
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"time"
 
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
 	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
 )
 
@@ -23,6 +28,12 @@ const (
 // Printer 负责格式化输出
 type Printer struct {
 	out io.Writer
+
+	// CostAllocationDocs 是成本分摊文档的链接，为空时建议中不附链接
+	CostAllocationDocs string
+	// CleanupPlan 为 true 时（--cleanup-plan）建议中给出所有保留了已结束 Pod 的命名空间的清理命令，
+	// 否则只给出达到 analyzer.RetainedPodsThreshold 的命名空间
+	CleanupPlan bool
 }
 
 // NewPrinter 创建一个新的 Printer
@@ -30,148 +41,233 @@ func NewPrinter(out io.Writer) *Printer {
 	return &Printer{out: out}
 }
 
+// TableOptions 控制 Pod 表格的显示内容
+type TableOptions struct {
+	ShowAll       bool // 显示所有 Pod，包括健康的
+	ShowNamespace bool // 显示 NAMESPACE 列
+	ShowPDB       bool // 显示 PDB 列（允许的中断数）
+	ShowOwner     bool // 显示 OWNER 列（负责团队标签的值）
+	Wide          bool // -o wide：显示 SCHED 列（调度耗时）和 EFF 列（资源利用率）
+	GroupByStatus bool // 按状态分段输出，最严重的在前（见 statusSections）
+
+	// FailedHusksOnly 只显示被 kubelet 准入拒绝的 Failed Pod，便于批量清理
+	FailedHusksOnly bool
+
+	// VerboseIssues 在 Pod 行下逐条列出配置问题，默认只在行尾显示简写标记，如 "⚙ req,lim,probe"
+	VerboseIssues bool
+	// MaxIssueLines 是每个 Pod 最多列出的配置问题数，超出的部分折叠为 "+N more"，0 表示不限制
+	// 只影响表格的显示，PodAnalysis.ConfigIssues 和 JSON 结果始终是完整的
+	MaxIssueLines int
+}
+
+// DefaultMaxIssueLines 是每个 Pod 默认最多列出的配置问题数
+const DefaultMaxIssueLines = 5
+
 // PrintPodTable 打印 Pod 表格
-func (p *Printer) PrintPodTable(result *analyzer.AnalysisResult, showAll bool, showNamespace bool) {
+func (p *Printer) PrintPodTable(result *analyzer.AnalysisResult, opts TableOptions) {
+	showAll := opts.ShowAll
+
 	// 先过滤出要显示的 pods
 	var podsToShow []analyzer.PodAnalysis
 	for _, pod := range result.Pods {
+		if opts.FailedHusksOnly {
+			if pod.AdmissionFailure {
+				podsToShow = append(podsToShow, pod)
+			}
+			continue
+		}
 		if showAll || pod.Status != analyzer.StatusHealthy || len(pod.ConfigIssues) > 0 {
 			podsToShow = append(podsToShow, pod)
 		}
 	}
 
+	if opts.FailedHusksOnly {
+		fmt.Fprintf(p.out, "Failed husks (rejected at admission): %d\n\n", len(podsToShow))
+		if len(podsToShow) == 0 {
+			return
+		}
+	}
+
 	if len(podsToShow) == 0 {
 		fmt.Fprintln(p.out, colorGreen+"  ✓ All pods are healthy!"+colorReset)
 		fmt.Fprintln(p.out)
 		return
 	}
 
-	// 计算各列的最大宽度
-	maxNameLen := len("NAME")
-	maxNsLen := len("NAMESPACE")
-	for _, pod := range podsToShow {
-		if len(pod.Name) > maxNameLen {
-			maxNameLen = len(pod.Name)
-		}
-		if showNamespace && len(pod.Namespace) > maxNsLen {
-			maxNsLen = len(pod.Namespace)
-		}
+	t := &table{headerColor: colorBold, rule: true, ruleTail: podTableRuleTail}
+	if opts.ShowNamespace {
+		t.columns = append(t.columns, column{header: "NAMESPACE", maxWidth: maxNamespaceWidth, gap: 2})
 	}
-
-	// 限制最大宽度，避免太长
-	if maxNameLen > 60 {
-		maxNameLen = 60
+	t.columns = append(t.columns,
+		column{header: "NAME", maxWidth: maxPodNameWidth, gap: 2},
+		column{header: "STATUS", width: 10},
+		column{header: "READY", width: 7},
+		column{header: "RESTARTS", width: 10},
+		column{header: "AGE", width: 9},
+		column{header: "RUNNING", width: 9},
+	)
+	if opts.Wide {
+		t.columns = append(t.columns, column{header: "SCHED", width: 7}, column{header: "EFF", width: 5})
 	}
-	if maxNsLen > 25 {
-		maxNsLen = 25
+	t.columns = append(t.columns, column{header: "ECI", width: 5})
+	// 可选的 PDB 列位于 ECI 和 REASON 之间
+	if opts.ShowPDB {
+		t.columns = append(t.columns, column{header: "PDB", width: 4})
 	}
-
-	// 构建表头格式
-	var headerFmt, rowFmt string
-	var separator int
-	if showNamespace {
-		headerFmt = fmt.Sprintf("%%-%ds  %%-%ds  %%-10s %%-7s %%-10s %%-9s %%-9s %%-5s %%s", maxNsLen, maxNameLen)
-		rowFmt = fmt.Sprintf("%%-%ds  %%-%ds  %%s%%-10s%%s %%-7s %%-10d %%-9s %%-9s %%-5s %%s%%s", maxNsLen, maxNameLen)
-		separator = maxNsLen + maxNameLen + 80
-	} else {
-		headerFmt = fmt.Sprintf("%%-%ds  %%-10s %%-7s %%-10s %%-9s %%-9s %%-5s %%s", maxNameLen)
-		rowFmt = fmt.Sprintf("%%-%ds  %%s%%-10s%%s %%-7s %%-10d %%-9s %%-9s %%-5s %%s%%s", maxNameLen)
-		separator = maxNameLen + 75
+	if opts.ShowOwner {
+		t.columns = append(t.columns, column{header: "OWNER", maxWidth: maxOwnerWidth, gap: 2})
 	}
+	t.columns = append(t.columns, column{header: "REASON"})
 
-	// 打印表头
-	if showNamespace {
-		header := fmt.Sprintf(headerFmt, "NAMESPACE", "NAME", "STATUS", "READY", "RESTARTS", "AGE", "RUNNING", "ECI", "REASON")
-		fmt.Fprintln(p.out, colorBold+header+colorReset)
-	} else {
-		header := fmt.Sprintf(headerFmt, "NAME", "STATUS", "READY", "RESTARTS", "AGE", "RUNNING", "ECI", "REASON")
-		fmt.Fprintln(p.out, colorBold+header+colorReset)
+	if opts.GroupByStatus {
+		p.writeStatusSections(t, podsToShow, opts)
+		fmt.Fprintln(p.out)
+		return
 	}
-	fmt.Fprintln(p.out, strings.Repeat("-", separator))
 
-	// 打印每行
 	for _, pod := range podsToShow {
-		p.printPodRowDynamic(pod, showNamespace, rowFmt, maxNsLen, maxNameLen)
+		p.addPodRow(t, pod, opts)
 	}
+	t.write(p.out)
 
 	fmt.Fprintln(p.out)
 }
 
-// printPodRowDynamic 使用动态格式打印单行 Pod 信息
-func (p *Printer) printPodRowDynamic(pod analyzer.PodAnalysis, showNamespace bool, rowFmt string, maxNsLen, maxNameLen int) {
-	// 状态颜色
-	statusColor := p.getStatusColor(pod.Status)
+// statusSections 是 --group-by status 的分段顺序，最严重的在前
+// Unknown 排在 Healthy 之前，避免状态未知的 Pod 淹没在健康的 Pod 中
+var statusSections = []analyzer.PodStatus{
+	analyzer.StatusError,
+	analyzer.StatusWarning,
+	analyzer.StatusPending,
+	analyzer.StatusUnknown,
+	analyzer.StatusHealthy,
+}
 
-	// 状态图标
-	statusIcon := p.getStatusIcon(pod.Status)
+// writeStatusSections 按状态分段输出 Pod 表格，每段有标题和数量，段内保持原有顺序，没有 Pod 的状态不输出
+func (p *Printer) writeStatusSections(t *table, pods []analyzer.PodAnalysis, opts TableOptions) {
+	type span struct {
+		status     analyzer.PodStatus
+		start, end int
+	}
+	var spans []span
+	for _, status := range statusSections {
+		start := len(t.rows)
+		for _, pod := range pods {
+			if pod.Status == status {
+				p.addPodRow(t, pod, opts)
+			}
+		}
+		if len(t.rows) > start {
+			spans = append(spans, span{status, start, len(t.rows)})
+		}
+	}
 
-	// 格式化 reason
-	reason := pod.Reason
+	sections := make([]tableSection, 0, len(spans))
+	for _, s := range spans {
+		// addPodRow 每个 Pod 只添加一行（子行在 tableRow.lines 中），行数即 Pod 数
+		title := fmt.Sprintf("%s%s%s (%d)%s", colorBold+p.getStatusColor(s.status), p.getStatusIcon(s.status), s.status, s.end-s.start, colorReset)
+		sections = append(sections, tableSection{title: title, rows: t.rows[s.start:s.end]})
+	}
+	t.writeSections(p.out, sections)
+}
 
+// Pod 表格的列宽限制
+const (
+	maxPodNameWidth   = 60
+	maxNamespaceWidth = 25
+	maxOwnerWidth     = 20
+	// podTableRuleTail 是分隔线在 REASON 列中延伸的长度
+	podTableRuleTail = 17
+)
+
+// addPodRow 将一个 Pod 追加为表格的一行，配置问题、缺失数据和终止消息作为子行
+func (p *Printer) addPodRow(t *table, pod analyzer.PodAnalysis, opts TableOptions) {
 	// ECI 标记：区分实际运行位置和配置
 	// ECI  = 实际运行在 ECI 节点上
 	// eci* = 有 ECI 配置但运行在普通节点
 	// -    = 与 ECI 无关
-	eciMark := "-"
+	eciCell := plain("-")
 	if pod.RunningOnECI {
-		eciMark = colorCyan + "ECI" + colorReset
+		// 自定义虚拟节点规则匹配时显示规则名称
+		mark := "ECI"
+		if pod.Provider != "" && pod.Provider != "eci" {
+			mark = truncate(pod.Provider, 5)
+		}
+		eciCell = colored(colorCyan, mark)
 	} else if pod.HasECIConfig {
-		eciMark = colorYellow + "eci*" + colorReset
+		eciCell = colored(colorYellow, "eci*")
 	}
 
-	// 配置问题标记
+	// 配置问题标记：逐条列出时只标记，否则在行尾显示问题的简写
 	configMark := ""
 	if len(pod.ConfigIssues) > 0 {
 		configMark = colorYellow + " ⚙" + colorReset
+		if !opts.VerboseIssues {
+			configMark = issueColor(mostSevereIssue(pod.ConfigIssues)) + " " + issueMarker(pod.ConfigIssues, opts.MaxIssueLines) + colorReset
+		}
 	}
 
-	// 处理名称截断（仅在超过最大宽度时）
-	displayName := pod.Name
-	if len(displayName) > maxNameLen {
-		displayName = displayName[:maxNameLen-3] + "..."
+	// 运行在已 cordon 节点上的 Pod 名称以品红色显示
+	nameCell := plain(truncate(pod.Name, maxPodNameWidth))
+	if pod.OnCordonedNode() {
+		nameCell.color = colorMagenta
 	}
 
-	displayNs := pod.Namespace
-	if len(displayNs) > maxNsLen {
-		displayNs = displayNs[:maxNsLen-3] + "..."
+	var cells []cell
+	if opts.ShowNamespace {
+		cells = append(cells, plain(truncate(pod.Namespace, maxNamespaceWidth)))
 	}
+	cells = append(cells,
+		nameCell,
+		colored(p.getStatusColor(pod.Status), p.getStatusIcon(pod.Status)+string(pod.Status)),
+		plain(pod.Ready),
+		plain(fmt.Sprint(pod.Restarts)),
+		plain(pod.Age),
+		plain(pod.RunningTime),
+	)
+	if opts.Wide {
+		cells = append(cells, plain(formatLatency(pod.SchedulingLatency)), plain(formatEfficiency(pod.EfficiencyScore)))
+	}
+	cells = append(cells, eciCell)
 
-	// 打印主行
-	if showNamespace {
-		fmt.Fprintf(p.out, rowFmt+"\n",
-			displayNs,
-			displayName,
-			statusColor,
-			statusIcon+string(pod.Status),
-			colorReset,
-			pod.Ready,
-			pod.Restarts,
-			pod.Age,
-			pod.RunningTime,
-			eciMark,
-			reason,
-			configMark,
-		)
-	} else {
-		fmt.Fprintf(p.out, rowFmt+"\n",
-			displayName,
-			statusColor,
-			statusIcon+string(pod.Status),
-			colorReset,
-			pod.Ready,
-			pod.Restarts,
-			pod.Age,
-			pod.RunningTime,
-			eciMark,
-			reason,
-			configMark,
-		)
+	// PDB 列：允许的中断数，Pod 健康但不允许任何中断时标红
+	if opts.ShowPDB {
+		pdbCell := plain("-")
+		if pod.PDBDisruptionsAllowed >= 0 {
+			pdbCell = plain(fmt.Sprint(pod.PDBDisruptionsAllowed))
+			if pod.PDBDisruptionsAllowed == 0 && pod.Status == analyzer.StatusHealthy {
+				pdbCell.color = colorRed
+			}
+		}
+		cells = append(cells, pdbCell)
+	}
+	if opts.ShowOwner {
+		owner := "-"
+		if pod.OwnerContact != "" {
+			owner = truncate(pod.OwnerContact, maxOwnerWidth)
+		}
+		cells = append(cells, plain(owner))
 	}
+	cells = append(cells, plain(pod.Reason+configMark))
 
-	// 如果有配置问题，打印详情
-	if len(pod.ConfigIssues) > 0 {
-		for _, issue := range pod.ConfigIssues {
-			fmt.Fprintf(p.out, "  %s└─ %s%s\n", colorYellow, issue, colorReset)
+	row := t.addRow(cells...)
+
+	// --verbose-issues 时打印配置问题详情，超过上限的折叠为一行
+	if opts.VerboseIssues {
+		row.lines = append(row.lines, issueLines(pod.ConfigIssues, opts.MaxIssueLines)...)
+	}
+
+	// 额外请求的配额用完时，说明部分检查没有数据，而不是静默地缺失
+	if pod.DetailsOmitted {
+		row.lines = append(row.lines, fmt.Sprintf("  %s└─ details omitted (budget)%s", colorCyan, colorReset))
+	}
+
+	// 有问题的 Pod 打印容器的终止消息，它通常就是真正的错误信息
+	if pod.Status != analyzer.StatusHealthy {
+		for _, c := range pod.ContainerInfo {
+			if c.TerminationMessage != "" {
+				row.lines = append(row.lines, fmt.Sprintf("  %s└─ %s: %s%s", colorRed, c.Name, truncate(c.TerminationMessage, 120), colorReset))
+			}
 		}
 	}
 }
@@ -205,6 +301,10 @@ func (p *Printer) PrintSummary(result *analyzer.AnalysisResult) {
 
 	fmt.Fprintf(p.out, "Total Restarts: %d\n", result.TotalRestarts)
 
+	if line := containerSummaryLine(result.Containers); line != "" {
+		fmt.Fprintf(p.out, "Containers:     %s\n", line)
+	}
+
 	// ECI 统计 - 区分实际运行和有配置的
 	if result.RunningOnECICount > 0 || result.HasECIConfigCount > 0 {
 		fmt.Fprintln(p.out)
@@ -229,10 +329,65 @@ func (p *Printer) PrintSummary(result *analyzer.AnalysisResult) {
 	if result.ConfigIssueCount > 0 {
 		fmt.Fprintf(p.out, "%sConfig Issues:  %d%s\n", colorYellow, result.ConfigIssueCount, colorReset)
 	}
+	if line := retainedPodsLine(result.RetainedPods); line != "" {
+		fmt.Fprintf(p.out, "Cleanup:        %s\n", line)
+	}
+
+	// 准入失败按节点汇总，通常集中在少数节点上
+	if len(result.AdmissionFailures) > 0 {
+		fmt.Fprintln(p.out)
+		fmt.Fprintln(p.out, colorBold+"Admission Failures:"+colorReset)
+		for _, node := range sortedKeys(result.AdmissionFailures) {
+			reasons := result.AdmissionFailures[node]
+			var parts []string
+			for _, reason := range sortedKeys(reasons) {
+				parts = append(parts, fmt.Sprintf("%d %s", reasons[reason], reason))
+			}
+			nodeName := node
+			if nodeName == "" {
+				nodeName = "<unknown>"
+			}
+			fmt.Fprintf(p.out, "  %snode %s: %s failures%s\n", colorRed, nodeName, strings.Join(parts, ", "), colorReset)
+		}
+	}
 
 	fmt.Fprintln(p.out)
 }
 
+// containerSummaryLine 生成如 "412 running, 9 waiting (6 ImagePullBackOff, 3 CrashLoopBackOff)" 的容器汇总
+// waiting 原因按数量从多到少排列
+func containerSummaryLine(s analyzer.ContainerSummary) string {
+	var parts []string
+	if s.Running > 0 {
+		parts = append(parts, fmt.Sprintf("%d running", s.Running))
+	}
+	if s.Waiting > 0 {
+		reasons := sortedKeys(s.WaitingReasons)
+		sort.SliceStable(reasons, func(i, j int) bool {
+			return s.WaitingReasons[reasons[i]] > s.WaitingReasons[reasons[j]]
+		})
+		var counts []string
+		for _, reason := range reasons {
+			counts = append(counts, fmt.Sprintf("%d %s", s.WaitingReasons[reason], reason))
+		}
+		parts = append(parts, fmt.Sprintf("%d waiting (%s)", s.Waiting, strings.Join(counts, ", ")))
+	}
+	if s.Terminated > 0 {
+		parts = append(parts, fmt.Sprintf("%d terminated", s.Terminated))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// sortedKeys 返回 map 按字典序排序的键
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 // PrintRecommendations 打印改进建议
 func (p *Printer) PrintRecommendations(result *analyzer.AnalysisResult) {
 	fmt.Fprintln(p.out, colorBold+"💡 Recommendations"+colorReset)
@@ -242,48 +397,785 @@ func (p *Printer) PrintRecommendations(result *analyzer.AnalysisResult) {
 
 	for _, pod := range result.Pods {
 		// 基于状态的建议
+		if pod.AdmissionFailure {
+			f := lookupFinding("admission-rejected")
+			recommendations[f.recommendation(p, pod, "")] = true
+			recommendations[strings.ReplaceAll(f.fix, "<namespace>", pod.Namespace)] = true
+			continue
+		}
+
+		var reasons []string
 		switch pod.Status {
 		case analyzer.StatusError:
-			recommendations["Check pod events: kubectl describe pod "+pod.Name] = true
+			reasons = append(reasons, "pod-error")
 		case analyzer.StatusPending:
-			if strings.Contains(pod.Reason, "Unschedulable") {
-				recommendations["Check node resources and taints"] = true
+			if pod.MissingNodeLabel != nil {
+				reasons = append(reasons, "missing-node-label")
+			} else if strings.Contains(pod.Reason, "Unschedulable") {
+				reasons = append(reasons, "Unschedulable")
 			}
 			if strings.Contains(pod.Reason, "ImagePull") {
-				recommendations["Verify image name and pull secrets"] = true
+				reasons = append(reasons, "ImagePullBackOff")
 			}
 		case analyzer.StatusWarning:
 			if pod.Restarts > 10 {
-				recommendations["Investigate high restart count - check logs: kubectl logs "+pod.Name+" --previous"] = true
+				reasons = append(reasons, "high-restarts")
 			}
 			if strings.Contains(pod.Reason, "CrashLoopBackOff") {
-				recommendations["Container keeps crashing - check application logs and resource limits"] = true
+				reasons = append(reasons, "CrashLoopBackOff")
 			}
 		}
+		for _, id := range reasons {
+			recommendations[lookupFinding(id).recommendation(p, pod, "")] = true
+		}
 
 		// 基于配置问题的建议
 		for _, issue := range pod.ConfigIssues {
-			switch issue {
-			case analyzer.IssueMissingRequests:
-				recommendations["Set resource requests to enable proper scheduling"] = true
-			case analyzer.IssueMissingLimits:
-				recommendations["Set resource limits to prevent resource exhaustion"] = true
-			case analyzer.IssueNoProbe:
-				recommendations["Add liveness/readiness probes for better health checking"] = true
+			if f := issueFinding(issue); f != nil && !f.quiet {
+				recommendations[f.recommendation(p, pod, issue)] = true
 			}
 		}
 	}
 
+	// 已结束的 Pod 按命名空间给出清理命令，少量保留的 Pod 不值得单独提醒
+	for _, retained := range result.RetainedPods {
+		if !p.CleanupPlan && retained.Total() < analyzer.RetainedPodsThreshold {
+			continue
+		}
+		recommendations[fmt.Sprintf("Delete %d finished pods in %s: %s",
+			retained.Total(), retained.Namespace, strings.Join(cleanupCommands(retained), " && "))] = true
+	}
+	for _, ns := range result.TerminatingNamespaces {
+		rec := "Namespace " + ns.Name + " is stuck Terminating"
+		if len(ns.Finalizers) > 0 {
+			rec += " - finalizers: " + strings.Join(ns.Finalizers, ", ")
+		}
+		for _, cond := range ns.Conditions {
+			rec += "; " + cond
+		}
+		recommendations[rec] = true
+		recommendations["Remove leftover resources of the listed types (or their finalizers, if the owning controller is gone) to let "+ns.Name+" finish deleting: kubectl get namespace "+ns.Name+" -o yaml"] = true
+	}
+	for _, job := range result.JobsWithoutTTL {
+		recommendations[fmt.Sprintf("Set ttlSecondsAfterFinished on Job %s/%s (%d finished pods retained): %s",
+			job.Namespace, job.Name, job.Pods, ttlPatchCommand(job))] = true
+	}
+
 	if len(recommendations) == 0 {
 		fmt.Fprintln(p.out, colorGreen+"  ✓ No specific recommendations"+colorReset)
 	} else {
-		for rec := range recommendations {
+		for _, rec := range sortedKeys(recommendations) {
 			fmt.Fprintf(p.out, "  • %s\n", rec)
 		}
 	}
 	fmt.Fprintln(p.out)
 }
 
+// PrintAddons 打印集群核心组件健康状况的简要信息
+func (p *Printer) PrintAddons(statuses []analyzer.AddonStatus) {
+	if len(statuses) == 0 {
+		return
+	}
+
+	parts := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		mark := colorGreen + "✓" + colorReset
+		if !s.OK() {
+			mark = colorRed + "✗" + colorReset
+		}
+		parts = append(parts, fmt.Sprintf("%s %d/%d %s", s.Name, s.Healthy, s.Total, mark))
+	}
+
+	fmt.Fprintln(p.out, colorBold+"🧩 Cluster addons"+colorReset)
+	fmt.Fprintf(p.out, "  %s\n\n", strings.Join(parts, ", "))
+}
+
+// PrintTopProblems 打印按问题评分排序的 Pod 列表
+func (p *Printer) PrintTopProblems(pods []analyzer.ScoredPod) {
+	fmt.Fprintln(p.out, colorBold+"🔥 Top Problems"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+
+	if len(pods) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No problem pods found"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	for _, sp := range pods {
+		pod := sp.Pod
+		owner := "-"
+		if pod.OwnerKind != "" {
+			owner = pod.OwnerKind + "/" + pod.OwnerName
+		}
+		fmt.Fprintf(p.out, "%3d. %s%s%-10s%s %5d  %s/%s\n",
+			sp.Rank,
+			p.getStatusColor(pod.Status),
+			p.getStatusIcon(pod.Status),
+			string(pod.Status),
+			colorReset,
+			sp.Score,
+			pod.Namespace,
+			pod.Name,
+		)
+		fmt.Fprintf(p.out, "     owner: %s  restarts: %d  reason: %s\n", owner, pod.Restarts, pod.Reason)
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintNodePressure 打印处于压力状态的节点，放在表格之前
+func (p *Printer) PrintNodePressure(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"🌡  Node Pressure"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.PressuredNodes) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No nodes under pressure"+colorReset)
+	}
+	for _, nodeName := range sortedKeys(result.PressuredNodes) {
+		fmt.Fprintf(p.out, "  %snode %s: %s%s\n", colorRed, nodeName, strings.Join(result.PressuredNodes[nodeName], ", "), colorReset)
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintPreemptions 打印正在抢占节点的 Pending Pod 及可能被驱逐的低优先级 Pod
+func (p *Printer) PrintPreemptions(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"⚔️  Preemption"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.Preemptions) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No pending pods are preempting"+colorReset)
+	}
+	for _, preemption := range result.Preemptions {
+		fmt.Fprintf(p.out, "  %s/%s (priority %d) → node %s\n",
+			preemption.Namespace, preemption.Name, preemption.Priority, preemption.NominatedNode)
+		if len(preemption.Victims) == 0 {
+			fmt.Fprintln(p.out, "    no lower-priority pods on the node")
+		}
+		for _, victim := range preemption.Victims {
+			fmt.Fprintf(p.out, "    %s└─ %s/%s (priority %d)%s\n", colorYellow, victim.Namespace, victim.Name, victim.Priority, colorReset)
+		}
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintLivenessCascade 打印多个工作负载在同一时间窗口内被存活探针重启的集群级警告
+func (p *Printer) PrintLivenessCascade(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"🔁 Liveness Probe Cascade"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	c := result.LivenessCascade
+	if c == nil {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No correlated liveness probe restarts across workloads"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+	fmt.Fprintf(p.out, "  %s⚠ %d liveness probe restarts across %d workloads between %s and %s%s\n",
+		colorYellow, c.Kills, len(c.Workloads), c.Start.Local().Format("15:04:05"), c.End.Local().Format("15:04:05"), colorReset)
+	for _, workload := range c.Workloads {
+		fmt.Fprintf(p.out, "    └─ %s\n", workload)
+	}
+	fmt.Fprintln(p.out, "  Liveness probes that check downstream dependencies restart healthy pods when a dependency is down.")
+	fmt.Fprintln(p.out, "  Keep livenessProbe to the process itself and move dependency checks to readinessProbe.")
+	fmt.Fprintln(p.out)
+}
+
+// PrintQuotaRollouts 打印剩余副本会耗尽命名空间配额的滚动更新，并列出计算过程
+func (p *Printer) PrintQuotaRollouts(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"📦 Rollouts vs ResourceQuota"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.QuotaRollouts) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No in-progress rollout is short of quota"+colorReset)
+	}
+	for _, q := range result.QuotaRollouts {
+		available, needed := q.Available(), q.Needed()
+		var verdict string
+		switch q.Fits {
+		case 0:
+			verdict = "no new pod fits in the remaining quota"
+		case 1:
+			verdict = "rollout will exhaust quota after 1 more pod"
+		default:
+			verdict = fmt.Sprintf("rollout will exhaust quota after %d more pods", q.Fits)
+		}
+		fmt.Fprintf(p.out, "  %s⚠ %s/%s: %s%s\n", colorYellow, q.Namespace, q.Deployment, verdict, colorReset)
+		fmt.Fprintf(p.out, "    └─ %s left × %s %s = %s, quota %s has %s left (hard %s, used %s)\n",
+			pluralizePods(q.Remaining), q.Resource, q.PerPod.String(), needed.String(), q.Quota, available.String(), q.Hard.String(), q.Used.String())
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintDuplicates 打印在多个命名空间中重复出现的工作负载，仅供参考
+func (p *Printer) PrintDuplicates(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"🧬 Possible Duplicates"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.Duplicates) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No workloads found in more than one namespace"+colorReset)
+	}
+	for _, d := range result.Duplicates {
+		fmt.Fprintf(p.out, "  %ssame %s: %s%s\n", colorCyan, d.MatchedBy, d.Key, colorReset)
+		for _, instance := range d.Instances {
+			fmt.Fprintf(p.out, "    └─ %s %s/%s  %s  age %s\n",
+				instance.Kind, instance.Namespace, instance.Name, pluralizeReplicas(instance.Replicas), instance.Age)
+		}
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintDeploys 打印每个 Deployment 最近一次发布的时间，问题在发布之后开始的标黄
+func (p *Printer) PrintDeploys(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"🚀 Last Deploys"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.Deploys) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No Deployment pods found"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	t := &table{
+		headerColor: colorBold,
+		columns: []column{
+			{header: "WORKLOAD", gap: 2},
+			{header: "DEPLOYED", gap: 2},
+			{header: "ISSUES"},
+		},
+	}
+	for _, d := range result.Deploys {
+		issues := plain("-")
+		if note := d.IssuesNote(); note != "" {
+			issues = plain(note)
+			if _, ok := d.IssuesAfterDeploy(); ok {
+				issues.color = colorYellow
+			}
+		}
+		t.addRow(plain(d.Namespace+"/"+d.Name), plain(d.Deployed), issues)
+	}
+	t.write(p.out)
+	fmt.Fprintln(p.out)
+}
+
+// pluralizeReplicas 返回 "1 replica"、"3 replicas"
+func pluralizeReplicas(n int) string {
+	if n == 1 {
+		return "1 replica"
+	}
+	return fmt.Sprintf("%d replicas", n)
+}
+
+// pluralizePods 返回 "1 pod"、"3 pods"
+func pluralizePods(n int) string {
+	if n == 1 {
+		return "1 pod"
+	}
+	return fmt.Sprintf("%d pods", n)
+}
+
+// PrintTerminatingNamespaces 在 Pod 表格上方为每个 Terminating 的命名空间打印横幅，没有时不输出
+func (p *Printer) PrintTerminatingNamespaces(result *analyzer.AnalysisResult) {
+	for _, ns := range result.TerminatingNamespaces {
+		line := fmt.Sprintf("🛑 Namespace '%s' is Terminating", ns.Name)
+		if ns.Age != "" {
+			line += " for " + ns.Age
+		}
+		fmt.Fprintln(p.out, colorRed+colorBold+line+colorReset)
+		fmt.Fprintln(p.out, "   pods here cannot be recreated until deletion finishes")
+	}
+	if len(result.TerminatingNamespaces) > 0 {
+		fmt.Fprintln(p.out)
+	}
+}
+
+// PrintNamespaceSummary 按命名空间打印 Pod 状态统计，failed 中的命名空间标记为获取失败
+func (p *Printer) PrintNamespaceSummary(summaries []analyzer.NamespaceSummary, failed map[string]error) {
+	fmt.Fprintln(p.out, colorBold+"🗂  Namespaces"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+
+	t := &table{
+		headerColor: colorBold,
+		columns: []column{
+			{header: "NAMESPACE", gap: 2},
+			{header: "PODS", width: 5},
+			{header: "HEALTHY", width: 8},
+			{header: "WARNING", width: 8},
+			{header: "ERROR", width: 6},
+			{header: "PENDING", width: 8},
+			{header: "RESTARTS"},
+		},
+	}
+	for _, s := range summaries {
+		if err, ok := failed[s.Namespace]; ok {
+			t.addRow(plain(s.Namespace), plain(fmt.Sprintf("fetch failed: %v", err))).color = colorRed
+			continue
+		}
+		row := t.addRow(plain(s.Namespace), plain(fmt.Sprint(s.Total)), plain(fmt.Sprint(s.Healthy)), plain(fmt.Sprint(s.Warning)),
+			plain(fmt.Sprint(s.Error)), plain(fmt.Sprint(s.Pending)), plain(fmt.Sprint(s.Restarts)))
+		switch {
+		case s.Error > 0:
+			row.color = colorRed
+		case s.Warning > 0 || s.Pending > 0:
+			row.color = colorYellow
+		}
+	}
+	t.write(p.out)
+	fmt.Fprintln(p.out)
+}
+
+// eciCSVHeader 是 ECI 实例 CSV 的表头
+var eciCSVHeader = []string{"namespace", "pod", "instance_id", "zone", "vswitch", "spec", "age", "status"}
+
+// PrintECIInstancesCSV 以 CSV 输出 ECI 实例，每个 Pod 一行，没有实例 ID 的 Pod 该列为空
+func (p *Printer) PrintECIInstancesCSV(instances []analyzer.ECIInstance) error {
+	w := csv.NewWriter(p.out)
+	if err := w.Write(eciCSVHeader); err != nil {
+		return err
+	}
+	for _, e := range instances {
+		row := []string{e.Namespace, e.Pod, e.InstanceID, e.Zone, e.VSwitch, e.Spec, e.Age, string(e.Status)}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// PrintECIInstancesJSON 以 JSON 数组输出 ECI 实例，字段见 analysisv1.ECIInstance
+func (p *Printer) PrintECIInstancesJSON(instances []analyzer.ECIInstance) error {
+	out := make([]analysisv1.ECIInstance, 0, len(instances))
+	for _, e := range instances {
+		out = append(out, e.ToV1())
+	}
+	enc := json.NewEncoder(p.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// PrintRunStats 打印一次运行的统计信息：各阶段耗时、API 请求数、接收的字节数和处理速度
+func (p *Printer) PrintRunStats(stats *analyzer.RunStats) {
+	if stats == nil {
+		return
+	}
+	fmt.Fprintln(p.out, colorBold+"📊 Run Statistics"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+
+	phases := make([]string, 0, len(stats.Phases))
+	for _, phase := range stats.Phases {
+		phases = append(phases, phase.Name+" "+roundDuration(phase.Duration).String())
+	}
+	fmt.Fprintf(p.out, "  Phases:      %s (total %s)\n", strings.Join(phases, ", "), roundDuration(stats.Duration()))
+
+	// 请求多的类型在前，次数相同时按名称排序
+	kinds := sortedKeys(stats.Requests)
+	sort.SliceStable(kinds, func(i, j int) bool {
+		return stats.Requests[kinds[i]] > stats.Requests[kinds[j]]
+	})
+	requests := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		requests = append(requests, fmt.Sprintf("%s %d", kind, stats.Requests[kind]))
+	}
+	line := fmt.Sprintf("%d", stats.TotalRequests())
+	if len(requests) > 0 {
+		line += " (" + strings.Join(requests, ", ") + ")"
+	}
+	fmt.Fprintf(p.out, "  Requests:    %s\n", line)
+	if stats.BytesReceived > 0 {
+		fmt.Fprintf(p.out, "  Received:    %s\n", formatBytes(stats.BytesReceived))
+	}
+	fmt.Fprintf(p.out, "  Throughput:  %.1f pods/s (%d pods)\n", stats.PodsPerSecond(), stats.Pods)
+	fmt.Fprintln(p.out)
+}
+
+// formatLatency 将调度耗时格式化到秒，如 "2m5s"，为负（还未调度）时为 "-"
+func formatLatency(d time.Duration) string {
+	if d < 0 {
+		return "-"
+	}
+	return d.Round(time.Second).String()
+}
+
+// formatEfficiency 将资源利用率格式化为百分比，没有数据时为 "-"
+func formatEfficiency(score float64) string {
+	if score < 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", score*100)
+}
+
+// roundDuration 将耗时舍入到便于阅读的精度：1 毫秒以下保留微秒，其余保留毫秒
+func roundDuration(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
+
+// formatBytes 以二进制单位格式化字节数，如 "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KiB"
+	for _, next := range []string{"MiB", "GiB"} {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// PrintCleanupPlan 打印清理已结束 Pod 的完整命令，只打印不执行
+func (p *Printer) PrintCleanupPlan(result *analyzer.AnalysisResult) {
+	fmt.Fprintln(p.out, colorBold+"🧹 Cleanup Plan (printed, not executed)"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	if len(result.RetainedPods) == 0 && len(result.JobsWithoutTTL) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No finished pods retained"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	for _, retained := range result.RetainedPods {
+		comment := fmt.Sprintf("%s: %d Succeeded, %d Failed", retained.Namespace, retained.Succeeded, retained.Failed)
+		if retained.Evicted > 0 {
+			comment += fmt.Sprintf(" (%d Evicted)", retained.Evicted)
+		}
+		fmt.Fprintf(p.out, "  %s# %s%s\n", colorCyan, comment, colorReset)
+		for _, command := range cleanupCommands(retained) {
+			fmt.Fprintf(p.out, "  %s\n", command)
+		}
+	}
+
+	if len(result.JobsWithoutTTL) > 0 {
+		fmt.Fprintf(p.out, "  %s# Jobs without ttlSecondsAfterFinished (the TTL controller then deletes the Job and its pods)%s\n", colorCyan, colorReset)
+		for _, job := range result.JobsWithoutTTL {
+			fmt.Fprintf(p.out, "  %s  # %d finished pods\n", ttlPatchCommand(job), job.Pods)
+		}
+	}
+	fmt.Fprintln(p.out)
+}
+
+// suggestedJobTTL 是建议为 Job 设置的 ttlSecondsAfterFinished（一天）
+const suggestedJobTTL = 86400
+
+// cleanupCommands 返回删除命名空间中已结束 Pod 的命令
+func cleanupCommands(retained analyzer.RetainedPods) []string {
+	var commands []string
+	if retained.Succeeded > 0 {
+		commands = append(commands, "kubectl delete pods -n "+retained.Namespace+" --field-selector=status.phase==Succeeded")
+	}
+	if retained.Failed > 0 {
+		commands = append(commands, "kubectl delete pods -n "+retained.Namespace+" --field-selector=status.phase==Failed")
+	}
+	return commands
+}
+
+// ttlPatchCommand 返回为 Job 设置 ttlSecondsAfterFinished 的命令
+func ttlPatchCommand(job analyzer.RetainingJob) string {
+	return fmt.Sprintf(`kubectl patch job -n %s %s --type=merge -p '{"spec":{"ttlSecondsAfterFinished":%d}}'`,
+		job.Namespace, job.Name, suggestedJobTTL)
+}
+
+// retainedPodsLine 返回汇总中已结束 Pod 的描述，没有时返回空字符串
+func retainedPodsLine(retained []analyzer.RetainedPods) string {
+	total := 0
+	for _, r := range retained {
+		total += r.Total()
+	}
+	if total == 0 {
+		return ""
+	}
+	line := "1 finished pod retained"
+	if total > 1 {
+		line = fmt.Sprintf("%d finished pods retained", total)
+	}
+	if len(retained) > 1 {
+		line += fmt.Sprintf(" across %d namespaces", len(retained))
+	}
+	return line
+}
+
+// PrintPodDetail 打印单个 Pod 的详细信息和生命周期时间线
+func (p *Printer) PrintPodDetail(pod analyzer.PodAnalysis, timeline []analyzer.TimelineEvent) {
+	fmt.Fprintf(p.out, "%s%s/%s%s\n", colorBold, pod.Namespace, pod.Name, colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	fmt.Fprintf(p.out, "Status:   %s%s%s%s\n", p.getStatusColor(pod.Status), p.getStatusIcon(pod.Status), pod.Status, colorReset)
+	if pod.Reason != "" {
+		fmt.Fprintf(p.out, "Reason:   %s\n", pod.Reason)
+	}
+	fmt.Fprintf(p.out, "Ready:    %s\n", pod.Ready)
+	fmt.Fprintf(p.out, "Restarts: %d\n", pod.Restarts)
+	fmt.Fprintf(p.out, "Age:      %s\n", pod.Age)
+	if pod.NodeName != "" {
+		fmt.Fprintf(p.out, "Node:     %s\n", pod.NodeName)
+	}
+	if pod.OwnerKind != "" {
+		fmt.Fprintf(p.out, "Owner:    %s/%s\n", pod.OwnerKind, pod.OwnerName)
+	}
+	if pod.OwnerKind == "Job" {
+		fmt.Fprintf(p.out, "Restart:  %s (%s)\n", pod.RestartPolicy, analyzer.JobRestartSemantics(pod.RestartPolicy))
+	}
+	fmt.Fprintln(p.out)
+
+	// 详情视图总是列出全部配置问题
+	if len(pod.ConfigIssues) > 0 {
+		fmt.Fprintln(p.out, colorBold+"⚙  Config Issues"+colorReset)
+		fmt.Fprintln(p.out, strings.Repeat("-", 40))
+		for _, issue := range pod.ConfigIssues {
+			fmt.Fprintf(p.out, "  %s%s%s\n", issueColor(issue), issue, colorReset)
+		}
+		fmt.Fprintln(p.out)
+	}
+
+	fmt.Fprintln(p.out, colorBold+"⏱  Timeline"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+	for i, event := range timeline {
+		line := fmt.Sprintf("  %s  %s", event.Time.Local().Format("2006-01-02 15:04:05"), event.Name)
+		if i > 0 {
+			line += fmt.Sprintf(" (+%s)", event.Since)
+		}
+		if event.Slowest {
+			line = colorYellow + line + "  ← slowest" + colorReset
+		}
+		fmt.Fprintln(p.out, line)
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintECICandidates 打印适合迁移到 ECI 的 Pod 列表
+func (p *Printer) PrintECICandidates(candidates []analyzer.ECICandidate) {
+	fmt.Fprintln(p.out, colorBold+"☁️  ECI Offload Candidates"+colorReset)
+	fmt.Fprintln(p.out, strings.Repeat("-", 40))
+
+	if len(candidates) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No ECI candidates found"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	for _, c := range candidates {
+		fitColor := colorYellow
+		if c.Fit == "good" {
+			fitColor = colorGreen
+		}
+		fmt.Fprintf(p.out, "%3d. %s%-8s%s %4d  %s/%s\n", c.Rank, fitColor, c.Fit, colorReset, c.Score, c.Namespace, c.Name)
+		if c.OwnerKind != "" {
+			fmt.Fprintf(p.out, "     owner: %s/%s\n", c.OwnerKind, c.OwnerName)
+		}
+		fmt.Fprintf(p.out, "     why:   %s\n", strings.Join(c.Reasons, "; "))
+		for _, k := range sortedKeys(c.Labels) {
+			fmt.Fprintf(p.out, "     label: %s: %q\n", k, c.Labels[k])
+		}
+		for _, k := range sortedKeys(c.Annotations) {
+			fmt.Fprintf(p.out, "     annotation: %s: %q\n", k, c.Annotations[k])
+		}
+	}
+	fmt.Fprintln(p.out)
+}
+
+// PrintCronJobTable 打印 CronJob 调度健康状况表格
+func (p *Printer) PrintCronJobTable(results []analyzer.CronJobAnalysis, showNamespace bool) {
+	if len(results) == 0 {
+		fmt.Fprintln(p.out, colorGreen+"  ✓ No CronJobs found"+colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	t := &table{headerColor: colorBold, rule: true, ruleTail: 5}
+	if showNamespace {
+		t.columns = append(t.columns, column{header: "NAMESPACE", gap: 2})
+	}
+	t.columns = append(t.columns,
+		column{header: "NAME", gap: 2},
+		column{header: "SCHEDULE", gap: 2},
+		column{header: "STATUS", width: 12},
+		column{header: "LAST SUCCESS", width: 14},
+		column{header: "LAST FAILURE", width: 14},
+		column{header: "ACTIVE", width: 7},
+		column{header: "REASON"},
+	)
+
+	for _, cj := range results {
+		var cells []cell
+		if showNamespace {
+			cells = append(cells, plain(cj.Namespace))
+		}
+		cells = append(cells,
+			plain(cj.Name),
+			plain(cj.Schedule),
+			colored(p.getCronJobStatusColor(cj.Status), string(cj.Status)),
+			plain(cj.LastSuccess),
+			plain(cj.LastFailure),
+			plain(fmt.Sprint(cj.Active)),
+			plain(cj.Reason),
+		)
+		t.addRow(cells...)
+	}
+	t.write(p.out)
+
+	fmt.Fprintln(p.out)
+}
+
+// PrintWorkloadTemplates 打印工作负载 Pod 模板的检查结果，每个工作负载一行，默认只显示有配置问题的工作负载
+func (p *Printer) PrintWorkloadTemplates(results []analyzer.WorkloadAnalysis, opts TableOptions) {
+	var shown []analyzer.WorkloadAnalysis
+	for _, w := range results {
+		if opts.ShowAll || len(w.ConfigIssues) > 0 {
+			shown = append(shown, w)
+		}
+	}
+	if len(shown) == 0 {
+		fmt.Fprintf(p.out, "%s  ✓ No config issues found in %d workload templates%s\n", colorGreen, len(results), colorReset)
+		fmt.Fprintln(p.out)
+		return
+	}
+
+	t := &table{headerColor: colorBold, rule: true, ruleTail: 5}
+	if opts.ShowNamespace {
+		t.columns = append(t.columns, column{header: "NAMESPACE", maxWidth: maxNamespaceWidth, gap: 2})
+	}
+	t.columns = append(t.columns,
+		column{header: "KIND", width: 13},
+		column{header: "NAME", maxWidth: maxPodNameWidth, gap: 2},
+		column{header: "REPLICAS", width: 10},
+		column{header: "ISSUES"},
+	)
+
+	withIssues := 0
+	for _, w := range shown {
+		var cells []cell
+		if opts.ShowNamespace {
+			cells = append(cells, plain(truncate(w.Namespace, maxNamespaceWidth)))
+		}
+		// CronJob 没有副本数
+		replicas := "-"
+		if w.Replicas >= 0 {
+			replicas = fmt.Sprint(w.Replicas)
+		}
+		issues := colored(colorGreen, "✓")
+		if len(w.ConfigIssues) > 0 {
+			withIssues++
+			issues = colored(issueColor(mostSevereIssue(w.ConfigIssues)), issueMarker(w.ConfigIssues, opts.MaxIssueLines))
+			// 逐条列出时只标记，与 Pod 表格一致
+			if opts.VerboseIssues {
+				issues = colored(colorYellow, "⚙")
+			}
+		}
+		cells = append(cells, plain(w.Kind), plain(truncate(w.Name, maxPodNameWidth)), plain(replicas), issues)
+
+		row := t.addRow(cells...)
+		if opts.VerboseIssues {
+			row.lines = append(row.lines, issueLines(w.ConfigIssues, opts.MaxIssueLines)...)
+		}
+	}
+	t.write(p.out)
+
+	fmt.Fprintln(p.out)
+	fmt.Fprintf(p.out, "⚙ %d of %d workload templates have config issues\n", withIssues, len(results))
+}
+
+// issueLines 返回逐条列出配置问题的子行，超过 max 条（max > 0）的部分折叠为 "+N more"
+func issueLines(issues []analyzer.ConfigIssue, max int) []string {
+	shown := issues
+	if max > 0 && len(shown) > max {
+		shown = shown[:max]
+	}
+	var lines []string
+	for _, issue := range shown {
+		lines = append(lines, fmt.Sprintf("  %s└─ %s%s", issueColor(issue), issue, colorReset))
+	}
+	if more := len(issues) - len(shown); more > 0 {
+		lines = append(lines, fmt.Sprintf("  %s└─ +%d more%s", colorYellow, more, colorReset))
+	}
+	return lines
+}
+
+// getCronJobStatusColor 返回 CronJob 状态对应的颜色代码
+func (p *Printer) getCronJobStatusColor(status analyzer.CronJobStatus) string {
+	switch status {
+	case analyzer.CronJobHealthy:
+		return colorGreen
+	case analyzer.CronJobLate:
+		return colorYellow
+	case analyzer.CronJobFailing:
+		return colorRed
+	case analyzer.CronJobSuspended:
+		return colorBlue
+	default:
+		return colorReset
+	}
+}
+
+// missingNodeLabelRecommendation 建议修改选择了不存在的节点标签的工作负载，并列出最接近的现有取值或键
+func missingNodeLabelRecommendation(pod analyzer.PodAnalysis, m *analyzer.MissingNodeLabel) string {
+	target := "pod " + pod.Name
+	if pod.OwnerKind != "" {
+		target = pod.OwnerKind + " " + pod.OwnerName
+	}
+	rec := "Update the nodeSelector/nodeAffinity of " + target + " - no node has label " + m.String()
+	switch {
+	case len(m.Closest) == 0:
+		return rec
+	case len(m.Values) == 0:
+		return rec + "; closest existing keys: " + strings.Join(m.Closest, ", ")
+	default:
+		return rec + "; closest existing values for " + m.Key + ": " + strings.Join(m.Closest, ", ")
+	}
+}
+
+// containerFlag 为多容器 Pod 生成指向主容器的 -c 参数
+func containerFlag(pod analyzer.PodAnalysis) string {
+	if len(pod.ContainerInfo) <= 1 || pod.MainContainer == "" {
+		return ""
+	}
+	return " -c " + pod.MainContainer
+}
+
+// issueColor 返回配置问题按严重程度对应的颜色代码
+func issueColor(issue analyzer.ConfigIssue) string {
+	switch issue.Severity() {
+	case analyzer.SeverityHigh:
+		return colorRed
+	case analyzer.SeverityLow:
+		return colorBlue
+	default:
+		return colorYellow
+	}
+}
+
+// issueTag 返回配置问题在表格行尾的简写，没有简写的问题（如注册的检查）为 "other"
+func issueTag(issue analyzer.ConfigIssue) string {
+	if f := issueFinding(issue); f != nil {
+		return f.tag
+	}
+	return "other"
+}
+
+// issueMarker 返回行尾的配置问题标记，如 "⚙ req,lim,probe"
+// 相同简写只列一次，超过 max 个（max > 0）时折叠为 "+N more"
+func issueMarker(issues []analyzer.ConfigIssue, max int) string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		tag := issueTag(issue)
+		if !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	marker := "⚙ "
+	if max > 0 && len(tags) > max {
+		return marker + strings.Join(tags[:max], ",") + fmt.Sprintf(" +%d more", len(tags)-max)
+	}
+	return marker + strings.Join(tags, ",")
+}
+
+// mostSevereIssue 返回最严重的配置问题，用于决定行尾标记的颜色
+func mostSevereIssue(issues []analyzer.ConfigIssue) analyzer.ConfigIssue {
+	worst := issues[0]
+	for _, issue := range issues[1:] {
+		if issue.Severity() > worst.Severity() {
+			worst = issue
+		}
+	}
+	return worst
+}
+
 // getStatusColor 返回状态对应的颜色代码
 func (p *Printer) getStatusColor(status analyzer.PodStatus) string {
 	switch status {
@@ -0,0 +1,597 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// allConfigIssues 覆盖所有内置的配置问题，用于检查表格和建议的完整输出
+var allConfigIssues = []analyzer.ConfigIssue{
+	analyzer.IssueMissingRequests,
+	analyzer.IssueMissingLimits,
+	analyzer.IssueNoProbe,
+	analyzer.IssueNoTerminationMessage,
+	analyzer.IssueAllReplicasSameZone,
+	analyzer.IssueSelectorMismatch,
+	analyzer.IssueTemplateHashMismatch,
+	analyzer.IssueNoTerminationMessagePolicy,
+	analyzer.IssueStdinEnabled,
+	analyzer.IssueTTYEnabled,
+	analyzer.IssueNoPDB,
+	analyzer.IssueMissingKataOverhead,
+	analyzer.IssueHighSeverityCVE,
+	analyzer.IssueImageDrift,
+	analyzer.IssueSpecDrift,
+	analyzer.IssueTemplateDrift,
+	analyzer.IssueAnnotationTooLarge,
+	analyzer.IssueNoPodFailurePolicy,
+	analyzer.IssueBarePodRestarting,
+	analyzer.IssueUnboundPVC,
+	analyzer.IssueVolumeAttachLimit,
+	analyzer.IssueVolumeExpansionPending,
+	analyzer.IssueWebhookBlocksDeletion,
+	analyzer.IssueNoDefaultDenyPolicy,
+	analyzer.IssueEnvConflictWithInjected,
+	analyzer.IssuePodFinalizer,
+	analyzer.IssueMissingDefaultContainer,
+	analyzer.IssueNameTooLong,
+	analyzer.IssueDNSOverride,
+	analyzer.IssueDNSDefaultPolicy,
+	analyzer.IssueNdotsExternalLookups,
+	analyzer.IssueDNSWithHostNetwork,
+	analyzer.IssueNodeCordoned,
+	analyzer.IssueScheduledAfterCordon,
+	analyzer.IssueDaemonSetAfterCordon,
+	analyzer.IssuePullSecretExpiring,
+	analyzer.IssueInvalidImagePullSecretType,
+	analyzer.IssueMissingRequiredLabels,
+	analyzer.IssueStaleReadyCondition,
+	analyzer.IssueInitTimeout,
+	analyzer.IssueHighSchedulingLatency,
+	analyzer.IssueServiceTargetPortMismatch,
+	analyzer.IssueServicePortUnverified,
+	analyzer.IssueOverProvisionedCPU,
+	analyzer.IssueOverProvisionedMemory,
+	analyzer.IssuePSPViolation,
+	analyzer.IssueEnvVarConflict,
+	analyzer.IssueConfigMapMutable,
+	analyzer.IssueSecretMutable,
+	analyzer.IssueStaleConfigHash,
+	analyzer.IssueSecretInEnv,
+	analyzer.IssueRunsAsRoot,
+	analyzer.IssueRootNotPrevented,
+	analyzer.IssueSecurityContextConflict,
+	analyzer.IssueShellAsPID1,
+	analyzer.IssueSharedProbePort,
+	analyzer.IssueServiceEndpointNotReady,
+	analyzer.IssueSequentialInitContainers,
+	analyzer.IssueHostPathWritable,
+	analyzer.IssueHostPathReadOnly,
+	analyzer.IssueLocalPersistentVolume,
+	analyzer.IssueReplicasPinnedSameNode,
+	analyzer.IssueCPUBurstRatioTooHigh,
+	analyzer.IssueStaleImage,
+	analyzer.IssueMissingCostAnnotation,
+	analyzer.IssueNotSafeToEvict,
+	analyzer.IssueBatchNotEvictable,
+	analyzer.IssueVirtualNodeNoZone,
+}
+
+func healthyPod(namespace, name string) analyzer.PodAnalysis {
+	return analyzer.PodAnalysis{
+		Name:                  name,
+		Namespace:             namespace,
+		Status:                analyzer.StatusHealthy,
+		Ready:                 "1/1",
+		Age:                   "2d",
+		RunningTime:           "2d",
+		EfficiencyScore:       -1,
+		PDBDisruptionsAllowed: -1,
+	}
+}
+
+func crashingPod(namespace, name string) analyzer.PodAnalysis {
+	pod := healthyPod(namespace, name)
+	pod.Status = analyzer.StatusWarning
+	pod.Ready = "0/1"
+	pod.Restarts = 12
+	pod.Reason = "CrashLoopBackOff"
+	return pod
+}
+
+func newResult(pods ...analyzer.PodAnalysis) *analyzer.AnalysisResult {
+	result := &analyzer.AnalysisResult{Pods: pods, TotalPods: len(pods)}
+	for _, pod := range pods {
+		switch pod.Status {
+		case analyzer.StatusHealthy:
+			result.HealthyPods++
+		case analyzer.StatusWarning:
+			result.WarningPods++
+		case analyzer.StatusError:
+			result.ErrorPods++
+		case analyzer.StatusPending:
+			result.PendingPods++
+		}
+		result.TotalRestarts += pod.Restarts
+		result.ConfigIssueCount += len(pod.ConfigIssues)
+	}
+	return result
+}
+
+func assertContains(t *testing.T, out string, want []string) {
+	t.Helper()
+	for _, s := range want {
+		if !strings.Contains(out, s) {
+			t.Errorf("output does not contain %q\noutput:\n%s", s, out)
+		}
+	}
+}
+
+func assertNotContains(t *testing.T, out string, unwanted []string) {
+	t.Helper()
+	for _, s := range unwanted {
+		if strings.Contains(out, s) {
+			t.Errorf("output unexpectedly contains %q\noutput:\n%s", s, out)
+		}
+	}
+}
+
+func TestPrintPodTable(t *testing.T) {
+	withAllIssues := crashingPod("default", "broken-api")
+	withAllIssues.ConfigIssues = allConfigIssues
+
+	onCordoned := crashingPod("default", "drained")
+	onCordoned.ConfigIssues = []analyzer.ConfigIssue{analyzer.IssueNodeCordoned}
+
+	withIssues := crashingPod("default", "sloppy")
+	withIssues.ConfigIssues = []analyzer.ConfigIssue{
+		analyzer.IssueMissingRequests,
+		analyzer.IssueMissingLimits,
+		analyzer.ConfigIssue(string(analyzer.IssueMissingLimits) + " (init container: setup)"),
+		analyzer.IssueNoProbe,
+	}
+
+	onECI := healthyPod("default", "burst-worker")
+	onECI.RunningOnECI = true
+	eciConfigured := healthyPod("default", "eci-ready")
+	eciConfigured.HasECIConfig = true
+
+	tests := []struct {
+		name       string
+		result     *analyzer.AnalysisResult
+		opts       TableOptions
+		want       []string
+		notWant    []string
+		wantIssues int
+	}{
+		{
+			name:    "empty pod list",
+			result:  newResult(),
+			want:    []string{"All pods are healthy!"},
+			notWant: []string{"NAME", "STATUS"},
+		},
+		{
+			name:    "all healthy hides rows by default",
+			result:  newResult(healthyPod("default", "web-1"), healthyPod("default", "web-2")),
+			want:    []string{"All pods are healthy!"},
+			notWant: []string{"web-1", "web-2"},
+		},
+		{
+			name:   "all healthy with show all",
+			result: newResult(healthyPod("default", "web-1"), healthyPod("default", "web-2")),
+			opts:   TableOptions{ShowAll: true},
+			want:   []string{"NAME", "STATUS", "READY", "RESTARTS", "web-1", "web-2", "✓ Healthy"},
+		},
+		{
+			name:    "unhealthy pods only by default",
+			result:  newResult(healthyPod("default", "web-1"), crashingPod("default", "api-1")),
+			want:    []string{"api-1", "⚠ Warning", "CrashLoopBackOff", "0/1"},
+			notWant: []string{"web-1", "NAMESPACE"},
+		},
+		{
+			name:       "pod with all config issues",
+			result:     newResult(withAllIssues),
+			opts:       TableOptions{VerboseIssues: true},
+			want:       append([]string{"broken-api", "⚙"}, issueStrings(allConfigIssues)...),
+			wantIssues: len(allConfigIssues),
+		},
+		{
+			name:    "config issues are marked inline by default",
+			result:  newResult(withIssues),
+			want:    []string{"⚙ req,lim,probe"},
+			notWant: []string{string(analyzer.IssueMissingRequests)},
+		},
+		{
+			name:    "inline marker is capped",
+			result:  newResult(withIssues),
+			opts:    TableOptions{MaxIssueLines: 2},
+			want:    []string{"⚙ req,lim +1 more"},
+			notWant: []string{"probe"},
+		},
+		{
+			name:       "verbose issues are capped",
+			result:     newResult(withIssues),
+			opts:       TableOptions{VerboseIssues: true, MaxIssueLines: 2},
+			want:       []string{string(analyzer.IssueMissingRequests), "└─ +2 more"},
+			notWant:    []string{string(analyzer.IssueNoProbe)},
+			wantIssues: 3,
+		},
+		{
+			name:   "mixed namespace mode",
+			result: newResult(crashingPod("prod", "api-1"), crashingPod("staging", "api-1")),
+			opts:   TableOptions{ShowNamespace: true},
+			want:   []string{"NAMESPACE", "prod", "staging"},
+		},
+		{
+			name:   "pdb column",
+			result: newResult(crashingPod("default", "api-1")),
+			opts:   TableOptions{ShowPDB: true},
+			want:   []string{"PDB"},
+		},
+		{
+			name:    "failed husks only",
+			result:  newResult(crashingPod("default", "api-1")),
+			opts:    TableOptions{FailedHusksOnly: true},
+			want:    []string{"Failed husks (rejected at admission): 0"},
+			notWant: []string{"api-1"},
+		},
+		{
+			name:       "cordoned node name is highlighted",
+			result:     newResult(onCordoned),
+			want:       []string{colorMagenta + "drained", "⚙ cordon"},
+			wantIssues: 0,
+		},
+		{
+			name:   "eci markers",
+			result: newResult(onECI, eciConfigured),
+			opts:   TableOptions{ShowAll: true},
+			want:   []string{colorCyan + "ECI  " + colorReset + " ", colorYellow + "eci* " + colorReset + " "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf).PrintPodTable(tt.result, tt.opts)
+			out := buf.String()
+
+			assertContains(t, out, tt.want)
+			assertNotContains(t, out, tt.notWant)
+			if got := strings.Count(out, "└─"); got != tt.wantIssues {
+				t.Errorf("issue lines = %d, want %d", got, tt.wantIssues)
+			}
+		})
+	}
+}
+
+// TestIssueTags 检查每个内置配置问题都有行尾标记用的简写
+func TestIssueTags(t *testing.T) {
+	for _, issue := range allConfigIssues {
+		if tag := issueTag(issue); tag == "other" {
+			t.Errorf("issue %q has no tag", issue)
+		}
+	}
+}
+
+// TestFindingCheckNames 检查说明中提到的检查名称都能用于 --enable-check/--disable-check
+func TestFindingCheckNames(t *testing.T) {
+	checkName := regexp.MustCompile(`\(check "([a-z0-9-]+)"`)
+	for _, f := range findings {
+		for _, m := range checkName.FindAllStringSubmatch(f.detect, -1) {
+			if err := analyzer.ValidateCheckNames([]string{m[1]}); err != nil {
+				t.Errorf("finding %s: %v", f.id, err)
+			}
+		}
+	}
+}
+
+// TestPrintPodTableKeepsIssues 检查表格的折叠只影响显示，JSON 结果仍然包含全部配置问题
+func TestPrintPodTableKeepsIssues(t *testing.T) {
+	pod := crashingPod("default", "broken-api")
+	pod.ConfigIssues = append([]analyzer.ConfigIssue(nil), allConfigIssues...)
+	result := newResult(pod)
+
+	NewPrinter(io.Discard).PrintPodTable(result, TableOptions{VerboseIssues: true, MaxIssueLines: 1})
+
+	data, err := json.Marshal(result.ToV1())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	for _, issue := range allConfigIssues {
+		want, _ := json.Marshal(string(issue))
+		if !bytes.Contains(data, want) {
+			t.Errorf("JSON result does not contain %s", want)
+		}
+	}
+}
+
+func TestPrintPodDetailListsAllIssues(t *testing.T) {
+	pod := crashingPod("default", "broken-api")
+	pod.ConfigIssues = allConfigIssues
+
+	var buf bytes.Buffer
+	NewPrinter(&buf).PrintPodDetail(pod, nil)
+	assertContains(t, buf.String(), append([]string{"Config Issues"}, issueStrings(allConfigIssues)...))
+}
+
+func issueStrings(issues []analyzer.ConfigIssue) []string {
+	out := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, string(issue))
+	}
+	return out
+}
+
+func TestPrintSummary(t *testing.T) {
+	pending := healthyPod("default", "queued")
+	pending.Status = analyzer.StatusPending
+	failing := healthyPod("default", "failing")
+	failing.Status = analyzer.StatusError
+
+	withIssues := newResult(healthyPod("default", "web-1"))
+	withIssues.ConfigIssueCount = 3
+
+	eci := newResult(healthyPod("default", "a"), healthyPod("default", "b"), healthyPod("default", "c"), healthyPod("default", "d"))
+	eci.RunningOnECICount = 1
+	eci.HasECIConfigCount = 2
+
+	containers := newResult(healthyPod("default", "web-1"))
+	containers.Containers = analyzer.ContainerSummary{
+		Running:        412,
+		Waiting:        9,
+		WaitingReasons: map[string]int{"CrashLoopBackOff": 3, "ImagePullBackOff": 6},
+	}
+
+	admission := newResult()
+	admission.AdmissionFailures = map[string]map[string]int{
+		"node-a": {"OutOfpods": 3, "OutOfcpu": 1},
+	}
+
+	retained := newResult()
+	retained.RetainedPods = []analyzer.RetainedPods{
+		{Namespace: "batch", Succeeded: 300, Failed: 2},
+		{Namespace: "default", Failed: 10, Evicted: 10},
+	}
+
+	tests := []struct {
+		name    string
+		result  *analyzer.AnalysisResult
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "empty pod list",
+			result:  newResult(),
+			want:    []string{"Summary", "Total Pods:     0", "Total Restarts: 0"},
+			notWant: []string{"Healthy:", "Pending:", "Warning:", "Error:", "ECI Status", "Config Issues", "Containers:", "Cleanup:"},
+		},
+		{
+			name:    "all healthy",
+			result:  newResult(healthyPod("default", "web-1"), healthyPod("default", "web-2")),
+			want:    []string{"Total Pods:     2", "Healthy:        2"},
+			notWant: []string{"Warning:", "Error:"},
+		},
+		{
+			name:   "mixed statuses",
+			result: newResult(healthyPod("default", "web-1"), crashingPod("default", "api-1"), pending, failing),
+			want: []string{
+				"Total Pods:     4",
+				"Healthy:        1",
+				"Pending:        1",
+				"Warning:        1",
+				"Error:          1",
+				"Total Restarts: 12",
+			},
+		},
+		{
+			name:   "config issues",
+			result: withIssues,
+			want:   []string{"Config Issues:  3"},
+		},
+		{
+			name:   "eci status",
+			result: eci,
+			want:   []string{"ECI Status:", "Running on ECI: 1", "(25.0%)", "ECI configured: 2", "(not on ECI: 1)"},
+		},
+		{
+			name:   "container states",
+			result: containers,
+			want:   []string{"Containers:     412 running, 9 waiting (6 ImagePullBackOff, 3 CrashLoopBackOff)"},
+		},
+		{
+			name:   "admission failures",
+			result: admission,
+			want:   []string{"Admission Failures:", "node node-a: 1 OutOfcpu, 3 OutOfpods failures"},
+		},
+		{
+			name:   "retained finished pods",
+			result: retained,
+			want:   []string{"Cleanup:        312 finished pods retained across 2 namespaces"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf).PrintSummary(tt.result)
+			out := buf.String()
+
+			assertContains(t, out, tt.want)
+			assertNotContains(t, out, tt.notWant)
+		})
+	}
+}
+
+func TestPrintRecommendations(t *testing.T) {
+	withAllIssues := healthyPod("default", "broken-api")
+	withAllIssues.ConfigIssues = allConfigIssues
+
+	unschedulable := healthyPod("default", "queued")
+	unschedulable.Status = analyzer.StatusPending
+	unschedulable.Reason = "Unschedulable"
+
+	pinned := healthyPod("default", "web-6d4cf56db6-x2x9k")
+	pinned.Status = analyzer.StatusPending
+	pinned.Reason = "Unschedulable: no node has label pool=old-name"
+	pinned.OwnerKind, pinned.OwnerName = "Deployment", "web"
+	pinned.MissingNodeLabel = &analyzer.MissingNodeLabel{Key: "pool", Values: []string{"old-name"}, Closest: []string{"new-name", "batch"}}
+
+	husk := healthyPod("default", "husk")
+	husk.Status = analyzer.StatusError
+	husk.AdmissionFailure = true
+	husk.NodeName = "node-a"
+	husk.Reason = "OutOfpods"
+
+	fewRetained := newResult()
+	fewRetained.RetainedPods = []analyzer.RetainedPods{{Namespace: "batch", Succeeded: 3}}
+	manyRetained := newResult()
+	manyRetained.RetainedPods = []analyzer.RetainedPods{{Namespace: "batch", Succeeded: 55, Failed: 5}}
+
+	tests := []struct {
+		name    string
+		result  *analyzer.AnalysisResult
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "empty pod list",
+			result: newResult(),
+			want:   []string{"Recommendations", "No specific recommendations"},
+		},
+		{
+			name:   "all healthy",
+			result: newResult(healthyPod("default", "web-1")),
+			want:   []string{"No specific recommendations"},
+		},
+		{
+			name:    "crash looping pod",
+			result:  newResult(crashingPod("default", "api-1")),
+			want:    []string{"kubectl logs api-1 --previous", "Container keeps crashing"},
+			notWant: []string{"No specific recommendations"},
+		},
+		{
+			name:   "unschedulable pod",
+			result: newResult(unschedulable),
+			want:   []string{"Check node resources and taints"},
+		},
+		{
+			name:    "pod selecting a missing node label",
+			result:  newResult(pinned),
+			want:    []string{"Update the nodeSelector/nodeAffinity of Deployment web - no node has label pool=old-name; closest existing values for pool: new-name, batch"},
+			notWant: []string{"Check node resources and taints"},
+		},
+		{
+			name:    "admission failure",
+			result:  newResult(husk),
+			want:    []string{"node node-a", "kubectl delete pods --field-selector=status.phase=Failed -n default", "kubectl podview --failed-husks"},
+			notWant: []string{"kubectl describe pod husk"},
+		},
+		{
+			name:    "few retained finished pods",
+			result:  fewRetained,
+			want:    []string{"No specific recommendations"},
+			notWant: []string{"Delete 3 finished pods"},
+		},
+		{
+			name:   "many retained finished pods",
+			result: manyRetained,
+			want:   []string{"Delete 60 finished pods in batch: kubectl delete pods -n batch"},
+		},
+		{
+			name:   "pod with all config issues",
+			result: newResult(withAllIssues),
+			want: []string{
+				"Set resource requests",
+				"Set resource limits",
+				"Add liveness/readiness probes",
+				"Create a PodDisruptionBudget",
+				"Run containers as a non-root user",
+				"Set immutable: true on ConfigMaps",
+				"Set immutable: true on Secrets",
+				"Data on hostPath/local PVs is lost",
+			},
+			notWant: []string{"No specific recommendations"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf).PrintRecommendations(tt.result)
+			out := buf.String()
+
+			assertContains(t, out, tt.want)
+			assertNotContains(t, out, tt.notWant)
+		})
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		n    int64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1536, "1.5 KiB"},
+		{5 << 20, "5.0 MiB"},
+		{3 << 30, "3.0 GiB"},
+	}
+	for _, tt := range tests {
+		if got := formatBytes(tt.n); got != tt.want {
+			t.Errorf("formatBytes(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestGetStatusColor(t *testing.T) {
+	tests := []struct {
+		status analyzer.PodStatus
+		want   string
+	}{
+		{analyzer.StatusHealthy, colorGreen},
+		{analyzer.StatusWarning, colorYellow},
+		{analyzer.StatusError, colorRed},
+		{analyzer.StatusPending, colorBlue},
+		{analyzer.StatusUnknown, colorReset},
+		{analyzer.PodStatus("Bogus"), colorReset},
+	}
+
+	p := NewPrinter(&bytes.Buffer{})
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := p.getStatusColor(tt.status); got != tt.want {
+				t.Errorf("getStatusColor(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetStatusIcon(t *testing.T) {
+	tests := []struct {
+		status analyzer.PodStatus
+		want   string
+	}{
+		{analyzer.StatusHealthy, "✓ "},
+		{analyzer.StatusWarning, "⚠ "},
+		{analyzer.StatusError, "✗ "},
+		{analyzer.StatusPending, "◷ "},
+		{analyzer.StatusUnknown, "? "},
+		{analyzer.PodStatus("Bogus"), "? "},
+	}
+
+	p := NewPrinter(&bytes.Buffer{})
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := p.getStatusIcon(tt.status); got != tt.want {
+				t.Errorf("getStatusIcon(%q) = %q, want %q", tt.status, got, tt.want)
+			}
+		})
+	}
+}
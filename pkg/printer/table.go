@@ -0,0 +1,194 @@
+package printer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// align 是单元格在列宽内的对齐方式
+type align int
+
+const (
+	alignLeft align = iota
+	alignRight
+)
+
+// column 描述表格的一列
+type column struct {
+	header string
+	// width 是最小列宽，实际列宽取表头和所有单元格中最宽的值
+	width int
+	// maxWidth 是列宽的上限，为 0 表示不限制；调用方负责截断超出的内容
+	maxWidth int
+	// gap 是与下一列之间的空格数，为 0 时为 1
+	gap   int
+	align align
+}
+
+// cell 是一个单元格，color 包裹填充后的内容；列宽按可见字符计算，不包含颜色代码
+type cell struct {
+	text  string
+	color string
+}
+
+// tableRow 是表格的一行
+// cells 可以少于列数，最后一个单元格不填充；color 非空时包裹整行；lines 是行下方原样输出的子行
+type tableRow struct {
+	cells []cell
+	color string
+	lines []string
+}
+
+// table 是所有表格视图共用的渲染模型：按列计算宽度，表头和每行使用同样的列宽，最后一列不填充
+type table struct {
+	columns []column
+	rows    []tableRow
+
+	// headerColor 包裹整个表头行
+	headerColor string
+	// rule 为 true 时在表头下打印分隔线，长度覆盖最后一列之前的所有列，再加上 ruleTail 个字符
+	rule     bool
+	ruleTail int
+}
+
+// plain 返回没有颜色的单元格
+func plain(text string) cell {
+	return cell{text: text}
+}
+
+// colored 返回带颜色的单元格
+func colored(color, text string) cell {
+	return cell{text: text, color: color}
+}
+
+// addRow 追加一行并返回它，便于继续设置颜色和子行
+func (t *table) addRow(cells ...cell) *tableRow {
+	t.rows = append(t.rows, tableRow{cells: cells})
+	return &t.rows[len(t.rows)-1]
+}
+
+// widths 返回每列的实际宽度，每行最后一个单元格不填充，不参与计算；没有单元格的行跳过
+func (t *table) widths() []int {
+	widths := make([]int, len(t.columns))
+	for i, col := range t.columns {
+		widths[i] = max(col.width, utf8.RuneCountInString(col.header))
+	}
+	for _, row := range t.rows {
+		if len(row.cells) == 0 {
+			continue
+		}
+		for i, c := range row.cells[:len(row.cells)-1] {
+			widths[i] = max(widths[i], utf8.RuneCountInString(c.text))
+		}
+	}
+	for i, col := range t.columns {
+		if col.maxWidth > 0 && widths[i] > col.maxWidth {
+			widths[i] = col.maxWidth
+		}
+	}
+	return widths
+}
+
+// gap 返回第 i 列之后的空格数
+func (t *table) gap(i int) int {
+	if t.columns[i].gap > 0 {
+		return t.columns[i].gap
+	}
+	return 1
+}
+
+// line 按列宽拼接一行单元格，最后一个单元格不填充
+func (t *table) line(cells []cell, widths []int) string {
+	var b strings.Builder
+	for i, c := range cells {
+		text := c.text
+		last := i == len(cells)-1
+		if !last {
+			text = pad(text, widths[i], t.columns[i].align)
+		}
+		if c.color != "" {
+			text = c.color + text + colorReset
+		}
+		b.WriteString(text)
+		if !last {
+			b.WriteString(strings.Repeat(" ", t.gap(i)))
+		}
+	}
+	return b.String()
+}
+
+// write 输出表头、分隔线、各行及其子行
+func (t *table) write(w io.Writer) {
+	widths := t.widths()
+	t.writeHeader(w, widths)
+	t.writeRows(w, widths, t.rows)
+}
+
+// tableSection 是分段输出中的一段：标题和属于该段的行
+type tableSection struct {
+	title string
+	rows  []tableRow
+}
+
+// writeSections 分段输出表格：每段依次输出标题、表头、分隔线和该段的行，段之间空一行
+// 列宽按 t.rows 中的所有行计算，各段的列对齐
+func (t *table) writeSections(w io.Writer, sections []tableSection) {
+	widths := t.widths()
+	for i, section := range sections {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintln(w, section.title)
+		t.writeHeader(w, widths)
+		t.writeRows(w, widths, section.rows)
+	}
+}
+
+// writeHeader 输出表头和分隔线
+func (t *table) writeHeader(w io.Writer, widths []int) {
+	headers := make([]cell, len(t.columns))
+	for i, col := range t.columns {
+		headers[i] = plain(col.header)
+	}
+	header := t.line(headers, widths)
+	if t.headerColor != "" {
+		header = t.headerColor + header + colorReset
+	}
+	fmt.Fprintln(w, header)
+
+	if t.rule {
+		n := t.ruleTail
+		for i := range t.columns[:len(t.columns)-1] {
+			n += widths[i] + t.gap(i)
+		}
+		fmt.Fprintln(w, strings.Repeat("-", n))
+	}
+}
+
+// writeRows 输出各行及其子行
+func (t *table) writeRows(w io.Writer, widths []int, rows []tableRow) {
+	for _, row := range rows {
+		line := t.line(row.cells, widths)
+		if row.color != "" {
+			line = row.color + line + colorReset
+		}
+		fmt.Fprintln(w, line)
+		for _, sub := range row.lines {
+			fmt.Fprintln(w, sub)
+		}
+	}
+}
+
+// pad 将文本按可见字符数填充到指定宽度
+func pad(text string, width int, a align) string {
+	n := width - utf8.RuneCountInString(text)
+	if n <= 0 {
+		return text
+	}
+	if a == alignRight {
+		return strings.Repeat(" ", n) + text
+	}
+	return text + strings.Repeat(" ", n)
+}
@@ -0,0 +1,139 @@
+package printer
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden 将输出与 testdata/<name>.golden 逐字节比较，-update 时改写 golden 文件
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// goldenPods 覆盖表格中各种单元格：状态颜色、截断的名称、cordon 高亮、ECI 标记、配置问题和终止消息
+func goldenPods() *analyzer.AnalysisResult {
+	pending := healthyPod("default", "queue-consumer-5f7d9c8b6-q2w3e")
+	pending.Status = analyzer.StatusPending
+	pending.Ready = "0/1"
+	pending.RunningTime = "-"
+	pending.Reason = "Unschedulable: 0/3 nodes are available"
+
+	failed := healthyPod("payments", "ledger-migrate-8k2lq")
+	failed.Status = analyzer.StatusError
+	failed.Ready = "0/1"
+	failed.Reason = "Error"
+	failed.ContainerInfo = []analyzer.ContainerAnalysis{{Name: "migrate", TerminationMessage: "panic: dial tcp 10.96.14.2:5432: connect: connection refused"}}
+	failed.DetailsOmitted = true
+
+	crashing := crashingPod("default", "api-5d8f6b7c9-r4t2n")
+	crashing.ConfigIssues = []analyzer.ConfigIssue{analyzer.IssueMissingLimits, analyzer.IssueNoProbe, analyzer.IssueRunsAsRoot}
+	crashing.PDBDisruptionsAllowed = 0
+
+	cordoned := crashingPod("default", "drained")
+	cordoned.ConfigIssues = []analyzer.ConfigIssue{analyzer.IssueNodeCordoned}
+
+	long := crashingPod("kube-system-with-a-very-long-namespace-name", "a-deployment-with-an-extraordinarily-long-generated-name-7c79c4bf97-abc12")
+
+	onECI := healthyPod("batch", "burst-worker")
+	onECI.RunningOnECI = true
+	onECI.PDBDisruptionsAllowed = 0
+	eciConfigured := healthyPod("batch", "eci-ready")
+	eciConfigured.HasECIConfig = true
+	eciConfigured.PDBDisruptionsAllowed = 2
+
+	for _, pod := range []*analyzer.PodAnalysis{&crashing, &failed, &cordoned, &long, &onECI, &eciConfigured} {
+		pod.SchedulingLatency = 2 * time.Second
+	}
+	pending.SchedulingLatency = -1
+	eciConfigured.SchedulingLatency = 3*time.Minute + 12*time.Second
+	crashing.EfficiencyScore = 1.25
+	onECI.EfficiencyScore = 0.04
+
+	return newResult(healthyPod("default", "web-1"), crashing, pending, failed, cordoned, long, onECI, eciConfigured)
+}
+
+func TestPodTableGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		opts TableOptions
+	}{
+		{"pods_default", TableOptions{MaxIssueLines: DefaultMaxIssueLines}},
+		{"pods_verbose_issues", TableOptions{VerboseIssues: true, MaxIssueLines: 2}},
+		{"pods_all_namespaces", TableOptions{ShowAll: true, ShowNamespace: true, ShowPDB: true, MaxIssueLines: DefaultMaxIssueLines}},
+		{"pods_wide", TableOptions{ShowAll: true, Wide: true, MaxIssueLines: DefaultMaxIssueLines}},
+		{"pods_group_by_status", TableOptions{ShowAll: true, GroupByStatus: true, MaxIssueLines: DefaultMaxIssueLines}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf).PrintPodTable(goldenPods(), tt.opts)
+			assertGolden(t, tt.name, buf.Bytes())
+		})
+	}
+}
+
+func TestNamespaceSummaryGolden(t *testing.T) {
+	summaries := []analyzer.NamespaceSummary{
+		{Namespace: "checkout", Total: 12, Healthy: 12, Restarts: 1},
+		{Namespace: "payments", Total: 8, Healthy: 5, Warning: 2, Pending: 1, Restarts: 31},
+		{Namespace: "search-indexing", Total: 3, Healthy: 1, Error: 2},
+		{Namespace: "tier1-restricted"},
+	}
+	failed := map[string]error{"tier1-restricted": errors.New("forbidden")}
+
+	var buf bytes.Buffer
+	NewPrinter(&buf).PrintNamespaceSummary(summaries, failed)
+	assertGolden(t, "namespaces", buf.Bytes())
+}
+
+func TestCronJobTableGolden(t *testing.T) {
+	results := []analyzer.CronJobAnalysis{
+		{Namespace: "batch", Name: "nightly-report", Schedule: "0 2 * * *", Status: analyzer.CronJobHealthy, LastSuccess: "9h", LastFailure: "-", Reason: ""},
+		{Namespace: "batch", Name: "sync", Schedule: "*/5 * * * *", Status: analyzer.CronJobFailing, LastSuccess: "2d", LastFailure: "4m", Active: 1, Reason: "last 3 runs failed"},
+	}
+	for _, showNamespace := range []bool{false, true} {
+		name := "cronjobs"
+		if showNamespace {
+			name = "cronjobs_all_namespaces"
+		}
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewPrinter(&buf).PrintCronJobTable(results, showNamespace)
+			assertGolden(t, name, buf.Bytes())
+		})
+	}
+}
+
+func TestTableEmptyRow(t *testing.T) {
+	tbl := &table{columns: []column{{header: "NAME"}, {header: "STATUS"}}}
+	tbl.addRow()
+	tbl.addRow(plain("web-1"), plain("Running"))
+
+	var buf bytes.Buffer
+	tbl.write(&buf)
+	if want := "NAME  STATUS\n\nweb-1 Running\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
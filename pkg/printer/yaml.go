@@ -0,0 +1,58 @@
+package printer
+
+import (
+	"io"
+
+	"sigs.k8s.io/yaml"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+	"github.com/FishPie-HQ/kubectl-podview/pkg/analyzer"
+)
+
+// YAMLPrinter 以 YAML 输出分析结果，字段与 JSONPrinter 相同，不含颜色和提示文字
+type YAMLPrinter struct {
+	out io.Writer
+}
+
+// NewYAMLPrinter 创建一个新的 YAMLPrinter
+func NewYAMLPrinter(out io.Writer) *YAMLPrinter {
+	return &YAMLPrinter{out: out}
+}
+
+// PrintAnalysis 输出完整的分析结果，Pod 按 namespace/name 排序（见 AnalysisResult.ToV1）
+func (p *YAMLPrinter) PrintAnalysis(results *analyzer.AnalysisResult) error {
+	return p.encode(results.ToV1())
+}
+
+// PrintTopProblems 按排名顺序输出 --top-problems 的结果，不重新排序
+func (p *YAMLPrinter) PrintTopProblems(pods []analyzer.ScoredPod) error {
+	out := make([]analysisv1.ScoredPod, 0, len(pods))
+	for _, pod := range pods {
+		out = append(out, pod.ToV1())
+	}
+	return p.encode(out)
+}
+
+// PrintECICandidates 按排名顺序输出 --suggest-eci 的结果
+func (p *YAMLPrinter) PrintECICandidates(candidates []analyzer.ECICandidate) error {
+	out := make([]analysisv1.ECICandidate, 0, len(candidates))
+	for _, c := range candidates {
+		out = append(out, c.ToV1())
+	}
+	return p.encode(out)
+}
+
+// PrintPodDetail 输出 kubectl podview detail 的单个 Pod 及其时间线
+func (p *YAMLPrinter) PrintPodDetail(pod analyzer.PodAnalysis, timeline []analyzer.TimelineEvent) error {
+	return p.encode(podDetail(pod, timeline))
+}
+
+// encode 以 YAML 输出 v，字段名与 JSON 相同
+func (p *YAMLPrinter) encode(v any) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = p.out.Write(data)
+	return err
+}
@@ -0,0 +1,103 @@
+package printer
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+
+	analysisv1 "github.com/FishPie-HQ/kubectl-podview/pkg/analysis/v1"
+)
+
+// TestPrintAnalysisSortsPods 检查 JSON 和 YAML 输出都按 namespace/name 排序 Pod
+func TestPrintAnalysisSortsPods(t *testing.T) {
+	printers := map[string]func(*bytes.Buffer) error{
+		"json": func(buf *bytes.Buffer) error { return NewJSONPrinter(buf).PrintAnalysis(goldenPods()) },
+		"yaml": func(buf *bytes.Buffer) error { return NewYAMLPrinter(buf).PrintAnalysis(goldenPods()) },
+	}
+	want := []string{
+		"batch/burst-worker",
+		"batch/eci-ready",
+		"default/api-5d8f6b7c9-r4t2n",
+		"default/drained",
+		"default/queue-consumer-5f7d9c8b6-q2w3e",
+		"default/web-1",
+		"kube-system-with-a-very-long-namespace-name/a-deployment-with-an-extraordinarily-long-generated-name-7c79c4bf97-abc12",
+		"payments/ledger-migrate-8k2lq",
+	}
+	for format, print := range printers {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := print(&buf); err != nil {
+				t.Fatalf("PrintAnalysis failed: %v", err)
+			}
+			if strings.Contains(buf.String(), "\x1b[") {
+				t.Errorf("output contains ANSI escape codes:\n%s", buf.String())
+			}
+
+			// YAML 是 JSON 的超集，两种输出都可以按 YAML 解析
+			var got analysisv1.AnalysisResult
+			if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("output is not valid %s: %v\n%s", format, err, buf.String())
+			}
+			var names []string
+			for _, pod := range got.Pods {
+				names = append(names, pod.Namespace+"/"+pod.Name)
+			}
+			if !reflect.DeepEqual(names, want) {
+				t.Errorf("pods = %v, want %v", names, want)
+			}
+		})
+	}
+}
+
+func TestYAMLPrinterRoundTrip(t *testing.T) {
+	for _, tt := range roundTripTests() {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := NewYAMLPrinter(&buf).PrintAnalysis(tt.results); err != nil {
+				t.Fatalf("PrintAnalysis failed: %v", err)
+			}
+
+			var got analysisv1.AnalysisResult
+			if err := yaml.UnmarshalStrict(buf.Bytes(), &got); err != nil {
+				t.Fatalf("output is not valid YAML: %v\n%s", err, buf.String())
+			}
+			want := tt.results.ToV1()
+			if !reflect.DeepEqual(&got, want) {
+				t.Errorf("round trip mismatch\ngot:  %+v\nwant: %+v", got, *want)
+			}
+		})
+	}
+}
+
+// TestYAMLPrinterMatchesJSON 检查 YAML 和 JSON 输出的字段名和取值完全相同，工具可以任选一种格式
+func TestYAMLPrinterMatchesJSON(t *testing.T) {
+	var jsonOut, yamlOut bytes.Buffer
+	if err := NewJSONPrinter(&jsonOut).PrintAnalysis(goldenPods()); err != nil {
+		t.Fatalf("JSON PrintAnalysis failed: %v", err)
+	}
+	if err := NewYAMLPrinter(&yamlOut).PrintAnalysis(goldenPods()); err != nil {
+		t.Fatalf("YAML PrintAnalysis failed: %v", err)
+	}
+
+	// 两种输出都解析为通用结构比较，字段名的差异也会被发现
+	var fromJSON, fromYAML map[string]any
+	if err := json.Unmarshal(jsonOut.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("JSON output is not valid: %v", err)
+	}
+	converted, err := yaml.YAMLToJSON(yamlOut.Bytes())
+	if err != nil {
+		t.Fatalf("YAML output is not valid: %v", err)
+	}
+	if err := json.Unmarshal(converted, &fromYAML); err != nil {
+		t.Fatalf("converted YAML output is not valid JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(fromYAML, fromJSON) {
+		t.Errorf("YAML fields differ from JSON fields\ngot:  %v\nwant: %v", fromYAML, fromJSON)
+	}
+}